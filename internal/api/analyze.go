@@ -0,0 +1,279 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// degradedMatchesTimeout is reported when analysis ran without match history
+const degradedMatchesTimeout = "match history timed out; analysis is based on summoner data only"
+
+// defaultAnalyzeMatchCount is how many matches the default analyze mode fetches when the
+// request sets no count
+const defaultAnalyzeMatchCount = 20
+
+// defaultSharedAnalysisTimeout bounds a coalesced analysis, which outlives the request that started it
+const defaultSharedAnalysisTimeout = 30 * time.Second
+
+// WithAnalyzeMatchDeadline sets a soft deadline for the analyze flow's match fetch. When the
+// fetch does not finish in time, analysis proceeds without matches and the response is marked
+// degraded instead of failing. Zero (the default) waits for matches for as long as the request lives.
+func WithAnalyzeMatchDeadline(deadline time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		handler.analyzeMatchDeadline = deadline
+	}
+}
+
+// WithAnalyzeCountLimit overrides the maximum analyze "count", which may be lower than the
+// limit for raw match requests. Modes whose default match count exceeds it are capped to it.
+func WithAnalyzeCountLimit(maxCount int) HandlerOption {
+	return func(handler *Handler) {
+		if maxCount > 0 {
+			handler.maxAnalyzeCount = maxCount
+		}
+	}
+}
+
+// WithAnalyzeMatchCount overrides how many matches the default analyze mode fetches when the
+// request sets no count, independently of the match-listing defaults. It is still capped by
+// the analyze count limit.
+func WithAnalyzeMatchCount(count int) HandlerOption {
+	return func(handler *Handler) {
+		if count > 0 {
+			handler.analyzeMatchCount = count
+		}
+	}
+}
+
+// WithAnalyzeMinMatches sets how many matches a player needs before opgl-cortex is asked to
+// analyze them; with fewer, analyze fails with 422 INSUFFICIENT_DATA without a cortex call.
+// Zero (the default) analyzes any number of matches.
+func WithAnalyzeMinMatches(minMatches int) HandlerOption {
+	return func(handler *Handler) {
+		if minMatches >= 0 {
+			handler.minAnalyzeMatches = minMatches
+		}
+	}
+}
+
+// analyzeFetchCount returns how many matches to fetch for analysis: the request's count if
+// set, otherwise the mode's count, with the default mode using the configured analyze match
+// count. The result is clamped to the analyze count limit.
+func (handler *Handler) analyzeFetchCount(options *validation.AnalyzeOptions) int {
+	count := options.MatchCount()
+	if requested, err := options.Count.Int(); (err != nil || requested <= 0) && (options.Mode == "" || options.Mode == validation.DefaultAnalyzeMode) {
+		count = handler.analyzeMatchCount
+	}
+	return min(count, handler.maxAnalyzeCount)
+}
+
+// WithAnalyzeCoalescing makes concurrent analyze requests for the same player and options
+// share one match fetch and cortex analysis, and reuses completed results for window.
+// Degraded results are shared with concurrent requests but not reused. Zero disables coalescing.
+func WithAnalyzeCoalescing(window time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		if window <= 0 {
+			handler.analyzeCoalescer = nil
+			handler.analysisCache = nil
+			return
+		}
+		handler.analyzeCoalescer = cache.NewCoalescer()
+		handler.analysisCache = cache.NewMemoryCache(window, 0)
+	}
+}
+
+// WithSharedAnalysisTimeout overrides how long a coalesced analysis may run. The shared
+// computation ignores the initiating client's cancellation, so this is what bounds it.
+func WithSharedAnalysisTimeout(timeout time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		if timeout > 0 {
+			handler.sharedAnalysisTimeout = timeout
+		}
+	}
+}
+
+// WithAnalysisCache replaces the in-memory store of completed analyses set up by
+// WithAnalyzeCoalescing (e.g. with a Redis cache shared across instances), so it must be
+// applied after it. It has no effect while coalescing is disabled.
+func WithAnalysisCache(analysisCache cache.Cache) HandlerOption {
+	return func(handler *Handler) {
+		if handler.analyzeCoalescer != nil {
+			handler.analysisCache = analysisCache
+		}
+	}
+}
+
+// analysisCacheKey identifies analyze requests that produce the same result: the same
+// player (scoped to an org) analyzed with the same mode, match count, partial setting and locale
+func analysisCacheKey(orgID string, region string, puuid string, options *validation.AnalyzeOptions, matchCount int, locale string) string {
+	mode := options.Mode
+	if mode == "" {
+		mode = validation.DefaultAnalyzeMode
+	}
+	return strings.Join([]string{
+		orgID,
+		region,
+		puuid,
+		mode,
+		strconv.Itoa(matchCount),
+		strconv.FormatBool(options.AllowsPartial()),
+		locale,
+	}, "|")
+}
+
+// analyze fetches matches and runs the cortex analysis for a resolved summoner. With
+// coalescing enabled, a recent result is reused (unless refresh is set) and concurrent
+// identical requests share one computation. The shared computation ignores the initiating
+// client's cancellation because other requests may be waiting on it, and is bounded by the
+// shared analysis timeout instead; each request stops waiting when its own context ends.
+func (handler *Handler) analyze(request *http.Request, region string, summoner *models.Summoner, options *validation.AnalyzeOptions, locale string, refresh bool) (*models.AnalysisResult, error) {
+	if handler.analyzeCoalescer == nil {
+		return handler.runAnalysis(request, region, summoner, options, locale)
+	}
+
+	cacheKey := analysisCacheKey(middleware.OrgIDFromContext(request.Context()), region, summoner.PUUID, options, handler.analyzeFetchCount(options), locale)
+	if !refresh {
+		if cached, found := handler.analysisCache.Get(cacheKey); found {
+			handler.cacheMetrics.Hit(metrics.CacheAnalysis)
+			return cached.(*models.AnalysisResult), nil
+		}
+		handler.cacheMetrics.Miss(metrics.CacheAnalysis)
+	}
+
+	value, err, _ := handler.analyzeCoalescer.Do(request.Context(), cacheKey, func() (interface{}, error) {
+		sharedContext, cancel := context.WithTimeout(context.WithoutCancel(request.Context()), handler.sharedAnalysisTimeout)
+		defer cancel()
+
+		// Steps are timed on a collector of the shared computation's own and added to every
+		// request sharing the result, so waiters report them as well as the initiator
+		sharedContext = withNewStepTimings(sharedContext)
+		sharedRequest := request.WithContext(sharedContext)
+		analysisResult, err := handler.runAnalysis(sharedRequest, region, summoner, options, locale)
+		if err == nil && !analysisResult.Degraded {
+			handler.analysisCache.Set(cacheKey, analysisResult)
+		}
+		return &sharedAnalysis{result: analysisResult, stepDurations: stepDurationsFromContext(sharedContext)}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shared := value.(*sharedAnalysis)
+	addStepDurations(request.Context(), shared.stepDurations)
+	return shared.result, nil
+}
+
+// sharedAnalysis is the outcome of a coalesced analysis: the result and how long each of
+// its steps took
+type sharedAnalysis struct {
+	result        *models.AnalysisResult
+	stepDurations map[string]time.Duration
+}
+
+// runAnalysis fetches the matches to analyze and sends them to opgl-cortex, marking the
+// result degraded when the match fetch hit the soft deadline
+func (handler *Handler) runAnalysis(request *http.Request, region string, summoner *models.Summoner, options *validation.AnalyzeOptions, locale string) (*models.AnalysisResult, error) {
+	matchesStart := time.Now()
+	matches, matchesDegraded, err := handler.fetchAnalyzeMatches(request, region, summoner.PUUID, options)
+	if err != nil {
+		return nil, err
+	}
+	recordStepTiming(request.Context(), timingMatches, matchesStart)
+
+	// A degraded fetch already chose to analyze without matches. The minimum never exceeds
+	// the number of matches requested, so small counts and quick mode stay usable.
+	if !matchesDegraded {
+		if required := min(handler.minAnalyzeMatches, handler.analyzeFetchCount(options)); len(matches) < required {
+			return nil, apierrors.InsufficientData(fmt.Sprintf("At least %d matches are required for analysis; found %d", required, len(matches)))
+		}
+	}
+
+	analyzeStart := time.Now()
+	analysisResult, err := handler.serviceProxy.AnalyzePlayer(request.Context(), summoner, matches, locale)
+	if err != nil {
+		return nil, err
+	}
+	recordStepTiming(request.Context(), timingAnalyze, analyzeStart)
+
+	if matchesDegraded {
+		markDegraded(analysisResult, degradedMatchesTimeout)
+	}
+	return analysisResult, nil
+}
+
+// fetchAnalyzeMatches retrieves the matches to analyze, as many as options select. degraded
+// is true when the soft deadline expired first; the returned error is then nil and matches
+// is empty. Requests with partial=false, and cancellation of the request itself, never degrade.
+func (handler *Handler) fetchAnalyzeMatches(request *http.Request, region string, puuid string, options *validation.AnalyzeOptions) (matches []models.Match, degraded bool, err error) {
+	count := handler.analyzeFetchCount(options)
+
+	if handler.analyzeMatchDeadline <= 0 || !options.AllowsPartial() {
+		matches, err = handler.serviceProxy.GetMatchesByPUUID(request.Context(), region, puuid, count, models.MatchFilter{})
+		return matches, false, err
+	}
+
+	matchCtx, cancel := context.WithTimeout(request.Context(), handler.analyzeMatchDeadline)
+	defer cancel()
+
+	matches, err = handler.serviceProxy.GetMatchesByPUUID(matchCtx, region, puuid, count, models.MatchFilter{})
+	if err == nil {
+		return matches, false, nil
+	}
+
+	// Only our own soft deadline degrades; upstream errors and client disconnects still fail
+	if matchCtx.Err() != context.DeadlineExceeded || request.Context().Err() != nil {
+		return nil, false, err
+	}
+
+	logger := middleware.LoggerFromContext(request.Context())
+	logger.Warn().
+		Err(err).
+		Dur("deadline", handler.analyzeMatchDeadline).
+		Msg("Match fetch exceeded soft deadline; analyzing without matches")
+
+	return []models.Match{}, true, nil
+}
+
+// isDryRunRequested reports whether the client asked for a dry-run analysis via ?dryRun=true
+func isDryRunRequested(request *http.Request) bool {
+	return request.URL.Query().Get("dryRun") == "true"
+}
+
+// analyzeDryRun completes a dry-run analysis for an already resolved summoner: it checks that
+// opgl-cortex is reachable and reports what would be analyzed, without fetching matches or
+// spending cortex compute on an analysis
+func (handler *Handler) analyzeDryRun(writer http.ResponseWriter, request *http.Request, summoner *models.Summoner) {
+	if err := handler.serviceProxy.CheckCortexHealth(request.Context()); err != nil {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, models.AnalyzeDryRunResponse{
+		WouldAnalyze: true,
+		Summoner:     summoner,
+	})
+}
+
+// markDegraded flags an analysis result as degraded
+func markDegraded(result *models.AnalysisResult, reason string) {
+	result.Degraded = true
+	result.DegradedReasons = append(result.DegradedReasons, reason)
+}
+
+// addDegradedWarnings adds a response warning for each reason the result is degraded
+func addDegradedWarnings(request *http.Request, result *models.AnalysisResult) {
+	for _, reason := range result.DegradedReasons {
+		middleware.AddWarning(request.Context(), reason)
+	}
+}
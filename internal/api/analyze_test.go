@@ -0,0 +1,594 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// newAnalyzeRequest builds a POST /api/v1/analyze request for a fixed test player
+func newAnalyzeRequest() *http.Request {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(requestBody))
+	return request
+}
+
+// newSoftDeadlineProxy returns a mock whose match fetch takes matchDelay and then returns
+// matchErr, recording the matches cortex receives
+func newSoftDeadlineProxy(matchDelay time.Duration, matchErr error, analyzedMatches *[]models.Match) *MockServiceProxy {
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			time.Sleep(matchDelay)
+			if matchErr != nil {
+				return nil, matchErr
+			}
+			return []models.Match{{MatchID: "NA1_1"}, {MatchID: "NA1_2"}}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			*analyzedMatches = matches
+			return &models.AnalysisResult{PlayerStats: map[string]interface{}{"winRate": 0.5}}, nil
+		},
+	}
+}
+
+// TestAnalyzePlayer_SoftDeadlineTimely tests that matches fetched within the soft deadline are analyzed normally
+func TestAnalyzePlayer_SoftDeadlineTimely(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(0, nil, &analyzedMatches)
+	handler := NewHandler(mockProxy, WithAnalyzeMatchDeadline(time.Second))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if len(analyzedMatches) != 2 {
+		t.Errorf("Expected 2 matches sent to cortex, got %d", len(analyzedMatches))
+	}
+
+	var response models.AnalysisResult
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if response.Degraded {
+		t.Error("Expected response to not be degraded")
+	}
+	if responseRecorder.Header().Get(WarningHeader) != "" {
+		t.Errorf("Expected no Warning header, got '%s'", responseRecorder.Header().Get(WarningHeader))
+	}
+}
+
+// TestAnalyzePlayer_SoftDeadlineDegraded tests that a slow match fetch degrades to a summoner-only analysis
+func TestAnalyzePlayer_SoftDeadlineDegraded(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(50*time.Millisecond, apierrors.DataServiceError("Unable to connect to data service"), &analyzedMatches)
+	handler := NewHandler(mockProxy, WithAnalyzeMatchDeadline(10*time.Millisecond))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if analyzedMatches == nil || len(analyzedMatches) != 0 {
+		t.Errorf("Expected an empty match list sent to cortex, got %v", analyzedMatches)
+	}
+
+	var response models.AnalysisResult
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if !response.Degraded || len(response.DegradedReasons) != 1 {
+		t.Errorf("Expected degraded response with one reason, got %+v", response)
+	}
+	if responseRecorder.Header().Get(WarningHeader) == "" {
+		t.Error("Expected a Warning header on degraded response")
+	}
+}
+
+// TestAnalyzePlayer_SoftDeadlineUpstreamError tests that upstream errors within the deadline still fail the request
+func TestAnalyzePlayer_SoftDeadlineUpstreamError(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(0, apierrors.DataServiceError("Data service error"), &analyzedMatches)
+	handler := NewHandler(mockProxy, WithAnalyzeMatchDeadline(time.Second))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestAnalyzePlayer_NoSoftDeadline tests that a slow match fetch failure is returned when no deadline is set
+func TestAnalyzePlayer_NoSoftDeadline(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(20*time.Millisecond, apierrors.DataServiceError("Unable to connect to data service"), &analyzedMatches)
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestAnalyzePlayer_DryRun tests that a dry run returns the summoner without fetching matches or analyzing
+func TestAnalyzePlayer_DryRun(t *testing.T) {
+	cortexPinged := false
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			t.Error("Expected dry run to not fetch matches")
+			return nil, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			t.Error("Expected dry run to not call analyze")
+			return nil, nil
+		},
+		CheckCortexHealthFunc: func() error {
+			cortexPinged = true
+			return nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	request := newAnalyzeRequest()
+	request.URL.RawQuery = "dryRun=true"
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if !cortexPinged {
+		t.Error("Expected cortex health to be checked")
+	}
+
+	var response models.AnalyzeDryRunResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if !response.WouldAnalyze || response.Summoner == nil || response.Summoner.PUUID != "test-puuid" {
+		t.Errorf("Unexpected dry run response: %+v", response)
+	}
+}
+
+// TestAnalyzePlayer_DryRunCortexUnavailable tests that a dry run fails when cortex is unreachable
+func TestAnalyzePlayer_DryRunCortexUnavailable(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		CheckCortexHealthFunc: func() error {
+			return apierrors.CortexServiceError("Unable to connect to analysis service")
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	request := newAnalyzeRequest()
+	request.URL.RawQuery = "dryRun=true"
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestAnalyzePlayer_Options tests that analyze options select the match count and reject invalid values
+func TestAnalyzePlayer_Options(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"default mode", ``, http.StatusOK, 20},
+		{"quick mode", `,"mode":"quick"`, http.StatusOK, 5},
+		{"explicit count", `,"mode":"quick","count":8`, http.StatusOK, 8},
+		{"invalid mode", `,"mode":"turbo"`, http.StatusBadRequest, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requestedCount := 0
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					return &models.Summoner{PUUID: "test-puuid"}, nil
+				},
+				GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+					requestedCount = count
+					return []models.Match{}, nil
+				},
+				AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+					return &models.AnalysisResult{}, nil
+				},
+			}
+			handler := NewHandler(mockProxy)
+
+			requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"` + testCase.options + `}`
+			request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(requestBody))
+			responseRecorder := httptest.NewRecorder()
+			handler.AnalyzePlayer(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if requestedCount != testCase.expectedCount {
+				t.Errorf("Expected %d matches requested, got %d", testCase.expectedCount, requestedCount)
+			}
+		})
+	}
+}
+
+// TestAnalyzePlayer_PartialDisabled tests that partial=false fails instead of degrading on a slow match fetch
+func TestAnalyzePlayer_PartialDisabled(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(30*time.Millisecond, apierrors.DataServiceError("Unable to connect to data service"), &analyzedMatches)
+	handler := NewHandler(mockProxy, WithAnalyzeMatchDeadline(10*time.Millisecond))
+
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","partial":false}`
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestAnalyzePlayer_Locale tests that Accept-Language is negotiated and forwarded to cortex
+func TestAnalyzePlayer_Locale(t *testing.T) {
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		expectedLocale string
+	}{
+		{name: "supported locale", acceptLanguage: "ko-KR,ko;q=0.9,en;q=0.8", expectedLocale: "ko"},
+		{name: "unknown locale falls back", acceptLanguage: "xx-YY", expectedLocale: "en"},
+		{name: "missing header falls back", acceptLanguage: "", expectedLocale: "en"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var forwardedLocale string
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					return &models.Summoner{PUUID: "test-puuid"}, nil
+				},
+				GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+					return []models.Match{{MatchID: "NA1_1"}}, nil
+				},
+				AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+					forwardedLocale = locale
+					return &models.AnalysisResult{}, nil
+				},
+			}
+			handler := NewHandler(mockProxy)
+
+			request := newAnalyzeRequest()
+			if testCase.acceptLanguage != "" {
+				request.Header.Set("Accept-Language", testCase.acceptLanguage)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler.AnalyzePlayer(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+			}
+			if forwardedLocale != testCase.expectedLocale {
+				t.Errorf("Expected locale '%s' forwarded to cortex, got '%s'", testCase.expectedLocale, forwardedLocale)
+			}
+		})
+	}
+}
+
+// newCoalescingProxy returns a mock whose cortex analysis counts calls and, when release is
+// non-nil, blocks until release is closed
+func newCoalescingProxy(cortexCalls *atomic.Int32, release chan struct{}) *MockServiceProxy {
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{{MatchID: "NA1_1"}}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			cortexCalls.Add(1)
+			if release != nil {
+				<-release
+			}
+			return &models.AnalysisResult{PlayerStats: map[string]interface{}{"winRate": 0.5}}, nil
+		},
+	}
+}
+
+// TestAnalyzePlayer_CoalescesConcurrentRequests tests that concurrent identical analyze requests share one cortex call
+func TestAnalyzePlayer_CoalescesConcurrentRequests(t *testing.T) {
+	var cortexCalls atomic.Int32
+	release := make(chan struct{})
+	handler := NewHandler(newCoalescingProxy(&cortexCalls, release), WithAnalyzeCoalescing(time.Minute))
+
+	const concurrentRequests = 5
+	statusCodes := make([]int, concurrentRequests)
+	var waitGroup sync.WaitGroup
+	for index := 0; index < concurrentRequests; index++ {
+		waitGroup.Add(1)
+		go func(index int) {
+			defer waitGroup.Done()
+			responseRecorder := httptest.NewRecorder()
+			serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+			statusCodes[index] = responseRecorder.Code
+		}(index)
+	}
+
+	// Give every request time to join the in-flight analysis before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+
+	if cortexCalls.Load() != 1 {
+		t.Errorf("Expected 1 cortex call, got %d", cortexCalls.Load())
+	}
+	for index, statusCode := range statusCodes {
+		if statusCode != http.StatusOK {
+			t.Errorf("Request %d: expected status code %d, got %d", index, http.StatusOK, statusCode)
+		}
+	}
+}
+
+// TestAnalyzePlayer_CoalescingReusesRecentResult tests that a completed result is reused within the window
+func TestAnalyzePlayer_CoalescingReusesRecentResult(t *testing.T) {
+	var cortexCalls atomic.Int32
+	handler := NewHandler(newCoalescingProxy(&cortexCalls, nil), WithAnalyzeCoalescing(time.Minute))
+
+	for index := 0; index < 3; index++ {
+		responseRecorder := httptest.NewRecorder()
+		serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+		if responseRecorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+		}
+	}
+
+	if cortexCalls.Load() != 1 {
+		t.Errorf("Expected 1 cortex call, got %d", cortexCalls.Load())
+	}
+}
+
+// TestAnalyzePlayer_CoalescingDistinctOptions tests that different modes and counts are analyzed separately
+func TestAnalyzePlayer_CoalescingDistinctOptions(t *testing.T) {
+	var cortexCalls atomic.Int32
+	handler := NewHandler(newCoalescingProxy(&cortexCalls, nil), WithAnalyzeCoalescing(time.Minute))
+
+	for _, requestBody := range []string{
+		`{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`,
+		`{"region":"na","gameName":"TestPlayer","tagLine":"NA1","mode":"quick"}`,
+		`{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":10}`,
+	} {
+		request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(requestBody))
+		responseRecorder := httptest.NewRecorder()
+		handler.AnalyzePlayer(responseRecorder, request)
+		if responseRecorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+		}
+	}
+
+	if cortexCalls.Load() != 3 {
+		t.Errorf("Expected 3 cortex calls, got %d", cortexCalls.Load())
+	}
+}
+
+// TestAnalyzePlayer_CoalescingDisabled tests that every request reaches cortex without coalescing
+func TestAnalyzePlayer_CoalescingDisabled(t *testing.T) {
+	var cortexCalls atomic.Int32
+	handler := NewHandler(newCoalescingProxy(&cortexCalls, nil))
+
+	for index := 0; index < 2; index++ {
+		handler.AnalyzePlayer(httptest.NewRecorder(), newAnalyzeRequest())
+	}
+
+	if cortexCalls.Load() != 2 {
+		t.Errorf("Expected 2 cortex calls, got %d", cortexCalls.Load())
+	}
+}
+
+// TestRefreshAnalysis_BypassesAndRepopulatesCache tests that refresh re-runs a cached analysis and caches the fresh result
+func TestRefreshAnalysis_BypassesAndRepopulatesCache(t *testing.T) {
+	var summonerCalls atomic.Int32
+	var cortexCalls atomic.Int32
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			summonerCalls.Add(1)
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{{MatchID: "NA1_1"}}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			run := cortexCalls.Add(1)
+			return &models.AnalysisResult{PlayerStats: map[string]interface{}{"run": float64(run)}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy,
+		WithAnalyzeCoalescing(time.Minute),
+		WithSummonerCache(cache.NewMemoryCache(time.Minute, 0)),
+	)
+
+	analyzeRun := func(handle http.HandlerFunc) float64 {
+		t.Helper()
+		responseRecorder := httptest.NewRecorder()
+		handle(responseRecorder, newAnalyzeRequest())
+		if responseRecorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+		}
+		var response models.AnalysisResult
+		json.NewDecoder(responseRecorder.Body).Decode(&response)
+		return response.PlayerStats.(map[string]interface{})["run"].(float64)
+	}
+
+	if run := analyzeRun(handler.AnalyzePlayer); run != 1 {
+		t.Errorf("Expected first analysis run 1, got %g", run)
+	}
+	if run := analyzeRun(handler.RefreshAnalysis); run != 2 {
+		t.Errorf("Expected refresh to re-run analysis (run 2), got %g", run)
+	}
+	if run := analyzeRun(handler.AnalyzePlayer); run != 2 {
+		t.Errorf("Expected cached refreshed result (run 2), got %g", run)
+	}
+
+	if cortexCalls.Load() != 2 {
+		t.Errorf("Expected 2 cortex calls, got %d", cortexCalls.Load())
+	}
+	if summonerCalls.Load() != 2 {
+		t.Errorf("Expected refresh to bypass the summoner cache (2 lookups), got %d", summonerCalls.Load())
+	}
+}
+
+// TestAnalyzePlayer_CountLimit tests the analyze-specific count limit and the capping of mode defaults
+func TestAnalyzePlayer_CountLimit(t *testing.T) {
+	var requestedCount int
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			requestedCount = count
+			return []models.Match{}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			return &models.AnalysisResult{}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithAnalyzeCountLimit(10))
+
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":30}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	if !strings.Contains(errorResponse.Error.Message, "count cannot exceed 10 for analyze") {
+		t.Errorf("Expected analyze count limit in error, got '%s'", errorResponse.Error.Message)
+	}
+
+	// The standard mode's default of 20 matches is capped to the limit
+	responseRecorder = httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if requestedCount != 10 {
+		t.Errorf("Expected 10 matches requested, got %d", requestedCount)
+	}
+}
+
+// TestAnalyzePlayer_MatchCount tests the configured analyze match count, its clamping to the
+// analyze count limit, and that explicit counts and other modes take precedence
+func TestAnalyzePlayer_MatchCount(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []HandlerOption
+		requestBody   string
+		expectedCount int
+	}{
+		{"default", nil, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`, 20},
+		{"configured", []HandlerOption{WithAnalyzeMatchCount(30)}, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`, 30},
+		{"explicit standard mode", []HandlerOption{WithAnalyzeMatchCount(30)}, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","mode":"standard"}`, 30},
+		{"clamped to limit", []HandlerOption{WithAnalyzeMatchCount(80)}, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`, validation.DefaultMaxAnalyzeCount},
+		{"explicit count", []HandlerOption{WithAnalyzeMatchCount(30)}, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":15}`, 15},
+		{"other mode", []HandlerOption{WithAnalyzeMatchCount(30)}, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","mode":"quick"}`, 5},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var requestedCount int
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					return &models.Summoner{PUUID: "test-puuid"}, nil
+				},
+				GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+					requestedCount = count
+					return []models.Match{}, nil
+				},
+				AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+					return &models.AnalysisResult{}, nil
+				},
+			}
+			handler := NewHandler(mockProxy, testCase.options...)
+
+			request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(testCase.requestBody))
+			responseRecorder := httptest.NewRecorder()
+			serveWithWarnings(handler.AnalyzePlayer, responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+			}
+			if requestedCount != testCase.expectedCount {
+				t.Errorf("Expected %d matches requested, got %d", testCase.expectedCount, requestedCount)
+			}
+		})
+	}
+}
+
+// TestAnalyzePlayer_InsufficientMatches tests that a player below the minimum match count gets
+// 422 INSUFFICIENT_DATA without a cortex call
+func TestAnalyzePlayer_InsufficientMatches(t *testing.T) {
+	cortexCalls := 0
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{{MatchID: "NA1_1"}, {MatchID: "NA1_2"}}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			cortexCalls++
+			return &models.AnalysisResult{}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithAnalyzeMinMatches(5))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, newAnalyzeRequest())
+
+	if responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status code %d, got %d", http.StatusUnprocessableEntity, responseRecorder.Code)
+	}
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != apierrors.ErrCodeInsufficientData {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeInsufficientData, errorResponse.Error.Code)
+	}
+	if cortexCalls != 0 {
+		t.Errorf("Expected no cortex call, got %d", cortexCalls)
+	}
+
+	// The minimum is capped at the requested count, so count=2 is analyzed
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":2}`))
+	responseRecorder = httptest.NewRecorder()
+	serveWithWarnings(handler.AnalyzePlayer, responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if cortexCalls != 1 {
+		t.Errorf("Expected one cortex call, got %d", cortexCalls)
+	}
+}
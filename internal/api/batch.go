@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+const (
+	// defaultMaxBatchSize caps how many players a single batch request may contain
+	defaultMaxBatchSize = 10
+
+	// defaultBatchTimeout bounds the total time spent fetching a batch
+	defaultBatchTimeout = 10 * time.Second
+
+	// defaultBatchConcurrency caps how many of a batch's upstream fetches run at once
+	defaultBatchConcurrency = 5
+)
+
+// WithBatchLimits overrides the maximum batch size and overall batch timeout; non-positive
+// values keep the defaults
+func WithBatchLimits(maxBatchSize int, batchTimeout time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		if maxBatchSize > 0 {
+			handler.maxBatchSize = maxBatchSize
+		}
+		if batchTimeout > 0 {
+			handler.batchTimeout = batchTimeout
+		}
+	}
+}
+
+// WithBatchConcurrency overrides how many of a batch's players are fetched from opgl-data at
+// once; the rest wait for a free slot
+func WithBatchConcurrency(concurrency int) HandlerOption {
+	return func(handler *Handler) {
+		if concurrency > 0 {
+			handler.batchConcurrency = concurrency
+		}
+	}
+}
+
+// GetMatchesBatch fetches match history for multiple players concurrently by PUUID.
+// Results are returned in request order; per-player failures are reported in the
+// corresponding result without failing the batch.
+func (handler *Handler) GetMatchesBatch(writer http.ResponseWriter, request *http.Request) {
+	var batchRequest validation.BatchMatchRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&batchRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	for index := range batchRequest.Players {
+		batchRequest.Players[index].Region = handler.resolveRegion(batchRequest.Players[index].Region)
+	}
+
+	// Validate request, including the batch size cap
+	validationResult := validation.ValidateBatchMatchRequest(&batchRequest, handler.maxBatchSize)
+	if validationResult.IsValid() {
+		for index, player := range batchRequest.Players {
+			handler.requireCount(player.Count, fmt.Sprintf("players[%d].count", index), validationResult)
+		}
+	}
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	// Bound the whole batch by a single overall timeout
+	batchContext, cancelBatch := context.WithTimeout(request.Context(), handler.batchTimeout)
+	defer cancelBatch()
+
+	// Each goroutine writes only its own slot, so results stay index-aligned with the
+	// request regardless of completion order
+	results := make([]models.BatchMatchResult, len(batchRequest.Players))
+	slots := make(chan struct{}, handler.batchConcurrency)
+	var waitGroup sync.WaitGroup
+
+	for index, item := range batchRequest.Players {
+		waitGroup.Add(1)
+		go func(index int, item validation.BatchMatchItem) {
+			defer waitGroup.Done()
+
+			// Players still queued when the batch times out are reported without a fetch
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-batchContext.Done():
+				results[index] = models.BatchMatchResult{
+					Region: validation.NormalizeRegion(item.Region),
+					PUUID:  item.PUUID,
+					Error:  newBatchError(apierrors.DataServiceError("Batch timed out before this player was fetched")),
+				}
+				return
+			}
+
+			results[index] = handler.fetchBatchMatchItem(batchContext, item)
+		}(index, item)
+	}
+
+	waitGroup.Wait()
+
+	handler.writeResponse(writer, request, http.StatusOK, models.BatchMatchResponse{Results: results})
+}
+
+// fetchBatchMatchItem fetches matches for a single batch entry, converting errors into a result
+func (handler *Handler) fetchBatchMatchItem(ctx context.Context, item validation.BatchMatchItem) models.BatchMatchResult {
+	normalizedRegion := validation.NormalizeRegion(item.Region)
+	result := models.BatchMatchResult{
+		Region: normalizedRegion,
+		PUUID:  item.PUUID,
+	}
+
+	if !handler.isRegionServed(normalizedRegion) {
+		result.Error = newBatchError(apierrors.RegionNotServed(normalizedRegion))
+		return result
+	}
+
+	count, _ := item.Count.Int()
+	if count <= 0 {
+		count = 20
+	}
+
+	matches, err := handler.serviceProxy.GetMatchesByPUUID(ctx, normalizedRegion, item.PUUID, count, models.MatchFilter{})
+	if err != nil {
+		result.Error = newBatchError(err)
+		return result
+	}
+
+	result.Matches = matches
+	return result
+}
+
+// newBatchError converts a proxy error into a per-item batch error
+func newBatchError(err error) *models.BatchError {
+	if apiErr, ok := err.(*apierrors.APIError); ok {
+		return &models.BatchError{Code: string(apiErr.Code), Message: apiErr.ClientMessage()}
+	}
+	return &models.BatchError{Code: string(apierrors.ErrCodeInternalError), Message: "An unexpected error occurred"}
+}
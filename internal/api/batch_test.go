@@ -0,0 +1,308 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// testPUUID builds a 78-character PUUID from a single repeated character
+func testPUUID(character string) string {
+	return strings.Repeat(character, 78)
+}
+
+// newBatchRequest builds a batch match request body for the given PUUIDs
+func newBatchRequest(puuids ...string) *http.Request {
+	players := make([]map[string]interface{}, len(puuids))
+	for index, puuid := range puuids {
+		players[index] = map[string]interface{}{"region": "na", "puuid": puuid, "count": 5}
+	}
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"players": players})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", bytes.NewBuffer(bodyBytes))
+	return request
+}
+
+// TestGetMatchesBatch_PartialFailure tests that one failing player does not fail the batch
+func TestGetMatchesBatch_PartialFailure(t *testing.T) {
+	failingPUUID := testPUUID("b")
+
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			if puuid == failingPUUID {
+				return nil, apierrors.MatchesNotFound("No matches found for this player")
+			}
+			return []models.Match{{MatchID: "NA1_" + puuid[:1]}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(testPUUID("a"), failingPUUID, testPUUID("c")))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response models.BatchMatchResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	if response.Results[0].Error != nil || len(response.Results[0].Matches) != 1 {
+		t.Errorf("Expected first player to succeed, got %+v", response.Results[0])
+	}
+
+	if response.Results[1].Error == nil || response.Results[1].Error.Code != string(apierrors.ErrCodeMatchesNotFound) {
+		t.Errorf("Expected second player to carry MATCHES_NOT_FOUND error, got %+v", response.Results[1])
+	}
+
+	if response.Results[2].Error != nil {
+		t.Errorf("Expected third player to succeed, got %+v", response.Results[2])
+	}
+}
+
+// TestGetMatchesBatch_ExceedsMaxSize tests that batches larger than the cap are rejected
+func TestGetMatchesBatch_ExceedsMaxSize(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			t.Error("Expected oversized batch to not reach the proxy")
+			return nil, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithBatchLimits(2, time.Second))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(testPUUID("a"), testPUUID("b"), testPUUID("c")))
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestGetMatchesBatch_InvalidItem tests that per-item validation rejects the batch
+func TestGetMatchesBatch_InvalidItem(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(testPUUID("a"), "short-puuid"))
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+
+	if !strings.Contains(responseRecorder.Body.String(), "players[1].puuid") {
+		t.Errorf("Expected indexed validation error, got '%s'", responseRecorder.Body.String())
+	}
+}
+
+// TestGetMatchesBatch_InvalidJSON tests invalid JSON request body
+func TestGetMatchesBatch_InvalidJSON(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", bytes.NewBufferString("invalid"))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestGetMatchesBatch_UnexpectedError tests that non-API errors are reported as internal errors per item
+func TestGetMatchesBatch_UnexpectedError(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return nil, fmt.Errorf("boom")
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(testPUUID("a")))
+
+	var response models.BatchMatchResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+
+	if len(response.Results) != 1 || response.Results[0].Error == nil {
+		t.Fatalf("Expected a single failed result, got %+v", response.Results)
+	}
+
+	if response.Results[0].Error.Code != string(apierrors.ErrCodeInternalError) {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeInternalError, response.Results[0].Error.Code)
+	}
+}
+
+// TestGetMatchesBatch_PreservesInputOrder tests that results follow request order even when
+// items complete in a different order
+func TestGetMatchesBatch_PreservesInputOrder(t *testing.T) {
+	characters := []string{"a", "b", "c", "d", "e"}
+	failingPUUID := testPUUID("c")
+
+	// Earlier players respond slower, so completion order is the reverse of request order
+	latencies := make(map[string]time.Duration)
+	for index, character := range characters {
+		latencies[testPUUID(character)] = time.Duration(len(characters)-index) * 15 * time.Millisecond
+	}
+
+	var completionMutex sync.Mutex
+	var completionOrder []string
+
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			time.Sleep(latencies[puuid])
+
+			completionMutex.Lock()
+			completionOrder = append(completionOrder, puuid[:1])
+			completionMutex.Unlock()
+
+			if puuid == failingPUUID {
+				return nil, apierrors.MatchesNotFound("No matches found for this player")
+			}
+			return []models.Match{{MatchID: "NA1_" + puuid[:1]}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	puuids := make([]string, len(characters))
+	for index, character := range characters {
+		puuids[index] = testPUUID(character)
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(puuids...))
+
+	var response models.BatchMatchResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if completionOrder[0] == characters[0] {
+		t.Fatalf("Expected items to complete out of request order, got %v", completionOrder)
+	}
+
+	if len(response.Results) != len(puuids) {
+		t.Fatalf("Expected %d results, got %d", len(puuids), len(response.Results))
+	}
+
+	for index, result := range response.Results {
+		if result.PUUID != puuids[index] {
+			t.Errorf("Result %d: expected PUUID for '%s', got '%s'", index, characters[index], result.PUUID[:1])
+		}
+
+		if puuids[index] == failingPUUID {
+			if result.Error == nil || len(result.Matches) != 0 {
+				t.Errorf("Result %d: expected an error slot, got %+v", index, result)
+			}
+			continue
+		}
+
+		if result.Error != nil || len(result.Matches) != 1 || result.Matches[0].MatchID != "NA1_"+characters[index] {
+			t.Errorf("Result %d: expected matches for '%s', got %+v", index, characters[index], result)
+		}
+	}
+}
+
+// TestGetMatchesBatch_ConcurrencyLimit tests that no more than the configured number of
+// upstream fetches are in flight at once
+func TestGetMatchesBatch_ConcurrencyLimit(t *testing.T) {
+	const concurrency = 3
+
+	var mutex sync.Mutex
+	inFlight, maxInFlight, calls := 0, 0, 0
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			mutex.Lock()
+			inFlight++
+			calls++
+			maxInFlight = max(maxInFlight, inFlight)
+			mutex.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mutex.Lock()
+			inFlight--
+			mutex.Unlock()
+			return []models.Match{{MatchID: "NA1_" + puuid[:1]}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithBatchConcurrency(concurrency))
+
+	characters := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	puuids := make([]string, len(characters))
+	for index, character := range characters {
+		puuids[index] = testPUUID(character)
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(puuids...))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if calls != len(puuids) {
+		t.Errorf("Expected %d upstream calls, got %d", len(puuids), calls)
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("Expected at most %d calls in flight, got %d", concurrency, maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("Expected calls to still run concurrently, got a maximum of %d in flight", maxInFlight)
+	}
+}
+
+// TestGetMatchesBatch_QueuedTimeout tests that players still waiting for a slot when the
+// batch times out are reported as failed without an upstream call
+func TestGetMatchesBatch_QueuedTimeout(t *testing.T) {
+	var mutex sync.Mutex
+	calls := 0
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			mutex.Lock()
+			calls++
+			mutex.Unlock()
+
+			time.Sleep(50 * time.Millisecond)
+			return []models.Match{{MatchID: "NA1_" + puuid[:1]}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithBatchLimits(defaultMaxBatchSize, 20*time.Millisecond), WithBatchConcurrency(1))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchesBatch(responseRecorder, newBatchRequest(testPUUID("a"), testPUUID("b"), testPUUID("c")))
+
+	var response models.BatchMatchResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected only the first player to be fetched, got %d upstream calls", calls)
+	}
+
+	failed := 0
+	for _, result := range response.Results {
+		if result.Error != nil {
+			failed++
+			if result.Error.Code != string(apierrors.ErrCodeDataServiceError) {
+				t.Errorf("Expected code %s, got %s", apierrors.ErrCodeDataServiceError, result.Error.Code)
+			}
+		}
+	}
+	if failed != 2 {
+		t.Errorf("Expected 2 queued players to time out, got %d failures", failed)
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+	"github.com/gorilla/mux"
+)
+
+// batchRoutePath is the route whose rate-limit cost is its number of players
+const batchRoutePath = "/matches/batch"
+
+const (
+	// batchPlayerBodyBytes is the body allowance per player; an item ({"region","puuid","count"})
+	// is about 120 bytes, leaving room for formatting whitespace
+	batchPlayerBodyBytes = 512
+
+	// batchBodyOverheadBytes is the body allowance for the enclosing object
+	batchBodyOverheadBytes = 1 << 10
+)
+
+// maxBatchBodyBytes returns the largest batch body accepted, sized to the maximum batch size
+func (handler *Handler) maxBatchBodyBytes() int64 {
+	return int64(batchBodyOverheadBytes + handler.maxBatchSize*batchPlayerBodyBytes)
+}
+
+// batchCostMiddleware charges a batch request one request of quota per player, so the rate
+// limiter reserves the whole batch up front and rejects it with 429 before any upstream
+// call when too little quota remains. It runs before rate limiting and restores the body
+// it reads, which is limited to what the maximum batch size needs; larger bodies are
+// rejected with 413. Bodies that do not decode, or batches over the size limit, cost one
+// request and are rejected by the handler.
+func (handler *Handler) batchCostMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		route := mux.CurrentRoute(request)
+		if route == nil || request.Body == nil {
+			next.ServeHTTP(writer, request)
+			return
+		}
+		if template, err := route.GetPathTemplate(); err != nil || !strings.HasSuffix(template, batchRoutePath) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, handler.maxBatchBodyBytes()))
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			handler.writeError(writer, request, apierrors.RequestBodyTooLarge(fmt.Sprintf("Batch request body must not exceed %d bytes", maxBytesError.Limit)))
+			return
+		}
+
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		var batchRequest validation.BatchMatchRequest
+		if err := json.Unmarshal(body, &batchRequest); err == nil && len(batchRequest.Players) <= handler.maxBatchSize {
+			request = request.WithContext(middleware.WithRateLimitCost(request.Context(), len(batchRequest.Players)))
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
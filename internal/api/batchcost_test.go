@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newQuotaAuthServer returns an auth service stub with remaining quota that reserves a
+// request's whole cost or denies it without consuming any, recording the costs it was asked for
+func newQuotaAuthServer(remaining int, requestedCosts *[]int, mutex *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var checkRequest struct {
+			Cost int `json:"cost"`
+		}
+		json.NewDecoder(request.Body).Decode(&checkRequest)
+
+		cost := max(checkRequest.Cost, 1)
+		mutex.Lock()
+		*requestedCosts = append(*requestedCosts, checkRequest.Cost)
+		allowed := cost <= remaining
+		if allowed {
+			remaining -= cost
+		}
+		left := remaining
+		mutex.Unlock()
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(map[string]interface{}{
+			"allowed":   allowed,
+			"limit":     100,
+			"remaining": left,
+		})
+	}))
+}
+
+// newBatchBody builds a batch request for the given number of players
+func newBatchBody(players int) string {
+	items := make([]string, players)
+	for index := range items {
+		puuid := fmt.Sprintf("%d%s", index, strings.Repeat("a", 77))
+		items[index] = fmt.Sprintf(`{"region":"na","puuid":"%s"}`, puuid)
+	}
+	return `{"players":[` + strings.Join(items, ",") + `]}`
+}
+
+// TestBatchCost_RejectsBatchOverQuota tests that a batch larger than the remaining quota is rejected before any upstream call
+func TestBatchCost_RejectsBatchOverQuota(t *testing.T) {
+	var requestedCosts []int
+	var mutex sync.Mutex
+	authServer := newQuotaAuthServer(5, &requestedCosts, &mutex)
+	defer authServer.Close()
+
+	var upstreamCalls int
+	var upstreamMutex sync.Mutex
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			upstreamMutex.Lock()
+			upstreamCalls++
+			upstreamMutex.Unlock()
+			return []models.Match{}, nil
+		},
+	}
+	router := SetupRouter(&RouterConfig{
+		Handler:         NewHandler(mockProxy),
+		RateLimitClient: middleware.NewRateLimitServiceClient(authServer.URL),
+	})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", bytes.NewBufferString(newBatchBody(8)))
+	request.Header.Set(middleware.DefaultAPIKeyHeader, "test-key")
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status code %d, got %d", http.StatusTooManyRequests, responseRecorder.Code)
+	}
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != apierrors.ErrCodeRateLimitExceeded {
+		t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeRateLimitExceeded, errorResponse.Error.Code)
+	}
+	if upstreamCalls != 0 {
+		t.Errorf("Expected no upstream calls, got %d", upstreamCalls)
+	}
+	if len(requestedCosts) != 1 || requestedCosts[0] != 8 {
+		t.Errorf("Expected one check reserving 8, got %v", requestedCosts)
+	}
+	if responseRecorder.Header().Get(middleware.RateLimitCostHeader) != "8" {
+		t.Errorf("Expected %s '8', got '%s'", middleware.RateLimitCostHeader, responseRecorder.Header().Get(middleware.RateLimitCostHeader))
+	}
+}
+
+// TestBatchCost_ReservesBatchWithinQuota tests that a batch within quota reserves its size and is served in full
+func TestBatchCost_ReservesBatchWithinQuota(t *testing.T) {
+	var requestedCosts []int
+	var mutex sync.Mutex
+	authServer := newQuotaAuthServer(5, &requestedCosts, &mutex)
+	defer authServer.Close()
+
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+	}
+	router := SetupRouter(&RouterConfig{
+		Handler:         NewHandler(mockProxy),
+		RateLimitClient: middleware.NewRateLimitServiceClient(authServer.URL),
+	})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", bytes.NewBufferString(newBatchBody(3)))
+	request.Header.Set(middleware.DefaultAPIKeyHeader, "test-key")
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if responseRecorder.Header().Get("X-RateLimit-Remaining") != "2" {
+		t.Errorf("Expected 2 requests remaining, got '%s'", responseRecorder.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	// Non-batch routes still cost a single request, sent without a cost
+	request, _ = http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`))
+	request.Header.Set(middleware.DefaultAPIKeyHeader, "test-key")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	if len(requestedCosts) != 2 || requestedCosts[0] != 3 || requestedCosts[1] != 0 {
+		t.Errorf("Expected costs [3 0], got %v", requestedCosts)
+	}
+}
+
+// TestBatchCost_RejectsOversizedBody tests that a body larger than the maximum batch could
+// need is rejected with 413 before it is buffered or checked against the rate limit
+func TestBatchCost_RejectsOversizedBody(t *testing.T) {
+	var requestedCosts []int
+	var mutex sync.Mutex
+	authServer := newQuotaAuthServer(100, &requestedCosts, &mutex)
+	defer authServer.Close()
+
+	handler := NewHandler(&MockServiceProxy{})
+	router := SetupRouter(&RouterConfig{
+		Handler:         handler,
+		RateLimitClient: middleware.NewRateLimitServiceClient(authServer.URL),
+	})
+
+	requestBody := `{"players":[],"padding":"` + strings.Repeat("a", int(handler.maxBatchBodyBytes())) + `"}`
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", strings.NewReader(requestBody))
+	request.Header.Set(middleware.DefaultAPIKeyHeader, "test-key")
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, responseRecorder.Code)
+	}
+	if len(requestedCosts) != 0 {
+		t.Errorf("Expected no rate-limit checks, got %v", requestedCosts)
+	}
+}
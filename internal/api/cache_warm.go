@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+const (
+	// defaultMaxCacheWarmSize caps how many players a single warm request may list
+	defaultMaxCacheWarmSize = 500
+	// defaultCacheWarmConcurrency caps concurrent opgl-data lookups while warming
+	defaultCacheWarmConcurrency = 4
+)
+
+// WithCacheWarmLimits overrides the maximum players per warm request and the number of
+// concurrent upstream lookups used while warming
+func WithCacheWarmLimits(maxSize int, concurrency int) HandlerOption {
+	return func(handler *Handler) {
+		if maxSize > 0 {
+			handler.maxCacheWarmSize = maxSize
+		}
+		if concurrency > 0 {
+			handler.cacheWarmConcurrency = concurrency
+		}
+	}
+}
+
+// WarmCache pre-populates the summoner cache for a list of players, fetching each from
+// opgl-data with bounded concurrency. Entries are refreshed even if already cached.
+func (handler *Handler) WarmCache(writer http.ResponseWriter, request *http.Request) {
+	if handler.summonerCache == nil {
+		handler.writeError(writer, request, apierrors.ServiceUnavailable("Summoner cache is disabled"))
+		return
+	}
+
+	var warmRequest validation.CacheWarmRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&warmRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	for index := range warmRequest.Players {
+		warmRequest.Players[index].Region = handler.resolveRegion(warmRequest.Players[index].Region)
+	}
+
+	validationResult := validation.ValidateCacheWarmRequest(&warmRequest, handler.maxCacheWarmSize)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	// A fixed pool of workers takes players in request order. Players not yet warmed when
+	// the request is canceled are skipped and reported with the context's error. Results
+	// are index-aligned so failures are reported in request order.
+	ctx := request.Context()
+	warmErrors := make([]error, len(warmRequest.Players))
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < min(handler.cacheWarmConcurrency, len(warmRequest.Players)); worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			for index := range indexes {
+				if err := ctx.Err(); err != nil {
+					warmErrors[index] = err
+					continue
+				}
+				warmErrors[index] = handler.warmSummoner(ctx, warmRequest.OrgID, warmRequest.Players[index])
+			}
+		}()
+	}
+
+	for index := range warmRequest.Players {
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			warmErrors[index] = ctx.Err()
+		}
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	var response models.CacheWarmResponse
+	for index, err := range warmErrors {
+		if err == nil {
+			response.Succeeded++
+			continue
+		}
+
+		player := warmRequest.Players[index]
+		response.Failed++
+		response.Failures = append(response.Failures, models.CacheWarmFailure{
+			Region:   validation.NormalizeRegion(player.Region),
+			GameName: player.GameName,
+			TagLine:  player.TagLine,
+			Error:    newBatchError(err),
+		})
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, response)
+}
+
+// warmSummoner fetches a single summoner from opgl-data and stores it in the summoner cache
+func (handler *Handler) warmSummoner(ctx context.Context, orgID string, player validation.SummonerRequest) error {
+	normalizedRegion := validation.NormalizeRegion(player.Region)
+
+	summoner, err := handler.fetchSummoner(ctx, normalizedRegion, player.GameName, player.TagLine)
+	if err != nil {
+		return err
+	}
+
+	handler.summonerCache.Set(summonerCacheKey(orgID, normalizedRegion, player.GameName, player.TagLine), summoner)
+	return nil
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+const testWarmOrgID = "123e4567-e89b-12d3-a456-426614174000"
+
+// newCacheWarmRequest builds a POST /admin/cache/warm request with the given JSON body
+func newCacheWarmRequest(body string) *http.Request {
+	request, _ := http.NewRequest("POST", "/admin/cache/warm", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+// TestWarmCache_PopulatesCache tests that warmed summoners are served from the cache afterwards
+func TestWarmCache_PopulatesCache(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: gameName + "-puuid", Name: gameName}, nil
+		},
+	}
+
+	summonerCache := cache.NewMemoryCache(time.Minute, time.Hour)
+	handler := NewHandler(mockProxy, WithSummonerCache(summonerCache))
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[` +
+		`{"region":"NA","gameName":"PlayerOne","tagLine":"NA1"},` +
+		`{"region":"euw","gameName":"PlayerTwo","tagLine":"EUW"}]}`
+	responseRecorder := httptest.NewRecorder()
+	handler.WarmCache(responseRecorder, newCacheWarmRequest(body))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response models.CacheWarmResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if response.Succeeded != 2 || response.Failed != 0 {
+		t.Errorf("Expected 2 succeeded and 0 failed, got %d and %d", response.Succeeded, response.Failed)
+	}
+
+	for _, key := range []string{
+		summonerCacheKey(testWarmOrgID, "na", "PlayerOne", "NA1"),
+		summonerCacheKey(testWarmOrgID, "euw", "PlayerTwo", "EUW"),
+	} {
+		if _, found := summonerCache.Get(key); !found {
+			t.Errorf("Expected cache entry for key '%s'", key)
+		}
+	}
+
+	// A subsequent lookup for the same org must not reach opgl-data
+	mockProxy.GetSummonerByRiotIDFunc = func(region, gameName, tagLine string) (*models.Summoner, error) {
+		t.Error("Expected warmed summoner to be served from cache")
+		return nil, apierrors.DataServiceError("unexpected call")
+	}
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner",
+		bytes.NewBufferString(`{"region":"na","gameName":"PlayerOne","tagLine":"NA1"}`))
+	request.Header.Set(middleware.OrgIDHeader, testWarmOrgID)
+	responseRecorder = httptest.NewRecorder()
+	middleware.OrgMiddleware(false)(http.HandlerFunc(handler.GetSummoner)).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected cached lookup to succeed with %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestWarmCache_ReportsFailures tests that failed lookups are counted and described in request order
+func TestWarmCache_ReportsFailures(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			if gameName == "missing" {
+				return nil, apierrors.PlayerNotFound(gameName, tagLine)
+			}
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+
+	summonerCache := cache.NewMemoryCache(time.Minute, time.Hour)
+	handler := NewHandler(mockProxy, WithSummonerCache(summonerCache))
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[` +
+		`{"region":"na","gameName":"Missing","tagLine":"NA1"},` +
+		`{"region":"na","gameName":"Found","tagLine":"NA1"}]}`
+	responseRecorder := httptest.NewRecorder()
+	handler.WarmCache(responseRecorder, newCacheWarmRequest(body))
+
+	var response models.CacheWarmResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+
+	if response.Succeeded != 1 || response.Failed != 1 {
+		t.Fatalf("Expected 1 succeeded and 1 failed, got %d and %d", response.Succeeded, response.Failed)
+	}
+	if len(response.Failures) != 1 || response.Failures[0].GameName != "Missing" {
+		t.Fatalf("Expected failure for 'Missing', got %+v", response.Failures)
+	}
+	if response.Failures[0].Error.Code != string(apierrors.ErrCodePlayerNotFound) {
+		t.Errorf("Expected error code %s, got %s", apierrors.ErrCodePlayerNotFound, response.Failures[0].Error.Code)
+	}
+	if _, found := summonerCache.Get(summonerCacheKey(testWarmOrgID, "na", "Missing", "NA1")); found {
+		t.Error("Expected failed lookup to not be cached")
+	}
+}
+
+// TestWarmCache_RespectsConcurrencyCap tests that no more than the configured number of lookups run at once
+func TestWarmCache_RespectsConcurrencyCap(t *testing.T) {
+	var inFlight int32
+	var peak int32
+	var peakMutex sync.Mutex
+
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			peakMutex.Lock()
+			if current > peak {
+				peak = current
+			}
+			peakMutex.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy,
+		WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)),
+		WithCacheWarmLimits(0, 2),
+	)
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[` +
+		`{"region":"na","gameName":"PlayerA","tagLine":"NA1"},{"region":"na","gameName":"PlayerB","tagLine":"NA1"},` +
+		`{"region":"na","gameName":"PlayerC","tagLine":"NA1"},{"region":"na","gameName":"PlayerD","tagLine":"NA1"},` +
+		`{"region":"na","gameName":"PlayerE","tagLine":"NA1"},{"region":"na","gameName":"PlayerF","tagLine":"NA1"}]}`
+	responseRecorder := httptest.NewRecorder()
+	handler.WarmCache(responseRecorder, newCacheWarmRequest(body))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent lookups, got %d", peak)
+	}
+}
+
+// TestWarmCache_StopsWhenCanceled tests that players still queued when the warm request is
+// canceled are skipped and reported as failures instead of being looked up
+func TestWarmCache_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lookups atomic.Int32
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			lookups.Add(1)
+			cancel()
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy,
+		WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)),
+		WithCacheWarmLimits(0, 1),
+	)
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[` +
+		`{"region":"na","gameName":"PlayerA","tagLine":"NA1"},{"region":"na","gameName":"PlayerB","tagLine":"NA1"},` +
+		`{"region":"na","gameName":"PlayerC","tagLine":"NA1"}]}`
+	responseRecorder := httptest.NewRecorder()
+	handler.WarmCache(responseRecorder, newCacheWarmRequest(body).WithContext(ctx))
+
+	if lookups.Load() != 1 {
+		t.Errorf("Expected only the first player to be looked up, got %d lookups", lookups.Load())
+	}
+
+	var response models.CacheWarmResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if response.Succeeded != 1 || response.Failed != 2 {
+		t.Errorf("Expected 1 warmed and 2 skipped players, got %d succeeded and %d failed", response.Succeeded, response.Failed)
+	}
+}
+
+// TestWarmCache_ValidationErrors tests that malformed warm requests are rejected
+func TestWarmCache_ValidationErrors(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{},
+		WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)),
+		WithCacheWarmLimits(1, 0),
+	)
+
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{`},
+		{"invalid orgId", `{"orgId":"not-a-uuid","players":[{"region":"na","gameName":"PlayerA","tagLine":"NA1"}]}`},
+		{"empty players", `{"orgId":"` + testWarmOrgID + `","players":[]}`},
+		{"too many players", `{"orgId":"` + testWarmOrgID + `","players":[` +
+			`{"region":"na","gameName":"PlayerA","tagLine":"NA1"},{"region":"na","gameName":"PlayerB","tagLine":"NA1"}]}`},
+		{"invalid player", `{"orgId":"` + testWarmOrgID + `","players":[{"region":"xx","gameName":"PlayerA","tagLine":"NA1"}]}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			responseRecorder := httptest.NewRecorder()
+			handler.WarmCache(responseRecorder, newCacheWarmRequest(testCase.body))
+
+			if responseRecorder.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+// TestWarmCache_CacheDisabled tests that warming is rejected when no summoner cache is configured
+func TestWarmCache_CacheDisabled(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[{"region":"na","gameName":"PlayerA","tagLine":"NA1"}]}`
+	responseRecorder := httptest.NewRecorder()
+	handler.WarmCache(responseRecorder, newCacheWarmRequest(body))
+
+	if responseRecorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, responseRecorder.Code)
+	}
+}
+
+// TestRouterCacheWarmRequiresAdminToken tests that the warm endpoint is only reachable with the admin token
+func TestRouterCacheWarmRequiresAdminToken(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)))
+	router := SetupRouter(&RouterConfig{Handler: handler, AdminToken: "secret"})
+
+	body := `{"orgId":"` + testWarmOrgID + `","players":[{"region":"na","gameName":"PlayerA","tagLine":"NA1"}]}`
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "wrong", http.StatusUnauthorized},
+		{"valid token", "secret", http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := newCacheWarmRequest(body)
+			if testCase.token != "" {
+				request.Header.Set(middleware.AdminTokenHeader, testCase.token)
+			}
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
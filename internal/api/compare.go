@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// ComparePlayers compares two players head-to-head. Both players' summoners and matches are
+// fetched concurrently and aggregated side by side. A player who cannot be loaded (e.g. not
+// found) gets a per-player error while the other player's data is still returned.
+func (handler *Handler) ComparePlayers(writer http.ResponseWriter, request *http.Request) {
+	var compareRequest validation.CompareRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&compareRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	for index := range compareRequest.Players {
+		compareRequest.Players[index].Region = handler.resolveRegion(compareRequest.Players[index].Region)
+	}
+
+	validationResult := validation.ValidateCompareRequest(&compareRequest)
+	handler.requireCount(compareRequest.Count, "count", validationResult)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	// Each goroutine writes only its own slot, so players stay in request order
+	players := make([]models.ComparedPlayer, len(compareRequest.Players))
+	playerMatches := make([][]models.Match, len(compareRequest.Players))
+	var waitGroup sync.WaitGroup
+
+	for index, player := range compareRequest.Players {
+		waitGroup.Add(1)
+		go func(index int, player validation.SummonerRequest) {
+			defer waitGroup.Done()
+			players[index], playerMatches[index] = handler.fetchComparedPlayer(request.Context(), player, compareRequest.Count)
+		}(index, player)
+	}
+
+	waitGroup.Wait()
+
+	// Aggregate once both fetches are done: the players' match lists can share matches, which
+	// enrichment fills in place
+	logger := middleware.LoggerFromContext(request.Context())
+	for index := range players {
+		if players[index].Error != nil {
+			logger.Warn().Str("error_code", players[index].Error.Code).Str("game_name", players[index].GameName).Msg("Compared player could not be loaded")
+			continue
+		}
+
+		if handler.championTable != nil {
+			handler.championTable.EnrichMatches(playerMatches[index])
+		}
+		players[index].Stats = aggregatePlayerStats(players[index].Summoner.PUUID, playerMatches[index])
+	}
+
+	comparison := models.Comparison{Players: players, SharedMatches: []models.SharedMatch{}}
+	if players[0].Stats != nil && players[1].Stats != nil {
+		comparison.SharedMatches = findSharedMatches(players[0].Summoner.PUUID, players[1].Summoner.PUUID, playerMatches[0])
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, comparison)
+}
+
+// fetchComparedPlayer loads one side of a comparison: the summoner, then its matches through
+// the batch match fetch. Failures are reported in the returned player's Error; stats are
+// aggregated by the caller.
+func (handler *Handler) fetchComparedPlayer(ctx context.Context, player validation.SummonerRequest, count validation.IntegerField) (models.ComparedPlayer, []models.Match) {
+	normalizedRegion := validation.NormalizeRegion(player.Region)
+	comparedPlayer := models.ComparedPlayer{
+		Region:   normalizedRegion,
+		GameName: player.GameName,
+		TagLine:  player.TagLine,
+	}
+
+	if !handler.isRegionServed(normalizedRegion) {
+		comparedPlayer.Error = newBatchError(apierrors.RegionNotServed(normalizedRegion))
+		return comparedPlayer, nil
+	}
+
+	summoner, err := handler.fetchSummoner(ctx, normalizedRegion, player.GameName, player.TagLine)
+	if err != nil {
+		comparedPlayer.Error = newBatchError(err)
+		return comparedPlayer, nil
+	}
+	comparedPlayer.Summoner = summoner
+
+	matchResult := handler.fetchBatchMatchItem(ctx, validation.BatchMatchItem{
+		Region: normalizedRegion,
+		PUUID:  summoner.PUUID,
+		Count:  count,
+	})
+	if matchResult.Error != nil {
+		comparedPlayer.Error = matchResult.Error
+		return comparedPlayer, nil
+	}
+
+	return comparedPlayer, matchResult.Matches
+}
+
+// findSharedMatches returns the matches both players took part in, in the first player's
+// match order. Participants carry no team ID, so players on the same side of a finished
+// match are recognized by sharing its result.
+func findSharedMatches(firstPUUID string, secondPUUID string, firstMatches []models.Match) []models.SharedMatch {
+	sharedMatches := []models.SharedMatch{}
+
+	for _, match := range firstMatches {
+		first, firstFound := findParticipant(match, firstPUUID)
+		second, secondFound := findParticipant(match, secondPUUID)
+		if !firstFound || !secondFound {
+			continue
+		}
+
+		sharedMatches = append(sharedMatches, models.SharedMatch{
+			MatchID:  match.MatchID,
+			SameTeam: first.Win == second.Win,
+		})
+	}
+
+	return sharedMatches
+}
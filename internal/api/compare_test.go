@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newCompareProxy builds a mock proxy knowing two players, "alpha" and "bravo", who played
+// one match together on opposite teams
+func newCompareProxy() *MockServiceProxy {
+	sharedMatch := models.Match{MatchID: "NA1_3", Participants: []models.Participant{
+		{PUUID: "alpha-puuid", ChampionID: 103, Kills: 2, Deaths: 2, Assists: 2, Win: true},
+		{PUUID: "bravo-puuid", ChampionID: 157, Kills: 1, Deaths: 4, Assists: 0, Win: false},
+	}}
+
+	matchesByPUUID := map[string][]models.Match{
+		"alpha-puuid": {
+			{MatchID: "NA1_1", Participants: []models.Participant{{PUUID: "alpha-puuid", ChampionID: 103, Kills: 10, Deaths: 2, Assists: 5, Win: true}}},
+			{MatchID: "NA1_2", Participants: []models.Participant{{PUUID: "alpha-puuid", ChampionID: 266, Kills: 3, Deaths: 5, Assists: 1, Win: false}}},
+			sharedMatch,
+		},
+		"bravo-puuid": {sharedMatch},
+	}
+
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			switch gameName {
+			case "alpha":
+				return &models.Summoner{PUUID: "alpha-puuid"}, nil
+			case "bravo":
+				return &models.Summoner{PUUID: "bravo-puuid"}, nil
+			}
+			return nil, apierrors.PlayerNotFound(gameName, tagLine)
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return matchesByPUUID[puuid], nil
+		},
+	}
+}
+
+// postCompare sends a compare request for the two given game names and decodes the comparison
+func postCompare(t *testing.T, handler *Handler, firstGameName string, secondGameName string) models.Comparison {
+	t.Helper()
+
+	requestBody := `{"players":[{"region":"na","gameName":"` + firstGameName + `","tagLine":"NA1"},{"region":"na","gameName":"` + secondGameName + `","tagLine":"NA1"}]}`
+	request, _ := http.NewRequest("POST", "/api/v1/compare", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.ComparePlayers(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	var comparison models.Comparison
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&comparison); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(comparison.Players) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(comparison.Players))
+	}
+	return comparison
+}
+
+// TestComparePlayers_BothFound tests that both players' stats are aggregated and shared matches flagged
+func TestComparePlayers_BothFound(t *testing.T) {
+	handler := NewHandler(newCompareProxy())
+	comparison := postCompare(t, handler, "alpha", "bravo")
+
+	alpha := comparison.Players[0]
+	if alpha.Error != nil || alpha.Stats == nil {
+		t.Fatalf("Expected alpha to be loaded, got %+v", alpha)
+	}
+	if alpha.Stats.Games != 3 || alpha.Stats.Wins != 2 || alpha.Stats.Losses != 1 {
+		t.Errorf("Expected alpha 3 games, 2 wins, 1 loss, got %+v", alpha.Stats)
+	}
+	if alpha.Stats.KDA != 23.0/9.0 {
+		t.Errorf("Expected alpha KDA %f, got %f", 23.0/9.0, alpha.Stats.KDA)
+	}
+
+	// Ahri (103) was played twice and comes first, with its name filled in from the champion table
+	topChampion := alpha.Stats.Champions[0]
+	if topChampion.ChampionID != 103 || topChampion.Games != 2 || topChampion.WinRate != 1 || topChampion.ChampionName != "Ahri" {
+		t.Errorf("Expected Ahri with 2 games at 100%%, got %+v", topChampion)
+	}
+
+	bravo := comparison.Players[1]
+	if bravo.Stats == nil || bravo.Stats.Games != 1 || bravo.Stats.Wins != 0 {
+		t.Errorf("Expected bravo 1 game without a win, got %+v", bravo.Stats)
+	}
+
+	if len(comparison.SharedMatches) != 1 || comparison.SharedMatches[0].MatchID != "NA1_3" || comparison.SharedMatches[0].SameTeam {
+		t.Errorf("Expected NA1_3 shared as opponents, got %+v", comparison.SharedMatches)
+	}
+}
+
+// TestComparePlayers_OneMissing tests that an unknown player gets a per-player error while the other is returned
+func TestComparePlayers_OneMissing(t *testing.T) {
+	handler := NewHandler(newCompareProxy())
+	comparison := postCompare(t, handler, "alpha", "ghost")
+
+	if comparison.Players[0].Error != nil || comparison.Players[0].Stats == nil || comparison.Players[0].Stats.Games != 3 {
+		t.Errorf("Expected alpha's data to be returned, got %+v", comparison.Players[0])
+	}
+
+	ghost := comparison.Players[1]
+	if ghost.Error == nil || ghost.Error.Code != string(apierrors.ErrCodePlayerNotFound) {
+		t.Fatalf("Expected a PLAYER_NOT_FOUND error for ghost, got %+v", ghost.Error)
+	}
+	if ghost.Error.Message != "Player not found: ghost#NA1" {
+		t.Errorf("Expected a clear not-found message, got '%s'", ghost.Error.Message)
+	}
+	if ghost.Summoner != nil || ghost.Stats != nil {
+		t.Errorf("Expected no data for ghost, got %+v", ghost)
+	}
+
+	if len(comparison.SharedMatches) != 0 {
+		t.Errorf("Expected no shared matches, got %+v", comparison.SharedMatches)
+	}
+}
+
+// TestComparePlayers_RequiresTwoPlayers tests that a comparison needs exactly two players
+func TestComparePlayers_RequiresTwoPlayers(t *testing.T) {
+	handler := NewHandler(newCompareProxy())
+
+	requestBody := `{"players":[{"region":"na","gameName":"alpha","tagLine":"NA1"}]}`
+	request, _ := http.NewRequest("POST", "/api/v1/compare", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.ComparePlayers(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// invalidJSONError maps a request body decode error to an InvalidRequestBody error. Type
+// mismatches name the field path and the expected and received JSON types (e.g. "field
+// 'region' expected string, got number"); any other failure is reported as invalid JSON.
+func invalidJSONError(err error) *apierrors.APIError {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return apierrors.InvalidRequestBody(fmt.Sprintf("field '%s' expected %s, got %s", typeError.Field, jsonTypeName(typeError.Type), typeError.Value))
+	}
+	return apierrors.InvalidRequestBody("Invalid JSON format")
+}
+
+// jsonTypeName returns the JSON type a Go type is decoded from
+func jsonTypeName(goType reflect.Type) string {
+	switch goType.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Pointer:
+		return jsonTypeName(goType.Elem())
+	default:
+		return "object"
+	}
+}
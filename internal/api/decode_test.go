@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// TestDecodeErrors_TypeMismatch tests that type-mismatched fields are reported with their path and types
+func TestDecodeErrors_TypeMismatch(t *testing.T) {
+	testCases := []struct {
+		name            string
+		handlerFunc     func(handler *Handler) http.HandlerFunc
+		requestBody     string
+		expectedCode    apierrors.ErrorCode
+		expectedMessage string
+	}{
+		{
+			name:            "region number",
+			handlerFunc:     func(handler *Handler) http.HandlerFunc { return handler.GetSummoner },
+			requestBody:     `{"region":5,"gameName":"TestPlayer","tagLine":"NA1"}`,
+			expectedCode:    apierrors.ErrCodeInvalidRequestBody,
+			expectedMessage: "field 'region' expected string, got number",
+		},
+		{
+			name:            "nested region object",
+			handlerFunc:     func(handler *Handler) http.HandlerFunc { return handler.GetMatchesBatch },
+			requestBody:     `{"players":[{"region":{},"puuid":"test-puuid"}]}`,
+			expectedCode:    apierrors.ErrCodeInvalidRequestBody,
+			expectedMessage: "field 'region' expected string, got object",
+		},
+		{
+			name:            "count object",
+			handlerFunc:     func(handler *Handler) http.HandlerFunc { return handler.GetMatches },
+			requestBody:     `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":{}}`,
+			expectedCode:    apierrors.ErrCodeValidationFailed,
+			expectedMessage: "count: count must be an integer",
+		},
+		{
+			name:            "malformed",
+			handlerFunc:     func(handler *Handler) http.HandlerFunc { return handler.GetSummoner },
+			requestBody:     `{"region":`,
+			expectedCode:    apierrors.ErrCodeInvalidRequestBody,
+			expectedMessage: "Invalid JSON format",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&MockServiceProxy{})
+			request, _ := http.NewRequest("POST", "/api/v1/test", bytes.NewBufferString(testCase.requestBody))
+			responseRecorder := httptest.NewRecorder()
+
+			testCase.handlerFunc(handler)(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+			}
+
+			var errorResponse apierrors.ErrorResponse
+			if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if errorResponse.Error.Code != testCase.expectedCode {
+				t.Errorf("Expected error code '%s', got '%s'", testCase.expectedCode, errorResponse.Error.Code)
+			}
+			if !strings.Contains(errorResponse.Error.Message, testCase.expectedMessage) {
+				t.Errorf("Expected message containing '%s', got '%s'", testCase.expectedMessage, errorResponse.Error.Message)
+			}
+		})
+	}
+}
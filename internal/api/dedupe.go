@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// isDedupeEnabled reports whether duplicate matches should be collapsed. Deduplication is
+// on by default; clients pass ?dedupe=false to receive opgl-data's list unmodified.
+func isDedupeEnabled(request *http.Request) bool {
+	return request.URL.Query().Get("dedupe") != "false"
+}
+
+// dedupeMatches removes repeated match IDs, keeping the first occurrence and the original
+// order. opgl-data can repeat matches across its internal pages. Matches without an ID
+// cannot be compared and are always kept.
+func dedupeMatches(matches []models.Match) []models.Match {
+	seenMatchIDs := make(map[string]struct{}, len(matches))
+	dedupedMatches := matches[:0:0]
+
+	for _, match := range matches {
+		if match.MatchID != "" {
+			if _, seen := seenMatchIDs[match.MatchID]; seen {
+				continue
+			}
+			seenMatchIDs[match.MatchID] = struct{}{}
+		}
+		dedupedMatches = append(dedupedMatches, match)
+	}
+
+	return dedupedMatches
+}
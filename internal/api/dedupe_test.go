@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newDuplicateMatchesProxy returns a mock proxy whose match list repeats entries, as opgl-data does
+func newDuplicateMatchesProxy() *MockServiceProxy {
+	return &MockServiceProxy{
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{
+				{MatchID: "NA1_3"},
+				{MatchID: "NA1_2"},
+				{MatchID: "NA1_3"},
+				{MatchID: ""},
+				{MatchID: "NA1_1"},
+				{MatchID: "NA1_2"},
+				{MatchID: ""},
+			}, nil
+		},
+	}
+}
+
+// TestGetMatches_Dedupe tests that duplicate match IDs are collapsed in order unless dedupe=false
+func TestGetMatches_Dedupe(t *testing.T) {
+	testCases := []struct {
+		name             string
+		query            string
+		expectedMatchIDs []string
+	}{
+		{"default", "", []string{"NA1_3", "NA1_2", "", "NA1_1", ""}},
+		{"explicit", "?dedupe=true", []string{"NA1_3", "NA1_2", "", "NA1_1", ""}},
+		{"disabled", "?dedupe=false", []string{"NA1_3", "NA1_2", "NA1_3", "", "NA1_1", "NA1_2", ""}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(newDuplicateMatchesProxy())
+
+			responseRecorder := httptest.NewRecorder()
+			handler.GetMatches(responseRecorder, newEnrichMatchesRequest(testCase.query))
+
+			if responseRecorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+			}
+
+			var response []models.Match
+			if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if len(response) != len(testCase.expectedMatchIDs) {
+				t.Fatalf("Expected %d matches, got %d", len(testCase.expectedMatchIDs), len(response))
+			}
+			for index, match := range response {
+				if match.MatchID != testCase.expectedMatchIDs[index] {
+					t.Errorf("Expected match %d to be '%s', got '%s'", index, testCase.expectedMatchIDs[index], match.MatchID)
+				}
+			}
+		})
+	}
+}
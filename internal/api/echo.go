@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// echoEndpointParam selects which endpoint's request pipeline POST /debug/echo runs
+const echoEndpointParam = "endpoint"
+
+// DebugEchoResponse shows how the gateway interpreted a request body: the decoded request
+// with defaults applied and normalization done, and the validation errors it would be
+// rejected with
+type DebugEchoResponse struct {
+	Endpoint string      `json:"endpoint"`
+	Request  interface{} `json:"request"`
+	// Locale is the analysis language negotiated from Accept-Language (analyze only)
+	Locale string                       `json:"locale,omitempty"`
+	Valid  bool                         `json:"valid"`
+	Errors []validation.ValidationError `json:"errors"`
+}
+
+// echoPipelines maps each echoable endpoint to the function running its parsing and
+// validation on a decoded body
+var echoPipelines = map[string]func(handler *Handler, request *http.Request, body []byte) (*DebugEchoResponse, error){
+	"summoner": (*Handler).echoSummoner,
+	"matches":  (*Handler).echoMatches,
+	"analyze":  (*Handler).echoAnalyze,
+	"profile":  (*Handler).echoProfile,
+}
+
+// DebugEcho runs the parsing and validation of the endpoint named by ?endpoint= (summoner,
+// matches, analyze or profile) on the request body and returns the result without calling
+// any upstream. Invalid requests are reported in the 200 response rather than rejected.
+func (handler *Handler) DebugEcho(writer http.ResponseWriter, request *http.Request) {
+	endpoint := request.URL.Query().Get(echoEndpointParam)
+	pipeline, found := echoPipelines[endpoint]
+	if !found {
+		handler.writeError(writer, request, apierrors.ValidationFailed("endpoint: must be one of summoner, matches, analyze, profile"))
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	echo, err := pipeline(handler, request, body)
+	if err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	echo.Endpoint = endpoint
+	echo.Valid = len(echo.Errors) == 0
+	if echo.Errors == nil {
+		echo.Errors = []validation.ValidationError{}
+	}
+	handler.writeResponse(writer, request, http.StatusOK, echo)
+}
+
+// echoResult builds an echo of request from a validation result, adding the region-served
+// check the handlers apply after validation
+func (handler *Handler) echoResult(request interface{}, region string, validationResult *validation.ValidationResult) *DebugEchoResponse {
+	if validationResult.IsValid() && !handler.isRegionServed(region) {
+		validationResult.AddError("region", apierrors.RegionNotServed(region).Message)
+	}
+	return &DebugEchoResponse{Request: request, Errors: validationResult.Errors}
+}
+
+// echoSummoner runs the GetSummoner pipeline
+func (handler *Handler) echoSummoner(request *http.Request, body []byte) (*DebugEchoResponse, error) {
+	var summonerRequest validation.SummonerRequest
+	if err := json.Unmarshal(body, &summonerRequest); err != nil {
+		return nil, err
+	}
+
+	summonerRequest.Region = handler.resolveRegion(summonerRequest.Region)
+	validationResult := validation.ValidateSummonerRequest(&summonerRequest)
+	summonerRequest.Region = validation.NormalizeRegion(summonerRequest.Region)
+	return handler.echoResult(summonerRequest, summonerRequest.Region, validationResult), nil
+}
+
+// echoMatches runs the GetMatches pipeline, including the default count
+func (handler *Handler) echoMatches(request *http.Request, body []byte) (*DebugEchoResponse, error) {
+	var matchRequest validation.MatchRequest
+	if err := json.Unmarshal(body, &matchRequest); err != nil {
+		return nil, err
+	}
+
+	matchRequest.Region = handler.resolveRegion(matchRequest.Region)
+	validationResult := validation.ValidateMatchRequest(&matchRequest)
+	handler.requireCount(matchRequest.Count, "count", validationResult)
+	matchRequest.Region = validation.NormalizeRegion(matchRequest.Region)
+	if count, err := matchRequest.Count.Int(); err == nil && count <= 0 {
+		matchRequest.Count = validation.IntegerField("20")
+	}
+	return handler.echoResult(matchRequest, matchRequest.Region, validationResult), nil
+}
+
+// echoAnalyze runs the analyze pipeline, resolving the mode (including the API key tier's
+// default), match count, partial setting and locale
+func (handler *Handler) echoAnalyze(request *http.Request, body []byte) (*DebugEchoResponse, error) {
+	var analyzeRequest validation.AnalyzeRequest
+	if err := json.Unmarshal(body, &analyzeRequest); err != nil {
+		return nil, err
+	}
+
+	analyzeRequest.Region = handler.resolveRegion(analyzeRequest.Region)
+	validationResult := validation.ValidateAnalyzeRequest(&analyzeRequest)
+	validationResult.Merge(validation.ValidateAnalyzeOptions(&analyzeRequest.AnalyzeOptions, handler.maxAnalyzeCount))
+	if validationResult.IsValid() {
+		if tierError := handler.applyTierMode(request.Context(), &analyzeRequest.AnalyzeOptions); tierError != nil {
+			validationResult.AddError("mode", tierError.Message)
+		}
+	}
+
+	analyzeRequest.Region = validation.NormalizeRegion(analyzeRequest.Region)
+	analyzeRequest.Count = validation.IntegerField(strconv.Itoa(handler.analyzeFetchCount(&analyzeRequest.AnalyzeOptions)))
+	if analyzeRequest.Mode == "" {
+		analyzeRequest.Mode = validation.DefaultAnalyzeMode
+	}
+	partial := analyzeRequest.AllowsPartial()
+	analyzeRequest.Partial = &partial
+
+	echo := handler.echoResult(analyzeRequest, analyzeRequest.Region, validationResult)
+	echo.Locale = validation.NegotiateLocale(request.Header.Get("Accept-Language"))
+	return echo, nil
+}
+
+// echoProfile runs the GetProfile pipeline, including the default count
+func (handler *Handler) echoProfile(request *http.Request, body []byte) (*DebugEchoResponse, error) {
+	var profileRequest validation.ProfileRequest
+	if err := json.Unmarshal(body, &profileRequest); err != nil {
+		return nil, err
+	}
+
+	profileRequest.Region = handler.resolveRegion(profileRequest.Region)
+	validationResult := validation.ValidateProfileRequest(&profileRequest)
+	handler.requireCount(profileRequest.Count, "count", validationResult)
+	profileRequest.Region = validation.NormalizeRegion(profileRequest.Region)
+	if count, err := profileRequest.Count.Int(); err == nil && count <= 0 {
+		profileRequest.Count = validation.IntegerField(strconv.Itoa(defaultProfileMatchCount))
+	}
+	return handler.echoResult(profileRequest, profileRequest.Region, validationResult), nil
+}
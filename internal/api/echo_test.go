@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// decodeEchoResponse decodes a debug echo response with the request as a generic map
+func decodeEchoResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) (DebugEchoResponse, map[string]interface{}) {
+	t.Helper()
+
+	var response DebugEchoResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	echoedRequest, _ := response.Request.(map[string]interface{})
+	return response, echoedRequest
+}
+
+// TestDebugEcho_Analyze tests that the echo reflects normalized values and resolved defaults without calling upstreams
+func TestDebugEcho_Analyze(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			t.Error("Expected no upstream call")
+			return nil, nil
+		},
+	}
+	router := SetupRouter(&RouterConfig{Handler: NewHandler(mockProxy), DebugEcho: true})
+
+	requestBody := `{"region":"EUW","game_name":"TestPlayer","tag_line":"EUW1","mode":"quick"}`
+	request, _ := http.NewRequest("POST", "/api/v1/debug/echo?endpoint=analyze", bytes.NewBufferString(requestBody))
+	request.Header.Set("Accept-Language", "ko-KR,ko;q=0.9")
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	response, echoedRequest := decodeEchoResponse(t, responseRecorder)
+	if !response.Valid || len(response.Errors) != 0 || response.Endpoint != "analyze" {
+		t.Errorf("Expected a valid analyze echo, got %+v", response)
+	}
+	expected := map[string]interface{}{
+		"region":   "euw",
+		"gameName": "TestPlayer",
+		"tagLine":  "EUW1",
+		"mode":     "quick",
+		"count":    float64(5),
+		"partial":  true,
+	}
+	for field, expectedValue := range expected {
+		if echoedRequest[field] != expectedValue {
+			t.Errorf("Expected %s %v, got %v", field, expectedValue, echoedRequest[field])
+		}
+	}
+	if response.Locale != "ko" {
+		t.Errorf("Expected locale 'ko', got '%s'", response.Locale)
+	}
+}
+
+// TestDebugEcho_ValidationErrors tests that invalid requests are echoed with their validation errors and defaults applied
+func TestDebugEcho_ValidationErrors(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("POST", "/api/v1/debug/echo?endpoint=matches", bytes.NewBufferString(`{"region":"NA","gameName":"ab","tagLine":"NA1"}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.DebugEcho(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	response, echoedRequest := decodeEchoResponse(t, responseRecorder)
+	if response.Valid || len(response.Errors) != 1 || response.Errors[0].Field != "gameName" {
+		t.Errorf("Expected a single gameName error, got %+v", response.Errors)
+	}
+	if echoedRequest["region"] != "na" || echoedRequest["count"] != float64(20) {
+		t.Errorf("Expected normalized region and default count, got %v", echoedRequest)
+	}
+}
+
+// TestDebugEcho_UnknownEndpoint tests that an unknown endpoint is rejected
+func TestDebugEcho_UnknownEndpoint(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("POST", "/api/v1/debug/echo?endpoint=refresh", bytes.NewBufferString(`{}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.DebugEcho(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestRouterDebugEchoDisabled tests that the echo endpoint is not mounted unless enabled
+func TestRouterDebugEchoDisabled(t *testing.T) {
+	router := SetupRouterSimple(NewHandler(&MockServiceProxy{}), nil)
+
+	request, _ := http.NewRequest("POST", "/api/v1/debug/echo?endpoint=summoner", bytes.NewBufferString(`{}`))
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, responseRecorder.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/ddragon"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// WarningHeader carries non-fatal problems with an otherwise successful response
+const WarningHeader = middleware.WarningHeader
+
+// WithChampionTable sets the champion table used to enrich matches when clients pass enrich=true.
+// A nil table disables enrichment; matches are then returned raw with a warning.
+func WithChampionTable(championTable *ddragon.ChampionTable) HandlerOption {
+	return func(handler *Handler) {
+		handler.championTable = championTable
+	}
+}
+
+// isEnrichmentRequested reports whether the client opted into match enrichment via ?enrich=true
+func isEnrichmentRequested(request *http.Request) bool {
+	return request.URL.Query().Get("enrich") == "true"
+}
+
+// enrichMatches maps participant champion IDs to names in place. When the table is unavailable
+// or an ID is unknown, the raw IDs are kept and a warning is added to the response.
+func (handler *Handler) enrichMatches(request *http.Request, matches []models.Match) {
+	logger := middleware.LoggerFromContext(request.Context())
+
+	if handler.championTable == nil {
+		logger.Warn().Msg("Champion enrichment requested but no champion table is loaded")
+		middleware.AddWarning(request.Context(), "champion enrichment unavailable; returning raw champion IDs")
+		return
+	}
+
+	unresolved := handler.championTable.EnrichMatches(matches)
+	if len(unresolved) > 0 {
+		logger.Warn().Ints("champion_ids", unresolved).Msg("Unknown champion IDs during enrichment")
+		middleware.AddWarning(request.Context(), fmt.Sprintf("unknown champion IDs %v returned unenriched", unresolved))
+	}
+}
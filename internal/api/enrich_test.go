@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/ddragon"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newEnrichMatchesRequest builds a matches request with the given query string
+func newEnrichMatchesRequest(query string) *http.Request {
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"region":   "na",
+		"gameName": "TestPlayer",
+		"tagLine":  "NA1",
+	})
+	request, _ := http.NewRequest("POST", "/api/v1/matches"+query, bytes.NewBuffer(bodyBytes))
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+// newChampionMatchesProxy returns a mock proxy whose matches carry only champion IDs
+func newChampionMatchesProxy(championIDs ...int) *MockServiceProxy {
+	return &MockServiceProxy{
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			participants := make([]models.Participant, len(championIDs))
+			for index, championID := range championIDs {
+				participants[index] = models.Participant{ChampionID: championID}
+			}
+			return []models.Match{{MatchID: "NA1_123", Participants: participants}}, nil
+		},
+	}
+}
+
+// TestGetMatches_EnrichChampionNames tests that enrich=true maps champion IDs to names
+func TestGetMatches_EnrichChampionNames(t *testing.T) {
+	championTable := ddragon.NewChampionTable(map[int]string{157: "Yasuo", 222: "Jinx"})
+	handler := NewHandler(newChampionMatchesProxy(157, 222), WithChampionTable(championTable))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetMatches, responseRecorder, newEnrichMatchesRequest("?enrich=true"))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response []models.Match
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	participants := response[0].Participants
+	if participants[0].ChampionName != "Yasuo" || participants[1].ChampionName != "Jinx" {
+		t.Errorf("Expected enriched champion names, got %+v", participants)
+	}
+
+	if responseRecorder.Header().Get(WarningHeader) != "" {
+		t.Errorf("Expected no warning, got '%s'", responseRecorder.Header().Get(WarningHeader))
+	}
+}
+
+// TestGetMatches_EnrichNotRequested tests that matches are returned raw without enrich=true
+func TestGetMatches_EnrichNotRequested(t *testing.T) {
+	championTable := ddragon.NewChampionTable(map[int]string{157: "Yasuo"})
+	handler := NewHandler(newChampionMatchesProxy(157), WithChampionTable(championTable))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetMatches, responseRecorder, newEnrichMatchesRequest(""))
+
+	var response []models.Match
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response[0].Participants[0].ChampionName != "" {
+		t.Errorf("Expected no enrichment, got '%s'", response[0].Participants[0].ChampionName)
+	}
+}
+
+// TestGetMatches_EnrichUnknownChampionFallback tests that unknown IDs are returned raw with a warning
+func TestGetMatches_EnrichUnknownChampionFallback(t *testing.T) {
+	championTable := ddragon.NewChampionTable(map[int]string{157: "Yasuo"})
+	handler := NewHandler(newChampionMatchesProxy(157, 9999), WithChampionTable(championTable))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetMatches, responseRecorder, newEnrichMatchesRequest("?enrich=true"))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response []models.Match
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	unknown := response[0].Participants[1]
+	if unknown.ChampionID != 9999 || unknown.ChampionName != "" {
+		t.Errorf("Expected unknown champion to keep its raw ID, got %+v", unknown)
+	}
+
+	if !strings.Contains(responseRecorder.Header().Get(WarningHeader), "9999") {
+		t.Errorf("Expected warning mentioning the unknown ID, got '%s'", responseRecorder.Header().Get(WarningHeader))
+	}
+}
+
+// TestGetMatches_EnrichTableUnavailable tests that a missing champion table falls back to raw IDs with a warning
+func TestGetMatches_EnrichTableUnavailable(t *testing.T) {
+	handler := NewHandler(newChampionMatchesProxy(157), WithChampionTable(nil))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetMatches, responseRecorder, newEnrichMatchesRequest("?enrich=true"))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if responseRecorder.Header().Get(WarningHeader) == "" {
+		t.Error("Expected a warning when enrichment is unavailable")
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// CheckPlayerExists reports whether a Riot ID resolves to a player, using only the summoner
+// lookup (and summoner cache). An unknown player is a 200 with exists=false rather than a 404;
+// validation and upstream failures are still returned as errors.
+func (handler *Handler) CheckPlayerExists(writer http.ResponseWriter, request *http.Request) {
+	var summonerRequest validation.SummonerRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&summonerRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	summonerRequest.Region = handler.resolveRegion(summonerRequest.Region)
+	validationResult := validation.ValidateSummonerRequest(&summonerRequest)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	normalizedRegion := validation.NormalizeRegion(summonerRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
+
+	_, err := handler.lookupSummoner(writer, request, normalizedRegion, summonerRequest.GameName, summonerRequest.TagLine)
+	if err != nil && !isPlayerNotFound(err) {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, models.PlayerExistsResponse{Exists: err == nil})
+}
+
+// isPlayerNotFound reports whether err is opgl-data's answer that the Riot ID does not exist
+func isPlayerNotFound(err error) bool {
+	apiErr, ok := err.(*apierrors.APIError)
+	return ok && apiErr.Code == apierrors.ErrCodePlayerNotFound
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newExistsRequest builds a POST /api/v1/exists request for a fixed test player
+func newExistsRequest() *http.Request {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/exists", bytes.NewBufferString(requestBody))
+	return request
+}
+
+// decodeExistsResponse decodes the exists flag from a response body
+func decodeExistsResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) models.PlayerExistsResponse {
+	t.Helper()
+
+	var response models.PlayerExistsResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response
+}
+
+// TestCheckPlayerExists_Found tests that an existing player returns exists=true
+func TestCheckPlayerExists_Found(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.CheckPlayerExists(responseRecorder, newExistsRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if !decodeExistsResponse(t, responseRecorder).Exists {
+		t.Error("Expected exists to be true")
+	}
+}
+
+// TestCheckPlayerExists_NotFound tests that an unknown player returns 200 with exists=false
+func TestCheckPlayerExists_NotFound(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return nil, apierrors.PlayerNotFound(gameName, tagLine)
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.CheckPlayerExists(responseRecorder, newExistsRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if decodeExistsResponse(t, responseRecorder).Exists {
+		t.Error("Expected exists to be false")
+	}
+}
+
+// TestCheckPlayerExists_UpstreamError tests that data service failures are still reported as errors
+func TestCheckPlayerExists_UpstreamError(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return nil, apierrors.DataServiceError("Data service unavailable")
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.CheckPlayerExists(responseRecorder, newExistsRequest())
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestCheckPlayerExists_ValidationError tests that an invalid Riot ID is rejected
+func TestCheckPlayerExists_ValidationError(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("POST", "/api/v1/exists", bytes.NewBufferString(`{"region":"na"}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.CheckPlayerExists(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// FieldMode controls whether optional request fields may be omitted
+type FieldMode string
+
+const (
+	// FieldModeLenient fills omitted optional fields (e.g. count) with their defaults
+	FieldModeLenient FieldMode = "lenient"
+	// FieldModeStrict requires clients to send optional fields such as count explicitly
+	FieldModeStrict FieldMode = "strict"
+)
+
+// ParseFieldMode parses FIELD_MODE; an empty value selects lenient mode, the historical behavior
+func ParseFieldMode(value string) (FieldMode, error) {
+	switch FieldMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FieldModeLenient:
+		return FieldModeLenient, nil
+	case FieldModeStrict:
+		return FieldModeStrict, nil
+	default:
+		return "", fmt.Errorf("invalid field mode %q (expected lenient or strict)", value)
+	}
+}
+
+// WithFieldMode sets whether omitted optional fields are defaulted or rejected
+func WithFieldMode(fieldMode FieldMode) HandlerOption {
+	return func(handler *Handler) {
+		handler.fieldMode = fieldMode
+	}
+}
+
+// requireCount reports an omitted count in strict field mode; in lenient mode an omitted
+// count is left for the handler to default
+func (handler *Handler) requireCount(count validation.IntegerField, field string, result *validation.ValidationResult) {
+	if handler.fieldMode == FieldModeStrict && !count.IsSet() {
+		result.AddError(field, field+" is required")
+	}
+}
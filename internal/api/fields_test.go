@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestParseFieldMode tests parsing of FIELD_MODE values
+func TestParseFieldMode(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    FieldMode
+		expectError bool
+	}{
+		{"", FieldModeLenient, false},
+		{"lenient", FieldModeLenient, false},
+		{" STRICT ", FieldModeStrict, false},
+		{"relaxed", "", true},
+	}
+
+	for _, testCase := range testCases {
+		fieldMode, err := ParseFieldMode(testCase.value)
+		if (err != nil) != testCase.expectError {
+			t.Errorf("ParseFieldMode(%q): expected error=%v, got %v", testCase.value, testCase.expectError, err)
+		}
+		if fieldMode != testCase.expected {
+			t.Errorf("ParseFieldMode(%q): expected '%s', got '%s'", testCase.value, testCase.expected, fieldMode)
+		}
+	}
+}
+
+// TestGetMatches_OmittedCountByFieldMode tests that an omitted count is defaulted in lenient mode and rejected in strict mode
+func TestGetMatches_OmittedCountByFieldMode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		fieldMode      FieldMode
+		expectedStatus int
+	}{
+		{"lenient", FieldModeLenient, http.StatusOK},
+		{"strict", FieldModeStrict, http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requestedCount := 0
+			mockProxy := &MockServiceProxy{
+				GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+					requestedCount = count
+					return []models.Match{}, nil
+				},
+			}
+			handler := NewHandler(mockProxy, WithFieldMode(testCase.fieldMode))
+
+			requestBody := `{"region":"na","puuid":"` + strings.Repeat("a", 78) + `"}`
+			request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBufferString(requestBody))
+			responseRecorder := httptest.NewRecorder()
+			handler.GetMatches(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", testCase.expectedStatus, responseRecorder.Code, responseRecorder.Body.String())
+			}
+
+			if testCase.fieldMode == FieldModeLenient && requestedCount != 20 {
+				t.Errorf("Expected the default count of 20, got %d", requestedCount)
+			}
+
+			if testCase.fieldMode == FieldModeStrict {
+				var errorResponse apierrors.ErrorResponse
+				json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+				if errorResponse.Error.Message != "count: count is required" {
+					t.Errorf("Expected 'count: count is required', got '%s'", errorResponse.Error.Message)
+				}
+				if requestedCount != 0 {
+					t.Error("Expected the proxy not to be called")
+				}
+			}
+		})
+	}
+}
+
+// TestGetMatches_StrictFieldModeExplicitCount tests that strict mode accepts a request that sends count
+func TestGetMatches_StrictFieldModeExplicitCount(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithFieldMode(FieldModeStrict))
+
+	requestBody := `{"region":"na","puuid":"` + strings.Repeat("a", 78) + `","count":5}`
+	request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatches(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusOK, responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
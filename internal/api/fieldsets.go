@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// fieldsQueryParam selects the top-level response fields to return, e.g. ?fields=puuid,name
+const fieldsQueryParam = "fields"
+
+// UnknownFieldsPolicy controls how ?fields= names that the response does not have are handled
+type UnknownFieldsPolicy string
+
+const (
+	// UnknownFieldsIgnore drops unknown names and returns the known ones
+	UnknownFieldsIgnore UnknownFieldsPolicy = "ignore"
+	// UnknownFieldsReject fails the request with 400 VALIDATION_FAILED
+	UnknownFieldsReject UnknownFieldsPolicy = "reject"
+)
+
+// ParseUnknownFieldsPolicy parses UNKNOWN_FIELDS_POLICY; an empty value selects ignore
+func ParseUnknownFieldsPolicy(value string) (UnknownFieldsPolicy, error) {
+	switch UnknownFieldsPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", UnknownFieldsIgnore:
+		return UnknownFieldsIgnore, nil
+	case UnknownFieldsReject:
+		return UnknownFieldsReject, nil
+	default:
+		return "", fmt.Errorf("invalid unknown fields policy %q (expected ignore or reject)", value)
+	}
+}
+
+// WithUnknownFieldsPolicy sets whether unknown ?fields= names are ignored or rejected
+func WithUnknownFieldsPolicy(policy UnknownFieldsPolicy) HandlerOption {
+	return func(handler *Handler) {
+		handler.unknownFieldsPolicy = policy
+	}
+}
+
+// fieldSelection is the set of top-level JSON fields a client asked for; nil selects every field
+type fieldSelection map[string]bool
+
+// parseFieldSelection reads ?fields= for a response shaped like prototype. It runs before any
+// upstream call so unknown names can be rejected up front under the reject policy.
+func (handler *Handler) parseFieldSelection(request *http.Request, prototype interface{}) (fieldSelection, *apierrors.APIError) {
+	value := request.URL.Query().Get(fieldsQueryParam)
+	if value == "" {
+		return nil, nil
+	}
+
+	knownFields := jsonFieldNames(reflect.TypeOf(prototype))
+	selection := make(fieldSelection)
+	var unknownFields []string
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !knownFields[name] {
+			unknownFields = append(unknownFields, name)
+			continue
+		}
+		selection[name] = true
+	}
+
+	if len(unknownFields) > 0 && handler.unknownFieldsPolicy == UnknownFieldsReject {
+		validFields := make([]string, 0, len(knownFields))
+		for name := range knownFields {
+			validFields = append(validFields, name)
+		}
+		sort.Strings(validFields)
+		return nil, apierrors.ValidationFailed(fmt.Sprintf("fields: unknown field(s) %s; valid fields: %s",
+			strings.Join(unknownFields, ", "), strings.Join(validFields, ", ")))
+	}
+
+	return selection, nil
+}
+
+// selectFields projects payload to the selected top-level JSON fields by marshaling it and
+// keeping only the selected keys. A nil selection returns payload unchanged.
+func selectFields(payload interface{}, selection fieldSelection) (interface{}, error) {
+	if selection == nil {
+		return payload, nil
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	for name := range fields {
+		if !selection[name] {
+			delete(fields, name)
+		}
+	}
+
+	return fields, nil
+}
+
+// writeSelectedFields writes a 200 response with payload projected to the selected fields
+func (handler *Handler) writeSelectedFields(writer http.ResponseWriter, request *http.Request, payload interface{}, selection fieldSelection) {
+	projected, err := selectFields(payload, selection)
+	if err != nil {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, projected)
+}
+
+// jsonFieldNames returns the top-level JSON field names of a struct type, following
+// encoding/json's tag rules, including the fields of untagged embedded structs
+func jsonFieldNames(structType reflect.Type) map[string]bool {
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	names := make(map[string]bool)
+	if structType.Kind() != reflect.Struct {
+		return names
+	}
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			for embeddedName := range jsonFieldNames(field.Type) {
+				names[embeddedName] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+
+	return names
+}
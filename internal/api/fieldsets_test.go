@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newFieldsSummonerProxy returns a mock proxy resolving every player to a fully populated summoner
+func newFieldsSummonerProxy() *MockServiceProxy {
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{ID: "id", AccountID: "account", PUUID: "test-puuid", Name: "TestPlayer", ProfileIconID: 7, SummonerLevel: 300}, nil
+		},
+	}
+}
+
+// getSummonerWithFields calls GetSummoner with the given ?fields= value
+func getSummonerWithFields(handler *Handler, fields string) *httptest.ResponseRecorder {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/summoner?fields="+fields, bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, request)
+	return responseRecorder
+}
+
+// decodeResponseKeys returns the sorted top-level keys of a JSON object response
+func decodeResponseKeys(t *testing.T, responseRecorder *httptest.ResponseRecorder) []string {
+	t.Helper()
+
+	var response map[string]json.RawMessage
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	keys := make([]string, 0, len(response))
+	for key := range response {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TestGetSummoner_Fields tests that ?fields= returns only the listed summoner fields
+func TestGetSummoner_Fields(t *testing.T) {
+	handler := NewHandler(newFieldsSummonerProxy())
+
+	responseRecorder := getSummonerWithFields(handler, "puuid,summonerLevel")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if keys := decodeResponseKeys(t, responseRecorder); !reflect.DeepEqual(keys, []string{"puuid", "summonerLevel"}) {
+		t.Errorf("Expected only puuid and summonerLevel, got %v", keys)
+	}
+}
+
+// TestGetSummoner_FieldsUnknownIgnored tests that fields=puuid,level returns only known keys under the ignore policy
+func TestGetSummoner_FieldsUnknownIgnored(t *testing.T) {
+	handler := NewHandler(newFieldsSummonerProxy())
+
+	// The summoner has summonerLevel but no "level" field, so only puuid is returned
+	responseRecorder := getSummonerWithFields(handler, "puuid,level")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if keys := decodeResponseKeys(t, responseRecorder); !reflect.DeepEqual(keys, []string{"puuid"}) {
+		t.Errorf("Expected only puuid, got %v", keys)
+	}
+}
+
+// TestGetSummoner_FieldsUnknownRejected tests that unknown fields are a 400 under the reject policy, before proxying
+func TestGetSummoner_FieldsUnknownRejected(t *testing.T) {
+	proxyCalled := false
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			proxyCalled = true
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithUnknownFieldsPolicy(UnknownFieldsReject))
+
+	responseRecorder := getSummonerWithFields(handler, "puuid,level")
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+	if !bytes.Contains(responseRecorder.Body.Bytes(), []byte("unknown field(s) level")) {
+		t.Errorf("Expected the unknown field to be named, got %s", responseRecorder.Body.String())
+	}
+	if proxyCalled {
+		t.Error("Expected the proxy not to be called")
+	}
+}
+
+// TestAnalyzePlayer_Fields tests that ?fields= projects the analysis result
+func TestAnalyzePlayer_Fields(t *testing.T) {
+	mockProxy := newFieldsSummonerProxy()
+	mockProxy.AnalyzePlayerFunc = func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+		return &models.AnalysisResult{PlayerStats: map[string]interface{}{"winRate": 0.5}, ImprovementAreas: []string{"vision"}}, nil
+	}
+	handler := NewHandler(mockProxy, WithResponseEnvelope(EnvelopeWrapped))
+
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/analyze?fields=playerStats", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data["playerStats"] == nil {
+		t.Errorf("Expected only playerStats in data, got %v", response.Data)
+	}
+}
+
+// TestJSONFieldNames tests that tag names, untagged fields and embedded structs are recognized
+func TestJSONFieldNames(t *testing.T) {
+	type embedded struct {
+		Inner string `json:"inner"`
+	}
+	type sample struct {
+		embedded
+		Tagged   string `json:"tagged,omitempty"`
+		Untagged string
+		Skipped  string `json:"-"`
+		private  string
+	}
+
+	names := jsonFieldNames(reflect.TypeOf(&sample{}))
+	expected := map[string]bool{"inner": true, "tagged": true, "Untagged": true}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
@@ -3,68 +3,205 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	"github.com/OPGLOL/opgl-gateway-service/internal/ddragon"
 	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
 	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/pagination"
 	"github.com/OPGLOL/opgl-gateway-service/internal/proxy"
+	"github.com/OPGLOL/opgl-gateway-service/internal/server"
 	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
 )
 
 // Handler manages HTTP request handlers for the gateway
 type Handler struct {
-	serviceProxy proxy.ServiceProxyInterface
+	serviceProxy     proxy.ServiceProxyInterface
+	envelope         ResponseEnvelope
+	maxBatchSize     int
+	batchTimeout     time.Duration
+	batchConcurrency int
+
+	summonerCache cache.Cache
+	championTable *ddragon.ChampionTable
+	readiness     *server.Readiness
+
+	readinessDependencies map[string]bool
+	readinessCache        *readinessCache
+
+	enabledRegions map[string]bool
+	regionMode     RegionMode
+	fieldMode      FieldMode
+
+	unknownFieldsPolicy UnknownFieldsPolicy
+
+	maxCacheWarmSize     int
+	cacheWarmConcurrency int
+
+	analyzeMatchDeadline  time.Duration
+	maxAnalyzeCount       int
+	analyzeMatchCount     int
+	minAnalyzeMatches     int
+	analyzeCoalescer      *cache.Coalescer
+	sharedAnalysisTimeout time.Duration
+	analysisCache         cache.Cache
+	analyzeTierModes      map[string]AnalyzeTierModes
+
+	cacheMetrics *metrics.CacheMetrics
+
+	livenessProbe *server.LivenessProbe
+}
+
+// HandlerOption configures optional Handler behavior
+type HandlerOption func(*Handler)
+
+// WithResponseEnvelope sets how response payloads are shaped (raw or wrapped)
+func WithResponseEnvelope(envelope ResponseEnvelope) HandlerOption {
+	return func(handler *Handler) {
+		handler.envelope = envelope
+	}
+}
+
+// WithSummonerCache enables caching of summoner lookups, including serving stale
+// entries when opgl-data is unavailable
+func WithSummonerCache(summonerCache cache.Cache) HandlerOption {
+	return func(handler *Handler) {
+		handler.summonerCache = summonerCache
+	}
+}
+
+// WithCacheMetrics records hit/miss counts for the handler's caches
+func WithCacheMetrics(cacheMetrics *metrics.CacheMetrics) HandlerOption {
+	return func(handler *Handler) {
+		handler.cacheMetrics = cacheMetrics
+	}
 }
 
 // NewHandler creates a new Handler instance
-func NewHandler(serviceProxy proxy.ServiceProxyInterface) *Handler {
-	return &Handler{
-		serviceProxy: serviceProxy,
+func NewHandler(serviceProxy proxy.ServiceProxyInterface, options ...HandlerOption) *Handler {
+	handler := &Handler{
+		serviceProxy:     serviceProxy,
+		envelope:         EnvelopeRaw,
+		maxBatchSize:     defaultMaxBatchSize,
+		batchTimeout:     defaultBatchTimeout,
+		batchConcurrency: defaultBatchConcurrency,
+
+		fieldMode:           FieldModeLenient,
+		unknownFieldsPolicy: UnknownFieldsIgnore,
+
+		maxCacheWarmSize:     defaultMaxCacheWarmSize,
+		cacheWarmConcurrency: defaultCacheWarmConcurrency,
+
+		maxAnalyzeCount:   validation.DefaultMaxAnalyzeCount,
+		analyzeMatchCount: defaultAnalyzeMatchCount,
+
+		sharedAnalysisTimeout: defaultSharedAnalysisTimeout,
+	}
+
+	// The embedded table always parses in practice; a nil table just disables enrichment
+	handler.championTable, _ = ddragon.DefaultChampionTable()
+
+	for _, option := range options {
+		option(handler)
 	}
+
+	return handler
 }
 
-// HealthCheck handles health check requests
+// withEnvelope returns a copy of the handler that shapes responses with the given envelope.
+// The copy shares the proxy, caches and other dependencies with the original.
+func (handler *Handler) withEnvelope(envelope ResponseEnvelope) *Handler {
+	versionHandler := *handler
+	versionHandler.envelope = envelope
+	return &versionHandler
+}
+
+// HealthCheck handles health check requests. When a liveness probe is configured, it returns
+// 503 once the probe's self-check has not succeeded recently (e.g. a deadlocked lock).
 func (handler *Handler) HealthCheck(writer http.ResponseWriter, request *http.Request) {
+	status := http.StatusOK
 	response := map[string]string{
 		"status":  "healthy",
 		"service": "opgl-gateway",
 	}
+
+	if handler.livenessProbe != nil && !handler.livenessProbe.IsHealthy() {
+		status = http.StatusServiceUnavailable
+		response["status"] = "unhealthy"
+		response["lastHealthy"] = handler.livenessProbe.LastHealthy().UTC().Format(time.RFC3339)
+	}
+
 	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
 	json.NewEncoder(writer).Encode(response)
 }
 
-// GetSummoner proxies summoner requests to opgl-data service using Riot ID
+// ErrorCodesResponse lists the published error codes
+type ErrorCodesResponse struct {
+	ErrorCodes []apierrors.ErrorCodeInfo `json:"errorCodes"`
+}
+
+// ListErrorCodes returns every error code clients may receive, with descriptions and typical statuses
+func (handler *Handler) ListErrorCodes(writer http.ResponseWriter, request *http.Request) {
+	handler.writeResponse(writer, request, http.StatusOK, ErrorCodesResponse{ErrorCodes: apierrors.ErrorCodes()})
+}
+
+// GetSummoner proxies summoner requests to opgl-data service using Riot ID. ?fields= projects
+// the summoner to the listed top-level fields, and ?includeLiveGame=true adds its live-game
+// status.
 func (handler *Handler) GetSummoner(writer http.ResponseWriter, request *http.Request) {
+	includeLiveGame := isLiveGameRequested(request)
+	var prototype interface{} = models.Summoner{}
+	if includeLiveGame {
+		prototype = models.SummonerWithLiveGame{}
+	}
+	selection, selectionError := handler.parseFieldSelection(request, prototype)
+	if selectionError != nil {
+		handler.writeError(writer, request, selectionError)
+		return
+	}
+
 	var summonerRequest validation.SummonerRequest
 
 	if err := json.NewDecoder(request.Body).Decode(&summonerRequest); err != nil {
-		apierrors.WriteError(writer, apierrors.InvalidRequestBody("Invalid JSON format"))
+		handler.writeError(writer, request, invalidJSONError(err))
 		return
 	}
 
-	// Validate request
+	// Validate request, after mapping platform-code aliases in lenient region mode
+	summonerRequest.Region = handler.resolveRegion(summonerRequest.Region)
 	validationResult := validation.ValidateSummonerRequest(&summonerRequest)
 	if !validationResult.IsValid() {
-		apierrors.WriteError(writer, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
 		return
 	}
 
 	// Normalize region to lowercase for consistent API calls
 	normalizedRegion := validation.NormalizeRegion(summonerRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
 
-	summoner, err := handler.serviceProxy.GetSummonerByRiotID(normalizedRegion, summonerRequest.GameName, summonerRequest.TagLine)
+	summoner, err := handler.lookupSummoner(writer, request, normalizedRegion, summonerRequest.GameName, summonerRequest.TagLine)
 	if err != nil {
-		// Check if the error is already an APIError
-		if apiErr, ok := err.(*apierrors.APIError); ok {
-			apierrors.WriteError(writer, apiErr)
-			return
-		}
-		// Wrap unknown errors as internal errors
-		apierrors.WriteError(writer, apierrors.InternalError("An unexpected error occurred"))
+		handler.writeProxyError(writer, request, err)
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(writer).Encode(summoner)
+	// Live-game status changes independently of the summoner, so it is never answered with a 304
+	if includeLiveGame {
+		handler.writeSelectedFields(writer, request, handler.withLiveGame(request, normalizedRegion, summoner), selection)
+		return
+	}
+
+	// Date-based conditional requests, when opgl-data reports when the summoner last changed
+	if summoner.RevisionDate > 0 && writeNotModified(writer, request, time.UnixMilli(summoner.RevisionDate)) {
+		return
+	}
+
+	handler.writeSelectedFields(writer, request, summoner, selection)
 }
 
 // GetMatches proxies match history requests to opgl-data service
@@ -73,102 +210,165 @@ func (handler *Handler) GetMatches(writer http.ResponseWriter, request *http.Req
 	var matchRequest validation.MatchRequest
 
 	if err := json.NewDecoder(request.Body).Decode(&matchRequest); err != nil {
-		apierrors.WriteError(writer, apierrors.InvalidRequestBody("Invalid JSON format"))
+		handler.writeError(writer, request, invalidJSONError(err))
 		return
 	}
 
-	// Validate request
+	// Validate request, after mapping platform-code aliases in lenient region mode
+	matchRequest.Region = handler.resolveRegion(matchRequest.Region)
 	validationResult := validation.ValidateMatchRequest(&matchRequest)
+	handler.requireCount(matchRequest.Count, "count", validationResult)
 	if !validationResult.IsValid() {
-		apierrors.WriteError(writer, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
 		return
 	}
 
 	// Normalize region and set default count
 	normalizedRegion := validation.NormalizeRegion(matchRequest.Region)
-	count := matchRequest.Count
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
+
+	count, _ := matchRequest.Count.Int()
 	if count <= 0 {
 		count = 20
 	}
 
+	// Decode the pagination cursor (already validated) into the upstream filter
+	filter := models.MatchFilter{
+		StartTime: matchRequest.StartTime,
+		EndTime:   matchRequest.EndTime,
+	}
+	if matchRequest.Cursor != nil && *matchRequest.Cursor != "" {
+		filter.Cursor, _ = pagination.DecodeMatchCursor(*matchRequest.Cursor)
+	}
+
 	var matches []models.Match
 	var err error
 
 	// Check if PUUID is provided for direct lookup
 	if matchRequest.PUUID != "" {
-		matches, err = handler.serviceProxy.GetMatchesByPUUID(normalizedRegion, matchRequest.PUUID, count)
+		matches, err = handler.serviceProxy.GetMatchesByPUUID(request.Context(), normalizedRegion, matchRequest.PUUID, count, filter)
 	} else {
 		// Use Riot ID lookup
-		matches, err = handler.serviceProxy.GetMatchesByRiotID(normalizedRegion, matchRequest.GameName, matchRequest.TagLine, count)
+		matches, err = handler.serviceProxy.GetMatchesByRiotID(request.Context(), normalizedRegion, matchRequest.GameName, matchRequest.TagLine, count, filter)
 	}
 
 	if err != nil {
-		// Check if the error is already an APIError
-		if apiErr, ok := err.(*apierrors.APIError); ok {
-			apierrors.WriteError(writer, apiErr)
-			return
-		}
-		// Wrap unknown errors as internal errors
-		apierrors.WriteError(writer, apierrors.InternalError("An unexpected error occurred"))
+		handler.writeProxyError(writer, request, err)
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(writer).Encode(matches)
+	// The next cursor comes from the upstream page so removing duplicates never ends pagination early
+	nextCursor := pagination.NextMatchCursor(matches, count)
+
+	if isDedupeEnabled(request) {
+		matches = dedupeMatches(matches)
+	}
+
+	if isEnrichmentRequested(request) {
+		handler.enrichMatches(request, matches)
+	}
+
+	// Cursor-paginated requests receive a page with the cursor for the next page
+	if matchRequest.Cursor != nil {
+		handler.writeResponse(writer, request, http.StatusOK, models.MatchPage{
+			Matches:    matches,
+			NextCursor: nextCursor,
+		})
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, matches)
 }
 
 // AnalyzePlayer orchestrates player analysis by calling both data and cortex services using Riot ID
 func (handler *Handler) AnalyzePlayer(writer http.ResponseWriter, request *http.Request) {
+	handler.handleAnalyze(writer, request, false)
+}
+
+// RefreshAnalysis re-runs a player's analysis, bypassing the summoner and analysis caches,
+// and stores the fresh results in those caches
+func (handler *Handler) RefreshAnalysis(writer http.ResponseWriter, request *http.Request) {
+	handler.handleAnalyze(writer, request, true)
+}
+
+// handleAnalyze implements AnalyzePlayer and, with refresh set, RefreshAnalysis. ?fields=
+// projects the analysis result (not the dry-run response) to the listed top-level fields.
+func (handler *Handler) handleAnalyze(writer http.ResponseWriter, request *http.Request, refresh bool) {
+	selection, selectionError := handler.parseFieldSelection(request, models.AnalysisResult{})
+	if selectionError != nil {
+		handler.writeError(writer, request, selectionError)
+		return
+	}
+
+	// Optional per-step timings, reported in meta.timings and Server-Timing
+	start := time.Now()
+	if isTimingsRequested(request) {
+		request = withStepTimings(request)
+	}
+
 	var analyzeRequest validation.AnalyzeRequest
 
 	if err := json.NewDecoder(request.Body).Decode(&analyzeRequest); err != nil {
-		apierrors.WriteError(writer, apierrors.InvalidRequestBody("Invalid JSON format"))
+		handler.writeError(writer, request, invalidJSONError(err))
 		return
 	}
 
-	// Validate request
+	// Validate request, after mapping platform-code aliases in lenient region mode
+	analyzeRequest.Region = handler.resolveRegion(analyzeRequest.Region)
 	validationResult := validation.ValidateAnalyzeRequest(&analyzeRequest)
+	validationResult.Merge(validation.ValidateAnalyzeOptions(&analyzeRequest.AnalyzeOptions, handler.maxAnalyzeCount))
 	if !validationResult.IsValid() {
-		apierrors.WriteError(writer, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	// Default the mode from the API key tier and enforce which modes the tier may request
+	if tierError := handler.applyTierMode(request.Context(), &analyzeRequest.AnalyzeOptions); tierError != nil {
+		handler.writeError(writer, request, tierError)
 		return
 	}
 
 	// Normalize region to lowercase
 	normalizedRegion := validation.NormalizeRegion(analyzeRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
 
 	// Step 1: Get summoner data from opgl-data
-	summoner, err := handler.serviceProxy.GetSummonerByRiotID(normalizedRegion, analyzeRequest.GameName, analyzeRequest.TagLine)
+	lookup := handler.lookupSummoner
+	if refresh {
+		lookup = handler.refreshSummoner
+	}
+	summonerStart := time.Now()
+	summoner, err := lookup(writer, request, normalizedRegion, analyzeRequest.GameName, analyzeRequest.TagLine)
 	if err != nil {
-		if apiErr, ok := err.(*apierrors.APIError); ok {
-			apierrors.WriteError(writer, apiErr)
-			return
-		}
-		apierrors.WriteError(writer, apierrors.InternalError("An unexpected error occurred"))
+		handler.writeProxyError(writer, request, err)
 		return
 	}
+	recordStepTiming(request.Context(), timingSummoner, summonerStart)
 
-	// Step 2: Get match history from opgl-data (using internal method with PUUID)
-	matches, err := handler.serviceProxy.GetMatchesByPUUID(normalizedRegion, summoner.PUUID, 20)
-	if err != nil {
-		if apiErr, ok := err.(*apierrors.APIError); ok {
-			apierrors.WriteError(writer, apiErr)
-			return
-		}
-		apierrors.WriteError(writer, apierrors.InternalError("An unexpected error occurred"))
+	if isDryRunRequested(request) {
+		handler.analyzeDryRun(writer, request, summoner)
 		return
 	}
 
-	// Step 3: Send data to opgl-cortex-engine for analysis
-	analysisResult, err := handler.serviceProxy.AnalyzePlayer(summoner, matches)
+	// Steps 2-3: Fetch match history and analyze it in the client's preferred language,
+	// sharing the work with identical concurrent requests
+	locale := validation.NegotiateLocale(request.Header.Get("Accept-Language"))
+	analysisResult, err := handler.analyze(request, normalizedRegion, summoner, &analyzeRequest.AnalyzeOptions, locale, refresh)
 	if err != nil {
-		if apiErr, ok := err.(*apierrors.APIError); ok {
-			apierrors.WriteError(writer, apiErr)
-			return
-		}
-		apierrors.WriteError(writer, apierrors.InternalError("An unexpected error occurred"))
+		handler.writeProxyError(writer, request, err)
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(writer).Encode(analysisResult)
+	addDegradedWarnings(request, analysisResult)
+
+	recordStepTiming(request.Context(), timingTotal, start)
+	if timings := analyzeTimingsFromContext(request.Context()); timings != nil {
+		setServerTimingHeader(writer, timings)
+	}
+
+	handler.writeSelectedFields(writer, request, analysisResult, selection)
 }
@@ -2,48 +2,108 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
 	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/pagination"
 )
 
 // MockServiceProxy is a mock implementation of ServiceProxyInterface for testing
 type MockServiceProxy struct {
-	GetSummonerByRiotIDFunc func(region, gameName, tagLine string) (*models.Summoner, error)
-	GetMatchesByRiotIDFunc  func(region, gameName, tagLine string, count int) ([]models.Match, error)
-	GetMatchesByPUUIDFunc   func(region, puuid string, count int) ([]models.Match, error)
-	AnalyzePlayerFunc       func(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error)
+	GetSummonerByRiotIDFunc  func(region, gameName, tagLine string) (*models.Summoner, error)
+	GetMatchesByRiotIDFunc   func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error)
+	GetMatchesByPUUIDFunc    func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error)
+	AnalyzePlayerFunc        func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error)
+	ForwardToDataServiceFunc func(path string, body []byte) (*http.Response, error)
+	CheckCortexHealthFunc    func() error
+	CheckDataHealthFunc      func() error
+	GetRankedStatsFunc       func(region, puuid string) ([]models.RankedStats, error)
+	GetMatchTimelineFunc     func(region, matchID string) (*models.MatchTimeline, error)
+	GetActiveGameFunc        func(region, puuid string) (*models.LiveGame, error)
+	GetMatchIDsFunc          func(region, puuid string, count int) ([]string, error)
 }
 
-func (m *MockServiceProxy) GetSummonerByRiotID(region, gameName, tagLine string) (*models.Summoner, error) {
+func (m *MockServiceProxy) GetSummonerByRiotID(ctx context.Context, region, gameName, tagLine string) (*models.Summoner, error) {
 	if m.GetSummonerByRiotIDFunc != nil {
 		return m.GetSummonerByRiotIDFunc(region, gameName, tagLine)
 	}
 	return nil, nil
 }
 
-func (m *MockServiceProxy) GetMatchesByRiotID(region, gameName, tagLine string, count int) ([]models.Match, error) {
+func (m *MockServiceProxy) GetMatchesByRiotID(ctx context.Context, region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
 	if m.GetMatchesByRiotIDFunc != nil {
-		return m.GetMatchesByRiotIDFunc(region, gameName, tagLine, count)
+		return m.GetMatchesByRiotIDFunc(region, gameName, tagLine, count, filter)
 	}
 	return nil, nil
 }
 
-func (m *MockServiceProxy) GetMatchesByPUUID(region, puuid string, count int) ([]models.Match, error) {
+func (m *MockServiceProxy) GetMatchesByPUUID(ctx context.Context, region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
 	if m.GetMatchesByPUUIDFunc != nil {
-		return m.GetMatchesByPUUIDFunc(region, puuid, count)
+		return m.GetMatchesByPUUIDFunc(region, puuid, count, filter)
 	}
 	return nil, nil
 }
 
-func (m *MockServiceProxy) AnalyzePlayer(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error) {
+func (m *MockServiceProxy) GetRankedStats(ctx context.Context, region, puuid string) ([]models.RankedStats, error) {
+	if m.GetRankedStatsFunc != nil {
+		return m.GetRankedStatsFunc(region, puuid)
+	}
+	return nil, nil
+}
+
+func (m *MockServiceProxy) GetMatchTimeline(ctx context.Context, region, matchID string) (*models.MatchTimeline, error) {
+	if m.GetMatchTimelineFunc != nil {
+		return m.GetMatchTimelineFunc(region, matchID)
+	}
+	return nil, nil
+}
+
+func (m *MockServiceProxy) GetMatchIDs(ctx context.Context, region, puuid string, count int) ([]string, error) {
+	if m.GetMatchIDsFunc != nil {
+		return m.GetMatchIDsFunc(region, puuid, count)
+	}
+	return nil, nil
+}
+
+func (m *MockServiceProxy) GetActiveGame(ctx context.Context, region, puuid string) (*models.LiveGame, error) {
+	if m.GetActiveGameFunc != nil {
+		return m.GetActiveGameFunc(region, puuid)
+	}
+	return nil, nil
+}
+
+func (m *MockServiceProxy) AnalyzePlayer(ctx context.Context, summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
 	if m.AnalyzePlayerFunc != nil {
-		return m.AnalyzePlayerFunc(summoner, matches)
+		return m.AnalyzePlayerFunc(summoner, matches, locale)
+	}
+	return nil, nil
+}
+
+func (m *MockServiceProxy) CheckCortexHealth(ctx context.Context) error {
+	if m.CheckCortexHealthFunc != nil {
+		return m.CheckCortexHealthFunc()
+	}
+	return nil
+}
+
+func (m *MockServiceProxy) CheckDataHealth(ctx context.Context) error {
+	if m.CheckDataHealthFunc != nil {
+		return m.CheckDataHealthFunc()
+	}
+	return nil
+}
+
+func (m *MockServiceProxy) ForwardToDataService(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	if m.ForwardToDataServiceFunc != nil {
+		return m.ForwardToDataServiceFunc(path, body)
 	}
 	return nil, nil
 }
@@ -124,7 +184,8 @@ func TestGetSummoner_Success(t *testing.T) {
 
 	mockProxy := &MockServiceProxy{
 		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
-			if region != "na" || gameName != "TestPlayer" || tagLine != "NA1" {
+			// The Riot ID is case-folded before it is sent upstream
+			if region != "na" || gameName != "testplayer" || tagLine != "na1" {
 				t.Errorf("Unexpected parameters: region=%s, gameName=%s, tagLine=%s", region, gameName, tagLine)
 			}
 			return expectedSummoner, nil
@@ -249,7 +310,7 @@ func TestGetMatches_Success(t *testing.T) {
 	}
 
 	mockProxy := &MockServiceProxy{
-		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int) ([]models.Match, error) {
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			if region != "na" || gameName != "TestPlayer" || tagLine != "NA1" {
 				t.Errorf("Unexpected parameters: region=%s, gameName=%s, tagLine=%s", region, gameName, tagLine)
 			}
@@ -293,7 +354,7 @@ func TestGetMatches_DefaultCount(t *testing.T) {
 	var capturedCount int
 
 	mockProxy := &MockServiceProxy{
-		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int) ([]models.Match, error) {
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			capturedCount = count
 			return []models.Match{}, nil
 		},
@@ -365,7 +426,7 @@ func TestGetMatches_MissingFields(t *testing.T) {
 // TestGetMatches_ServiceError tests service error handling
 func TestGetMatches_ServiceError(t *testing.T) {
 	mockProxy := &MockServiceProxy{
-		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int) ([]models.Match, error) {
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			return nil, errors.New("service error")
 		},
 	}
@@ -409,13 +470,13 @@ func TestAnalyzePlayer_Success(t *testing.T) {
 		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
 			return expectedSummoner, nil
 		},
-		GetMatchesByPUUIDFunc: func(region, puuid string, count int) ([]models.Match, error) {
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			if puuid != expectedSummoner.PUUID {
 				t.Errorf("Expected PUUID '%s', got '%s'", expectedSummoner.PUUID, puuid)
 			}
 			return expectedMatches, nil
 		},
-		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error) {
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
 			return expectedAnalysis, nil
 		},
 	}
@@ -517,7 +578,7 @@ func TestAnalyzePlayer_MatchHistoryError(t *testing.T) {
 		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
 			return &models.Summoner{PUUID: "test-puuid"}, nil
 		},
-		GetMatchesByPUUIDFunc: func(region, puuid string, count int) ([]models.Match, error) {
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			return nil, errors.New("match history error")
 		},
 	}
@@ -548,10 +609,10 @@ func TestAnalyzePlayer_AnalysisError(t *testing.T) {
 		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
 			return &models.Summoner{PUUID: "test-puuid"}, nil
 		},
-		GetMatchesByPUUIDFunc: func(region, puuid string, count int) ([]models.Match, error) {
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
 			return []models.Match{}, nil
 		},
-		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error) {
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
 			return nil, errors.New("analysis error")
 		},
 	}
@@ -575,3 +636,235 @@ func TestAnalyzePlayer_AnalysisError(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, responseRecorder.Code)
 	}
 }
+
+// TestGetMatches_CursorPagination tests that cursor requests return a MatchPage with a next cursor
+func TestGetMatches_CursorPagination(t *testing.T) {
+	requestCursor := pagination.EncodeMatchCursor(models.MatchCursor{Timestamp: 1700000000000, MatchID: "NA1_10"})
+	lastGameCreation := time.UnixMilli(1690000000000)
+
+	mockProxy := &MockServiceProxy{
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			if filter.Cursor == nil || filter.Cursor.MatchID != "NA1_10" {
+				t.Errorf("Expected decoded cursor to be forwarded, got %+v", filter.Cursor)
+			}
+			return []models.Match{
+				{MatchID: "NA1_9", GameCreation: lastGameCreation.Add(time.Hour)},
+				{MatchID: "NA1_8", GameCreation: lastGameCreation},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"region":   "na",
+		"gameName": "TestPlayer",
+		"tagLine":  "NA1",
+		"count":    2,
+		"cursor":   requestCursor,
+	})
+	request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+
+	handler.GetMatches(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var page models.MatchPage
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(page.Matches) != 2 {
+		t.Errorf("Expected 2 matches, got %d", len(page.Matches))
+	}
+
+	nextCursor, err := pagination.DecodeMatchCursor(page.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected decodable next cursor, got error: %v", err)
+	}
+
+	if nextCursor.MatchID != "NA1_8" {
+		t.Errorf("Expected next cursor to point at 'NA1_8', got '%s'", nextCursor.MatchID)
+	}
+}
+
+// TestGetMatches_MalformedCursor tests that malformed cursors are rejected
+func TestGetMatches_MalformedCursor(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"region":   "na",
+		"gameName": "TestPlayer",
+		"tagLine":  "NA1",
+		"cursor":   "%%%garbage",
+	})
+	request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+
+	handler.GetMatches(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestGetMatches_NonIntegerCount tests that float and string counts return a validation error
+func TestGetMatches_NonIntegerCount(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{"float count", `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":1.5}`},
+		{"string count", `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":"10"}`},
+	}
+
+	handler := NewHandler(&MockServiceProxy{})
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBufferString(testCase.body))
+			responseRecorder := httptest.NewRecorder()
+
+			handler.GetMatches(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+			}
+
+			var response map[string]map[string]string
+			json.NewDecoder(responseRecorder.Body).Decode(&response)
+
+			if response["error"]["code"] != "VALIDATION_FAILED" {
+				t.Errorf("Expected code 'VALIDATION_FAILED', got '%s'", response["error"]["code"])
+			}
+
+			if !strings.Contains(response["error"]["message"], "count must be an integer") {
+				t.Errorf("Expected integer message, got '%s'", response["error"]["message"])
+			}
+		})
+	}
+}
+
+// TestListErrorCodes tests that the published error codes are returned with descriptions and statuses
+func TestListErrorCodes(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("GET", "/api/v1/error-codes", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ListErrorCodes(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response ErrorCodesResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.ErrorCodes) != len(apierrors.ErrorCodes()) {
+		t.Errorf("Expected %d error codes, got %d", len(apierrors.ErrorCodes()), len(response.ErrorCodes))
+	}
+
+	found := false
+	for _, info := range response.ErrorCodes {
+		if info.Code == apierrors.ErrCodePlayerNotFound {
+			found = true
+			if info.Status != http.StatusNotFound {
+				t.Errorf("Expected status %d for PLAYER_NOT_FOUND, got %d", http.StatusNotFound, info.Status)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected PLAYER_NOT_FOUND in published error codes")
+	}
+}
+
+// TestGetMatches_TimeRangeForwarded tests that startTime/endTime reach the proxy filter
+func TestGetMatches_TimeRangeForwarded(t *testing.T) {
+	var capturedFilter models.MatchFilter
+	mockProxy := &MockServiceProxy{
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			capturedFilter = filter
+			return []models.Match{}, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy)
+
+	endTime := time.Now().Unix()
+	startTime := endTime - 7*24*60*60
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"region":    "na",
+		"gameName":  "TestPlayer",
+		"tagLine":   "NA1",
+		"startTime": startTime,
+		"endTime":   endTime,
+	})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatches(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if capturedFilter.StartTime == nil || *capturedFilter.StartTime != startTime {
+		t.Errorf("Expected startTime %d to be forwarded, got %v", startTime, capturedFilter.StartTime)
+	}
+	if capturedFilter.EndTime == nil || *capturedFilter.EndTime != endTime {
+		t.Errorf("Expected endTime %d to be forwarded, got %v", endTime, capturedFilter.EndTime)
+	}
+}
+
+// TestGetMatches_InvertedTimeRange tests that an inverted range is rejected before calling upstream
+func TestGetMatches_InvertedTimeRange(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetMatchesByRiotIDFunc: func(region, gameName, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			t.Error("Expected upstream not to be called")
+			return nil, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"region":    "na",
+		"gameName":  "TestPlayer",
+		"tagLine":   "NA1",
+		"startTime": 1700600000,
+		"endTime":   1700000000,
+	})
+
+	request, _ := http.NewRequest("POST", "/api/v1/matches", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatches(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestGetSummoner_SnakeCaseBody tests that snake_case request bodies are accepted
+func TestGetSummoner_SnakeCaseBody(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	requestBody := `{"region":"na","game_name":"TestPlayer","tag_line":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	handler.GetSummoner(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusOK, responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
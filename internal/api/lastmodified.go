@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// writeNotModified sets Last-Modified and, when the request's If-Modified-Since is not older
+// than lastModified, answers 304 with no body and returns true. A zero lastModified (the
+// upstream gave no timestamp) and malformed If-Modified-Since values are ignored so the full
+// response is served.
+//
+// RFC 9110 only evaluates If-Modified-Since on GET and HEAD. The gateway's lookups are
+// read-only POSTs (the body carries the Riot ID), so POST is deliberately accepted too; any
+// other method never gets a 304.
+func writeNotModified(writer http.ResponseWriter, request *http.Request, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+
+	// HTTP dates have one-second resolution
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	writer.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	ifModifiedSince := request.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || !conditionalLookupMethod(request.Method) {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil || lastModified.After(since) {
+		return false
+	}
+
+	writer.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// conditionalLookupMethod reports whether If-Modified-Since is evaluated for method
+func conditionalLookupMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return true
+	default:
+		return false
+	}
+}
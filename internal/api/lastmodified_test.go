@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestGetSummoner_LastModified tests Last-Modified on summoner responses and If-Modified-Since handling
+func TestGetSummoner_LastModified(t *testing.T) {
+	revision := time.Date(2026, 3, 1, 12, 30, 45, 500*int(time.Millisecond), time.UTC)
+	lastModified := "Sun, 01 Mar 2026 12:30:45 GMT"
+
+	testCases := []struct {
+		name            string
+		revisionDate    int64
+		ifModifiedSince string
+		expectedStatus  int
+		expectedHeader  string
+	}{
+		{"no conditional header", revision.UnixMilli(), "", http.StatusOK, lastModified},
+		{"not modified since the same second", revision.UnixMilli(), lastModified, http.StatusNotModified, lastModified},
+		{"not modified since later", revision.UnixMilli(), "Mon, 02 Mar 2026 00:00:00 GMT", http.StatusNotModified, lastModified},
+		{"modified since earlier", revision.UnixMilli(), "Sun, 01 Mar 2026 12:30:44 GMT", http.StatusOK, lastModified},
+		{"malformed header is ignored", revision.UnixMilli(), "yesterday", http.StatusOK, lastModified},
+		{"no upstream timestamp", 0, lastModified, http.StatusOK, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					return &models.Summoner{PUUID: "test-puuid", RevisionDate: testCase.revisionDate}, nil
+				},
+			}
+			handler := NewHandler(mockProxy)
+
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`))
+			if testCase.ifModifiedSince != "" {
+				request.Header.Set("If-Modified-Since", testCase.ifModifiedSince)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler.GetSummoner(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if header := responseRecorder.Header().Get("Last-Modified"); header != testCase.expectedHeader {
+				t.Errorf("Expected Last-Modified '%s', got '%s'", testCase.expectedHeader, header)
+			}
+			if testCase.expectedStatus == http.StatusNotModified && responseRecorder.Body.Len() != 0 {
+				t.Errorf("Expected an empty 304 body, got %s", responseRecorder.Body.String())
+			}
+			if testCase.expectedStatus == http.StatusOK && responseRecorder.Body.Len() == 0 {
+				t.Error("Expected the full summoner body")
+			}
+		})
+	}
+}
+
+// TestWriteNotModified_Methods tests that If-Modified-Since is only honored for GET, HEAD and
+// the read-only POST lookups
+func TestWriteNotModified_Methods(t *testing.T) {
+	lastModified := time.Date(2026, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	testCases := []struct {
+		method      string
+		expected304 bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodPost, true},
+		{http.MethodPut, false},
+		{http.MethodPatch, false},
+		{http.MethodDelete, false},
+	}
+
+	for _, testCase := range testCases {
+		request := httptest.NewRequest(testCase.method, "/api/v1/summoner", nil)
+		request.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+		responseRecorder := httptest.NewRecorder()
+
+		if notModified := writeNotModified(responseRecorder, request, lastModified); notModified != testCase.expected304 {
+			t.Errorf("%s: expected 304=%v, got %v", testCase.method, testCase.expected304, notModified)
+		}
+		if responseRecorder.Header().Get("Last-Modified") == "" {
+			t.Errorf("%s: expected Last-Modified to be set regardless of method", testCase.method)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// isLiveGameRequested reports whether the client asked for live-game status via ?includeLiveGame=true
+func isLiveGameRequested(request *http.Request) bool {
+	return request.URL.Query().Get("includeLiveGame") == "true"
+}
+
+// withLiveGame enriches a summoner with the game it is currently in. The enrichment is
+// best-effort: when the lookup fails, liveGame is null and a warning explains why, so the
+// summoner itself is still returned.
+func (handler *Handler) withLiveGame(request *http.Request, region string, summoner *models.Summoner) models.SummonerWithLiveGame {
+	enriched := models.SummonerWithLiveGame{Summoner: *summoner}
+
+	liveGame, err := handler.serviceProxy.GetActiveGame(request.Context(), region, summoner.PUUID)
+	if err != nil {
+		logger := middleware.LoggerFromContext(request.Context())
+		logger.Warn().Err(err).Msg("Live game lookup failed; returning summoner without it")
+		middleware.AddWarning(request.Context(), "live game status unavailable")
+		return enriched
+	}
+
+	enriched.LiveGame = liveGame
+	return enriched
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newLiveGameProxy returns a mock proxy whose active-game lookup calls activeGame, counting calls
+func newLiveGameProxy(activeGameCalls *int, activeGame func(region, puuid string) (*models.LiveGame, error)) *MockServiceProxy {
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer", SummonerLevel: 100}, nil
+		},
+		GetActiveGameFunc: func(region, puuid string) (*models.LiveGame, error) {
+			*activeGameCalls++
+			return activeGame(region, puuid)
+		},
+	}
+}
+
+// getSummoner posts a summoner request to url and returns the recorded response
+func getSummoner(handler http.Handler, url string) *httptest.ResponseRecorder {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", url, bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
+// TestGetSummoner_LiveGameInGame tests that ?includeLiveGame=true attaches the active game
+func TestGetSummoner_LiveGameInGame(t *testing.T) {
+	var activeGameCalls int
+	handler := NewHandler(newLiveGameProxy(&activeGameCalls, func(region, puuid string) (*models.LiveGame, error) {
+		if region != "na" || puuid != "test-puuid" {
+			t.Errorf("Unexpected parameters: region=%s, puuid=%s", region, puuid)
+		}
+		return &models.LiveGame{GameID: 4567890123, GameMode: "CLASSIC", QueueID: 420, ChampionID: 103}, nil
+	}))
+
+	responseRecorder := getSummoner(http.HandlerFunc(handler.GetSummoner), "/api/v1/summoner?includeLiveGame=true")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response models.SummonerWithLiveGame
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Name != "TestPlayer" {
+		t.Errorf("Expected the summoner fields to be kept, got %+v", response.Summoner)
+	}
+	if response.LiveGame == nil || response.LiveGame.GameID != 4567890123 || response.LiveGame.ChampionID != 103 {
+		t.Errorf("Expected the active game, got %+v", response.LiveGame)
+	}
+}
+
+// TestGetSummoner_LiveGameNotInGame tests that a player who is not in a game gets liveGame null
+func TestGetSummoner_LiveGameNotInGame(t *testing.T) {
+	var activeGameCalls int
+	handler := NewHandler(newLiveGameProxy(&activeGameCalls, func(region, puuid string) (*models.LiveGame, error) {
+		return nil, nil
+	}))
+
+	responseRecorder := getSummoner(http.HandlerFunc(handler.GetSummoner), "/api/v1/summoner?includeLiveGame=true")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response map[string]json.RawMessage
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if liveGame, present := response["liveGame"]; !present || string(liveGame) != "null" {
+		t.Errorf("Expected liveGame to be null, got '%s'", liveGame)
+	}
+}
+
+// TestGetSummoner_LiveGameDisabled tests that the active game is not looked up unless requested
+func TestGetSummoner_LiveGameDisabled(t *testing.T) {
+	var activeGameCalls int
+	handler := NewHandler(newLiveGameProxy(&activeGameCalls, func(region, puuid string) (*models.LiveGame, error) {
+		return &models.LiveGame{GameID: 1}, nil
+	}))
+
+	responseRecorder := getSummoner(http.HandlerFunc(handler.GetSummoner), "/api/v1/summoner")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if activeGameCalls != 0 {
+		t.Errorf("Expected no active-game lookup, got %d", activeGameCalls)
+	}
+	if strings.Contains(responseRecorder.Body.String(), "liveGame") {
+		t.Errorf("Expected no liveGame field, got %s", responseRecorder.Body.String())
+	}
+}
+
+// TestGetSummoner_LiveGameLookupFailed tests that a failed lookup still returns the summoner with a warning
+func TestGetSummoner_LiveGameLookupFailed(t *testing.T) {
+	var activeGameCalls int
+	handler := NewHandler(newLiveGameProxy(&activeGameCalls, func(region, puuid string) (*models.LiveGame, error) {
+		return nil, errors.New("connection refused")
+	}))
+
+	responseRecorder := getSummoner(middleware.WarningsMiddleware(http.HandlerFunc(handler.GetSummoner)), "/api/v1/summoner?includeLiveGame=true")
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if !strings.Contains(responseRecorder.Header().Get("Warning"), "live game status unavailable") {
+		t.Errorf("Expected a live game warning, got '%s'", responseRecorder.Header().Get("Warning"))
+	}
+}
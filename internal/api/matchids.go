@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// GetMatchIDs returns only the IDs of a player's recent matches, for clients diffing
+// against their own match cache without transferring full match data
+func (handler *Handler) GetMatchIDs(writer http.ResponseWriter, request *http.Request) {
+	var matchIDsRequest validation.MatchIDsRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&matchIDsRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	matchIDsRequest.Region = handler.resolveRegion(matchIDsRequest.Region)
+	validationResult := validation.ValidateMatchIDsRequest(&matchIDsRequest)
+	handler.requireCount(matchIDsRequest.Count, "count", validationResult)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	normalizedRegion := validation.NormalizeRegion(matchIDsRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
+
+	// Default to 20 match IDs, like match history
+	count, _ := matchIDsRequest.Count.Int()
+	if count <= 0 {
+		count = 20
+	}
+
+	matchIDs, err := handler.serviceProxy.GetMatchIDs(request.Context(), normalizedRegion, matchIDsRequest.PUUID, count)
+	if err != nil {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, matchIDs)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// fixtureMatchIDs is the match ID list returned by the mocked opgl-data lookup
+var fixtureMatchIDs = []string{"NA1_4567890125", "NA1_4567890124", "NA1_4567890123"}
+
+// TestGetMatchIDs tests that match IDs are returned as a plain list with the default count applied
+func TestGetMatchIDs(t *testing.T) {
+	var requestedCount int
+	mockProxy := &MockServiceProxy{
+		GetMatchIDsFunc: func(region, puuid string, count int) ([]string, error) {
+			if region != "na" || puuid != testPUUID("a") {
+				t.Errorf("Unexpected parameters: region=%s, puuid=%s", region, puuid)
+			}
+			requestedCount = count
+			return fixtureMatchIDs, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	requestBody := `{"region":"NA","puuid":"` + testPUUID("a") + `"}`
+	request, _ := http.NewRequest("POST", "/api/v1/matches/ids", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchIDs(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if requestedCount != 20 {
+		t.Errorf("Expected the default count of 20, got %d", requestedCount)
+	}
+
+	var matchIDs []string
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&matchIDs); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if strings.Join(matchIDs, ",") != strings.Join(fixtureMatchIDs, ",") {
+		t.Errorf("Expected match IDs %v, got %v", fixtureMatchIDs, matchIDs)
+	}
+}
+
+// TestGetMatchIDs_Validation tests that the PUUID and count are validated before any upstream call
+func TestGetMatchIDs_Validation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		requestBody string
+		expectedErr string
+	}{
+		{"missing puuid", `{"region":"na"}`, "puuid"},
+		{"count too large", `{"region":"na","puuid":"` + testPUUID("a") + `","count":101}`, "count cannot exceed 100"},
+		{"non-integer count", `{"region":"na","puuid":"` + testPUUID("a") + `","count":"ten"}`, "count must be an integer"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockProxy := &MockServiceProxy{
+				GetMatchIDsFunc: func(region, puuid string, count int) ([]string, error) {
+					t.Error("Expected no upstream call for an invalid request")
+					return nil, nil
+				},
+			}
+			handler := NewHandler(mockProxy)
+
+			request, _ := http.NewRequest("POST", "/api/v1/matches/ids", bytes.NewBufferString(testCase.requestBody))
+			responseRecorder := httptest.NewRecorder()
+			handler.GetMatchIDs(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+			}
+
+			var errorResponse apierrors.ErrorResponse
+			json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+			if !strings.Contains(errorResponse.Error.Message, testCase.expectedErr) {
+				t.Errorf("Expected error containing '%s', got '%s'", testCase.expectedErr, errorResponse.Error.Message)
+			}
+		})
+	}
+}
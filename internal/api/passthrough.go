@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// PassthroughRoute whitelists a gateway path that is forwarded verbatim to opgl-data
+type PassthroughRoute struct {
+	// GatewayPath is the path under /api/v1 exposed by the gateway (e.g. "/ranked")
+	GatewayPath string
+	// DataServicePath is the opgl-data path the body is forwarded to (e.g. "/api/v1/ranked")
+	DataServicePath string
+	// Validator optionally names a request validator to run before forwarding
+	Validator string
+}
+
+// passthroughValidators maps validator names to functions that decode and validate a request body
+var passthroughValidators = map[string]func(body []byte) (*validation.ValidationResult, error){
+	"summoner": func(body []byte) (*validation.ValidationResult, error) {
+		var summonerRequest validation.SummonerRequest
+		if err := json.Unmarshal(body, &summonerRequest); err != nil {
+			return nil, err
+		}
+		return validation.ValidateSummonerRequest(&summonerRequest), nil
+	},
+	"matches": func(body []byte) (*validation.ValidationResult, error) {
+		var matchRequest validation.MatchRequest
+		if err := json.Unmarshal(body, &matchRequest); err != nil {
+			return nil, err
+		}
+		return validation.ValidateMatchRequest(&matchRequest), nil
+	},
+	"analyze": func(body []byte) (*validation.ValidationResult, error) {
+		var analyzeRequest validation.AnalyzeRequest
+		if err := json.Unmarshal(body, &analyzeRequest); err != nil {
+			return nil, err
+		}
+		return validation.ValidateAnalyzeRequest(&analyzeRequest), nil
+	},
+}
+
+// ParsePassthroughRoutes parses a comma-separated list of passthrough routes in the
+// form "gatewayPath=dataServicePath[:validator]"
+func ParsePassthroughRoutes(spec string) ([]PassthroughRoute, error) {
+	var routes []PassthroughRoute
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		gatewayPath, target, found := strings.Cut(entry, "=")
+		if !found || gatewayPath == "" || target == "" {
+			return nil, fmt.Errorf("invalid passthrough route %q: expected gatewayPath=dataServicePath", entry)
+		}
+
+		dataServicePath, validatorName, _ := strings.Cut(target, ":")
+		if validatorName != "" {
+			if _, ok := passthroughValidators[validatorName]; !ok {
+				return nil, fmt.Errorf("invalid passthrough route %q: unknown validator %q", entry, validatorName)
+			}
+		}
+
+		routes = append(routes, PassthroughRoute{
+			GatewayPath:     gatewayPath,
+			DataServicePath: dataServicePath,
+			Validator:       validatorName,
+		})
+	}
+
+	return routes, nil
+}
+
+const (
+	// maxPassthroughBodyBytes bounds the request body buffered for forwarding
+	maxPassthroughBodyBytes = 1 << 20
+
+	// maxPassthroughErrorBytes bounds how much of an upstream error body is kept for mapping
+	maxPassthroughErrorBytes = 64 << 10
+)
+
+// Passthrough returns a handler that forwards the request body to the route's opgl-data path
+// and streams successful upstream responses back to the client. Upstream errors (4xx/5xx) are
+// mapped to gateway errors like any other proxy call, unless raw passthrough mode was granted.
+func (handler *Handler) Passthrough(route PassthroughRoute) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, maxPassthroughBodyBytes))
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				handler.writeError(writer, request, apierrors.RequestBodyTooLarge(fmt.Sprintf("Request body must not exceed %d bytes", maxPassthroughBodyBytes)))
+				return
+			}
+			handler.writeError(writer, request, apierrors.InvalidRequestBody("Unable to read request body"))
+			return
+		}
+
+		// Apply validation if configured for this route
+		if validate, ok := passthroughValidators[route.Validator]; ok {
+			validationResult, err := validate(body)
+			if err != nil {
+				handler.writeError(writer, request, invalidJSONError(err))
+				return
+			}
+			if !validationResult.IsValid() {
+				handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+				return
+			}
+		}
+
+		response, err := handler.serviceProxy.ForwardToDataService(request.Context(), route.DataServicePath, body)
+		if err != nil {
+			handler.writeProxyError(writer, request, err)
+			return
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode >= http.StatusBadRequest {
+			errorBody, _ := io.ReadAll(io.LimitReader(response.Body, maxPassthroughErrorBytes))
+			handler.writeProxyError(writer, request, passthroughUpstreamError(response.StatusCode, errorBody))
+			return
+		}
+
+		contentType := response.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		writer.Header().Set("Content-Type", contentType)
+		writer.WriteHeader(response.StatusCode)
+		io.Copy(writer, response.Body)
+	}
+}
+
+// passthroughUpstreamError maps an upstream error response from a passthrough route to a
+// gateway error, keeping the original response for callers granted raw passthrough mode.
+// Client-facing messages for 4xx do not repeat the upstream body; 5xx messages do, and are
+// sanitized in production like other data-service errors.
+func passthroughUpstreamError(status int, body []byte) *apierrors.APIError {
+	var apiError *apierrors.APIError
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		apiError = apierrors.InvalidRequestBody("Data service rejected the request")
+	case http.StatusNotFound:
+		apiError = apierrors.ResourceNotFound("Data service resource not found")
+	default:
+		apiError = apierrors.DataServiceError(fmt.Sprintf("Data service error (status %d): %s", status, body))
+	}
+	return apiError.WithUpstream(status, body)
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// newPassthroughRouter creates a router with a single whitelisted passthrough route
+func newPassthroughRouter(mockProxy *MockServiceProxy) http.Handler {
+	return SetupRouter(&RouterConfig{
+		Handler: NewHandler(mockProxy),
+		PassthroughRoutes: []PassthroughRoute{
+			{GatewayPath: "/ranked", DataServicePath: "/api/v1/ranked", Validator: "summoner"},
+		},
+	})
+}
+
+// TestPassthrough_WhitelistedPathForwarded tests that a whitelisted path is forwarded and streamed back
+func TestPassthrough_WhitelistedPathForwarded(t *testing.T) {
+	var forwardedPath string
+	var forwardedBody string
+
+	mockProxy := &MockServiceProxy{
+		ForwardToDataServiceFunc: func(path string, body []byte) (*http.Response, error) {
+			forwardedPath = path
+			forwardedBody = string(body)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"rankedStats":[]}`)),
+			}, nil
+		},
+	}
+	router := newPassthroughRouter(mockProxy)
+
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/ranked", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if forwardedPath != "/api/v1/ranked" {
+		t.Errorf("Expected forwarded path '/api/v1/ranked', got '%s'", forwardedPath)
+	}
+
+	if forwardedBody != requestBody {
+		t.Errorf("Expected request body to be forwarded unchanged, got '%s'", forwardedBody)
+	}
+
+	if responseRecorder.Body.String() != `{"rankedStats":[]}` {
+		t.Errorf("Expected upstream body to be streamed, got '%s'", responseRecorder.Body.String())
+	}
+}
+
+// TestPassthrough_NonWhitelistedPathRejected tests that paths not in the whitelist are not exposed
+func TestPassthrough_NonWhitelistedPathRejected(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		ForwardToDataServiceFunc: func(path string, body []byte) (*http.Response, error) {
+			t.Error("Expected non-whitelisted path to not be forwarded")
+			return nil, nil
+		},
+	}
+	router := newPassthroughRouter(mockProxy)
+
+	request, _ := http.NewRequest("POST", "/api/v1/mastery", bytes.NewBufferString(`{}`))
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, responseRecorder.Code)
+	}
+}
+
+// TestPassthrough_ValidationFailure tests that configured validation runs before forwarding
+func TestPassthrough_ValidationFailure(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		ForwardToDataServiceFunc: func(path string, body []byte) (*http.Response, error) {
+			t.Error("Expected invalid request to not be forwarded")
+			return nil, nil
+		},
+	}
+	router := newPassthroughRouter(mockProxy)
+
+	request, _ := http.NewRequest("POST", "/api/v1/ranked", bytes.NewBufferString(`{"region":"invalid"}`))
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestPassthrough_UpstreamErrorMapped tests that an upstream 404 is remapped to a gateway
+// error, and returned verbatim only to callers granted raw passthrough mode
+func TestPassthrough_UpstreamErrorMapped(t *testing.T) {
+	const upstreamBody = `{"detail":"no ranked entry for shard 7"}`
+	mockProxy := &MockServiceProxy{
+		ForwardToDataServiceFunc: func(path string, body []byte) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(upstreamBody)),
+			}, nil
+		},
+	}
+	router := SetupRouter(&RouterConfig{
+		Handler:           NewHandler(mockProxy),
+		AdminToken:        "admin-secret",
+		PassthroughRoutes: []PassthroughRoute{{GatewayPath: "/ranked", DataServicePath: "/api/v1/ranked"}},
+	})
+
+	sendRankedRequest := func(path string, adminToken string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest("POST", path, bytes.NewBufferString(`{}`))
+		if adminToken != "" {
+			request.Header.Set(middleware.AdminTokenHeader, adminToken)
+		}
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	normal := sendRankedRequest("/api/v1/ranked", "")
+	if normal.Code != http.StatusNotFound {
+		t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, normal.Code)
+	}
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(normal.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != apierrors.ErrCodeResourceNotFound {
+		t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeResourceNotFound, errorResponse.Error.Code)
+	}
+	if strings.Contains(errorResponse.Error.Message, "shard 7") {
+		t.Errorf("Expected the upstream body to stay out of the error message, got '%s'", errorResponse.Error.Message)
+	}
+
+	raw := sendRankedRequest("/api/v1/ranked?passthrough=true", "admin-secret")
+	if raw.Code != http.StatusNotFound || raw.Body.String() != upstreamBody {
+		t.Errorf("Expected the raw upstream 404 %s, got %d %s", upstreamBody, raw.Code, raw.Body.String())
+	}
+}
+
+// TestPassthrough_OversizedBody tests that bodies over the passthrough limit are rejected with 413
+func TestPassthrough_OversizedBody(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		ForwardToDataServiceFunc: func(path string, body []byte) (*http.Response, error) {
+			t.Error("Expected an oversized body to not be forwarded")
+			return nil, nil
+		},
+	}
+	router := newPassthroughRouter(mockProxy)
+
+	requestBody := strings.Repeat("a", maxPassthroughBodyBytes+1)
+	request, _ := http.NewRequest("POST", "/api/v1/ranked", strings.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, responseRecorder.Code)
+	}
+}
+
+// TestParsePassthroughRoutes tests parsing of the passthrough route configuration
+func TestParsePassthroughRoutes(t *testing.T) {
+	routes, err := ParsePassthroughRoutes("/ranked=/api/v1/ranked:summoner, /mastery=/api/v1/mastery")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+
+	if routes[0].GatewayPath != "/ranked" || routes[0].DataServicePath != "/api/v1/ranked" || routes[0].Validator != "summoner" {
+		t.Errorf("Unexpected first route: %+v", routes[0])
+	}
+
+	if routes[1].Validator != "" {
+		t.Errorf("Expected no validator for second route, got '%s'", routes[1].Validator)
+	}
+}
+
+// TestParsePassthroughRoutes_Invalid tests that malformed entries are rejected
+func TestParsePassthroughRoutes_Invalid(t *testing.T) {
+	testCases := []string{
+		"/ranked",
+		"=/api/v1/ranked",
+		"/ranked=/api/v1/ranked:unknown",
+	}
+
+	for _, spec := range testCases {
+		if _, err := ParsePassthroughRoutes(spec); err == nil {
+			t.Errorf("Expected error for spec '%s'", spec)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// findParticipant returns the participant entry for puuid in a match, if the player took part
+func findParticipant(match models.Match, puuid string) (models.Participant, bool) {
+	for _, participant := range match.Participants {
+		if participant.PUUID == puuid {
+			return participant, true
+		}
+	}
+	return models.Participant{}, false
+}
+
+// aggregatePlayerStats totals a player's results, average KDA and per-champion record over
+// the matches they appear in. Matches without the player are ignored.
+func aggregatePlayerStats(puuid string, matches []models.Match) *models.PlayerStats {
+	stats := &models.PlayerStats{Champions: []models.ChampionStats{}}
+	championIndexes := make(map[int]int)
+	var kills, deaths, assists int
+
+	for _, match := range matches {
+		participant, found := findParticipant(match, puuid)
+		if !found {
+			continue
+		}
+
+		stats.Games++
+		kills += participant.Kills
+		deaths += participant.Deaths
+		assists += participant.Assists
+
+		index, seen := championIndexes[participant.ChampionID]
+		if !seen {
+			index = len(stats.Champions)
+			championIndexes[participant.ChampionID] = index
+			stats.Champions = append(stats.Champions, models.ChampionStats{
+				ChampionID:   participant.ChampionID,
+				ChampionName: participant.ChampionName,
+			})
+		}
+		stats.Champions[index].Games++
+
+		if participant.Win {
+			stats.Wins++
+			stats.Champions[index].Wins++
+		}
+	}
+
+	if stats.Games == 0 {
+		return stats
+	}
+
+	games := float64(stats.Games)
+	stats.Losses = stats.Games - stats.Wins
+	stats.WinRate = float64(stats.Wins) / games
+	stats.AvgKills = float64(kills) / games
+	stats.AvgDeaths = float64(deaths) / games
+	stats.AvgAssists = float64(assists) / games
+	stats.KDA = float64(kills+assists) / float64(max(deaths, 1))
+
+	for index := range stats.Champions {
+		champion := &stats.Champions[index]
+		champion.WinRate = float64(champion.Wins) / float64(champion.Games)
+	}
+
+	// Most played first; the stable sort keeps first-seen order among ties
+	sort.SliceStable(stats.Champions, func(left, right int) bool {
+		return stats.Champions[left].Games > stats.Champions[right].Games
+	})
+
+	return stats
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestAggregatePlayerStats tests totals, averages and champion ordering
+func TestAggregatePlayerStats(t *testing.T) {
+	matches := []models.Match{
+		{Participants: []models.Participant{{PUUID: "player", ChampionID: 1, Kills: 4, Deaths: 0, Assists: 6, Win: true}}},
+		{Participants: []models.Participant{{PUUID: "player", ChampionID: 2, Kills: 0, Deaths: 0, Assists: 0, Win: false}}},
+		{Participants: []models.Participant{{PUUID: "player", ChampionID: 2, Kills: 2, Deaths: 0, Assists: 0, Win: true}}},
+		{Participants: []models.Participant{{PUUID: "someone-else", ChampionID: 3, Win: true}}},
+	}
+
+	stats := aggregatePlayerStats("player", matches)
+
+	if stats.Games != 3 || stats.Wins != 2 || stats.Losses != 1 {
+		t.Errorf("Expected 3 games, 2 wins, 1 loss, got %+v", stats)
+	}
+	if stats.AvgKills != 2 || stats.AvgDeaths != 0 {
+		t.Errorf("Expected 2 average kills and 0 deaths, got %f and %f", stats.AvgKills, stats.AvgDeaths)
+	}
+	// Deathless games count deaths as 1 so the KDA stays finite
+	if stats.KDA != 12 {
+		t.Errorf("Expected KDA 12, got %f", stats.KDA)
+	}
+	if len(stats.Champions) != 2 || stats.Champions[0].ChampionID != 2 || stats.Champions[0].WinRate != 0.5 {
+		t.Errorf("Expected champion 2 first at 50%%, got %+v", stats.Champions)
+	}
+}
+
+// TestAggregatePlayerStats_NoGames tests that a player without matches gets zeroed stats
+func TestAggregatePlayerStats_NoGames(t *testing.T) {
+	stats := aggregatePlayerStats("player", nil)
+
+	if stats.Games != 0 || stats.WinRate != 0 || stats.Champions == nil {
+		t.Errorf("Expected zeroed stats with an empty champion list, got %+v", stats)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// defaultProfileMatchCount is how many recent matches a profile includes when count is omitted
+const defaultProfileMatchCount = 20
+
+// GetProfile returns a player's summoner, recent matches, and ranked stats in one call. The
+// summoner is required; matches and ranked stats are then fetched concurrently, and either
+// failing leaves that field null with a warning instead of failing the request.
+func (handler *Handler) GetProfile(writer http.ResponseWriter, request *http.Request) {
+	var profileRequest validation.ProfileRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&profileRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	profileRequest.Region = handler.resolveRegion(profileRequest.Region)
+	validationResult := validation.ValidateProfileRequest(&profileRequest)
+	handler.requireCount(profileRequest.Count, "count", validationResult)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	normalizedRegion := validation.NormalizeRegion(profileRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
+
+	count, _ := profileRequest.Count.Int()
+	if count <= 0 {
+		count = defaultProfileMatchCount
+	}
+
+	// The summoner's PUUID keys the other lookups, so its failure fails the whole profile
+	summoner, err := handler.lookupSummoner(writer, request, normalizedRegion, profileRequest.GameName, profileRequest.TagLine)
+	if err != nil {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	profile := models.PlayerProfile{Summoner: summoner}
+	var matchesErr, rankedErr error
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+		profile.Matches, matchesErr = handler.serviceProxy.GetMatchesByPUUID(request.Context(), normalizedRegion, summoner.PUUID, count, models.MatchFilter{})
+	}()
+	go func() {
+		defer waitGroup.Done()
+		profile.RankedStats, rankedErr = handler.serviceProxy.GetRankedStats(request.Context(), normalizedRegion, summoner.PUUID)
+	}()
+	waitGroup.Wait()
+
+	if matchesErr != nil {
+		profile.Matches = nil
+		handler.addProfileWarning(request, &profile, "matches", matchesErr)
+	} else if profile.Matches == nil {
+		profile.Matches = []models.Match{}
+	}
+
+	if rankedErr != nil {
+		profile.RankedStats = nil
+		handler.addProfileWarning(request, &profile, "rankedStats", rankedErr)
+	} else if profile.RankedStats == nil {
+		profile.RankedStats = []models.RankedStats{}
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, profile)
+}
+
+// addProfileWarning records that a profile field could not be loaded, in both the body and the
+// response warnings. The body warning carries the error's client message, so production
+// sanitization applies to it like any other error response; other errors are only logged.
+func (handler *Handler) addProfileWarning(request *http.Request, profile *models.PlayerProfile, field string, err error) {
+	logger := middleware.LoggerFromContext(request.Context())
+	logger.Warn().Err(err).Str("field", field).Msg("Profile lookup partially failed")
+
+	warning := field + " unavailable"
+	if apiErr, ok := err.(*apierrors.APIError); ok {
+		warning += ": " + apiErr.ClientMessage()
+	}
+	profile.Warnings = append(profile.Warnings, warning)
+	middleware.AddWarning(request.Context(), field+" unavailable")
+}
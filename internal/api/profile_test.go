@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newProfileRequest builds a POST /api/v1/profile request for a fixed test player
+func newProfileRequest() *http.Request {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","count":5}`
+	request, _ := http.NewRequest("POST", "/api/v1/profile", bytes.NewBufferString(requestBody))
+	return request
+}
+
+// newProfileProxy returns a mock serving a summoner and, unless errors are given, matches and ranked stats
+func newProfileProxy(matchesErr error, rankedErr error) *MockServiceProxy {
+	return &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			if matchesErr != nil {
+				return nil, matchesErr
+			}
+			if puuid != "test-puuid" || count != 5 {
+				return nil, apierrors.InternalError("unexpected parameters")
+			}
+			return []models.Match{{MatchID: "NA1_1"}}, nil
+		},
+		GetRankedStatsFunc: func(region, puuid string) ([]models.RankedStats, error) {
+			if rankedErr != nil {
+				return nil, rankedErr
+			}
+			return []models.RankedStats{{QueueType: "RANKED_SOLO_5x5", Tier: "GOLD", Rank: "II"}}, nil
+		},
+	}
+}
+
+// decodeProfile decodes a profile response body, failing the test on error
+func decodeProfile(t *testing.T, responseRecorder *httptest.ResponseRecorder) map[string]json.RawMessage {
+	var profile map[string]json.RawMessage
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&profile); err != nil {
+		t.Fatalf("Failed to decode profile: %v", err)
+	}
+	return profile
+}
+
+// TestGetProfile_Success tests that a profile combines summoner, matches and ranked stats
+func TestGetProfile_Success(t *testing.T) {
+	handler := NewHandler(newProfileProxy(nil, nil))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetProfile, responseRecorder, newProfileRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var profile models.PlayerProfile
+	json.NewDecoder(responseRecorder.Body).Decode(&profile)
+
+	if profile.Summoner == nil || profile.Summoner.PUUID != "test-puuid" {
+		t.Errorf("Expected summoner 'test-puuid', got %+v", profile.Summoner)
+	}
+	if len(profile.Matches) != 1 || len(profile.RankedStats) != 1 {
+		t.Errorf("Expected 1 match and 1 ranked entry, got %d and %d", len(profile.Matches), len(profile.RankedStats))
+	}
+	if len(profile.Warnings) != 0 || responseRecorder.Header().Get(WarningHeader) != "" {
+		t.Errorf("Expected no warnings, got %v", profile.Warnings)
+	}
+}
+
+// TestGetProfile_PartialFailure tests that a failed sub-lookup is null with a warning
+func TestGetProfile_PartialFailure(t *testing.T) {
+	handler := NewHandler(newProfileProxy(nil, apierrors.DataServiceError("Unable to connect to data service")))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetProfile, responseRecorder, newProfileRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if responseRecorder.Header().Get(WarningHeader) == "" {
+		t.Error("Expected a Warning header")
+	}
+
+	profile := decodeProfile(t, responseRecorder)
+
+	if string(profile["rankedStats"]) != "null" {
+		t.Errorf("Expected rankedStats to be null, got %s", profile["rankedStats"])
+	}
+	if string(profile["matches"]) == "null" {
+		t.Error("Expected matches to still be present")
+	}
+
+	var warnings []string
+	json.Unmarshal(profile["warnings"], &warnings)
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+}
+
+// TestGetProfile_PartialFailureSanitized tests that in production a failed sub-lookup's
+// warning does not repeat the upstream body carried by the error
+func TestGetProfile_PartialFailureSanitized(t *testing.T) {
+	apierrors.SetEnvironment(apierrors.EnvironmentProduction)
+	defer apierrors.SetEnvironment(apierrors.EnvironmentDevelopment)
+
+	const upstreamBody = `{"trace":"pq: relation ranked_entries_shard_7 does not exist"}`
+	handler := NewHandler(newProfileProxy(nil, apierrors.DataServiceError("Data service error: "+upstreamBody)))
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetProfile, responseRecorder, newProfileRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if strings.Contains(responseRecorder.Body.String(), "ranked_entries_shard_7") {
+		t.Errorf("Expected the upstream body to be withheld, got %s", responseRecorder.Body.String())
+	}
+
+	var profile models.PlayerProfile
+	json.NewDecoder(responseRecorder.Body).Decode(&profile)
+	if len(profile.Warnings) != 1 || !strings.HasPrefix(profile.Warnings[0], "rankedStats unavailable") {
+		t.Errorf("Expected a rankedStats warning, got %v", profile.Warnings)
+	}
+}
+
+// TestGetProfile_EmptyResultsAreArrays tests that successful empty lookups are [] rather than null
+func TestGetProfile_EmptyResultsAreArrays(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetProfile, responseRecorder, newProfileRequest())
+
+	profile := decodeProfile(t, responseRecorder)
+	if string(profile["matches"]) != "[]" || string(profile["rankedStats"]) != "[]" {
+		t.Errorf("Expected empty arrays, got matches=%s rankedStats=%s", profile["matches"], profile["rankedStats"])
+	}
+}
+
+// TestGetProfile_SummonerFailure tests that a summoner failure fails the whole profile
+func TestGetProfile_SummonerFailure(t *testing.T) {
+	mockProxy := newProfileProxy(nil, nil)
+	mockProxy.GetSummonerByRiotIDFunc = func(region, gameName, tagLine string) (*models.Summoner, error) {
+		return nil, apierrors.PlayerNotFound(gameName, tagLine)
+	}
+	mockProxy.GetMatchesByPUUIDFunc = func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+		t.Error("Expected matches to not be fetched without a summoner")
+		return nil, nil
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handler.GetProfile, responseRecorder, newProfileRequest())
+
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, responseRecorder.Code)
+	}
+}
+
+// TestGetProfile_ValidationError tests that invalid requests are rejected
+func TestGetProfile_ValidationError(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	request, _ := http.NewRequest("POST", "/api/v1/profile", bytes.NewBufferString(`{"region":"xx","gameName":"TestPlayer","tagLine":"NA1"}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetProfile(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// parseIntParam reads an optional integer query parameter such as count, start or startTime.
+// An absent or empty parameter returns provided=false. A non-numeric value adds a
+// "<name> must be an integer" error to result instead of silently defaulting to 0.
+func parseIntParam(query url.Values, name string, result *validation.ValidationResult) (value int, provided bool) {
+	rawValue := strings.TrimSpace(query.Get(name))
+	if rawValue == "" {
+		return 0, false
+	}
+
+	value, err := strconv.Atoi(rawValue)
+	if err != nil {
+		result.AddError(name, name+" must be an integer")
+		return 0, false
+	}
+
+	return value, true
+}
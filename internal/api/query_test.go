@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// TestParseIntParam tests parsing of valid, empty and non-numeric integer query parameters
+func TestParseIntParam(t *testing.T) {
+	testCases := []struct {
+		name             string
+		rawQuery         string
+		expectedValue    int
+		expectedProvided bool
+		expectedError    string
+	}{
+		{name: "valid", rawQuery: "count=25", expectedValue: 25, expectedProvided: true},
+		{name: "negative", rawQuery: "count=-1", expectedValue: -1, expectedProvided: true},
+		{name: "absent", rawQuery: "", expectedProvided: false},
+		{name: "empty", rawQuery: "count=", expectedProvided: false},
+		{name: "non-numeric", rawQuery: "count=abc", expectedError: "count must be an integer"},
+		{name: "fractional", rawQuery: "count=2.5", expectedError: "count must be an integer"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(testCase.rawQuery)
+			result := &validation.ValidationResult{}
+
+			value, provided := parseIntParam(query, "count", result)
+
+			if value != testCase.expectedValue || provided != testCase.expectedProvided {
+				t.Errorf("Expected (%d, %v), got (%d, %v)", testCase.expectedValue, testCase.expectedProvided, value, provided)
+			}
+			if testCase.expectedError == "" && !result.IsValid() {
+				t.Errorf("Expected no validation error, got '%s'", result.GetErrorMessages())
+			}
+			if testCase.expectedError != "" && result.GetErrorMessages() != "count: "+testCase.expectedError {
+				t.Errorf("Expected validation error '%s', got '%s'", testCase.expectedError, result.GetErrorMessages())
+			}
+		})
+	}
+}
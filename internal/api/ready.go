@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	"github.com/OPGLOL/opgl-gateway-service/internal/server"
+)
+
+// Dependencies that /ready can check, as named in READY_DEPENDENCIES
+const (
+	DependencyData   = "data"
+	DependencyCortex = "cortex"
+)
+
+// DefaultReadinessDependencies requires opgl-data, which every endpoint needs, while an
+// opgl-cortex outage only degrades the gateway since summoner and match lookups still work
+const DefaultReadinessDependencies = "data:required,cortex:optional"
+
+// readinessCheckTimeout bounds each dependency check so a hung upstream cannot stall /ready
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadyResponse is the JSON structure returned by /ready
+type ReadyResponse struct {
+	// Status is ready, degraded (an optional dependency is down), down (a required
+	// dependency is down) or draining
+	Status string `json:"status"`
+	// Dependencies maps each checked dependency to "up" or "down"
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+}
+
+// ParseReadinessDependencies parses a comma-separated list of name:required or
+// name:optional entries (e.g. "data:required,cortex:optional") into a map from dependency
+// name to whether it is required. "none" (or an empty spec) checks no dependencies.
+func ParseReadinessDependencies(spec string) (map[string]bool, error) {
+	dependencies := make(map[string]bool)
+	if strings.TrimSpace(spec) == "none" {
+		return dependencies, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, classification, _ := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if name != DependencyData && name != DependencyCortex {
+			return nil, fmt.Errorf("invalid readiness dependency %q: expected data or cortex", entry)
+		}
+
+		switch strings.TrimSpace(classification) {
+		case "required":
+			dependencies[name] = true
+		case "optional":
+			dependencies[name] = false
+		default:
+			return nil, fmt.Errorf("invalid readiness dependency %q: expected name:required or name:optional", entry)
+		}
+	}
+
+	return dependencies, nil
+}
+
+// WithReadinessDependencies makes /ready check the given upstream dependencies, keyed by
+// name with whether each is required. A down required dependency reports 503; a down
+// optional one reports 200 with status degraded, so an analysis outage does not get the
+// gateway pulled from the load balancer.
+func WithReadinessDependencies(dependencies map[string]bool) HandlerOption {
+	return func(handler *Handler) {
+		handler.readinessDependencies = dependencies
+	}
+}
+
+// WithReadiness sets the readiness state reported by the /ready endpoint
+func WithReadiness(readiness *server.Readiness) HandlerOption {
+	return func(handler *Handler) {
+		handler.readiness = readiness
+	}
+}
+
+// WithLivenessProbe makes /health report 503 when the probe's self-check has gone stale
+func WithLivenessProbe(livenessProbe *server.LivenessProbe) HandlerOption {
+	return func(handler *Handler) {
+		handler.livenessProbe = livenessProbe
+	}
+}
+
+// lockPinger is a shared component whose lock can be taken and released on demand
+type lockPinger interface {
+	Ping()
+}
+
+// LivenessCheck takes and releases each shared lock the handler's requests contend on: the
+// readiness cache, the analyze coalescer and in-memory caches, whichever are configured. It
+// blocks while any of them is held, so a request goroutine deadlocked while holding one makes
+// the liveness probe running this check time out.
+func (handler *Handler) LivenessCheck(ctx context.Context) error {
+	if handler.readinessCache != nil {
+		handler.readinessCache.mutex.Lock()
+		handler.readinessCache.mutex.Unlock()
+	}
+	if handler.analyzeCoalescer != nil {
+		handler.analyzeCoalescer.Ping()
+	}
+	for _, sharedCache := range []cache.Cache{handler.summonerCache, handler.analysisCache} {
+		if pinger, ok := sharedCache.(lockPinger); ok {
+			pinger.Ping()
+		}
+	}
+	return nil
+}
+
+// Ready reports whether the gateway should receive traffic. It returns 503 while the
+// gateway is draining before shutdown so the load balancer deregisters it, or when a
+// required dependency is down; optional dependencies being down only degrade it.
+// Dependency results may come from the readiness cache; draining is always current.
+func (handler *Handler) Ready(writer http.ResponseWriter, request *http.Request) {
+	status := http.StatusOK
+	response := ReadyResponse{Status: "ready"}
+
+	if handler.readiness != nil && !handler.readiness.IsReady() {
+		status = http.StatusServiceUnavailable
+		response.Status = "draining"
+	} else if len(handler.readinessDependencies) > 0 {
+		response.Dependencies = handler.dependencyStatus(request.Context())
+		for name, required := range handler.readinessDependencies {
+			if response.Dependencies[name] == "up" {
+				continue
+			}
+			if required {
+				status = http.StatusServiceUnavailable
+				response.Status = "down"
+				break
+			}
+			response.Status = "degraded"
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(response)
+}
+
+// checkDependencies checks every configured dependency concurrently and reports each as up or down
+func (handler *Handler) checkDependencies(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	checks := map[string]func(context.Context) error{
+		DependencyData:   handler.serviceProxy.CheckDataHealth,
+		DependencyCortex: handler.serviceProxy.CheckCortexHealth,
+	}
+
+	var mutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	results := make(map[string]string, len(handler.readinessDependencies))
+	for name := range handler.readinessDependencies {
+		waitGroup.Add(1)
+		go func(name string) {
+			defer waitGroup.Done()
+
+			result := "up"
+			if err := checks[name](ctx); err != nil {
+				result = "down"
+			}
+
+			mutex.Lock()
+			results[name] = result
+			mutex.Unlock()
+		}(name)
+	}
+	waitGroup.Wait()
+
+	return results
+}
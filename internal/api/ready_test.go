@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/server"
+)
+
+// getReadyStatus calls the Ready handler and returns the status code and body status
+func getReadyStatus(t *testing.T, handler *Handler) (int, string) {
+	request, _ := http.NewRequest("GET", "/ready", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	handler.Ready(responseRecorder, request)
+
+	var response ReadyResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	return responseRecorder.Code, response.Status
+}
+
+// TestReady_Ready tests that /ready returns 200 when not draining
+func TestReady_Ready(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithReadiness(server.NewReadiness()))
+
+	statusCode, status := getReadyStatus(t, handler)
+
+	if statusCode != http.StatusOK || status != "ready" {
+		t.Errorf("Expected 200 ready, got %d %s", statusCode, status)
+	}
+}
+
+// TestReady_DrainWindow tests that /ready flips to 503 during the pre-shutdown drain window
+func TestReady_DrainWindow(t *testing.T) {
+	readiness := server.NewReadiness()
+	handler := NewHandler(&MockServiceProxy{}, WithReadiness(readiness))
+
+	drainDone := make(chan struct{})
+	go func() {
+		readiness.Drain(context.Background(), 50*time.Millisecond)
+		close(drainDone)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	statusCode, status := getReadyStatus(t, handler)
+	if statusCode != http.StatusServiceUnavailable || status != "draining" {
+		t.Errorf("Expected 503 draining during drain window, got %d %s", statusCode, status)
+	}
+
+	<-drainDone
+}
+
+// TestParseReadinessDependencies tests parsing of the READY_DEPENDENCIES classification
+func TestParseReadinessDependencies(t *testing.T) {
+	dependencies, err := ParseReadinessDependencies(DefaultReadinessDependencies)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dependencies) != 2 || !dependencies[DependencyData] || dependencies[DependencyCortex] {
+		t.Errorf("Expected data required and cortex optional, got %v", dependencies)
+	}
+
+	if dependencies, err := ParseReadinessDependencies("none"); err != nil || len(dependencies) != 0 {
+		t.Errorf("Expected none to check no dependencies, got %v (%v)", dependencies, err)
+	}
+
+	for _, spec := range []string{"auth:required", "data", "data:maybe"} {
+		if _, err := ParseReadinessDependencies(spec); err == nil {
+			t.Errorf("Expected error for spec %q", spec)
+		}
+	}
+}
+
+// TestReady_Dependencies tests healthy, degraded and down readiness for required and optional dependencies
+func TestReady_Dependencies(t *testing.T) {
+	upstreamDown := errors.New("connection refused")
+
+	testCases := []struct {
+		name           string
+		dataErr        error
+		cortexErr      error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"healthy", nil, nil, http.StatusOK, "ready"},
+		{"degraded when optional cortex is down", nil, upstreamDown, http.StatusOK, "degraded"},
+		{"down when required data is down", upstreamDown, nil, http.StatusServiceUnavailable, "down"},
+		{"down when both are down", upstreamDown, upstreamDown, http.StatusServiceUnavailable, "down"},
+	}
+
+	dependencies, _ := ParseReadinessDependencies(DefaultReadinessDependencies)
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockProxy := &MockServiceProxy{
+				CheckDataHealthFunc:   func() error { return testCase.dataErr },
+				CheckCortexHealthFunc: func() error { return testCase.cortexErr },
+			}
+			handler := NewHandler(mockProxy, WithReadiness(server.NewReadiness()), WithReadinessDependencies(dependencies))
+
+			statusCode, status := getReadyStatus(t, handler)
+
+			if statusCode != testCase.expectedCode || status != testCase.expectedStatus {
+				t.Errorf("Expected %d %s, got %d %s", testCase.expectedCode, testCase.expectedStatus, statusCode, status)
+			}
+		})
+	}
+}
+
+// TestReady_CortexRequired tests that reclassifying cortex as required makes its outage report down
+func TestReady_CortexRequired(t *testing.T) {
+	dependencies, _ := ParseReadinessDependencies("data:required,cortex:required")
+	mockProxy := &MockServiceProxy{
+		CheckCortexHealthFunc: func() error { return errors.New("connection refused") },
+	}
+	handler := NewHandler(mockProxy, WithReadinessDependencies(dependencies))
+
+	statusCode, status := getReadyStatus(t, handler)
+
+	if statusCode != http.StatusServiceUnavailable || status != "down" {
+		t.Errorf("Expected 503 down, got %d %s", statusCode, status)
+	}
+}
+
+// TestRouterReadyEndpoint tests that GET /ready is registered
+func TestRouterReadyEndpoint(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+	router := SetupRouterSimple(handler, nil)
+
+	request, _ := http.NewRequest("GET", "/ready", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestHealthCheck_LivenessStale tests that /health returns 503 once the liveness check goes stale
+func TestHealthCheck_LivenessStale(t *testing.T) {
+	// The probe is never run, so its initial timestamp goes stale after the threshold
+	livenessProbe := server.NewLivenessProbe(func(ctx context.Context) error { return nil }, time.Hour, time.Second, time.Millisecond)
+	handler := NewHandler(&MockServiceProxy{}, WithLivenessProbe(livenessProbe))
+
+	time.Sleep(5 * time.Millisecond)
+
+	request, _ := http.NewRequest("POST", "/health", nil)
+	responseRecorder := httptest.NewRecorder()
+	handler.HealthCheck(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, responseRecorder.Code)
+	}
+
+	var response map[string]string
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if response["status"] != "unhealthy" {
+		t.Errorf("Expected status 'unhealthy', got '%s'", response["status"])
+	}
+}
+
+// TestHealthCheck_LivenessFresh tests that /health returns 200 while the liveness check is fresh
+func TestHealthCheck_LivenessFresh(t *testing.T) {
+	livenessProbe := server.NewLivenessProbe(func(ctx context.Context) error { return nil }, time.Hour, time.Second, time.Minute)
+	handler := NewHandler(&MockServiceProxy{}, WithLivenessProbe(livenessProbe))
+
+	request, _ := http.NewRequest("POST", "/health", nil)
+	responseRecorder := httptest.NewRecorder()
+	handler.HealthCheck(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestHealthCheck_LivenessDeadlock tests that /health returns 503 when a shared lock the
+// liveness check takes stays held, and recovers once it is released
+func TestHealthCheck_LivenessDeadlock(t *testing.T) {
+	var handler *Handler
+	livenessProbe := server.NewLivenessProbe(func(ctx context.Context) error {
+		return handler.LivenessCheck(ctx)
+	}, time.Millisecond, 5*time.Millisecond, 30*time.Millisecond)
+	handler = NewHandler(&MockServiceProxy{}, WithLivenessProbe(livenessProbe), WithReadinessCache(time.Minute), WithAnalyzeCoalescing(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go livenessProbe.Run(ctx)
+
+	getHealthStatus := func() int {
+		request, _ := http.NewRequest("GET", "/health", nil)
+		responseRecorder := httptest.NewRecorder()
+		handler.HealthCheck(responseRecorder, request)
+		return responseRecorder.Code
+	}
+
+	// Simulate a request goroutine stuck while holding the readiness cache lock
+	handler.readinessCache.mutex.Lock()
+	time.Sleep(100 * time.Millisecond)
+	if status := getHealthStatus(); status != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d while the lock is held, got %d", http.StatusServiceUnavailable, status)
+	}
+
+	handler.readinessCache.mutex.Unlock()
+	deadline := time.Now().Add(time.Second)
+	for getHealthStatus() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected /health to recover once the lock is released")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
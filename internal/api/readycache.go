@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultReadinessCacheTTL is how long /ready reuses the last dependency check
+const DefaultReadinessCacheTTL = 2 * time.Second
+
+// readinessCache holds the last /ready dependency check so frequent probes do not each hit
+// the upstreams. A background refresher keeps it fresh; a probe finding it older than the
+// TTL (e.g. before the refresher has run) checks synchronously instead.
+type readinessCache struct {
+	ttl time.Duration
+
+	mutex     sync.Mutex
+	results   map[string]string
+	checkedAt time.Time
+
+	// refreshMutex lets one probe run a synchronous check while concurrent probes wait for it
+	refreshMutex sync.Mutex
+}
+
+// WithReadinessCache makes /ready reuse dependency check results for ttl. Call
+// RunReadinessRefresher to refresh them in the background. A ttl of 0 or less checks on
+// every probe.
+func WithReadinessCache(ttl time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		if ttl <= 0 {
+			handler.readinessCache = nil
+			return
+		}
+		handler.readinessCache = &readinessCache{ttl: ttl}
+	}
+}
+
+// fresh returns the cached results when they were checked within the TTL
+func (cache *readinessCache) fresh() (map[string]string, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.results == nil || time.Since(cache.checkedAt) > cache.ttl {
+		return nil, false
+	}
+	return cache.results, true
+}
+
+// refresh runs check and stores its results
+func (cache *readinessCache) refresh(ctx context.Context, check func(ctx context.Context) map[string]string) map[string]string {
+	results := check(ctx)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.results = results
+	cache.checkedAt = time.Now()
+	return results
+}
+
+// get returns fresh cached results, checking synchronously when they are stale. Concurrent
+// probes finding stale results share a single check.
+func (cache *readinessCache) get(ctx context.Context, check func(ctx context.Context) map[string]string) map[string]string {
+	if results, ok := cache.fresh(); ok {
+		return results
+	}
+
+	cache.refreshMutex.Lock()
+	defer cache.refreshMutex.Unlock()
+	if results, ok := cache.fresh(); ok {
+		return results
+	}
+	return cache.refresh(ctx, check)
+}
+
+// dependencyStatus returns each configured dependency's status, from the readiness cache
+// when enabled. Cached checks are detached from the probe's cancellation so an abandoned
+// probe cannot cache its dependencies as down.
+func (handler *Handler) dependencyStatus(ctx context.Context) map[string]string {
+	if handler.readinessCache == nil {
+		return handler.checkDependencies(ctx)
+	}
+	return handler.readinessCache.get(context.WithoutCancel(ctx), handler.checkDependencies)
+}
+
+// RunReadinessRefresher re-checks the /ready dependencies twice per cache TTL until ctx is
+// done, so probes are answered from a fresh cache without waiting on upstreams. It returns
+// at once when the readiness cache is disabled or no dependencies are configured.
+func (handler *Handler) RunReadinessRefresher(ctx context.Context) {
+	if handler.readinessCache == nil || len(handler.readinessDependencies) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(handler.readinessCache.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		handler.readinessCache.refresh(ctx, handler.checkDependencies)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
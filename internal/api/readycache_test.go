@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/server"
+)
+
+// newCountingReadyHandler returns a handler checking data readiness, counting health checks
+func newCountingReadyHandler(dataChecks *atomic.Int32, options ...HandlerOption) *Handler {
+	mockProxy := &MockServiceProxy{
+		CheckDataHealthFunc: func() error {
+			dataChecks.Add(1)
+			return nil
+		},
+	}
+	options = append([]HandlerOption{
+		WithReadiness(server.NewReadiness()),
+		WithReadinessDependencies(map[string]bool{DependencyData: true}),
+	}, options...)
+	return NewHandler(mockProxy, options...)
+}
+
+// TestReady_CachedWithinTTL tests that rapid /ready calls within the cache TTL hit upstreams only once
+func TestReady_CachedWithinTTL(t *testing.T) {
+	var dataChecks atomic.Int32
+	handler := newCountingReadyHandler(&dataChecks, WithReadinessCache(time.Minute))
+
+	for range 5 {
+		if statusCode, status := getReadyStatus(t, handler); statusCode != http.StatusOK || status != "ready" {
+			t.Fatalf("Expected 200 ready, got %d %s", statusCode, status)
+		}
+	}
+
+	if checks := dataChecks.Load(); checks != 1 {
+		t.Errorf("Expected 1 upstream check, got %d", checks)
+	}
+}
+
+// TestReady_StaleCacheForcesCheck tests that results older than the TTL are re-checked
+func TestReady_StaleCacheForcesCheck(t *testing.T) {
+	var dataChecks atomic.Int32
+	handler := newCountingReadyHandler(&dataChecks, WithReadinessCache(20*time.Millisecond))
+
+	getReadyStatus(t, handler)
+	time.Sleep(40 * time.Millisecond)
+	getReadyStatus(t, handler)
+
+	if checks := dataChecks.Load(); checks != 2 {
+		t.Errorf("Expected 2 upstream checks, got %d", checks)
+	}
+}
+
+// TestReady_CacheDisabled tests that without a cache every probe checks upstreams
+func TestReady_CacheDisabled(t *testing.T) {
+	var dataChecks atomic.Int32
+	handler := newCountingReadyHandler(&dataChecks, WithReadinessCache(0))
+
+	getReadyStatus(t, handler)
+	getReadyStatus(t, handler)
+
+	if checks := dataChecks.Load(); checks != 2 {
+		t.Errorf("Expected 2 upstream checks, got %d", checks)
+	}
+}
+
+// TestRunReadinessRefresher tests that the refresher fills the cache so probes do not check upstreams themselves
+func TestRunReadinessRefresher(t *testing.T) {
+	var dataChecks atomic.Int32
+	handler := newCountingReadyHandler(&dataChecks, WithReadinessCache(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		handler.RunReadinessRefresher(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for dataChecks.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	getReadyStatus(t, handler)
+	if checks := dataChecks.Load(); checks != 1 {
+		t.Errorf("Expected only the refresher's check, got %d", checks)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// ParseEnabledRegions parses a comma-separated allowlist of regions this gateway serves.
+// Every entry must be a valid region; an empty spec returns nil, meaning all regions are served.
+func ParseEnabledRegions(spec string) (map[string]bool, error) {
+	var enabledRegions map[string]bool
+
+	for _, entry := range strings.Split(spec, ",") {
+		region := validation.NormalizeRegion(strings.TrimSpace(entry))
+		if region == "" {
+			continue
+		}
+
+		if !validation.ValidRegions[region] {
+			return nil, fmt.Errorf("invalid region %q in enabled regions", entry)
+		}
+
+		if enabledRegions == nil {
+			enabledRegions = make(map[string]bool)
+		}
+		enabledRegions[region] = true
+	}
+
+	return enabledRegions, nil
+}
+
+// WithEnabledRegions restricts the gateway to the given normalized regions. Valid regions
+// outside the allowlist are rejected with 403. A nil or empty set serves all regions.
+func WithEnabledRegions(enabledRegions map[string]bool) HandlerOption {
+	return func(handler *Handler) {
+		handler.enabledRegions = enabledRegions
+	}
+}
+
+// RegionMode controls how region codes outside the canonical short list are treated
+type RegionMode string
+
+const (
+	// RegionModeStrict rejects anything not in validation.ValidRegions (e.g. "na1")
+	RegionModeStrict RegionMode = "strict"
+	// RegionModeLenient maps Riot platform codes (e.g. "na1" to "na") before validating
+	RegionModeLenient RegionMode = "lenient"
+)
+
+// ParseRegionMode parses REGION_MODE; an empty value selects strict mode
+func ParseRegionMode(value string) (RegionMode, error) {
+	switch RegionMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RegionModeStrict:
+		return RegionModeStrict, nil
+	case RegionModeLenient:
+		return RegionModeLenient, nil
+	default:
+		return "", fmt.Errorf("invalid region mode %q (expected strict or lenient)", value)
+	}
+}
+
+// WithRegionMode sets whether platform-code region aliases are accepted
+func WithRegionMode(regionMode RegionMode) HandlerOption {
+	return func(handler *Handler) {
+		handler.regionMode = regionMode
+	}
+}
+
+// resolveRegion maps a platform-code alias to its canonical region in lenient mode, so
+// validation sees the canonical code; in strict mode the region is returned unchanged
+func (handler *Handler) resolveRegion(region string) string {
+	if handler.regionMode != RegionModeLenient {
+		return region
+	}
+	return validation.ResolveRegionAlias(region)
+}
+
+// isRegionServed reports whether a normalized region is served by this gateway
+func (handler *Handler) isRegionServed(region string) bool {
+	return len(handler.enabledRegions) == 0 || handler.enabledRegions[region]
+}
+
+// checkRegionServed writes a 403 and returns false when the region is not served
+func (handler *Handler) checkRegionServed(writer http.ResponseWriter, request *http.Request, region string) bool {
+	if handler.isRegionServed(region) {
+		return true
+	}
+
+	handler.writeError(writer, request, apierrors.RegionNotServed(region))
+	return false
+}
+
+// RegionInfo describes a supported region for clients building region pickers
+type RegionInfo struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Routing string `json:"routing"`
+	// Served is false for valid regions outside this gateway's ENABLED_REGIONS allowlist
+	Served bool `json:"served"`
+}
+
+// RegionsResponse lists the supported regions
+type RegionsResponse struct {
+	Regions []RegionInfo `json:"regions"`
+}
+
+// regionsCacheControl lets clients and CDNs cache the region list, which only changes on deploy
+const regionsCacheControl = "public, max-age=3600"
+
+// ListRegions returns every valid region with its display name and routing cluster, sorted by code
+func (handler *Handler) ListRegions(writer http.ResponseWriter, request *http.Request) {
+	regions := make([]RegionInfo, 0, len(validation.ValidRegions))
+	for region := range validation.ValidRegions {
+		regions = append(regions, RegionInfo{
+			Code:    region,
+			Name:    validation.RegionNames[region],
+			Routing: validation.RegionToRouting[region],
+			Served:  handler.isRegionServed(region),
+		})
+	}
+	sort.Slice(regions, func(left, right int) bool {
+		return regions[left].Code < regions[right].Code
+	})
+
+	writer.Header().Set("Cache-Control", regionsCacheControl)
+	handler.writeResponse(writer, request, http.StatusOK, RegionsResponse{Regions: regions})
+}
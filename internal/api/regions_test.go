@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestParseEnabledRegions tests parsing of the enabled regions allowlist
+func TestParseEnabledRegions(t *testing.T) {
+	enabledRegions, err := ParseEnabledRegions(" KR, jp ,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(enabledRegions) != 2 || !enabledRegions["kr"] || !enabledRegions["jp"] {
+		t.Errorf("Expected {kr, jp}, got %v", enabledRegions)
+	}
+}
+
+// TestParseEnabledRegions_Empty tests that an empty spec serves all regions
+func TestParseEnabledRegions_Empty(t *testing.T) {
+	enabledRegions, err := ParseEnabledRegions("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if enabledRegions != nil {
+		t.Errorf("Expected nil allowlist, got %v", enabledRegions)
+	}
+}
+
+// TestParseEnabledRegions_Invalid tests that unknown regions are rejected at startup
+func TestParseEnabledRegions_Invalid(t *testing.T) {
+	if _, err := ParseEnabledRegions("kr,atlantis"); err == nil {
+		t.Error("Expected error for invalid region")
+	}
+}
+
+// postSummonerForRegion sends a summoner request for the given region to the handler
+func postSummonerForRegion(handler *Handler, region string) *httptest.ResponseRecorder {
+	bodyBytes, _ := json.Marshal(map[string]string{"region": region, "gameName": "TestPlayer", "tagLine": "KR1"})
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, request)
+	return responseRecorder
+}
+
+// TestEnabledRegions_EnabledRegionPasses tests that an enabled region is served
+func TestEnabledRegions_EnabledRegionPasses(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithEnabledRegions(map[string]bool{"kr": true, "jp": true}))
+
+	responseRecorder := postSummonerForRegion(handler, "KR")
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestEnabledRegions_DisabledRegionForbidden tests that a valid but disabled region gets 403
+func TestEnabledRegions_DisabledRegionForbidden(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			t.Error("Expected upstream not to be called for a disabled region")
+			return nil, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithEnabledRegions(map[string]bool{"kr": true, "jp": true}))
+
+	responseRecorder := postSummonerForRegion(handler, "na")
+
+	if responseRecorder.Code != http.StatusForbidden {
+		t.Fatalf("Expected status code %d, got %d", http.StatusForbidden, responseRecorder.Code)
+	}
+
+	var errorResponse apierrors.ErrorResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if errorResponse.Error.Code != apierrors.ErrCodeRegionNotServed {
+		t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeRegionNotServed, errorResponse.Error.Code)
+	}
+}
+
+// TestEnabledRegions_InvalidRegionStillBadRequest tests that invalid regions keep failing validation with 400
+func TestEnabledRegions_InvalidRegionStillBadRequest(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithEnabledRegions(map[string]bool{"kr": true}))
+
+	responseRecorder := postSummonerForRegion(handler, "atlantis")
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestParseRegionMode tests parsing of REGION_MODE
+func TestParseRegionMode(t *testing.T) {
+	testCases := map[string]RegionMode{"": RegionModeStrict, "strict": RegionModeStrict, " Lenient ": RegionModeLenient}
+	for value, expectedMode := range testCases {
+		regionMode, err := ParseRegionMode(value)
+		if err != nil || regionMode != expectedMode {
+			t.Errorf("Expected %q to parse as '%s', got '%s' (err %v)", value, expectedMode, regionMode, err)
+		}
+	}
+
+	if _, err := ParseRegionMode("loose"); err == nil {
+		t.Error("Expected an error for an unknown region mode")
+	}
+}
+
+// TestRegionMode_PlatformCode tests that "na1" is mapped to "na" in lenient mode and rejected in strict mode
+func TestRegionMode_PlatformCode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		regionMode     RegionMode
+		expectedStatus int
+	}{
+		{name: "lenient", regionMode: RegionModeLenient, expectedStatus: http.StatusOK},
+		{name: "strict", regionMode: RegionModeStrict, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var upstreamRegion string
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					upstreamRegion = region
+					return &models.Summoner{PUUID: "test-puuid"}, nil
+				},
+			}
+			handler := NewHandler(mockProxy, WithRegionMode(testCase.regionMode))
+
+			responseRecorder := postSummonerForRegion(handler, "NA1")
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if testCase.expectedStatus == http.StatusOK && upstreamRegion != "na" {
+				t.Errorf("Expected upstream region 'na', got '%s'", upstreamRegion)
+			}
+		})
+	}
+}
+
+// TestEnabledRegions_BatchItemNotServed tests that disabled regions fail only their batch entry
+func TestEnabledRegions_BatchItemNotServed(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{{MatchID: "KR_1"}}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithEnabledRegions(map[string]bool{"kr": true}))
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"players": []map[string]interface{}{
+			{"region": "kr", "puuid": testPUUID("a")},
+			{"region": "na", "puuid": testPUUID("b")},
+		},
+	})
+	request, _ := http.NewRequest("POST", "/api/v1/matches/batch", bytes.NewBuffer(bodyBytes))
+	responseRecorder := httptest.NewRecorder()
+
+	handler.GetMatchesBatch(responseRecorder, request)
+
+	var response models.BatchMatchResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Results[0].Error != nil {
+		t.Errorf("Expected enabled region to succeed, got %+v", response.Results[0].Error)
+	}
+
+	if response.Results[1].Error == nil || response.Results[1].Error.Code != string(apierrors.ErrCodeRegionNotServed) {
+		t.Errorf("Expected REGION_NOT_SERVED for disabled region, got %+v", response.Results[1].Error)
+	}
+}
+
+// TestRouterRegionsEndpoint tests that GET /api/v1/regions lists all 16 regions with routing clusters
+func TestRouterRegionsEndpoint(t *testing.T) {
+	enabledRegions, _ := ParseEnabledRegions("na,euw")
+	handler := NewHandler(&MockServiceProxy{}, WithEnabledRegions(enabledRegions))
+	router := SetupRouter(&RouterConfig{Handler: handler, RequireOrgID: true})
+
+	request, _ := http.NewRequest("GET", "/api/v1/regions", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if responseRecorder.Header().Get("Cache-Control") != regionsCacheControl {
+		t.Errorf("Expected Cache-Control '%s', got '%s'", regionsCacheControl, responseRecorder.Header().Get("Cache-Control"))
+	}
+
+	var response RegionsResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Regions) != 16 {
+		t.Fatalf("Expected 16 regions, got %d", len(response.Regions))
+	}
+
+	expectedRouting := map[string]string{"na": "americas", "euw": "europe", "kr": "asia", "oce": "sea"}
+	for _, region := range response.Regions {
+		if region.Name == "" || region.Routing == "" {
+			t.Errorf("Expected name and routing for region '%s', got %+v", region.Code, region)
+		}
+		if routing, ok := expectedRouting[region.Code]; ok && region.Routing != routing {
+			t.Errorf("Expected region '%s' to route to '%s', got '%s'", region.Code, routing, region.Routing)
+		}
+		if region.Served != (region.Code == "na" || region.Code == "euw") {
+			t.Errorf("Expected region '%s' served to be %v", region.Code, !region.Served)
+		}
+	}
+}
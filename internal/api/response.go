@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// ResponseEnvelope selects how successful and error payloads are shaped
+type ResponseEnvelope string
+
+const (
+	// EnvelopeRaw returns payloads as-is (default, backward compatible)
+	EnvelopeRaw ResponseEnvelope = "raw"
+	// EnvelopeWrapped returns payloads as {data, error, meta}
+	EnvelopeWrapped ResponseEnvelope = "wrapped"
+)
+
+// ParseResponseEnvelope parses a RESPONSE_ENVELOPE value, defaulting to raw when empty
+func ParseResponseEnvelope(value string) (ResponseEnvelope, error) {
+	switch ResponseEnvelope(value) {
+	case "", EnvelopeRaw:
+		return EnvelopeRaw, nil
+	case EnvelopeWrapped:
+		return EnvelopeWrapped, nil
+	default:
+		return "", fmt.Errorf("invalid response envelope %q: expected raw or wrapped", value)
+	}
+}
+
+// WrappedResponse is the JSON structure returned to clients in wrapped envelope mode
+type WrappedResponse struct {
+	Data  interface{}            `json:"data"`
+	Error *apierrors.ErrorDetail `json:"error"`
+	Meta  ResponseMeta           `json:"meta"`
+}
+
+// ResponseMeta contains request metadata included in wrapped responses
+type ResponseMeta struct {
+	RequestID  string `json:"requestId,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	// Warnings lists non-fatal issues added with middleware.AddWarning (successful responses only)
+	Warnings []string `json:"warnings,omitempty"`
+	// Timings breaks down analyze latency by step when requested with ?timings=true
+	Timings *AnalyzeTimings `json:"timings,omitempty"`
+}
+
+// newResponseMeta builds response metadata from the request context
+func newResponseMeta(request *http.Request) ResponseMeta {
+	meta := ResponseMeta{
+		RequestID: middleware.RequestIDFromContext(request.Context()),
+	}
+
+	if startTime, ok := middleware.RequestStartFromContext(request.Context()); ok {
+		meta.DurationMs = time.Since(startTime).Milliseconds()
+	}
+
+	return meta
+}
+
+// writeResponse writes a successful JSON payload using the configured envelope
+func (handler *Handler) writeResponse(writer http.ResponseWriter, request *http.Request, statusCode int, payload interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+
+	if handler.envelope == EnvelopeWrapped {
+		meta := newResponseMeta(request)
+		meta.Warnings = middleware.WarningsFromContext(request.Context())
+		meta.Timings = analyzeTimingsFromContext(request.Context())
+		json.NewEncoder(writer).Encode(WrappedResponse{
+			Data: payload,
+			Meta: meta,
+		})
+		return
+	}
+
+	json.NewEncoder(writer).Encode(payload)
+}
+
+// writeError writes an APIError using the configured envelope. When the client message is
+// sanitized (production), the full message is logged so the detail is not lost.
+func (handler *Handler) writeError(writer http.ResponseWriter, request *http.Request, apiError *apierrors.APIError) {
+	if apiError.IsSanitized() {
+		logger := middleware.LoggerFromContext(request.Context())
+		logger.Error().
+			Str("error_code", string(apiError.Code)).
+			Msg("Error detail withheld from client: " + apiError.Message)
+	}
+
+	// problem+json has its own fixed shape, so it replaces the wrapped envelope too
+	if handler.envelope != EnvelopeWrapped || apierrors.WantsProblem(request) {
+		apierrors.WriteError(writer, request, apiError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(apiError.Status)
+
+	json.NewEncoder(writer).Encode(WrappedResponse{
+		Error: &apierrors.ErrorDetail{
+			Code:    apiError.Code,
+			Message: apiError.ClientMessage(),
+		},
+		Meta: newResponseMeta(request),
+	})
+}
+
+// writeProxyError writes an error returned by the service proxy, logging it with the
+// request-scoped logger. Unknown errors are wrapped as internal errors.
+func (handler *Handler) writeProxyError(writer http.ResponseWriter, request *http.Request, err error) {
+	logger := middleware.LoggerFromContext(request.Context())
+
+	// Check if the error is already an APIError
+	if apiErr, ok := err.(*apierrors.APIError); ok {
+		logger.Warn().Str("error_code", string(apiErr.Code)).Msg(apiErr.Message)
+		if apiErr.Upstream != nil && middleware.RawUpstreamRequested(request.Context()) {
+			writeRawUpstream(writer, apiErr.Upstream)
+			return
+		}
+		handler.writeError(writer, request, apiErr)
+		return
+	}
+
+	// Wrap unknown errors as internal errors
+	logger.Error().Err(err).Msg("Unexpected error from service proxy")
+	handler.writeError(writer, request, apierrors.InternalError("An unexpected error occurred"))
+}
+
+// writeRawUpstream writes an upstream error response with its original status code. The
+// body is passed through untouched when it is JSON and encoded as a JSON string otherwise,
+// so passthrough responses are always valid JSON.
+func writeRawUpstream(writer http.ResponseWriter, upstream *apierrors.UpstreamResponse) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(upstream.Status)
+
+	if json.Valid(upstream.Body) {
+		writer.Write(upstream.Body)
+		return
+	}
+
+	json.NewEncoder(writer).Encode(string(upstream.Body))
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newSummonerRequest builds a valid summoner request for envelope tests
+func newSummonerRequest() *http.Request {
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString(requestBody))
+	request.Header.Set(middleware.RequestIDHeader, "envelope-request-id")
+	return request
+}
+
+// TestParseResponseEnvelope tests parsing of the RESPONSE_ENVELOPE setting
+func TestParseResponseEnvelope(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected ResponseEnvelope
+	}{
+		{"", EnvelopeRaw},
+		{"raw", EnvelopeRaw},
+		{"wrapped", EnvelopeWrapped},
+	}
+
+	for _, testCase := range testCases {
+		envelope, err := ParseResponseEnvelope(testCase.value)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", testCase.value, err)
+		}
+		if envelope != testCase.expected {
+			t.Errorf("Expected '%s' for '%s', got '%s'", testCase.expected, testCase.value, envelope)
+		}
+	}
+
+	if _, err := ParseResponseEnvelope("xml"); err == nil {
+		t.Error("Expected error for unknown envelope mode")
+	}
+}
+
+// TestGetSummoner_RawEnvelope tests that raw mode returns the summoner object directly
+func TestGetSummoner_RawEnvelope(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithResponseEnvelope(EnvelopeRaw))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, newSummonerRequest())
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response["puuid"] != "test-puuid" {
+		t.Errorf("Expected raw summoner payload, got %v", response)
+	}
+
+	if _, hasData := response["data"]; hasData {
+		t.Error("Expected raw mode to not include a data envelope")
+	}
+}
+
+// TestGetSummoner_WrappedEnvelope tests that wrapped mode places the summoner under data with meta
+func TestGetSummoner_WrappedEnvelope(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithResponseEnvelope(EnvelopeWrapped))
+	wrappedHandler := middleware.RequestIDMiddleware(middleware.LoggingMiddleware(http.HandlerFunc(handler.GetSummoner)))
+
+	responseRecorder := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(responseRecorder, newSummonerRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response struct {
+		Data  models.Summoner        `json:"data"`
+		Error map[string]interface{} `json:"error"`
+		Meta  ResponseMeta           `json:"meta"`
+	}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Data.PUUID != "test-puuid" {
+		t.Errorf("Expected data.puuid 'test-puuid', got '%s'", response.Data.PUUID)
+	}
+
+	if response.Error != nil {
+		t.Errorf("Expected error to be null, got %v", response.Error)
+	}
+
+	if response.Meta.RequestID != "envelope-request-id" {
+		t.Errorf("Expected meta.requestId 'envelope-request-id', got '%s'", response.Meta.RequestID)
+	}
+}
+
+// TestGetSummoner_WrappedEnvelopeError tests that wrapped mode places errors under error with null data
+func TestGetSummoner_WrappedEnvelopeError(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithResponseEnvelope(EnvelopeWrapped))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString("invalid json"))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response["data"] != nil {
+		t.Errorf("Expected data to be null, got %v", response["data"])
+	}
+
+	errorDetail, ok := response["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected error object, got %v", response["error"])
+	}
+
+	if errorDetail["code"] != "INVALID_REQUEST_BODY" {
+		t.Errorf("Expected code 'INVALID_REQUEST_BODY', got '%v'", errorDetail["code"])
+	}
+
+	if _, hasMeta := response["meta"]; !hasMeta {
+		t.Error("Expected meta to be present in wrapped error response")
+	}
+}
+
+// TestGetSummoner_WrappedEnvelopeProblemError tests that a client accepting problem+json gets an
+// RFC 7807 document instead of the wrapped envelope
+func TestGetSummoner_WrappedEnvelopeProblemError(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithResponseEnvelope(EnvelopeWrapped))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString("invalid json"))
+	request.Header.Set("Accept", apierrors.ProblemContentType)
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, request)
+
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != apierrors.ProblemContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", apierrors.ProblemContentType, contentType)
+	}
+
+	var problem apierrors.ProblemDetails
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if problem.Status != http.StatusBadRequest || problem.Code != apierrors.ErrCodeInvalidRequestBody || problem.Instance != "/api/v1/summoner" {
+		t.Errorf("Unexpected problem document: %+v", problem)
+	}
+}
+
+// serveWithWarnings serves request with handlerFunc behind the warnings middleware the router installs
+func serveWithWarnings(handlerFunc http.HandlerFunc, writer http.ResponseWriter, request *http.Request) {
+	middleware.WarningsMiddleware(handlerFunc).ServeHTTP(writer, request)
+}
+
+// TestWriteResponse_Warnings tests that warnings added by a handler appear as Warning headers and in meta.warnings
+func TestWriteResponse_Warnings(t *testing.T) {
+	for _, envelope := range []ResponseEnvelope{EnvelopeRaw, EnvelopeWrapped} {
+		t.Run(string(envelope), func(t *testing.T) {
+			handler := NewHandler(&MockServiceProxy{}, WithResponseEnvelope(envelope))
+			handlerFunc := func(writer http.ResponseWriter, request *http.Request) {
+				middleware.AddWarning(request.Context(), "matches unavailable")
+				middleware.AddWarning(request.Context(), `ranked "stats" stale`)
+				handler.writeResponse(writer, request, http.StatusOK, map[string]string{"status": "ok"})
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			serveWithWarnings(handlerFunc, responseRecorder, newSummonerRequest())
+
+			expectedHeaders := []string{`199 opgl-gateway "matches unavailable"`, `199 opgl-gateway "ranked \"stats\" stale"`}
+			headers := responseRecorder.Header().Values(WarningHeader)
+			if len(headers) != 2 || headers[0] != expectedHeaders[0] || headers[1] != expectedHeaders[1] {
+				t.Errorf("Expected Warning headers %q, got %q", expectedHeaders, headers)
+			}
+
+			if envelope != EnvelopeWrapped {
+				return
+			}
+			var response struct {
+				Meta ResponseMeta `json:"meta"`
+			}
+			if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Meta.Warnings) != 2 || response.Meta.Warnings[0] != "matches unavailable" || response.Meta.Warnings[1] != `ranked "stats" stale` {
+				t.Errorf("Expected both warnings in meta.warnings, got %v", response.Meta.Warnings)
+			}
+		})
+	}
+}
+
+// TestWriteError_NoWarnings tests that warnings are not reported on error responses
+func TestWriteError_NoWarnings(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithResponseEnvelope(EnvelopeWrapped))
+	handlerFunc := func(writer http.ResponseWriter, request *http.Request) {
+		middleware.AddWarning(request.Context(), "matches unavailable")
+		handler.writeError(writer, request, apierrors.InternalError("boom"))
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	serveWithWarnings(handlerFunc, responseRecorder, newSummonerRequest())
+
+	if headers := responseRecorder.Header().Values(WarningHeader); len(headers) != 0 {
+		t.Errorf("Expected no Warning headers on an error response, got %q", headers)
+	}
+	if strings.Contains(responseRecorder.Body.String(), "warnings") {
+		t.Errorf("Expected no meta.warnings on an error response, got %s", responseRecorder.Body.String())
+	}
+}
+
+// TestWriteProxyError_SanitizedInProduction tests that upstream bodies are withheld from clients in production
+func TestWriteProxyError_SanitizedInProduction(t *testing.T) {
+	apierrors.SetEnvironment(apierrors.EnvironmentProduction)
+	defer apierrors.SetEnvironment(apierrors.EnvironmentDevelopment)
+
+	for _, envelope := range []ResponseEnvelope{EnvelopeRaw, EnvelopeWrapped} {
+		handler := NewHandler(&MockServiceProxy{}, WithResponseEnvelope(envelope))
+		request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+		responseRecorder := httptest.NewRecorder()
+
+		handler.writeProxyError(responseRecorder, request, apierrors.InternalError("panic: nil map in riot client (db=10.0.3.7)"))
+
+		if responseRecorder.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected status code %d, got %d", envelope, http.StatusInternalServerError, responseRecorder.Code)
+		}
+		if strings.Contains(responseRecorder.Body.String(), "10.0.3.7") {
+			t.Errorf("%s: expected internal detail to be redacted, got %s", envelope, responseRecorder.Body.String())
+		}
+		if !strings.Contains(responseRecorder.Body.String(), "INTERNAL_ERROR") {
+			t.Errorf("%s: expected the error code to be kept, got %s", envelope, responseRecorder.Body.String())
+		}
+	}
+}
@@ -1,14 +1,54 @@
 package api
 
 import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
 	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
 	"github.com/gorilla/mux"
 )
 
 // RouterConfig holds all dependencies for router setup
 type RouterConfig struct {
-	Handler         *Handler
-	RateLimitClient *middleware.RateLimitServiceClient
+	Handler           *Handler
+	RateLimitClient   *middleware.RateLimitServiceClient
+	PassthroughRoutes []PassthroughRoute
+	RequireOrgID      bool
+	Bulkhead          *middleware.Bulkhead
+	AdminToken        string
+	MetricsHandler    http.Handler
+	StatsHandler      http.Handler
+	// APIKeyHeaders lists the headers the API key may be sent in, in priority order (default X-API-Key)
+	APIKeyHeaders []string
+	// AnalyzeBulkhead optionally caps concurrent analyze orchestrations separately from Bulkhead
+	AnalyzeBulkhead *middleware.Bulkhead
+	// SchemaVersions lists the accepted X-API-Schema versions (default: only the current version)
+	SchemaVersions []string
+	// SlowRequestsHandler optionally serves recent slow-request samples on GET /debug/slow
+	SlowRequestsHandler http.Handler
+	// HTTPMetrics optionally records request body sizes per API endpoint
+	HTTPMetrics *metrics.HTTPMetrics
+	// DebugEcho mounts POST {prefix}/debug/echo, which shows how a request body is interpreted
+	DebugEcho bool
+}
+
+// apiVersion is a mounted API version with the handler serving its routes
+type apiVersion struct {
+	prefix  string
+	handler *Handler
+}
+
+// apiVersions lists the API versions mounted by the router. Versions share the proxy and
+// middleware; each gets its own handler so response shapes can evolve independently.
+// To add a version, derive its handler here.
+func apiVersions(handler *Handler) []apiVersion {
+	return []apiVersion{
+		{prefix: "/api/v1", handler: handler},
+		// v2 always uses the wrapped {data, error, meta} envelope
+		{prefix: "/api/v2", handler: handler.withEnvelope(EnvelopeWrapped)},
+	}
 }
 
 // SetupRouter configures all routes for the gateway
@@ -18,22 +58,159 @@ func SetupRouter(config *RouterConfig) *mux.Router {
 	// Health check endpoint - no rate limiting
 	router.HandleFunc("/health", config.Handler.HealthCheck).Methods("POST")
 
+	// Readiness probe for the load balancer - reports 503 while draining before shutdown
+	router.HandleFunc("/ready", config.Handler.Ready).Methods("GET")
+
+	// Prometheus metrics - not rate limited (restricted to internal networks in main)
+	if config.MetricsHandler != nil {
+		router.Handle("/metrics", config.MetricsHandler).Methods("GET")
+	}
+
+	// JSON metrics snapshot for deployments without Prometheus (restricted to internal networks in main)
+	if config.StatsHandler != nil {
+		router.Handle("/stats", config.StatsHandler).Methods("GET")
+	}
+
+	// Recent slow-request samples, only when enabled (restricted to internal networks in main)
+	if config.SlowRequestsHandler != nil {
+		router.Handle("/debug/slow", config.SlowRequestsHandler).Methods("GET")
+	}
+
+	for _, version := range apiVersions(config.Handler) {
+		registerAPIRoutes(router, config, version)
+	}
+
+	// Admin endpoints - token protected (and restricted to internal networks in main)
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middleware.AdminTokenMiddleware(config.AdminToken))
+	adminRouter.HandleFunc("/cache/warm", config.Handler.WarmCache).Methods("POST")
+
+	// Answer OPTIONS on every route with its Allow header; registered last so every route is covered
+	registerOptionsRoutes(router)
+
+	return router
+}
+
+// registerOptionsRoutes registers an OPTIONS handler for each routed path that responds 204
+// with an Allow header listing the path's methods. The handlers sit on the root router so
+// OPTIONS requests skip the subrouters' org, rate-limit and bulkhead middleware. CORS
+// preflights are answered earlier by CORSMiddleware.
+func registerOptionsRoutes(router *mux.Router) {
+	var pathTemplates []string
+	allowedMethods := make(map[string][]string)
+
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		pathTemplate, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		// Subrouter prefixes have no methods and are skipped
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		if _, seen := allowedMethods[pathTemplate]; !seen {
+			pathTemplates = append(pathTemplates, pathTemplate)
+		}
+		for _, method := range methods {
+			if !slices.Contains(allowedMethods[pathTemplate], method) {
+				allowedMethods[pathTemplate] = append(allowedMethods[pathTemplate], method)
+			}
+		}
+		return nil
+	})
+
+	for _, pathTemplate := range pathTemplates {
+		allowHeader := strings.Join(append(allowedMethods[pathTemplate], http.MethodOptions), ", ")
+		router.Path(pathTemplate).Methods(http.MethodOptions).HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Allow", allowHeader)
+			writer.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// registerAPIRoutes mounts the routes of one API version under its prefix
+func registerAPIRoutes(router *mux.Router, config *RouterConfig, version apiVersion) {
+	handler := version.handler
+
+	// Published error code contract and region list - public and not rate limited, so registered ahead of the subrouter
+	router.HandleFunc(version.prefix+"/error-codes", handler.ListErrorCodes).Methods("GET")
+	router.HandleFunc(version.prefix+"/regions", handler.ListRegions).Methods("GET")
+
 	// API routes subrouter
-	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter := router.PathPrefix(version.prefix).Subrouter()
+
+	// Record request body sizes per endpoint, counting bytes actually read while decoding
+	apiRouter.Use(middleware.RequestBodySizeMiddleware(config.HTTPMetrics))
 
-	// Apply rate limiting middleware if configured
+	// Collect non-fatal warnings from handlers into Warning headers and meta.warnings
+	apiRouter.Use(middleware.WarningsMiddleware)
+
+	// Tag requests with their organization so rate limits and logs are scoped per org
+	apiRouter.Use(middleware.OrgMiddleware(config.RequireOrgID))
+
+	// Reject request schema versions this deployment does not understand
+	schemaVersions := config.SchemaVersions
+	if len(schemaVersions) == 0 {
+		schemaVersions = []string{middleware.CurrentSchemaVersion}
+	}
+	apiRouter.Use(middleware.SchemaVersionMiddleware(schemaVersions))
+
+	// Admin-gated ?passthrough=true returns upstream errors without remapping, for debugging
+	apiRouter.Use(middleware.RawUpstreamMiddleware(config.AdminToken))
+
+	// Apply rate limiting middleware if configured; batches reserve one request per player
 	if config.RateLimitClient != nil {
-		apiRouter.Use(middleware.RateLimitMiddleware(config.RateLimitClient))
+		apiRouter.Use(handler.batchCostMiddleware)
+		apiRouter.Use(middleware.RateLimitMiddleware(config.RateLimitClient, config.APIKeyHeaders...))
+	}
+
+	// Cap concurrent API work after rate limiting so rejected requests never hold a slot
+	if config.Bulkhead != nil {
+		apiRouter.Use(config.Bulkhead.Middleware)
 	}
 
 	// Proxied data endpoints (rate limited)
-	apiRouter.HandleFunc("/summoner", config.Handler.GetSummoner).Methods("POST")
-	apiRouter.HandleFunc("/matches", config.Handler.GetMatches).Methods("POST")
+	apiRouter.HandleFunc("/summoner", handler.GetSummoner).Methods("POST")
+	apiRouter.HandleFunc("/matches", handler.GetMatches).Methods("POST")
+	apiRouter.HandleFunc("/matches/batch", handler.GetMatchesBatch).Methods("POST")
+	apiRouter.HandleFunc("/matches/ids", handler.GetMatchIDs).Methods("POST")
+	apiRouter.HandleFunc("/match/timeline", handler.GetMatchTimeline).Methods("POST")
 
-	// Orchestrated analysis endpoint (rate limited)
-	apiRouter.HandleFunc("/analyze", config.Handler.AnalyzePlayer).Methods("POST")
+	// Cheap Riot ID existence check before an analyze (rate limited)
+	apiRouter.HandleFunc("/exists", handler.CheckPlayerExists).Methods("POST")
 
-	return router
+	// Field-by-field validation for forms; ?partial=true skips fields not yet filled in
+	apiRouter.HandleFunc("/validate/summoner", handler.ValidateSummoner).Methods("POST")
+
+	// Orchestrated analysis endpoints (rate limited), isolated by their own concurrency cap
+	apiRouter.Handle("/analyze", limitAnalyze(config, handler.AnalyzePlayer)).Methods("POST")
+	apiRouter.Handle("/analyze/refresh", limitAnalyze(config, handler.RefreshAnalysis)).Methods("POST")
+
+	// Combined summoner + matches + ranked profile (rate limited)
+	apiRouter.HandleFunc("/profile", handler.GetProfile).Methods("POST")
+
+	// Head-to-head comparison of two players (rate limited)
+	apiRouter.HandleFunc("/compare", handler.ComparePlayers).Methods("POST")
+
+	// Integration debugging: echo the parsed and validated request without proxying (rate limited)
+	if config.DebugEcho {
+		apiRouter.HandleFunc("/debug/echo", handler.DebugEcho).Methods("POST")
+	}
+
+	// Whitelisted passthrough endpoints forwarded verbatim to opgl-data (rate limited)
+	for _, route := range config.PassthroughRoutes {
+		apiRouter.HandleFunc(route.GatewayPath, handler.Passthrough(route)).Methods("POST")
+	}
+}
+
+// limitAnalyze wraps an analyze handler with the analyze bulkhead when one is configured
+func limitAnalyze(config *RouterConfig, handlerFunc http.HandlerFunc) http.Handler {
+	if config.AnalyzeBulkhead == nil {
+		return handlerFunc
+	}
+	return config.AnalyzeBulkhead.Middleware(handlerFunc)
 }
 
 // SetupRouterSimple configures routes with minimal dependencies (for testing)
@@ -2,11 +2,16 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
 	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/proxy"
 )
 
 // TestSetupRouter tests that all routes are registered correctly
@@ -144,3 +149,313 @@ func TestRouterAllEndpointsUsePOST(t *testing.T) {
 	// Note: Subrouter endpoints return 404 for wrong methods due to gorilla/mux behavior
 	// This is acceptable as the endpoints are not exposed for wrong methods
 }
+
+// TestRouterOptionsUnknownPathWithCORS tests that a non-preflight OPTIONS request to an unknown path is not answered with 200
+func TestRouterOptionsUnknownPathWithCORS(t *testing.T) {
+	mockProxy := &MockServiceProxy{}
+	handler := NewHandler(mockProxy)
+	router := middleware.CORSMiddleware(SetupRouterSimple(handler, nil))
+
+	request, _ := http.NewRequest("OPTIONS", "/api/v1/nonexistent", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code == http.StatusOK {
+		t.Errorf("Expected OPTIONS to unknown path to not return %d", http.StatusOK)
+	}
+}
+
+// TestRouterErrorCodesEndpoint tests that the error code registry is public even when org IDs are required
+func TestRouterErrorCodesEndpoint(t *testing.T) {
+	mockProxy := &MockServiceProxy{}
+	handler := NewHandler(mockProxy)
+	router := SetupRouter(&RouterConfig{Handler: handler, RequireOrgID: true})
+
+	request, _ := http.NewRequest("GET", "/api/v1/error-codes", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestRouterAPIVersions tests that v1 and v2 both route to their own handlers
+func TestRouterAPIVersions(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid", Name: "TestPlayer"}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+	router := SetupRouterSimple(handler, nil)
+
+	testCases := []struct {
+		path          string
+		expectWrapped bool
+	}{
+		{"/api/v1/summoner", false},
+		{"/api/v2/summoner", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			request, _ := http.NewRequest("POST", testCase.path, bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`))
+			responseRecorder := httptest.NewRecorder()
+
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+			}
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			_, wrapped := response["data"]
+			if wrapped != testCase.expectWrapped {
+				t.Errorf("Expected wrapped=%v, got response %v", testCase.expectWrapped, response)
+			}
+		})
+	}
+}
+
+// TestRouterAPIVersions_V1EnvelopeUnchanged tests that deriving the v2 handler does not alter v1
+func TestRouterAPIVersions_V1EnvelopeUnchanged(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+	SetupRouterSimple(handler, nil)
+
+	if handler.envelope != EnvelopeRaw {
+		t.Errorf("Expected v1 handler envelope to stay '%s', got '%s'", EnvelopeRaw, handler.envelope)
+	}
+}
+
+// TestRouterMetricsEndpoint tests that /metrics is mounted only when a metrics handler is configured
+func TestRouterMetricsEndpoint(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	testCases := []struct {
+		name           string
+		metricsHandler http.Handler
+		expectedStatus int
+	}{
+		{"configured", metrics.NewRegistry().Handler(), http.StatusOK},
+		{"not configured", nil, http.StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := SetupRouter(&RouterConfig{Handler: handler, MetricsHandler: testCase.metricsHandler})
+
+			request, _ := http.NewRequest("GET", "/metrics", nil)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+// TestRouterStatsEndpoint tests that /stats is mounted only when a stats handler is configured
+func TestRouterStatsEndpoint(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	testCases := []struct {
+		name           string
+		statsHandler   http.Handler
+		expectedStatus int
+	}{
+		{"configured", metrics.NewRegistry().StatsHandler(nil), http.StatusOK},
+		{"not configured", nil, http.StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := SetupRouter(&RouterConfig{Handler: handler, StatsHandler: testCase.statsHandler})
+
+			request, _ := http.NewRequest("GET", "/stats", nil)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+// TestRouterSlowRequestsEndpoint tests that GET /debug/slow is only mounted when the slow-request log is enabled
+func TestRouterSlowRequestsEndpoint(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	testCases := []struct {
+		name                string
+		slowRequestsHandler http.Handler
+		expectedStatus      int
+	}{
+		{"enabled", middleware.NewSlowRequestRecorder(time.Second, 10).Handler(), http.StatusOK},
+		{"disabled", nil, http.StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := SetupRouter(&RouterConfig{Handler: handler, SlowRequestsHandler: testCase.slowRequestsHandler})
+
+			request, _ := http.NewRequest("GET", "/debug/slow", nil)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+// TestRouterAnalyzeBulkhead tests that a saturated analyze cap rejects analyze but not summoner requests
+func TestRouterAnalyzeBulkhead(t *testing.T) {
+	analyzeStarted := make(chan struct{})
+	releaseAnalyze := make(chan struct{})
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			return []models.Match{}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			close(analyzeStarted)
+			<-releaseAnalyze
+			return &models.AnalysisResult{}, nil
+		},
+	}
+	router := SetupRouter(&RouterConfig{
+		Handler:         NewHandler(mockProxy),
+		AnalyzeBulkhead: middleware.NewBulkhead(1, 0),
+	})
+
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	serve := func(path string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest("POST", path, bytes.NewBufferString(requestBody))
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	// Saturate the analyze cap with one in-flight analysis
+	firstAnalyze := make(chan int)
+	go func() {
+		firstAnalyze <- serve("/api/v1/analyze").Code
+	}()
+	<-analyzeStarted
+
+	rejected := serve("/api/v1/analyze")
+	if rejected.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected analyze at capacity to return %d, got %d", http.StatusServiceUnavailable, rejected.Code)
+	}
+	if rejected.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on analyze capacity rejection")
+	}
+
+	if summoner := serve("/api/v1/summoner"); summoner.Code != http.StatusOK {
+		t.Errorf("Expected summoner to be unaffected with status %d, got %d", http.StatusOK, summoner.Code)
+	}
+
+	close(releaseAnalyze)
+	if code := <-firstAnalyze; code != http.StatusOK {
+		t.Errorf("Expected in-flight analyze to complete with %d, got %d", http.StatusOK, code)
+	}
+}
+
+// TestRouterPassthroughMode tests that passthrough mode returns the exact upstream 404 while
+// normal mode remaps it to the gateway error format
+func TestRouterPassthroughMode(t *testing.T) {
+	upstreamBody := `{"detail":"account not found","upstreamCode":"RIOT_404"}`
+	dataService := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte(upstreamBody))
+	}))
+	defer dataService.Close()
+
+	handler := NewHandler(proxy.NewServiceProxy(dataService.URL, dataService.URL))
+	router := SetupRouter(&RouterConfig{Handler: handler, AdminToken: "admin-secret"})
+
+	sendSummonerRequest := func(path string, adminToken string) *httptest.ResponseRecorder {
+		requestBody := `{"region":"na","gameName":"Missing","tagLine":"NA1"}`
+		request, _ := http.NewRequest("POST", path, bytes.NewBufferString(requestBody))
+		if adminToken != "" {
+			request.Header.Set(middleware.AdminTokenHeader, adminToken)
+		}
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	normal := sendSummonerRequest("/api/v1/summoner", "")
+	if normal.Code != http.StatusNotFound {
+		t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, normal.Code)
+	}
+	var errorResponse map[string]map[string]interface{}
+	if err := json.NewDecoder(normal.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errorResponse["error"]["code"] != "PLAYER_NOT_FOUND" {
+		t.Errorf("Expected remapped code 'PLAYER_NOT_FOUND', got '%v'", errorResponse["error"]["code"])
+	}
+
+	passthrough := sendSummonerRequest("/api/v1/summoner?passthrough=true", "admin-secret")
+	if passthrough.Code != http.StatusNotFound {
+		t.Fatalf("Expected upstream status code %d, got %d", http.StatusNotFound, passthrough.Code)
+	}
+	if passthrough.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got '%s'", passthrough.Header().Get("Content-Type"))
+	}
+	if passthrough.Body.String() != upstreamBody {
+		t.Errorf("Expected upstream body %s, got %s", upstreamBody, passthrough.Body.String())
+	}
+
+	denied := sendSummonerRequest("/api/v1/summoner?passthrough=true", "")
+	if denied.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d without admin token, got %d", http.StatusForbidden, denied.Code)
+	}
+}
+
+// TestRouterOptionsAllowHeader tests that plain OPTIONS requests get 204 with the route's methods in Allow
+func TestRouterOptionsAllowHeader(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+	router := middleware.CORSMiddleware(SetupRouter(&RouterConfig{Handler: handler, RequireOrgID: true}))
+
+	testCases := []struct {
+		path          string
+		expectedAllow string
+	}{
+		{"/api/v1/summoner", "POST, OPTIONS"},
+		{"/api/v2/analyze", "POST, OPTIONS"},
+		{"/api/v1/regions", "GET, OPTIONS"},
+		{"/health", "POST, OPTIONS"},
+		{"/ready", "GET, OPTIONS"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			request, _ := http.NewRequest("OPTIONS", testCase.path, nil)
+			responseRecorder := httptest.NewRecorder()
+
+			router.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusNoContent {
+				t.Fatalf("Expected status code %d, got %d", http.StatusNoContent, responseRecorder.Code)
+			}
+			if allow := responseRecorder.Header().Get("Allow"); allow != testCase.expectedAllow {
+				t.Errorf("Expected Allow '%s', got '%s'", testCase.expectedAllow, allow)
+			}
+		})
+	}
+}
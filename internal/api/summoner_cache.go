@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// CacheStatusHeader reports how a cached endpoint was served
+const CacheStatusHeader = "X-Cache"
+
+// summonerCacheKey builds the cache key for a summoner lookup, scoped to an org. The Riot
+// ID is case-folded so spellings differing only in case share an entry.
+func summonerCacheKey(orgID string, region string, gameName string, tagLine string) string {
+	return orgID + "|" + region + "|" + validation.FoldRiotID(gameName) + "#" + validation.FoldRiotID(tagLine)
+}
+
+// fetchSummoner looks up a summoner in opgl-data using the case-folded Riot ID. A not-found
+// error is reported with the Riot ID as the client typed it.
+func (handler *Handler) fetchSummoner(ctx context.Context, region string, gameName string, tagLine string) (*models.Summoner, error) {
+	summoner, err := handler.serviceProxy.GetSummonerByRiotID(ctx, region, validation.FoldRiotID(gameName), validation.FoldRiotID(tagLine))
+	if isPlayerNotFound(err) {
+		notFound := apierrors.PlayerNotFound(gameName, tagLine)
+		notFound.Upstream = err.(*apierrors.APIError).Upstream
+		return nil, notFound
+	}
+	return summoner, err
+}
+
+// lookupSummoner fetches a summoner through the summoner cache when one is configured,
+// reporting X-Cache: HIT or MISS. If opgl-data fails and a stale entry is still within the
+// stale window, the stale entry is returned with X-Cache: STALE instead of the error.
+func (handler *Handler) lookupSummoner(writer http.ResponseWriter, request *http.Request, region string, gameName string, tagLine string) (*models.Summoner, error) {
+	if handler.summonerCache == nil {
+		return handler.fetchSummoner(request.Context(), region, gameName, tagLine)
+	}
+
+	cacheKey := summonerCacheKey(middleware.OrgIDFromContext(request.Context()), region, gameName, tagLine)
+
+	if cached, found := handler.summonerCache.Get(cacheKey); found {
+		handler.cacheMetrics.Hit(metrics.CacheSummoner)
+		writer.Header().Set(CacheStatusHeader, "HIT")
+		return cached.(*models.Summoner), nil
+	}
+
+	handler.cacheMetrics.Miss(metrics.CacheSummoner)
+	writer.Header().Set(CacheStatusHeader, "MISS")
+
+	summoner, err := handler.fetchSummoner(request.Context(), region, gameName, tagLine)
+	if err == nil {
+		handler.summonerCache.Set(cacheKey, summoner)
+		return summoner, nil
+	}
+
+	// Only upstream failures fall back to stale data; client errors such as 404 are returned as-is
+	if !isUpstreamFailure(err) {
+		return nil, err
+	}
+
+	stale, found := handler.summonerCache.GetStale(cacheKey)
+	if !found {
+		return nil, err
+	}
+
+	logger := middleware.LoggerFromContext(request.Context())
+	logger.Warn().Err(err).Str("cache_key", cacheKey).Msg("Serving stale summoner after upstream failure")
+	writer.Header().Set(CacheStatusHeader, "STALE")
+	middleware.AddWarning(request.Context(), "summoner data may be out of date; opgl-data is unavailable")
+
+	return stale.(*models.Summoner), nil
+}
+
+// refreshSummoner fetches a summoner from opgl-data without consulting the summoner cache,
+// then stores the result in the cache when one is configured
+func (handler *Handler) refreshSummoner(writer http.ResponseWriter, request *http.Request, region string, gameName string, tagLine string) (*models.Summoner, error) {
+	summoner, err := handler.fetchSummoner(request.Context(), region, gameName, tagLine)
+	if err != nil || handler.summonerCache == nil {
+		return summoner, err
+	}
+
+	cacheKey := summonerCacheKey(middleware.OrgIDFromContext(request.Context()), region, gameName, tagLine)
+	handler.summonerCache.Set(cacheKey, summoner)
+	return summoner, nil
+}
+
+// isUpstreamFailure reports whether err indicates the upstream was unavailable or erroring,
+// as opposed to a definitive client-facing answer like "not found"
+func isUpstreamFailure(err error) bool {
+	if apiErr, ok := err.(*apierrors.APIError); ok {
+		return apiErr.Status >= http.StatusInternalServerError
+	}
+	return true
+}
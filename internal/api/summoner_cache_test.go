@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestGetSummoner_ServesStaleOnUpstreamFailure tests that a stale entry is served when opgl-data fails
+func TestGetSummoner_ServesStaleOnUpstreamFailure(t *testing.T) {
+	upstreamDown := false
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			if upstreamDown {
+				return nil, apierrors.DataServiceError("Unable to connect to data service")
+			}
+			return &models.Summoner{PUUID: "cached-puuid", Name: "TestPlayer"}, nil
+		},
+	}
+
+	// A tiny TTL makes the entry stale almost immediately while the stale window keeps it servable
+	summonerCache := cache.NewMemoryCache(time.Nanosecond, time.Hour)
+	handler := NewHandler(mockProxy, WithSummonerCache(summonerCache))
+
+	// Populate the cache
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, newSummonerRequest())
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected initial lookup to succeed, got %d", responseRecorder.Code)
+	}
+
+	time.Sleep(time.Millisecond)
+	upstreamDown = true
+
+	responseRecorder = httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, newSummonerRequest())
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected stale summoner to be served with %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if responseRecorder.Header().Get(CacheStatusHeader) != "STALE" {
+		t.Errorf("Expected X-Cache 'STALE', got '%s'", responseRecorder.Header().Get(CacheStatusHeader))
+	}
+
+	var response models.Summoner
+	json.NewDecoder(responseRecorder.Body).Decode(&response)
+	if response.PUUID != "cached-puuid" {
+		t.Errorf("Expected stale PUUID 'cached-puuid', got '%s'", response.PUUID)
+	}
+}
+
+// TestGetSummoner_NoStaleForNotFound tests that a 404 from opgl-data is not masked by stale data
+func TestGetSummoner_NoStaleForNotFound(t *testing.T) {
+	playerDeleted := false
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			if playerDeleted {
+				return nil, apierrors.PlayerNotFound(gameName, tagLine)
+			}
+			return &models.Summoner{PUUID: "cached-puuid"}, nil
+		},
+	}
+
+	summonerCache := cache.NewMemoryCache(time.Nanosecond, time.Hour)
+	handler := NewHandler(mockProxy, WithSummonerCache(summonerCache))
+
+	handler.GetSummoner(httptest.NewRecorder(), newSummonerRequest())
+	time.Sleep(time.Millisecond)
+	playerDeleted = true
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, newSummonerRequest())
+
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, responseRecorder.Code)
+	}
+}
+
+// TestGetSummoner_UpstreamFailureWithoutCacheEntry tests that errors surface when nothing is cached
+func TestGetSummoner_UpstreamFailureWithoutCacheEntry(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return nil, apierrors.DataServiceError("Unable to connect to data service")
+		},
+	}
+
+	handler := NewHandler(mockProxy, WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, newSummonerRequest())
+
+	if responseRecorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadGateway, responseRecorder.Code)
+	}
+}
+
+// TestGetSummoner_FreshCacheHit tests that fresh entries are served without calling opgl-data
+func TestGetSummoner_FreshCacheHit(t *testing.T) {
+	callCount := 0
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			callCount++
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy, WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)))
+
+	handler.GetSummoner(httptest.NewRecorder(), newSummonerRequest())
+	handler.GetSummoner(httptest.NewRecorder(), newSummonerRequest())
+
+	if callCount != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", callCount)
+	}
+}
+
+// TestSummonerCacheKey_CaseInsensitive tests that Riot IDs differing only in case share a cache key
+func TestSummonerCacheKey_CaseInsensitive(t *testing.T) {
+	if summonerCacheKey("", "kr", "Faker", "KR1") != summonerCacheKey("", "kr", "faker", "kr1") {
+		t.Error("Expected 'Faker#KR1' and 'faker#kr1' to produce the same cache key")
+	}
+}
+
+// TestGetSummoner_CaseInsensitiveCacheHit tests that a differently-cased lookup is served from cache
+// and that the upstream display name is returned unchanged
+func TestGetSummoner_CaseInsensitiveCacheHit(t *testing.T) {
+	callCount := 0
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			callCount++
+			return &models.Summoner{PUUID: "faker-puuid", Name: "Faker"}, nil
+		},
+	}
+
+	handler := NewHandler(mockProxy, WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)))
+
+	for _, body := range []string{
+		`{"region":"kr","gameName":"Faker","tagLine":"KR1"}`,
+		`{"region":"kr","gameName":"faker","tagLine":"kr1"}`,
+	} {
+		request, _ := http.NewRequest("POST", "/api/v1/summoner", bytes.NewBufferString(body))
+		responseRecorder := httptest.NewRecorder()
+		handler.GetSummoner(responseRecorder, request)
+
+		var response models.Summoner
+		json.NewDecoder(responseRecorder.Body).Decode(&response)
+		if response.Name != "Faker" {
+			t.Errorf("Expected display name 'Faker', got '%s'", response.Name)
+		}
+	}
+
+	if callCount != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", callCount)
+	}
+}
+
+// TestGetSummoner_NotFoundKeepsDisplayCase tests that not-found errors echo the Riot ID as typed
+func TestGetSummoner_NotFoundKeepsDisplayCase(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return nil, apierrors.PlayerNotFound(gameName, tagLine)
+		},
+	}
+
+	handler := NewHandler(mockProxy)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner",
+		bytes.NewBufferString(`{"region":"kr","gameName":"Faker","tagLine":"KR1"}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.GetSummoner(responseRecorder, request)
+
+	if !strings.Contains(responseRecorder.Body.String(), "Faker#KR1") {
+		t.Errorf("Expected error to mention 'Faker#KR1', got '%s'", responseRecorder.Body.String())
+	}
+}
+
+// TestGetSummoner_CacheMetrics tests that a miss then a hit update the counters and X-Cache header
+func TestGetSummoner_CacheMetrics(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+	}
+
+	cacheMetrics := metrics.NewCacheMetrics(metrics.NewRegistry())
+	handler := NewHandler(mockProxy,
+		WithSummonerCache(cache.NewMemoryCache(time.Minute, time.Hour)),
+		WithCacheMetrics(cacheMetrics),
+	)
+
+	for index, expected := range []struct {
+		header string
+		hits   float64
+		misses float64
+	}{
+		{"MISS", 0, 1},
+		{"HIT", 1, 1},
+	} {
+		responseRecorder := httptest.NewRecorder()
+		handler.GetSummoner(responseRecorder, newSummonerRequest())
+
+		if responseRecorder.Header().Get(CacheStatusHeader) != expected.header {
+			t.Errorf("Request %d: expected X-Cache '%s', got '%s'", index, expected.header, responseRecorder.Header().Get(CacheStatusHeader))
+		}
+		if cacheMetrics.Hits(metrics.CacheSummoner) != expected.hits || cacheMetrics.Misses(metrics.CacheSummoner) != expected.misses {
+			t.Errorf("Request %d: expected %g hits and %g misses, got %g and %g", index, expected.hits, expected.misses,
+				cacheMetrics.Hits(metrics.CacheSummoner), cacheMetrics.Misses(metrics.CacheSummoner))
+		}
+	}
+}
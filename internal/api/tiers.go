@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// DefaultAnalyzeTierModes gives free keys quick analysis and premium keys deep analysis by default
+const DefaultAnalyzeTierModes = "free=quick|standard,premium=deep|standard|quick"
+
+// AnalyzeTierModes is the analyze mode policy of one API key tier
+type AnalyzeTierModes struct {
+	// DefaultMode is used when an analyze request does not set a mode
+	DefaultMode string
+	// AllowedModes are the modes the tier may request explicitly
+	AllowedModes map[string]bool
+}
+
+// ParseAnalyzeTierModes parses a comma-separated list of tier=mode|mode... entries. The first
+// mode of each entry is the tier's default and every listed mode is allowed, e.g.
+// "free=quick|standard" defaults free keys to quick and forbids deep. "none" (or an empty
+// value) disables tier policies.
+func ParseAnalyzeTierModes(spec string) (map[string]AnalyzeTierModes, error) {
+	tierModes := make(map[string]AnalyzeTierModes)
+	if strings.TrimSpace(spec) == "none" {
+		return tierModes, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		tier, modeList, found := strings.Cut(entry, "=")
+		tier = strings.TrimSpace(tier)
+		if !found || tier == "" || strings.TrimSpace(modeList) == "" {
+			return nil, fmt.Errorf("invalid analyze tier modes %q: expected tier=mode|mode", entry)
+		}
+
+		policy := AnalyzeTierModes{AllowedModes: make(map[string]bool)}
+		for _, mode := range strings.Split(modeList, "|") {
+			mode = strings.TrimSpace(mode)
+			if _, known := validation.AnalyzeModes[mode]; !known {
+				return nil, fmt.Errorf("invalid analyze tier modes %q: unknown mode %q", entry, mode)
+			}
+			if policy.DefaultMode == "" {
+				policy.DefaultMode = mode
+			}
+			policy.AllowedModes[mode] = true
+		}
+		tierModes[tier] = policy
+	}
+
+	return tierModes, nil
+}
+
+// WithAnalyzeTierModes sets per-tier analyze mode defaults and permissions, keyed by the API
+// key tier the auth service reports. Requests without a tier, or with a tier not listed,
+// keep DefaultAnalyzeMode and may request any mode.
+func WithAnalyzeTierModes(tierModes map[string]AnalyzeTierModes) HandlerOption {
+	return func(handler *Handler) {
+		handler.analyzeTierModes = tierModes
+	}
+}
+
+// applyTierMode fills in the tier's default mode when options set none, and rejects an
+// explicit mode the tier may not use with 403
+func (handler *Handler) applyTierMode(ctx context.Context, options *validation.AnalyzeOptions) *apierrors.APIError {
+	tier := middleware.APIKeyTierFromContext(ctx)
+	policy, found := handler.analyzeTierModes[tier]
+	if tier == "" || !found {
+		return nil
+	}
+
+	if options.Mode == "" {
+		options.Mode = policy.DefaultMode
+		return nil
+	}
+
+	if !policy.AllowedModes[options.Mode] {
+		return apierrors.Forbidden(fmt.Sprintf("mode %q is not available for the %s tier", options.Mode, tier))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newTieredAuthServer returns an auth service stub that allows every request and reports the
+// API key itself as the key's tier
+func newTieredAuthServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var checkRequest struct {
+			APIKey string `json:"apiKey"`
+		}
+		json.NewDecoder(request.Body).Decode(&checkRequest)
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(map[string]interface{}{
+			"allowed":   true,
+			"limit":     100,
+			"remaining": 99,
+			"tier":      checkRequest.APIKey,
+		})
+	}))
+}
+
+// TestAnalyzePlayer_TierModes tests tier-based mode defaulting and enforcement of tier mode permissions
+func TestAnalyzePlayer_TierModes(t *testing.T) {
+	authServer := newTieredAuthServer()
+	defer authServer.Close()
+
+	tierModes, err := ParseAnalyzeTierModes(DefaultAnalyzeTierModes)
+	if err != nil {
+		t.Fatalf("Expected default tier modes to parse, got %v", err)
+	}
+
+	testCases := []struct {
+		name           string
+		tier           string
+		options        string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"free defaults to quick", "free", ``, http.StatusOK, 5},
+		{"premium defaults to deep", "premium", ``, http.StatusOK, 50},
+		{"unknown tier keeps the default mode", "enterprise", ``, http.StatusOK, 20},
+		{"explicit mode overrides the tier default", "premium", `,"mode":"quick"`, http.StatusOK, 5},
+		{"free may request standard", "free", `,"mode":"standard"`, http.StatusOK, 20},
+		{"free may not request deep", "free", `,"mode":"deep"`, http.StatusForbidden, 0},
+		{"unknown tier may request deep", "enterprise", `,"mode":"deep"`, http.StatusOK, 50},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requestedCount := 0
+			mockProxy := &MockServiceProxy{
+				GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+					return &models.Summoner{PUUID: "test-puuid"}, nil
+				},
+				GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+					requestedCount = count
+					return []models.Match{}, nil
+				},
+				AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+					return &models.AnalysisResult{}, nil
+				},
+			}
+			handler := NewHandler(mockProxy, WithAnalyzeTierModes(tierModes))
+			rateLimited := middleware.RateLimitMiddleware(middleware.NewRateLimitServiceClient(authServer.URL))(http.HandlerFunc(handler.AnalyzePlayer))
+
+			requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"` + testCase.options + `}`
+			request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(requestBody))
+			request.Header.Set(middleware.DefaultAPIKeyHeader, testCase.tier)
+			responseRecorder := httptest.NewRecorder()
+			rateLimited.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", testCase.expectedStatus, responseRecorder.Code, responseRecorder.Body.String())
+			}
+			if requestedCount != testCase.expectedCount {
+				t.Errorf("Expected %d matches requested, got %d", testCase.expectedCount, requestedCount)
+			}
+			if testCase.expectedStatus == http.StatusForbidden && !strings.Contains(responseRecorder.Body.String(), "FORBIDDEN") {
+				t.Errorf("Expected FORBIDDEN error code, got %s", responseRecorder.Body.String())
+			}
+		})
+	}
+}
+
+// TestAnalyzePlayer_NoTier tests that requests without a tier keep the default mode and may request any mode
+func TestAnalyzePlayer_NoTier(t *testing.T) {
+	tierModes, _ := ParseAnalyzeTierModes(DefaultAnalyzeTierModes)
+	requestedCount := 0
+	mockProxy := &MockServiceProxy{
+		GetSummonerByRiotIDFunc: func(region, gameName, tagLine string) (*models.Summoner, error) {
+			return &models.Summoner{PUUID: "test-puuid"}, nil
+		},
+		GetMatchesByPUUIDFunc: func(region, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+			requestedCount = count
+			return []models.Match{}, nil
+		},
+		AnalyzePlayerFunc: func(summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
+			return &models.AnalysisResult{}, nil
+		},
+	}
+	handler := NewHandler(mockProxy, WithAnalyzeTierModes(tierModes))
+
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1","mode":"deep"}`))
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if requestedCount != 50 {
+		t.Errorf("Expected 50 matches requested, got %d", requestedCount)
+	}
+}
+
+// TestParseAnalyzeTierModes tests parsing of per-tier analyze mode policies
+func TestParseAnalyzeTierModes(t *testing.T) {
+	tierModes, err := ParseAnalyzeTierModes(" free = quick | standard , premium=deep")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tierModes["free"].DefaultMode != "quick" || !tierModes["free"].AllowedModes["standard"] || tierModes["free"].AllowedModes["deep"] {
+		t.Errorf("Unexpected free tier policy: %+v", tierModes["free"])
+	}
+	if tierModes["premium"].DefaultMode != "deep" || len(tierModes["premium"].AllowedModes) != 1 {
+		t.Errorf("Unexpected premium tier policy: %+v", tierModes["premium"])
+	}
+
+	if tierModes, err := ParseAnalyzeTierModes("none"); err != nil || len(tierModes) != 0 {
+		t.Errorf("Expected no tier policies for none, got %v (%v)", tierModes, err)
+	}
+
+	for _, invalid := range []string{"free", "free=", "=quick", "free=turbo", "free=quick|"} {
+		if _, err := ParseAnalyzeTierModes(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// GetMatchTimeline handles requests for the minute-by-minute timeline of a single match
+func (handler *Handler) GetMatchTimeline(writer http.ResponseWriter, request *http.Request) {
+	var timelineRequest validation.MatchTimelineRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&timelineRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	timelineRequest.Region = handler.resolveRegion(timelineRequest.Region)
+	validationResult := validation.ValidateMatchTimelineRequest(&timelineRequest)
+	if !validationResult.IsValid() {
+		handler.writeError(writer, request, apierrors.ValidationFailed(validationResult.GetErrorMessages()))
+		return
+	}
+
+	normalizedRegion := validation.NormalizeRegion(timelineRequest.Region)
+	if !handler.checkRegionServed(writer, request, normalizedRegion) {
+		return
+	}
+
+	timeline, err := handler.serviceProxy.GetMatchTimeline(request.Context(), normalizedRegion, timelineRequest.MatchID)
+	if err != nil {
+		handler.writeProxyError(writer, request, err)
+		return
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, timeline)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newTimelineRequest builds a POST /api/v1/match/timeline request with the given body
+func newTimelineRequest(requestBody string) *http.Request {
+	request, _ := http.NewRequest("POST", "/api/v1/match/timeline", bytes.NewBufferString(requestBody))
+	return request
+}
+
+// TestGetMatchTimeline_Success tests that the timeline from the proxy is returned
+func TestGetMatchTimeline_Success(t *testing.T) {
+	var receivedRegion, receivedMatchID string
+	mockProxy := &MockServiceProxy{
+		GetMatchTimelineFunc: func(region, matchID string) (*models.MatchTimeline, error) {
+			receivedRegion, receivedMatchID = region, matchID
+			return &models.MatchTimeline{
+				MatchID:       matchID,
+				FrameInterval: 60000,
+				Frames:        []models.TimelineFrame{{Timestamp: 0}, {Timestamp: 60000}},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchTimeline(responseRecorder, newTimelineRequest(`{"region":"NA","matchId":"NA1_4567890123"}`))
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if receivedRegion != "na" || receivedMatchID != "NA1_4567890123" {
+		t.Errorf("Expected proxy call with na/NA1_4567890123, got %s/%s", receivedRegion, receivedMatchID)
+	}
+
+	var timeline models.MatchTimeline
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&timeline); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(timeline.Frames) != 2 {
+		t.Errorf("Expected 2 frames, got %d", len(timeline.Frames))
+	}
+}
+
+// TestGetMatchTimeline_NotFound tests that an unknown match is reported as MATCH_NOT_FOUND
+func TestGetMatchTimeline_NotFound(t *testing.T) {
+	mockProxy := &MockServiceProxy{
+		GetMatchTimelineFunc: func(region, matchID string) (*models.MatchTimeline, error) {
+			return nil, apierrors.MatchNotFound(matchID)
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchTimeline(responseRecorder, newTimelineRequest(`{"region":"na","matchId":"NA1_4567890123"}`))
+
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, responseRecorder.Code)
+	}
+
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != apierrors.ErrCodeMatchNotFound {
+		t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeMatchNotFound, errorResponse.Error.Code)
+	}
+}
+
+// TestGetMatchTimeline_InvalidMatchID tests that a malformed match ID is rejected before proxying
+func TestGetMatchTimeline_InvalidMatchID(t *testing.T) {
+	proxyCalled := false
+	mockProxy := &MockServiceProxy{
+		GetMatchTimelineFunc: func(region, matchID string) (*models.MatchTimeline, error) {
+			proxyCalled = true
+			return nil, nil
+		},
+	}
+	handler := NewHandler(mockProxy)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.GetMatchTimeline(responseRecorder, newTimelineRequest(`{"region":"na","matchId":"not-a-match"}`))
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+	if proxyCalled {
+		t.Error("Expected the proxy not to be called for an invalid match ID")
+	}
+}
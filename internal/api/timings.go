@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Analyze steps reported by ?timings=true
+const (
+	timingSummoner = "summoner"
+	timingMatches  = "matches"
+	timingAnalyze  = "analyze"
+	timingTotal    = "total"
+)
+
+// AnalyzeTimings reports how long each analyze step took, in milliseconds. A request that
+// joined a coalesced analysis reports the steps of the shared computation; steps that did
+// not run for this request (e.g. a cached analysis) are zero.
+type AnalyzeTimings struct {
+	SummonerMs int64 `json:"summonerMs"`
+	MatchesMs  int64 `json:"matchesMs"`
+	AnalyzeMs  int64 `json:"analyzeMs"`
+	TotalMs    int64 `json:"totalMs"`
+}
+
+// timingsContextKey stores the request's *stepTimings in its context
+type timingsContextKey struct{}
+
+// stepTimings collects step durations while a request is handled
+type stepTimings struct {
+	mutex     sync.Mutex
+	durations map[string]time.Duration
+}
+
+// isTimingsRequested reports whether the client asked for step timings via ?timings=true
+func isTimingsRequested(request *http.Request) bool {
+	return request.URL.Query().Get("timings") == "true"
+}
+
+// withStepTimings returns the request with an empty timings collector in its context
+func withStepTimings(request *http.Request) *http.Request {
+	return request.WithContext(withNewStepTimings(request.Context()))
+}
+
+// withNewStepTimings returns ctx with an empty timings collector, replacing any it inherits
+func withNewStepTimings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timingsContextKey{}, &stepTimings{durations: make(map[string]time.Duration)})
+}
+
+// recordStepTiming records the time since start for a step when timings are being collected
+func recordStepTiming(ctx context.Context, step string, start time.Time) {
+	timings, ok := ctx.Value(timingsContextKey{}).(*stepTimings)
+	if !ok {
+		return
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	timings.durations[step] += time.Since(start)
+}
+
+// stepDurationsFromContext returns a copy of the step durations collected in ctx, or nil
+// when timings are not being collected
+func stepDurationsFromContext(ctx context.Context) map[string]time.Duration {
+	timings, ok := ctx.Value(timingsContextKey{}).(*stepTimings)
+	if !ok {
+		return nil
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	durations := make(map[string]time.Duration, len(timings.durations))
+	for step, duration := range timings.durations {
+		durations[step] = duration
+	}
+	return durations
+}
+
+// addStepDurations adds durations measured elsewhere (e.g. by a shared analysis) to the
+// timings collected in ctx, if any
+func addStepDurations(ctx context.Context, durations map[string]time.Duration) {
+	timings, ok := ctx.Value(timingsContextKey{}).(*stepTimings)
+	if !ok {
+		return
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	for step, duration := range durations {
+		timings.durations[step] += duration
+	}
+}
+
+// analyzeTimingsFromContext returns the collected analyze timings, or nil when the client
+// did not ask for them
+func analyzeTimingsFromContext(ctx context.Context) *AnalyzeTimings {
+	timings, ok := ctx.Value(timingsContextKey{}).(*stepTimings)
+	if !ok {
+		return nil
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	return &AnalyzeTimings{
+		SummonerMs: timings.durations[timingSummoner].Milliseconds(),
+		MatchesMs:  timings.durations[timingMatches].Milliseconds(),
+		AnalyzeMs:  timings.durations[timingAnalyze].Milliseconds(),
+		TotalMs:    timings.durations[timingTotal].Milliseconds(),
+	}
+}
+
+// setServerTimingHeader reports analyze timings in a Server-Timing header, so they are
+// available in the raw envelope too
+func setServerTimingHeader(writer http.ResponseWriter, timings *AnalyzeTimings) {
+	metrics := []string{
+		fmt.Sprintf("%s;dur=%d", timingSummoner, timings.SummonerMs),
+		fmt.Sprintf("%s;dur=%d", timingMatches, timings.MatchesMs),
+		fmt.Sprintf("%s;dur=%d", timingAnalyze, timings.AnalyzeMs),
+		fmt.Sprintf("%s;dur=%d", timingTotal, timings.TotalMs),
+	}
+	writer.Header().Set("Server-Timing", strings.Join(metrics, ", "))
+}
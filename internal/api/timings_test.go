@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestAnalyzePlayer_Timings tests that ?timings=true reports step durations in meta.timings and Server-Timing
+func TestAnalyzePlayer_Timings(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(20*time.Millisecond, nil, &analyzedMatches)
+	handler := NewHandler(mockProxy, WithResponseEnvelope(EnvelopeWrapped))
+
+	requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+	request, _ := http.NewRequest("POST", "/api/v1/analyze?timings=true", bytes.NewBufferString(requestBody))
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response struct {
+		Meta ResponseMeta `json:"meta"`
+	}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	timings := response.Meta.Timings
+	if timings == nil {
+		t.Fatal("Expected meta.timings to be present")
+	}
+	if timings.MatchesMs < 20 {
+		t.Errorf("Expected matchesMs of at least 20, got %d", timings.MatchesMs)
+	}
+	if timings.TotalMs < timings.SummonerMs+timings.MatchesMs+timings.AnalyzeMs {
+		t.Errorf("Expected totalMs to cover every step, got %+v", timings)
+	}
+
+	serverTiming := responseRecorder.Header().Get("Server-Timing")
+	for _, step := range []string{"summoner;dur=", "matches;dur=", "analyze;dur=", "total;dur="} {
+		if !strings.Contains(serverTiming, step) {
+			t.Errorf("Expected Server-Timing to contain '%s', got '%s'", step, serverTiming)
+		}
+	}
+}
+
+// TestAnalyzePlayer_TimingsNotRequested tests that timings are omitted by default
+func TestAnalyzePlayer_TimingsNotRequested(t *testing.T) {
+	var analyzedMatches []models.Match
+	mockProxy := newSoftDeadlineProxy(0, nil, &analyzedMatches)
+	handler := NewHandler(mockProxy, WithResponseEnvelope(EnvelopeWrapped))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.AnalyzePlayer(responseRecorder, newAnalyzeRequest())
+
+	if strings.Contains(responseRecorder.Body.String(), "timings") {
+		t.Errorf("Expected no timings in the response, got %s", responseRecorder.Body.String())
+	}
+	if responseRecorder.Header().Get("Server-Timing") != "" {
+		t.Errorf("Expected no Server-Timing header, got '%s'", responseRecorder.Header().Get("Server-Timing"))
+	}
+}
+
+// TestAnalyzePlayer_TimingsCoalesced tests that every request sharing a coalesced analysis
+// reports the shared computation's step timings, not just the one that started it
+func TestAnalyzePlayer_TimingsCoalesced(t *testing.T) {
+	var cortexCalls atomic.Int32
+	release := make(chan struct{})
+	handler := NewHandler(newCoalescingProxy(&cortexCalls, release), WithResponseEnvelope(EnvelopeWrapped), WithAnalyzeCoalescing(time.Minute))
+
+	const concurrentRequests = 3
+	timings := make([]*AnalyzeTimings, concurrentRequests)
+	var waitGroup sync.WaitGroup
+	for index := 0; index < concurrentRequests; index++ {
+		waitGroup.Add(1)
+		go func(index int) {
+			defer waitGroup.Done()
+			requestBody := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+			request, _ := http.NewRequest("POST", "/api/v1/analyze?timings=true", bytes.NewBufferString(requestBody))
+			responseRecorder := httptest.NewRecorder()
+			handler.AnalyzePlayer(responseRecorder, request)
+
+			var response struct {
+				Meta ResponseMeta `json:"meta"`
+			}
+			json.NewDecoder(responseRecorder.Body).Decode(&response)
+			timings[index] = response.Meta.Timings
+		}(index)
+	}
+
+	// Hold the shared cortex call long enough for every request to join it
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+
+	if cortexCalls.Load() != 1 {
+		t.Fatalf("Expected 1 shared cortex call, got %d", cortexCalls.Load())
+	}
+	for index, requestTimings := range timings {
+		if requestTimings == nil {
+			t.Errorf("Request %d: expected meta.timings to be present", index)
+			continue
+		}
+		if requestTimings.AnalyzeMs < 20 {
+			t.Errorf("Request %d: expected the shared analyze step of at least 20ms, got %d", index, requestTimings.AnalyzeMs)
+		}
+	}
+}
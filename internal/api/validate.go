@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/validation"
+)
+
+// isPartialRequested reports whether the client asked to validate only the fields it sent via ?partial=true
+func isPartialRequested(request *http.Request) bool {
+	return request.URL.Query().Get("partial") == "true"
+}
+
+// ValidateSummoner validates a summoner request without calling any upstream and reports
+// the validity of each field, so forms can validate input as it is typed. With
+// ?partial=true, empty fields are skipped instead of reported as required. Invalid input is
+// a 200 with valid=false; only an undecodable body is an error.
+func (handler *Handler) ValidateSummoner(writer http.ResponseWriter, request *http.Request) {
+	var summonerRequest validation.SummonerRequest
+
+	if err := json.NewDecoder(request.Body).Decode(&summonerRequest); err != nil {
+		handler.writeError(writer, request, invalidJSONError(err))
+		return
+	}
+
+	partial := isPartialRequested(request)
+	summonerRequest.Region = handler.resolveRegion(summonerRequest.Region)
+
+	var validationResult *validation.ValidationResult
+	if partial {
+		validationResult = validation.ValidatePartialSummonerRequest(&summonerRequest)
+	} else {
+		validationResult = validation.ValidateSummonerRequest(&summonerRequest)
+	}
+	if summonerRequest.Region != "" && !hasFieldError(validationResult, "region") {
+		if region := validation.NormalizeRegion(summonerRequest.Region); !handler.isRegionServed(region) {
+			validationResult.AddError("region", apierrors.RegionNotServed(region).Message)
+		}
+	}
+
+	fieldValues := map[string]string{
+		"region":   summonerRequest.Region,
+		"gameName": summonerRequest.GameName,
+		"tagLine":  summonerRequest.TagLine,
+	}
+	fields := make(map[string]models.FieldValidity, len(fieldValues))
+	for field, value := range fieldValues {
+		fields[field] = models.FieldValidity{Checked: !partial || value != "", Valid: true}
+	}
+	for _, validationError := range validationResult.Errors {
+		fields[validationError.Field] = models.FieldValidity{Checked: true, Valid: false, Message: validationError.Message}
+	}
+
+	handler.writeResponse(writer, request, http.StatusOK, models.SummonerValidationResponse{
+		Valid:   validationResult.IsValid(),
+		Partial: partial,
+		Fields:  fields,
+	})
+}
+
+// hasFieldError reports whether result already holds an error for field
+func hasFieldError(result *validation.ValidationResult, field string) bool {
+	for _, validationError := range result.Errors {
+		if validationError.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// validateSummoner posts body to the summoner validation endpoint and decodes the response
+func validateSummoner(t *testing.T, handler *Handler, url string, body string) models.SummonerValidationResponse {
+	request, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+	responseRecorder := httptest.NewRecorder()
+	handler.ValidateSummoner(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	var response models.SummonerValidationResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response
+}
+
+// TestValidateSummoner_Partial tests that partial validation skips empty fields but still rejects invalid ones
+func TestValidateSummoner_Partial(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	response := validateSummoner(t, handler, "/api/v1/validate/summoner?partial=true", `{"region":"na"}`)
+	if !response.Valid || !response.Partial {
+		t.Errorf("Expected a valid partial result, got %+v", response)
+	}
+	if gameName := response.Fields["gameName"]; gameName.Checked || !gameName.Valid || gameName.Message != "" {
+		t.Errorf("Expected empty gameName to be skipped without error, got %+v", gameName)
+	}
+	if region := response.Fields["region"]; !region.Checked || !region.Valid {
+		t.Errorf("Expected region to be checked and valid, got %+v", region)
+	}
+
+	response = validateSummoner(t, handler, "/api/v1/validate/summoner?partial=true", `{"region":"xx"}`)
+	if response.Valid {
+		t.Error("Expected an invalid region to fail partial validation")
+	}
+	if region := response.Fields["region"]; region.Valid || region.Message == "" {
+		t.Errorf("Expected a region error, got %+v", region)
+	}
+	if gameName := response.Fields["gameName"]; !gameName.Valid {
+		t.Errorf("Expected empty gameName to produce no error, got %+v", gameName)
+	}
+}
+
+// TestValidateSummoner_Full tests that without partial, missing fields are reported as required
+func TestValidateSummoner_Full(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{})
+
+	response := validateSummoner(t, handler, "/api/v1/validate/summoner", `{"region":"na"}`)
+	if response.Valid || response.Partial {
+		t.Errorf("Expected an invalid full result, got %+v", response)
+	}
+	if gameName := response.Fields["gameName"]; !gameName.Checked || gameName.Valid || gameName.Message != "gameName is required" {
+		t.Errorf("Expected gameName to be required, got %+v", gameName)
+	}
+}
+
+// TestValidateSummoner_RegionNotServed tests that a valid region this gateway does not serve is reported
+func TestValidateSummoner_RegionNotServed(t *testing.T) {
+	handler := NewHandler(&MockServiceProxy{}, WithEnabledRegions(map[string]bool{"euw": true}))
+
+	response := validateSummoner(t, handler, "/api/v1/validate/summoner?partial=true", `{"region":"na"}`)
+	if region := response.Fields["region"]; region.Valid {
+		t.Errorf("Expected region 'na' to be reported as not served, got %+v", region)
+	}
+}
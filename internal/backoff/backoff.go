@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Delay returns the exponential backoff for the given retry attempt (1 for the first retry)
+// with up to 50% jitter
+func Delay(baseDelay time.Duration, attempt int) time.Duration {
+	delay := baseDelay << (attempt - 1)
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
+
+// Wait sleeps for delay, returning early with the context error if ctx is done
+func Wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
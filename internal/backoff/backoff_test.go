@@ -0,0 +1,43 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestDelay tests that the backoff doubles per attempt and adds at most 50% jitter
+func TestDelay(t *testing.T) {
+	baseDelay := 100 * time.Millisecond
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		minimum := baseDelay << (attempt - 1)
+		maximum := minimum + minimum/2
+
+		delay := Delay(baseDelay, attempt)
+		if delay < minimum || delay > maximum {
+			t.Errorf("Attempt %d: expected delay in [%v, %v], got %v", attempt, minimum, maximum, delay)
+		}
+	}
+}
+
+// TestWait_Elapses tests that Wait returns nil once the delay has passed
+func TestWait_Elapses(t *testing.T) {
+	if err := Wait(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
+
+// TestWait_ContextDone tests that Wait returns early with the context error
+func TestWait_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := Wait(ctx, time.Minute); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected Wait to return without waiting for the delay")
+	}
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Cache is a key-value cache whose entries are fresh for a TTL and can then be served stale
+// for an optional window. Implementations are safe for concurrent use; failures of a remote
+// backend are reported as misses so callers fall through to the upstream.
+type Cache interface {
+	// Get returns the cached value for key if it is still fresh
+	Get(key string) (interface{}, bool)
+	// GetStale returns the cached value for key if it is past its TTL but still within the stale window
+	GetStale(key string) (interface{}, bool)
+	// Set stores value under key, resetting its TTL
+	Set(key string, value interface{})
+	// Delete removes key from the cache
+	Delete(key string)
+}
+
+var (
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*RedisCache)(nil)
+)
+
+// Backend selects where cached entries are stored
+type Backend string
+
+const (
+	// BackendMemory keeps entries in process memory (default)
+	BackendMemory Backend = "memory"
+	// BackendRedis keeps entries in Redis so they are shared across gateway instances
+	BackendRedis Backend = "redis"
+)
+
+// ParseBackend parses a CACHE_BACKEND value, defaulting to memory when empty
+func ParseBackend(value string) (Backend, error) {
+	switch Backend(strings.ToLower(strings.TrimSpace(value))) {
+	case "", BackendMemory:
+		return BackendMemory, nil
+	case BackendRedis:
+		return BackendRedis, nil
+	default:
+		return "", fmt.Errorf("invalid cache backend %q: expected memory or redis", value)
+	}
+}
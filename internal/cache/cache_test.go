@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// testCacheValue is a JSON-serializable value stored by the cache contract tests
+type testCacheValue struct {
+	Name string `json:"name"`
+}
+
+// TestParseBackend tests parsing of CACHE_BACKEND values
+func TestParseBackend(t *testing.T) {
+	testCases := map[string]Backend{"": BackendMemory, "memory": BackendMemory, " Redis ": BackendRedis}
+	for value, expectedBackend := range testCases {
+		backend, err := ParseBackend(value)
+		if err != nil || backend != expectedBackend {
+			t.Errorf("Expected %q to parse as '%s', got '%s' (err %v)", value, expectedBackend, backend, err)
+		}
+	}
+
+	if _, err := ParseBackend("memcached"); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+// TestCacheContract tests that every backend serves fresh, stale and deleted entries the same way
+func TestCacheContract(t *testing.T) {
+	backends := map[string]func(t *testing.T, now func() time.Time) Cache{
+		"memory": func(t *testing.T, now func() time.Time) Cache {
+			memoryCache := NewMemoryCache(time.Minute, time.Hour)
+			memoryCache.now = now
+			return memoryCache
+		},
+		"redis": func(t *testing.T, now func() time.Time) Cache {
+			client, _ := NewRedisClient("redis://" + miniredis.RunT(t).Addr())
+			t.Cleanup(func() { client.Close() })
+			redisCache := NewRedisCache(client, "test:", time.Minute, time.Hour, func() interface{} { return &testCacheValue{} })
+			redisCache.now = now
+			return redisCache
+		},
+	}
+
+	for name, newCache := range backends {
+		t.Run(name, func(t *testing.T) {
+			currentTime := time.Now()
+			testCache := newCache(t, func() time.Time { return currentTime })
+
+			if _, found := testCache.Get("key"); found {
+				t.Fatal("Expected a miss for a missing key")
+			}
+
+			testCache.Set("key", &testCacheValue{Name: "value"})
+			value, found := testCache.Get("key")
+			if !found || value.(*testCacheValue).Name != "value" {
+				t.Fatalf("Expected fresh value 'value', got %v (found=%v)", value, found)
+			}
+
+			currentTime = currentTime.Add(2 * time.Minute)
+			if _, found := testCache.Get("key"); found {
+				t.Error("Expected expired entry to not be returned by Get")
+			}
+			value, found = testCache.GetStale("key")
+			if !found || value.(*testCacheValue).Name != "value" {
+				t.Errorf("Expected stale value 'value', got %v (found=%v)", value, found)
+			}
+
+			testCache.Delete("key")
+			if _, found := testCache.GetStale("key"); found {
+				t.Error("Expected deleted entry to not be returned")
+			}
+		})
+	}
+}
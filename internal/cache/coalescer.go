@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// call is an in-flight or completed Coalescer computation
+type call struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// Coalescer collapses concurrent calls for the same key into a single execution whose
+// result is shared by every caller (the "singleflight" pattern)
+type Coalescer struct {
+	mutex sync.Mutex
+	calls map[string]*call
+}
+
+// NewCoalescer creates an empty Coalescer
+func NewCoalescer() *Coalescer {
+	return &Coalescer{calls: make(map[string]*call)}
+}
+
+// Do runs compute for key unless a call for key is already in flight, in which case it joins
+// that call. compute runs in its own goroutine so it is never abandoned midway: every caller,
+// including the one that started it, waits for the result or for its own ctx to end, whichever
+// comes first, and receives ctx.Err() in the latter case. compute must therefore bound its own
+// running time. shared reports whether the result came from another caller's execution.
+func (coalescer *Coalescer) Do(ctx context.Context, key string, compute func() (interface{}, error)) (value interface{}, err error, shared bool) {
+	coalescer.mutex.Lock()
+	inFlight, shared := coalescer.calls[key]
+	if !shared {
+		inFlight = &call{done: make(chan struct{})}
+		coalescer.calls[key] = inFlight
+		go coalescer.run(key, inFlight, compute)
+	}
+	coalescer.mutex.Unlock()
+
+	select {
+	case <-inFlight.done:
+		return inFlight.value, inFlight.err, shared
+	case <-ctx.Done():
+		return nil, ctx.Err(), shared
+	}
+}
+
+// run executes compute for an in-flight call and releases its waiters
+func (coalescer *Coalescer) run(key string, current *call, compute func() (interface{}, error)) {
+	defer func() {
+		coalescer.mutex.Lock()
+		delete(coalescer.calls, key)
+		coalescer.mutex.Unlock()
+		close(current.done)
+	}()
+
+	current.value, current.err = compute()
+}
+
+// Ping takes and releases the coalescer's lock, blocking while another goroutine holds it.
+// Liveness self-checks use it to detect a goroutine deadlocked while holding the lock.
+func (coalescer *Coalescer) Ping() {
+	coalescer.mutex.Lock()
+	coalescer.mutex.Unlock()
+}
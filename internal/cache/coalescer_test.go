@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCoalescer_SharesInFlightCall tests that concurrent calls for one key execute once
+func TestCoalescer_SharesInFlightCall(t *testing.T) {
+	coalescer := NewCoalescer()
+	release := make(chan struct{})
+	var executions atomic.Int32
+
+	const callers = 5
+	var started sync.WaitGroup
+	var finished sync.WaitGroup
+	results := make([]interface{}, callers)
+	var sharedCount atomic.Int32
+
+	for index := 0; index < callers; index++ {
+		started.Add(1)
+		finished.Add(1)
+		go func(index int) {
+			defer finished.Done()
+			started.Done()
+			value, _, shared := coalescer.Do(context.Background(), "key", func() (interface{}, error) {
+				executions.Add(1)
+				<-release
+				return "result", nil
+			})
+			results[index] = value
+			if shared {
+				sharedCount.Add(1)
+			}
+		}(index)
+	}
+
+	// Give every caller time to join the in-flight call before it completes
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	finished.Wait()
+
+	if executions.Load() != 1 {
+		t.Errorf("Expected 1 execution, got %d", executions.Load())
+	}
+	for index, value := range results {
+		if value != "result" {
+			t.Errorf("Caller %d got %v", index, value)
+		}
+	}
+	if sharedCount.Load() == 0 {
+		t.Error("Expected at least one caller to receive a shared result")
+	}
+}
+
+// TestCoalescer_SequentialCallsExecute tests that completed calls are not reused
+func TestCoalescer_SequentialCallsExecute(t *testing.T) {
+	coalescer := NewCoalescer()
+	executions := 0
+	compute := func() (interface{}, error) {
+		executions++
+		return nil, errors.New("failed")
+	}
+
+	coalescer.Do(context.Background(), "key", compute)
+	_, err, shared := coalescer.Do(context.Background(), "key", compute)
+
+	if executions != 2 {
+		t.Errorf("Expected 2 executions, got %d", executions)
+	}
+	if err == nil || shared {
+		t.Errorf("Expected an unshared error, got err=%v shared=%v", err, shared)
+	}
+}
+
+// TestCoalescer_DistinctKeys tests that different keys do not share results
+func TestCoalescer_DistinctKeys(t *testing.T) {
+	coalescer := NewCoalescer()
+
+	first, _, _ := coalescer.Do(context.Background(), "first", func() (interface{}, error) { return 1, nil })
+	second, _, _ := coalescer.Do(context.Background(), "second", func() (interface{}, error) { return 2, nil })
+
+	if first != 1 || second != 2 {
+		t.Errorf("Expected 1 and 2, got %v and %v", first, second)
+	}
+}
+
+// TestCoalescer_WaiterCancellation tests that a waiter whose context ends stops waiting
+// while the shared computation still completes for the remaining callers
+func TestCoalescer_WaiterCancellation(t *testing.T) {
+	coalescer := NewCoalescer()
+	release := make(chan struct{})
+	compute := func() (interface{}, error) {
+		<-release
+		return "result", nil
+	}
+
+	leaderResult := make(chan interface{})
+	go func() {
+		value, _, _ := coalescer.Do(context.Background(), "key", compute)
+		leaderResult <- value
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	waiterContext, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err, shared := coalescer.Do(waiterContext, "key", compute)
+	if err != context.DeadlineExceeded || !shared {
+		t.Errorf("Expected the waiter to time out on the shared call, got err=%v shared=%v", err, shared)
+	}
+
+	close(release)
+	select {
+	case value := <-leaderResult:
+		if value != "result" {
+			t.Errorf("Expected the leader to receive 'result', got %v", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the shared computation to complete after the waiter left")
+	}
+}
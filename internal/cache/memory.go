@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultMaxEntries bounds memory use when no explicit limit is configured
+const defaultMaxEntries = 10000
+
+// entry is a cached value with its freshness deadline
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// MemoryCache is a concurrency-safe in-memory cache with a TTL and an optional stale window.
+// Entries past their TTL are no longer returned by Get, but remain available to GetStale
+// until the stale window also elapses.
+type MemoryCache struct {
+	mutex       sync.RWMutex
+	entries     map[string]entry
+	ttl         time.Duration
+	staleWindow time.Duration
+	maxEntries  int
+	now         func() time.Time
+}
+
+// NewMemoryCache creates a cache whose entries are fresh for ttl and can be served stale
+// for an additional staleWindow
+func NewMemoryCache(ttl time.Duration, staleWindow time.Duration) *MemoryCache {
+	return &MemoryCache{
+		entries:     make(map[string]entry),
+		ttl:         ttl,
+		staleWindow: staleWindow,
+		maxEntries:  defaultMaxEntries,
+		now:         time.Now,
+	}
+}
+
+// Get returns the cached value for key if it is still fresh
+func (memoryCache *MemoryCache) Get(key string) (interface{}, bool) {
+	memoryCache.mutex.RLock()
+	cached, found := memoryCache.entries[key]
+	memoryCache.mutex.RUnlock()
+
+	if !found || memoryCache.now().After(cached.expiresAt) {
+		return nil, false
+	}
+
+	return cached.value, true
+}
+
+// GetStale returns the cached value for key if it is past its TTL but still within the stale window
+func (memoryCache *MemoryCache) GetStale(key string) (interface{}, bool) {
+	memoryCache.mutex.RLock()
+	cached, found := memoryCache.entries[key]
+	memoryCache.mutex.RUnlock()
+
+	if !found {
+		return nil, false
+	}
+
+	currentTime := memoryCache.now()
+	if memoryCache.pastStaleWindow(cached, currentTime) {
+		memoryCache.deleteIfPastStaleWindow(key, currentTime)
+		return nil, false
+	}
+
+	return cached.value, true
+}
+
+// pastStaleWindow reports whether cached can no longer be served, even stale
+func (memoryCache *MemoryCache) pastStaleWindow(cached entry, currentTime time.Time) bool {
+	return currentTime.After(cached.expiresAt.Add(memoryCache.staleWindow))
+}
+
+// deleteIfPastStaleWindow removes key unless it was refreshed by a Set since it was found
+// expired, re-checking the entry under the write lock
+func (memoryCache *MemoryCache) deleteIfPastStaleWindow(key string, currentTime time.Time) {
+	memoryCache.mutex.Lock()
+	defer memoryCache.mutex.Unlock()
+
+	if cached, found := memoryCache.entries[key]; found && memoryCache.pastStaleWindow(cached, currentTime) {
+		delete(memoryCache.entries, key)
+	}
+}
+
+// Set stores value under key, resetting its TTL
+func (memoryCache *MemoryCache) Set(key string, value interface{}) {
+	memoryCache.mutex.Lock()
+	defer memoryCache.mutex.Unlock()
+
+	if _, exists := memoryCache.entries[key]; !exists && len(memoryCache.entries) >= memoryCache.maxEntries {
+		memoryCache.evictLocked()
+	}
+
+	memoryCache.entries[key] = entry{
+		value:     value,
+		expiresAt: memoryCache.now().Add(memoryCache.ttl),
+	}
+}
+
+// Ping takes and releases the cache's write lock, blocking while any other goroutine holds
+// it. Liveness self-checks use it to detect a goroutine deadlocked while holding the lock.
+func (memoryCache *MemoryCache) Ping() {
+	memoryCache.mutex.Lock()
+	memoryCache.mutex.Unlock()
+}
+
+// Delete removes key from the cache
+func (memoryCache *MemoryCache) Delete(key string) {
+	memoryCache.mutex.Lock()
+	defer memoryCache.mutex.Unlock()
+
+	delete(memoryCache.entries, key)
+}
+
+// evictLocked removes entries past their stale window, falling back to an arbitrary
+// entry when everything is still servable. The caller must hold the write lock.
+func (memoryCache *MemoryCache) evictLocked() {
+	currentTime := memoryCache.now()
+	for key, cached := range memoryCache.entries {
+		if memoryCache.pastStaleWindow(cached, currentTime) {
+			delete(memoryCache.entries, key)
+		}
+	}
+
+	if len(memoryCache.entries) < memoryCache.maxEntries {
+		return
+	}
+
+	for key := range memoryCache.entries {
+		delete(memoryCache.entries, key)
+		return
+	}
+}
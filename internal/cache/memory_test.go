@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestCache creates a cache with a controllable clock
+func newTestCache(ttl time.Duration, staleWindow time.Duration) (*MemoryCache, *time.Time) {
+	currentTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	memoryCache := NewMemoryCache(ttl, staleWindow)
+	memoryCache.now = func() time.Time { return currentTime }
+	return memoryCache, &currentTime
+}
+
+// TestMemoryCache_GetFresh tests that fresh entries are returned
+func TestMemoryCache_GetFresh(t *testing.T) {
+	memoryCache, _ := newTestCache(time.Minute, time.Hour)
+
+	memoryCache.Set("key", "value")
+
+	value, found := memoryCache.Get("key")
+	if !found || value != "value" {
+		t.Errorf("Expected fresh value 'value', got %v (found=%v)", value, found)
+	}
+}
+
+// TestMemoryCache_Expired tests that entries past their TTL are not returned by Get
+func TestMemoryCache_Expired(t *testing.T) {
+	memoryCache, currentTime := newTestCache(time.Minute, time.Hour)
+
+	memoryCache.Set("key", "value")
+	*currentTime = currentTime.Add(2 * time.Minute)
+
+	if _, found := memoryCache.Get("key"); found {
+		t.Error("Expected expired entry to not be returned by Get")
+	}
+}
+
+// TestMemoryCache_GetStale tests that expired entries are served stale within the stale window
+func TestMemoryCache_GetStale(t *testing.T) {
+	memoryCache, currentTime := newTestCache(time.Minute, time.Hour)
+
+	memoryCache.Set("key", "value")
+	*currentTime = currentTime.Add(30 * time.Minute)
+
+	value, found := memoryCache.GetStale("key")
+	if !found || value != "value" {
+		t.Errorf("Expected stale value 'value', got %v (found=%v)", value, found)
+	}
+
+	*currentTime = currentTime.Add(2 * time.Hour)
+
+	if _, found := memoryCache.GetStale("key"); found {
+		t.Error("Expected entry past the stale window to not be returned")
+	}
+}
+
+// TestMemoryCache_GetStaleKeepsConcurrentSet tests that a Set landing between GetStale
+// finding an entry expired and deleting it is not deleted along with the expired entry
+func TestMemoryCache_GetStaleKeepsConcurrentSet(t *testing.T) {
+	memoryCache, currentTime := newTestCache(time.Minute, time.Hour)
+	memoryCache.Set("key", "old")
+	*currentTime = currentTime.Add(2 * time.Hour)
+
+	// GetStale reads the clock after releasing its read lock; refresh the key right there
+	clock := memoryCache.now
+	refreshed := false
+	memoryCache.now = func() time.Time {
+		if !refreshed {
+			refreshed = true
+			memoryCache.Set("key", "fresh")
+		}
+		return clock()
+	}
+
+	if _, found := memoryCache.GetStale("key"); found {
+		t.Error("Expected the entry read before the refresh to be past the stale window")
+	}
+
+	value, found := memoryCache.Get("key")
+	if !found || value != "fresh" {
+		t.Errorf("Expected the refreshed value 'fresh' to survive, got %v (found=%v)", value, found)
+	}
+}
+
+// TestMemoryCache_Delete tests that deleted entries are not returned
+func TestMemoryCache_Delete(t *testing.T) {
+	memoryCache, _ := newTestCache(time.Minute, time.Hour)
+
+	memoryCache.Set("key", "value")
+	memoryCache.Delete("key")
+
+	if _, found := memoryCache.Get("key"); found {
+		t.Error("Expected deleted entry to not be returned")
+	}
+}
+
+// TestMemoryCache_MaxEntries tests that the cache does not grow beyond its limit
+func TestMemoryCache_MaxEntries(t *testing.T) {
+	memoryCache, _ := newTestCache(time.Minute, time.Hour)
+	memoryCache.maxEntries = 2
+
+	memoryCache.Set("a", 1)
+	memoryCache.Set("b", 2)
+	memoryCache.Set("c", 3)
+
+	if len(memoryCache.entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(memoryCache.entries))
+	}
+
+	if _, found := memoryCache.Get("c"); !found {
+		t.Error("Expected most recently set entry to be present")
+	}
+}
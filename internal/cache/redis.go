@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultRedisTimeout bounds dialing and each Redis round trip unless the URL sets its own
+// timeouts, so a slow Redis degrades to cache misses instead of stalling requests
+const defaultRedisTimeout = 500 * time.Millisecond
+
+// NewRedisClient creates a go-redis client for a redis:// or rediss:// (TLS) URL, e.g.
+// redis://[[username]:password@]host[:port][/database]. Connection options such as
+// dial_timeout or pool_size may be given as query parameters. Since a failed cache call
+// falls through to the upstream anyway, a failed dial or command is not retried unless
+// the URL sets max_retries. Connections are opened lazily, so an unreachable Redis is not
+// an error here.
+func NewRedisClient(redisURL string) (*redis.Client, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	if options.DialTimeout == 0 {
+		options.DialTimeout = defaultRedisTimeout
+	}
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = defaultRedisTimeout
+	}
+	if options.WriteTimeout == 0 {
+		options.WriteTimeout = defaultRedisTimeout
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = -1
+	}
+	options.DialerRetries = 1
+
+	return redis.NewClient(options), nil
+}
+
+// redisEntry is the JSON document stored per key. Redis expires the key once the stale
+// window has also elapsed; ExpiresAt marks the end of freshness.
+type redisEntry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt int64           `json:"expiresAt"`
+}
+
+// RedisCache is a Cache stored in Redis as JSON, shared by every gateway instance using the
+// same Redis and key prefix. Redis failures are logged and treated as misses (fail-open), so
+// requests fall through to the upstream services.
+type RedisCache struct {
+	client      *redis.Client
+	keyPrefix   string
+	ttl         time.Duration
+	staleWindow time.Duration
+	// newValue returns a pointer for cached JSON to be decoded into, e.g. &models.Summoner{}
+	newValue func() interface{}
+	now      func() time.Time
+}
+
+// NewRedisCache creates a cache whose entries are fresh for ttl and can be served stale for
+// an additional staleWindow. Keys are prefixed with keyPrefix, and values read back are
+// decoded into a fresh value from newValue, so Get returns the same type that was Set.
+func NewRedisCache(client *redis.Client, keyPrefix string, ttl time.Duration, staleWindow time.Duration, newValue func() interface{}) *RedisCache {
+	return &RedisCache{
+		client:      client,
+		keyPrefix:   keyPrefix,
+		ttl:         ttl,
+		staleWindow: staleWindow,
+		newValue:    newValue,
+		now:         time.Now,
+	}
+}
+
+// Get returns the cached value for key if it is still fresh
+func (redisCache *RedisCache) Get(key string) (interface{}, bool) {
+	value, expiresAt, found := redisCache.load(key)
+	if !found || redisCache.now().After(expiresAt) {
+		return nil, false
+	}
+	return value, true
+}
+
+// GetStale returns the cached value for key if it is past its TTL but still within the stale window
+func (redisCache *RedisCache) GetStale(key string) (interface{}, bool) {
+	value, _, found := redisCache.load(key)
+	return value, found
+}
+
+// Set stores value under key, resetting its TTL
+func (redisCache *RedisCache) Set(key string, value interface{}) {
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		log.Warn().Err(err).Str("cache_key", key).Msg("Failed to encode value for Redis cache")
+		return
+	}
+
+	entry, err := json.Marshal(redisEntry{
+		Value:     encodedValue,
+		ExpiresAt: redisCache.now().Add(redisCache.ttl).UnixMilli(),
+	})
+	if err != nil {
+		return
+	}
+
+	if err := redisCache.client.Set(context.Background(), redisCache.keyPrefix+key, entry, redisCache.ttl+redisCache.staleWindow).Err(); err != nil {
+		log.Warn().Err(err).Str("cache_key", key).Msg("Redis cache write failed")
+	}
+}
+
+// Delete removes key from the cache
+func (redisCache *RedisCache) Delete(key string) {
+	if err := redisCache.client.Del(context.Background(), redisCache.keyPrefix+key).Err(); err != nil {
+		log.Warn().Err(err).Str("cache_key", key).Msg("Redis cache delete failed")
+	}
+}
+
+// load reads and decodes the entry for key. Redis errors and undecodable entries are logged
+// and reported as not found.
+func (redisCache *RedisCache) load(key string) (value interface{}, expiresAt time.Time, found bool) {
+	data, err := redisCache.client.Get(context.Background(), redisCache.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, time.Time{}, false
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("cache_key", key).Msg("Redis cache read failed; treating as a miss")
+		return nil, time.Time{}, false
+	}
+
+	var entry redisEntry
+	value = redisCache.newValue()
+	if err := json.Unmarshal(data, &entry); err != nil || json.Unmarshal(entry.Value, value) != nil {
+		log.Warn().Str("cache_key", key).Msg("Ignoring undecodable Redis cache entry")
+		return nil, time.Time{}, false
+	}
+
+	return value, time.UnixMilli(entry.ExpiresAt), true
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// TestNewRedisClient_InvalidURL tests that malformed Redis URLs are rejected
+func TestNewRedisClient_InvalidURL(t *testing.T) {
+	for _, redisURL := range []string{"", "localhost:6379", "http://localhost:6379", "redis://localhost:6379/db"} {
+		if _, err := NewRedisClient(redisURL); err == nil {
+			t.Errorf("Expected an error for redis URL %q", redisURL)
+		}
+	}
+}
+
+// TestNewRedisClient_Options tests that TLS URLs are accepted, and that timeouts default to
+// defaultRedisTimeout and retries are off unless the URL sets them
+func TestNewRedisClient_Options(t *testing.T) {
+	client, err := NewRedisClient("rediss://localhost:6380")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	if client.Options().TLSConfig == nil {
+		t.Error("Expected rediss:// to enable TLS")
+	}
+	if client.Options().ReadTimeout != defaultRedisTimeout {
+		t.Errorf("Expected the default read timeout %v, got %v", defaultRedisTimeout, client.Options().ReadTimeout)
+	}
+	// go-redis normalizes the -1 that disables retries to 0
+	if client.Options().MaxRetries != 0 {
+		t.Errorf("Expected retries to be disabled, got max retries %d", client.Options().MaxRetries)
+	}
+
+	configured, err := NewRedisClient("redis://localhost:6379?read_timeout=2s&max_retries=2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer configured.Close()
+	if configured.Options().ReadTimeout != 2*time.Second {
+		t.Errorf("Expected the URL's read timeout 2s, got %v", configured.Options().ReadTimeout)
+	}
+	if configured.Options().MaxRetries != 2 {
+		t.Errorf("Expected the URL's max retries 2, got %d", configured.Options().MaxRetries)
+	}
+}
+
+// TestRedisCache_AuthenticatesAndSelectsDatabase tests that the password, ACL user and
+// database from the URL are used
+func TestRedisCache_AuthenticatesAndSelectsDatabase(t *testing.T) {
+	testCases := []struct {
+		name        string
+		user        string
+		requireAuth func(server *miniredis.Miniredis)
+	}{
+		{name: "default user", requireAuth: func(server *miniredis.Miniredis) { server.RequireAuth("secret") }},
+		{name: "acl user", user: "gateway", requireAuth: func(server *miniredis.Miniredis) { server.RequireUserAuth("gateway", "secret") }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			testCase.requireAuth(server)
+
+			client, err := NewRedisClient("redis://" + testCase.user + ":secret@" + server.Addr() + "/2")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer client.Close()
+			redisCache := NewRedisCache(client, "test:", time.Minute, 0, func() interface{} { return &testCacheValue{} })
+
+			redisCache.Set("key", &testCacheValue{Name: "value"})
+
+			if !server.DB(2).Exists("test:key") {
+				t.Error("Expected the entry to be written to database 2")
+			}
+			if value, found := redisCache.Get("key"); !found || value.(*testCacheValue).Name != "value" {
+				t.Errorf("Expected fresh value 'value', got %v (found=%v)", value, found)
+			}
+		})
+	}
+}
+
+// TestRedisCache_FailsOpen tests that an unreachable Redis behaves as an empty cache
+func TestRedisCache_FailsOpen(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	listener.Close()
+
+	client, _ := NewRedisClient("redis://" + address)
+	defer client.Close()
+	redisCache := NewRedisCache(client, "test:", time.Minute, time.Hour, func() interface{} { return &testCacheValue{} })
+
+	redisCache.Set("key", &testCacheValue{Name: "value"})
+	if _, found := redisCache.Get("key"); found {
+		t.Error("Expected a miss when Redis is unreachable")
+	}
+	if _, found := redisCache.GetStale("key"); found {
+		t.Error("Expected a stale miss when Redis is unreachable")
+	}
+	redisCache.Delete("key")
+}
+
+// TestRedisCache_IgnoresUndecodableEntry tests that an entry that is not valid JSON is a miss
+func TestRedisCache_IgnoresUndecodableEntry(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, _ := NewRedisClient("redis://" + server.Addr())
+	defer client.Close()
+	redisCache := NewRedisCache(client, "test:", time.Minute, time.Hour, func() interface{} { return &testCacheValue{} })
+
+	server.Set("test:key", "not json")
+
+	if _, found := redisCache.GetStale("key"); found {
+		t.Error("Expected an undecodable entry to be a miss")
+	}
+}
+
+// TestRedisCache_KeyPrefixAndExpiry tests that keys are prefixed and expire after the TTL plus stale window
+func TestRedisCache_KeyPrefixAndExpiry(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, _ := NewRedisClient("redis://" + server.Addr())
+	defer client.Close()
+	redisCache := NewRedisCache(client, "gateway:summoner:", time.Minute, time.Hour, func() interface{} { return &testCacheValue{} })
+
+	redisCache.Set("na|faker", &testCacheValue{Name: "value"})
+
+	if ttl := server.TTL("gateway:summoner:na|faker"); ttl != 61*time.Minute {
+		t.Errorf("Expected a prefixed key expiring after 61 minutes, got TTL %v (keys %q)", ttl, server.Keys())
+	}
+
+	server.FastForward(61 * time.Minute)
+	if _, found := redisCache.GetStale("na|faker"); found {
+		t.Error("Expected the entry to be gone once the stale window has elapsed")
+	}
+}
@@ -0,0 +1,87 @@
+package ddragon
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// championsJSON is a snapshot of Data Dragon champion IDs to display names
+//
+//go:embed champions.json
+var championsJSON []byte
+
+// ChampionTable maps champion IDs to display names
+type ChampionTable struct {
+	names map[int]string
+}
+
+// NewChampionTable creates a ChampionTable from an ID to name mapping
+func NewChampionTable(names map[int]string) *ChampionTable {
+	return &ChampionTable{names: names}
+}
+
+// ParseChampionTable parses a JSON object of champion ID strings to names
+func ParseChampionTable(data []byte) (*ChampionTable, error) {
+	var rawNames map[string]string
+	if err := json.Unmarshal(data, &rawNames); err != nil {
+		return nil, fmt.Errorf("failed to parse champion table: %w", err)
+	}
+
+	names := make(map[int]string, len(rawNames))
+	for rawID, name := range rawNames {
+		championID, err := strconv.Atoi(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid champion ID %q: %w", rawID, err)
+		}
+		names[championID] = name
+	}
+
+	return NewChampionTable(names), nil
+}
+
+// DefaultChampionTable returns the champion table embedded in the binary
+func DefaultChampionTable() (*ChampionTable, error) {
+	return ParseChampionTable(championsJSON)
+}
+
+// Name returns the display name for a champion ID
+func (table *ChampionTable) Name(championID int) (string, bool) {
+	name, found := table.names[championID]
+	return name, found
+}
+
+// EnrichMatches fills in missing participant champion names in place. Participants whose
+// champion ID is not in the table keep their raw ID; the unresolved IDs are returned sorted.
+func (table *ChampionTable) EnrichMatches(matches []models.Match) []int {
+	unresolved := make(map[int]bool)
+
+	for matchIndex := range matches {
+		participants := matches[matchIndex].Participants
+		for participantIndex := range participants {
+			participant := &participants[participantIndex]
+			if participant.ChampionName != "" {
+				continue
+			}
+
+			name, found := table.Name(participant.ChampionID)
+			if !found {
+				unresolved[participant.ChampionID] = true
+				continue
+			}
+			participant.ChampionName = name
+		}
+	}
+
+	unresolvedIDs := make([]int, 0, len(unresolved))
+	for championID := range unresolved {
+		unresolvedIDs = append(unresolvedIDs, championID)
+	}
+	sort.Ints(unresolvedIDs)
+
+	return unresolvedIDs
+}
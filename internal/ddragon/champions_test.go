@@ -0,0 +1,87 @@
+package ddragon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestDefaultChampionTable tests that the embedded table parses and maps known IDs
+func TestDefaultChampionTable(t *testing.T) {
+	table, err := DefaultChampionTable()
+	if err != nil {
+		t.Fatalf("Failed to load embedded champion table: %v", err)
+	}
+
+	testCases := map[int]string{
+		1:   "Annie",
+		62:  "Wukong",
+		157: "Yasuo",
+		222: "Jinx",
+	}
+
+	for championID, expectedName := range testCases {
+		name, found := table.Name(championID)
+		if !found {
+			t.Errorf("Expected champion ID %d to be found", championID)
+			continue
+		}
+		if name != expectedName {
+			t.Errorf("Expected champion ID %d to map to '%s', got '%s'", championID, expectedName, name)
+		}
+	}
+}
+
+// TestParseChampionTable_Invalid tests that malformed tables are rejected
+func TestParseChampionTable_Invalid(t *testing.T) {
+	for _, data := range []string{`not json`, `{"abc":"Annie"}`} {
+		if _, err := ParseChampionTable([]byte(data)); err == nil {
+			t.Errorf("Expected error for table '%s'", data)
+		}
+	}
+}
+
+// TestEnrichMatches tests that missing champion names are filled from the table
+func TestEnrichMatches(t *testing.T) {
+	table := NewChampionTable(map[int]string{1: "Annie", 2: "Olaf"})
+
+	matches := []models.Match{
+		{MatchID: "NA1_1", Participants: []models.Participant{{ChampionID: 1}, {ChampionID: 2}}},
+		{MatchID: "NA1_2", Participants: []models.Participant{{ChampionID: 2, ChampionName: "Upstream Olaf"}}},
+	}
+
+	unresolved := table.EnrichMatches(matches)
+
+	if len(unresolved) != 0 {
+		t.Errorf("Expected no unresolved IDs, got %v", unresolved)
+	}
+
+	if matches[0].Participants[0].ChampionName != "Annie" || matches[0].Participants[1].ChampionName != "Olaf" {
+		t.Errorf("Expected champion names to be filled, got %+v", matches[0].Participants)
+	}
+
+	if matches[1].Participants[0].ChampionName != "Upstream Olaf" {
+		t.Errorf("Expected existing champion name to be kept, got '%s'", matches[1].Participants[0].ChampionName)
+	}
+}
+
+// TestEnrichMatches_UnknownIDFallback tests that unknown IDs keep their raw ID and are reported
+func TestEnrichMatches_UnknownIDFallback(t *testing.T) {
+	table := NewChampionTable(map[int]string{1: "Annie"})
+
+	matches := []models.Match{
+		{MatchID: "NA1_1", Participants: []models.Participant{{ChampionID: 9999}, {ChampionID: 1}, {ChampionID: 4242}, {ChampionID: 9999}}},
+	}
+
+	unresolved := table.EnrichMatches(matches)
+
+	if !reflect.DeepEqual(unresolved, []int{4242, 9999}) {
+		t.Errorf("Expected unresolved IDs [4242 9999], got %v", unresolved)
+	}
+
+	participant := matches[0].Participants[0]
+	if participant.ChampionID != 9999 || participant.ChampionName != "" {
+		t.Errorf("Expected unknown champion to keep raw ID without a name, got %+v", participant)
+	}
+}
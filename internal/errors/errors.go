@@ -5,7 +5,9 @@ import (
 	"net/http"
 )
 
-// ErrorCode represents a unique error code for client handling
+// ErrorCode represents a unique error code for client handling. The string values are a
+// stable client contract: never rename an existing code, only add new ones (and register
+// them in registry.go so they are published via GET /api/v1/error-codes).
 type ErrorCode string
 
 const (
@@ -15,13 +17,22 @@ const (
 	ErrCodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
 	ErrCodePlayerNotFound     ErrorCode = "PLAYER_NOT_FOUND"
 	ErrCodeMatchesNotFound    ErrorCode = "MATCHES_NOT_FOUND"
+	ErrCodeMatchNotFound      ErrorCode = "MATCH_NOT_FOUND"
+	ErrCodeResourceNotFound   ErrorCode = "RESOURCE_NOT_FOUND"
 	ErrCodeInvalidRegion      ErrorCode = "INVALID_REGION"
+	ErrCodeRegionNotServed    ErrorCode = "REGION_NOT_SERVED"
 	ErrCodeMissingAPIKey      ErrorCode = "MISSING_API_KEY"
 	ErrCodeInvalidAPIKey      ErrorCode = "INVALID_API_KEY"
 	ErrCodeRateLimitExceeded  ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrCodeHeadersTooLarge    ErrorCode = "REQUEST_HEADERS_TOO_LARGE"
+	ErrCodeBodyTooLarge       ErrorCode = "REQUEST_BODY_TOO_LARGE"
+	ErrCodeUnsupportedSchema  ErrorCode = "UNSUPPORTED_SCHEMA_VERSION"
+	ErrCodeInvalidSignature   ErrorCode = "INVALID_SIGNATURE"
+	ErrCodeInsufficientData   ErrorCode = "INSUFFICIENT_DATA"
 
 	// Auth errors
 	ErrCodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden          ErrorCode = "FORBIDDEN"
 	ErrCodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
 	ErrCodeInvalidToken       ErrorCode = "INVALID_TOKEN"
 	ErrCodeEmailAlreadyExists ErrorCode = "EMAIL_ALREADY_EXISTS"
@@ -31,6 +42,7 @@ const (
 	ErrCodeDataServiceError   ErrorCode = "DATA_SERVICE_ERROR"
 	ErrCodeCortexServiceError ErrorCode = "CORTEX_SERVICE_ERROR"
 	ErrCodeInternalError      ErrorCode = "INTERNAL_ERROR"
+	ErrCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
 )
 
 // APIError represents a structured error response
@@ -38,6 +50,15 @@ type APIError struct {
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message"`
 	Status  int       `json:"-"`
+	// Upstream is the raw upstream response this error was mapped from, if any
+	Upstream *UpstreamResponse `json:"-"`
+}
+
+// UpstreamResponse is an upstream status code and body, kept for debugging clients that
+// ask for raw upstream responses
+type UpstreamResponse struct {
+	Status int
+	Body   []byte
 }
 
 // Error implements the error interface
@@ -45,6 +66,12 @@ func (apiError *APIError) Error() string {
 	return apiError.Message
 }
 
+// WithUpstream records the upstream response the error was mapped from and returns the error
+func (apiError *APIError) WithUpstream(status int, body []byte) *APIError {
+	apiError.Upstream = &UpstreamResponse{Status: status, Body: body}
+	return apiError
+}
+
 // ErrorResponse is the JSON structure returned to clients
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
@@ -82,6 +109,14 @@ func MatchesNotFound(message string) *APIError {
 	return NewAPIError(ErrCodeMatchesNotFound, message, http.StatusNotFound)
 }
 
+func MatchNotFound(matchID string) *APIError {
+	return NewAPIError(ErrCodeMatchNotFound, "Match not found: "+matchID, http.StatusNotFound)
+}
+
+func ResourceNotFound(message string) *APIError {
+	return NewAPIError(ErrCodeResourceNotFound, message, http.StatusNotFound)
+}
+
 func DataServiceError(message string) *APIError {
 	return NewAPIError(ErrCodeDataServiceError, message, http.StatusBadGateway)
 }
@@ -98,15 +133,56 @@ func ValidationFailed(message string) *APIError {
 	return NewAPIError(ErrCodeValidationFailed, message, http.StatusBadRequest)
 }
 
-// WriteError writes a JSON error response to the http.ResponseWriter
-func WriteError(writer http.ResponseWriter, apiError *APIError) {
+func Forbidden(message string) *APIError {
+	return NewAPIError(ErrCodeForbidden, message, http.StatusForbidden)
+}
+
+func RegionNotServed(region string) *APIError {
+	return NewAPIError(ErrCodeRegionNotServed, "Region not served by this gateway: "+region, http.StatusForbidden)
+}
+
+func HeadersTooLarge(message string) *APIError {
+	return NewAPIError(ErrCodeHeadersTooLarge, message, http.StatusRequestHeaderFieldsTooLarge)
+}
+
+func RequestBodyTooLarge(message string) *APIError {
+	return NewAPIError(ErrCodeBodyTooLarge, message, http.StatusRequestEntityTooLarge)
+}
+
+func UnsupportedSchemaVersion(message string) *APIError {
+	return NewAPIError(ErrCodeUnsupportedSchema, message, http.StatusBadRequest)
+}
+
+func InvalidSignature(message string) *APIError {
+	return NewAPIError(ErrCodeInvalidSignature, message, http.StatusUnauthorized)
+}
+
+func InsufficientData(message string) *APIError {
+	return NewAPIError(ErrCodeInsufficientData, message, http.StatusUnprocessableEntity)
+}
+
+func ServiceUnavailable(message string) *APIError {
+	return NewAPIError(ErrCodeServiceUnavailable, message, http.StatusServiceUnavailable)
+}
+
+// WriteError writes a JSON error response to the http.ResponseWriter, as RFC 7807
+// problem+json when WantsProblem(request) and in the default error shape otherwise.
+// Server-side error messages are sanitized in production (see ClientMessage).
+func WriteError(writer http.ResponseWriter, request *http.Request, apiError *APIError) {
+	if WantsProblem(request) {
+		writer.Header().Set("Content-Type", ProblemContentType)
+		writer.WriteHeader(apiError.Status)
+		json.NewEncoder(writer).Encode(NewProblemDetails(request, apiError))
+		return
+	}
+
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(apiError.Status)
 
 	errorResponse := ErrorResponse{
 		Error: ErrorDetail{
 			Code:    apiError.Code,
-			Message: apiError.Message,
+			Message: apiError.ClientMessage(),
 		},
 	}
 
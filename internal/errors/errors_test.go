@@ -136,7 +136,7 @@ func TestWriteError(t *testing.T) {
 	apiError := PlayerNotFound("TestPlayer", "NA1")
 
 	responseRecorder := httptest.NewRecorder()
-	WriteError(responseRecorder, apiError)
+	WriteError(responseRecorder, nil, apiError)
 
 	// Check status code
 	if responseRecorder.Code != http.StatusNotFound {
@@ -182,7 +182,7 @@ func TestWriteError_DifferentStatusCodes(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			responseRecorder := httptest.NewRecorder()
-			WriteError(responseRecorder, testCase.apiError)
+			WriteError(responseRecorder, nil, testCase.apiError)
 
 			if responseRecorder.Code != testCase.expectedStatus {
 				t.Errorf("Expected status %d, got %d", testCase.expectedStatus, responseRecorder.Code)
@@ -190,3 +190,73 @@ func TestWriteError_DifferentStatusCodes(t *testing.T) {
 		})
 	}
 }
+
+// TestForbidden tests the Forbidden constructor
+func TestForbidden(t *testing.T) {
+	apiError := Forbidden("Access denied")
+
+	if apiError.Code != ErrCodeForbidden {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeForbidden, apiError.Code)
+	}
+
+	if apiError.Status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, apiError.Status)
+	}
+}
+
+// TestServiceUnavailable tests the ServiceUnavailable constructor
+func TestServiceUnavailable(t *testing.T) {
+	apiError := ServiceUnavailable("Upstream rate limited")
+
+	if apiError.Code != ErrCodeServiceUnavailable {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeServiceUnavailable, apiError.Code)
+	}
+
+	if apiError.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, apiError.Status)
+	}
+}
+
+// TestInvalidSignature tests the InvalidSignature constructor
+func TestInvalidSignature(t *testing.T) {
+	apiError := InvalidSignature("Request signature does not match")
+
+	if apiError.Code != ErrCodeInvalidSignature {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeInvalidSignature, apiError.Code)
+	}
+
+	if apiError.Status != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, apiError.Status)
+	}
+}
+
+// TestInsufficientData tests the InsufficientData constructor
+func TestInsufficientData(t *testing.T) {
+	apiError := InsufficientData("At least 5 matches are required for analysis")
+
+	if apiError.Code != ErrCodeInsufficientData {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeInsufficientData, apiError.Code)
+	}
+
+	if apiError.Status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, apiError.Status)
+	}
+}
+
+// TestRegionNotServed tests the RegionNotServed constructor
+func TestRegionNotServed(t *testing.T) {
+	apiError := RegionNotServed("euw")
+
+	if apiError.Code != ErrCodeRegionNotServed {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeRegionNotServed, apiError.Code)
+	}
+
+	if apiError.Status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, apiError.Status)
+	}
+
+	expectedMessage := "Region not served by this gateway: euw"
+	if apiError.Message != expectedMessage {
+		t.Errorf("Expected message '%s', got '%s'", expectedMessage, apiError.Message)
+	}
+}
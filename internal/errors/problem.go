@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrorFormat selects the JSON shape of error responses
+type ErrorFormat string
+
+const (
+	// ErrorFormatJSON writes the gateway's {"error": {"code", "message"}} shape (default)
+	ErrorFormatJSON ErrorFormat = "json"
+	// ErrorFormatProblem writes RFC 7807 application/problem+json for every error
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+const (
+	// ProblemContentType is the RFC 7807 media type; clients sending it in Accept get
+	// problem+json errors regardless of the configured format
+	ProblemContentType = "application/problem+json"
+
+	// problemTypePrefix namespaces the stable problem type URIs derived from error codes
+	problemTypePrefix = "urn:opgl:problem:"
+)
+
+// problemFormatDefault is set when problem+json is the configured default error format
+var problemFormatDefault atomic.Bool
+
+// ProblemDetails is an RFC 7807 problem document. Code is an extension member carrying the
+// same ErrCode* value as the default error shape, so clients can switch formats safely.
+type ProblemDetails struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail"`
+	Instance string    `json:"instance,omitempty"`
+	Code     ErrorCode `json:"code"`
+}
+
+// ParseErrorFormat parses an ERROR_FORMAT value, defaulting to json when empty
+func ParseErrorFormat(value string) (ErrorFormat, error) {
+	switch ErrorFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ErrorFormatJSON:
+		return ErrorFormatJSON, nil
+	case ErrorFormatProblem:
+		return ErrorFormatProblem, nil
+	default:
+		return "", fmt.Errorf("invalid error format %q: expected json or problem", value)
+	}
+}
+
+// SetErrorFormat configures the default shape of every error written to clients. It is
+// called once at startup; the default is json.
+func SetErrorFormat(format ErrorFormat) {
+	problemFormatDefault.Store(format == ErrorFormatProblem)
+}
+
+// ProblemTypeURI returns the stable problem type URI for code, e.g.
+// urn:opgl:problem:validation-failed for VALIDATION_FAILED
+func ProblemTypeURI(code ErrorCode) string {
+	return problemTypePrefix + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// WantsProblem reports whether the error for request should be written as problem+json:
+// either it is the configured format or the client lists application/problem+json in Accept
+func WantsProblem(request *http.Request) bool {
+	if problemFormatDefault.Load() {
+		return true
+	}
+	if request == nil {
+		return false
+	}
+
+	for _, mediaRange := range strings.Split(request.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewProblemDetails builds the problem document for apiError. The title is the code's
+// published description, and the instance is the request path when a request is given.
+func NewProblemDetails(request *http.Request, apiError *APIError) ProblemDetails {
+	problem := ProblemDetails{
+		Type:   ProblemTypeURI(apiError.Code),
+		Title:  http.StatusText(apiError.Status),
+		Status: apiError.Status,
+		Detail: apiError.ClientMessage(),
+		Code:   apiError.Code,
+	}
+	if info, found := LookupErrorCode(apiError.Code); found {
+		problem.Title = info.Description
+	}
+	if request != nil {
+		problem.Instance = request.URL.Path
+	}
+	return problem
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseErrorFormat tests parsing of ERROR_FORMAT values
+func TestParseErrorFormat(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    ErrorFormat
+		expectError bool
+	}{
+		{"", ErrorFormatJSON, false},
+		{"json", ErrorFormatJSON, false},
+		{"Problem", ErrorFormatProblem, false},
+		{"xml", "", true},
+	}
+
+	for _, testCase := range testCases {
+		format, err := ParseErrorFormat(testCase.value)
+		if (err != nil) != testCase.expectError {
+			t.Errorf("ParseErrorFormat(%q): expected error=%v, got %v", testCase.value, testCase.expectError, err)
+		}
+		if format != testCase.expected {
+			t.Errorf("ParseErrorFormat(%q): expected '%s', got '%s'", testCase.value, testCase.expected, format)
+		}
+	}
+}
+
+// TestProblemTypeURI tests that every published code maps to a distinct type URI
+func TestProblemTypeURI(t *testing.T) {
+	if uri := ProblemTypeURI(ErrCodeValidationFailed); uri != "urn:opgl:problem:validation-failed" {
+		t.Errorf("Expected 'urn:opgl:problem:validation-failed', got '%s'", uri)
+	}
+
+	seen := make(map[string]ErrorCode)
+	for _, info := range ErrorCodes() {
+		uri := ProblemTypeURI(info.Code)
+		if previous, found := seen[uri]; found {
+			t.Errorf("Codes %s and %s share type URI '%s'", previous, info.Code, uri)
+		}
+		seen[uri] = info.Code
+	}
+}
+
+// TestWriteError_ProblemNegotiated tests that Accept: application/problem+json gets an RFC 7807 document
+func TestWriteError_ProblemNegotiated(t *testing.T) {
+	request := httptest.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set("Accept", "application/json;q=0.5, application/problem+json")
+
+	responseRecorder := httptest.NewRecorder()
+	WriteError(responseRecorder, request, ValidationFailed("region is required"))
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != ProblemContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", ProblemContentType, contentType)
+	}
+
+	var problem map[string]interface{}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"type":     "urn:opgl:problem:validation-failed",
+		"title":    "One or more fields failed validation",
+		"status":   float64(http.StatusBadRequest),
+		"detail":   "region is required",
+		"instance": "/api/v1/summoner",
+		"code":     string(ErrCodeValidationFailed),
+	}
+	for field, value := range expected {
+		if problem[field] != value {
+			t.Errorf("Expected %s %v, got %v", field, value, problem[field])
+		}
+	}
+	if _, found := problem["error"]; found {
+		t.Error("Expected no default error object in a problem document")
+	}
+}
+
+// TestWriteError_ProblemConfigured tests that ERROR_FORMAT=problem applies without an Accept header
+func TestWriteError_ProblemConfigured(t *testing.T) {
+	SetErrorFormat(ErrorFormatProblem)
+	defer SetErrorFormat(ErrorFormatJSON)
+
+	responseRecorder := httptest.NewRecorder()
+	WriteError(responseRecorder, httptest.NewRequest("GET", "/api/v1/match/NA1_1", nil), MatchNotFound("NA1_1"))
+
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != ProblemContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", ProblemContentType, contentType)
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if problem.Type != "urn:opgl:problem:match-not-found" || problem.Status != http.StatusNotFound || problem.Detail != "Match not found: NA1_1" {
+		t.Errorf("Unexpected problem document: %+v", problem)
+	}
+}
+
+// TestWriteError_DefaultFormat tests that a plain JSON Accept header keeps the default error shape
+func TestWriteError_DefaultFormat(t *testing.T) {
+	request := httptest.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set("Accept", "application/json")
+
+	responseRecorder := httptest.NewRecorder()
+	WriteError(responseRecorder, request, ValidationFailed("region is required"))
+
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", contentType)
+	}
+
+	var errorResponse ErrorResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errorResponse.Error.Code != ErrCodeValidationFailed {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeValidationFailed, errorResponse.Error.Code)
+	}
+}
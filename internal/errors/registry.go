@@ -0,0 +1,59 @@
+package errors
+
+import "net/http"
+
+// ErrorCodeInfo documents a published error code for clients
+type ErrorCodeInfo struct {
+	Code        ErrorCode `json:"code"`
+	Description string    `json:"description"`
+	Status      int       `json:"status"`
+}
+
+// errorCodeRegistry lists every error code the gateway can return, with its description
+// and typical HTTP status. Keep in sync with the ErrCode* constants.
+var errorCodeRegistry = []ErrorCodeInfo{
+	{ErrCodeInvalidRequestBody, "The request body is not valid JSON or has the wrong shape", http.StatusBadRequest},
+	{ErrCodeMissingFields, "One or more required fields or headers are missing", http.StatusBadRequest},
+	{ErrCodeValidationFailed, "One or more fields failed validation", http.StatusBadRequest},
+	{ErrCodePlayerNotFound, "No player exists for the given Riot ID and region", http.StatusNotFound},
+	{ErrCodeMatchesNotFound, "No matches were found for the player", http.StatusNotFound},
+	{ErrCodeMatchNotFound, "No match exists for the given match ID and region", http.StatusNotFound},
+	{ErrCodeResourceNotFound, "The data service has no resource matching a passthrough request", http.StatusNotFound},
+	{ErrCodeInvalidRegion, "The region is not a supported League of Legends region", http.StatusBadRequest},
+	{ErrCodeRegionNotServed, "The region is valid but this gateway deployment does not serve it", http.StatusForbidden},
+	{ErrCodeMissingAPIKey, "The request does not include an API key in any of the headers this gateway accepts", http.StatusUnauthorized},
+	{ErrCodeInvalidAPIKey, "The API key is invalid or inactive", http.StatusUnauthorized},
+	{ErrCodeRateLimitExceeded, "The API key has exceeded its rate limit; retry after the Retry-After delay", http.StatusTooManyRequests},
+	{ErrCodeHeadersTooLarge, "The request has too many header fields", http.StatusRequestHeaderFieldsTooLarge},
+	{ErrCodeBodyTooLarge, "The request body exceeds the size this endpoint accepts", http.StatusRequestEntityTooLarge},
+	{ErrCodeUnsupportedSchema, "The X-API-Schema request schema version is not accepted by this gateway", http.StatusBadRequest},
+	{ErrCodeInvalidSignature, "The X-Signature request signature is missing, does not match the body, or its X-Timestamp is outside the allowed window", http.StatusUnauthorized},
+	{ErrCodeInsufficientData, "The player has too few recent matches for a meaningful analysis", http.StatusUnprocessableEntity},
+	{ErrCodeUnauthorized, "The Authorization header is missing or malformed", http.StatusUnauthorized},
+	{ErrCodeForbidden, "The caller is not allowed to access this endpoint", http.StatusForbidden},
+	{ErrCodeInvalidCredentials, "The supplied credentials are incorrect", http.StatusUnauthorized},
+	{ErrCodeInvalidToken, "The access token is invalid or expired", http.StatusUnauthorized},
+	{ErrCodeEmailAlreadyExists, "An account with this email already exists", http.StatusConflict},
+	{ErrCodeUserNotFound, "The user does not exist", http.StatusNotFound},
+	{ErrCodeDataServiceError, "The data service failed or is unavailable", http.StatusBadGateway},
+	{ErrCodeCortexServiceError, "The analysis service failed or is unavailable", http.StatusBadGateway},
+	{ErrCodeInternalError, "An unexpected error occurred in the gateway", http.StatusInternalServerError},
+	{ErrCodeServiceUnavailable, "An upstream service is temporarily unavailable; retry later", http.StatusServiceUnavailable},
+}
+
+// ErrorCodes returns all published error codes in a stable order
+func ErrorCodes() []ErrorCodeInfo {
+	codes := make([]ErrorCodeInfo, len(errorCodeRegistry))
+	copy(codes, errorCodeRegistry)
+	return codes
+}
+
+// LookupErrorCode returns the registry entry for code, if it is published
+func LookupErrorCode(code ErrorCode) (ErrorCodeInfo, bool) {
+	for _, info := range errorCodeRegistry {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return ErrorCodeInfo{}, false
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestErrorCodes_ConstructorsRegistered tests that every constructor's code is published
+// with the status the constructor actually returns
+func TestErrorCodes_ConstructorsRegistered(t *testing.T) {
+	constructed := []*APIError{
+		InvalidRequestBody("test"),
+		MissingFields("test"),
+		PlayerNotFound("Test", "NA1"),
+		MatchesNotFound("test"),
+		MatchNotFound("NA1_1"),
+		ResourceNotFound("test"),
+		DataServiceError("test"),
+		CortexServiceError("test"),
+		InternalError("test"),
+		ValidationFailed("test"),
+		Forbidden("test"),
+		ServiceUnavailable("test"),
+		RegionNotServed("kr"),
+		HeadersTooLarge("test"),
+		RequestBodyTooLarge("test"),
+		UnsupportedSchemaVersion("test"),
+		InvalidSignature("test"),
+		InsufficientData("test"),
+	}
+
+	for _, apiError := range constructed {
+		info, found := LookupErrorCode(apiError.Code)
+		if !found {
+			t.Errorf("Error code '%s' is missing from the registry", apiError.Code)
+			continue
+		}
+
+		if info.Status != apiError.Status {
+			t.Errorf("Registry status for '%s' is %d, constructor returns %d", apiError.Code, info.Status, apiError.Status)
+		}
+	}
+}
+
+// TestErrorCodes_MiddlewareCodesRegistered tests that codes built directly with NewAPIError are published
+func TestErrorCodes_MiddlewareCodesRegistered(t *testing.T) {
+	for _, code := range []ErrorCode{
+		ErrCodeMissingAPIKey,
+		ErrCodeInvalidAPIKey,
+		ErrCodeRateLimitExceeded,
+		ErrCodeUnauthorized,
+		ErrCodeInvalidToken,
+	} {
+		if _, found := LookupErrorCode(code); !found {
+			t.Errorf("Error code '%s' is missing from the registry", code)
+		}
+	}
+}
+
+// TestErrorCodes_UniqueAndDescribed tests that each code is listed once with a description
+func TestErrorCodes_UniqueAndDescribed(t *testing.T) {
+	seen := make(map[ErrorCode]bool)
+
+	for _, info := range ErrorCodes() {
+		if seen[info.Code] {
+			t.Errorf("Error code '%s' is registered more than once", info.Code)
+		}
+		seen[info.Code] = true
+
+		if info.Description == "" {
+			t.Errorf("Error code '%s' has no description", info.Code)
+		}
+
+		if info.Status < http.StatusBadRequest {
+			t.Errorf("Error code '%s' has non-error status %d", info.Code, info.Status)
+		}
+	}
+}
+
+// TestErrorCodes_ReturnsCopy tests that callers cannot mutate the registry
+func TestErrorCodes_ReturnsCopy(t *testing.T) {
+	codes := ErrorCodes()
+	codes[0].Description = "mutated"
+
+	if ErrorCodes()[0].Description == "mutated" {
+		t.Error("Expected ErrorCodes to return a copy of the registry")
+	}
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Environment selects how much error detail is returned to clients
+type Environment string
+
+const (
+	// EnvironmentDevelopment returns error messages verbatim, including upstream bodies (default)
+	EnvironmentDevelopment Environment = "development"
+	// EnvironmentProduction replaces server-side error messages with generic client-safe text
+	EnvironmentProduction Environment = "production"
+)
+
+// genericServerErrorMessage is returned for 5xx codes without a published description
+const genericServerErrorMessage = "An unexpected error occurred"
+
+// sanitizeMessages is set in production, where server-side error details are not returned
+var sanitizeMessages atomic.Bool
+
+// ParseEnvironment parses an ENVIRONMENT value, defaulting to development when empty
+func ParseEnvironment(value string) (Environment, error) {
+	switch Environment(strings.ToLower(strings.TrimSpace(value))) {
+	case "", EnvironmentDevelopment:
+		return EnvironmentDevelopment, nil
+	case EnvironmentProduction:
+		return EnvironmentProduction, nil
+	default:
+		return "", fmt.Errorf("invalid environment %q: expected production or development", value)
+	}
+}
+
+// SetEnvironment configures error message sanitization for every error written to clients.
+// It is called once at startup; the default is development.
+func SetEnvironment(environment Environment) {
+	sanitizeMessages.Store(environment == EnvironmentProduction)
+}
+
+// IsSanitized reports whether the message returned to clients differs from Message, i.e. the
+// error is a server-side error and production sanitization is enabled
+func (apiError *APIError) IsSanitized() bool {
+	return sanitizeMessages.Load() && apiError.Status >= http.StatusInternalServerError
+}
+
+// ClientMessage returns the message to send to clients. In production, server-side (5xx)
+// errors, which may carry upstream bodies or internal details, get their code's published
+// description instead; client errors such as validation failures and not-found stay specific.
+func (apiError *APIError) ClientMessage() string {
+	if !apiError.IsSanitized() {
+		return apiError.Message
+	}
+
+	if info, found := LookupErrorCode(apiError.Code); found {
+		return info.Description
+	}
+	return genericServerErrorMessage
+}
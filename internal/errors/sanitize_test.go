@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestParseEnvironment tests parsing of ENVIRONMENT values
+func TestParseEnvironment(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    Environment
+		expectError bool
+	}{
+		{"", EnvironmentDevelopment, false},
+		{"development", EnvironmentDevelopment, false},
+		{"Production", EnvironmentProduction, false},
+		{"staging", "", true},
+	}
+
+	for _, testCase := range testCases {
+		environment, err := ParseEnvironment(testCase.value)
+		if (err != nil) != testCase.expectError {
+			t.Errorf("ParseEnvironment(%q): expected error=%v, got %v", testCase.value, testCase.expectError, err)
+		}
+		if environment != testCase.expected {
+			t.Errorf("ParseEnvironment(%q): expected '%s', got '%s'", testCase.value, testCase.expected, environment)
+		}
+	}
+}
+
+// TestWriteError_SanitizedInProduction tests that a 500's internal detail is redacted in production
+func TestWriteError_SanitizedInProduction(t *testing.T) {
+	SetEnvironment(EnvironmentProduction)
+	defer SetEnvironment(EnvironmentDevelopment)
+
+	responseRecorder := httptest.NewRecorder()
+	WriteError(responseRecorder, nil, InternalError("pq: connection refused at 10.0.3.7:5432"))
+
+	if responseRecorder.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status code %d, got %d", http.StatusInternalServerError, responseRecorder.Code)
+	}
+
+	var errorResponse ErrorResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errorResponse.Error.Code != ErrCodeInternalError {
+		t.Errorf("Expected code '%s', got '%s'", ErrCodeInternalError, errorResponse.Error.Code)
+	}
+	if strings.Contains(errorResponse.Error.Message, "10.0.3.7") {
+		t.Errorf("Expected internal detail to be redacted, got '%s'", errorResponse.Error.Message)
+	}
+	if errorResponse.Error.Message != "An unexpected error occurred in the gateway" {
+		t.Errorf("Expected the published description, got '%s'", errorResponse.Error.Message)
+	}
+}
+
+// TestClientMessage tests which errors keep their message in each environment
+func TestClientMessage(t *testing.T) {
+	upstreamError := DataServiceError("Data service error: stack trace at handler.go:42")
+	validationError := ValidationFailed("region: region is required")
+	notFoundError := PlayerNotFound("Faker", "KR1")
+
+	if upstreamError.ClientMessage() != upstreamError.Message {
+		t.Errorf("Expected verbose message in development, got '%s'", upstreamError.ClientMessage())
+	}
+
+	SetEnvironment(EnvironmentProduction)
+	defer SetEnvironment(EnvironmentDevelopment)
+
+	if strings.Contains(upstreamError.ClientMessage(), "handler.go") {
+		t.Errorf("Expected upstream detail to be redacted, got '%s'", upstreamError.ClientMessage())
+	}
+	if validationError.ClientMessage() != validationError.Message {
+		t.Errorf("Expected validation message to stay specific, got '%s'", validationError.ClientMessage())
+	}
+	if notFoundError.ClientMessage() != "Player not found: Faker#KR1" {
+		t.Errorf("Expected not-found message to stay specific, got '%s'", notFoundError.ClientMessage())
+	}
+	if NewAPIError("UNPUBLISHED", "secret", http.StatusBadGateway).ClientMessage() != genericServerErrorMessage {
+		t.Error("Expected unpublished 5xx codes to use the generic message")
+	}
+}
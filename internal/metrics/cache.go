@@ -0,0 +1,71 @@
+package metrics
+
+// Cache names used as the "cache" label on cache metrics
+const (
+	CacheSummoner = "summoner"
+	CacheAnalysis = "analysis"
+	CacheMatch    = "match"
+)
+
+// CacheMetrics counts cache hits and misses by cache name. A nil *CacheMetrics is valid
+// and records nothing, so callers need not check whether metrics are enabled.
+type CacheMetrics struct {
+	hits   *CounterVec
+	misses *CounterVec
+}
+
+// NewCacheMetrics registers the cache hit/miss counters with the registry
+func NewCacheMetrics(registry *Registry) *CacheMetrics {
+	return &CacheMetrics{
+		hits:   registry.NewCounterVec("gateway_cache_hits_total", "Cache lookups served from a fresh entry.", "cache"),
+		misses: registry.NewCounterVec("gateway_cache_misses_total", "Cache lookups that had to go upstream.", "cache"),
+	}
+}
+
+// Hit records a fresh cache hit
+func (cacheMetrics *CacheMetrics) Hit(cacheName string) {
+	if cacheMetrics != nil {
+		cacheMetrics.hits.Inc(cacheName)
+	}
+}
+
+// Miss records a cache miss
+func (cacheMetrics *CacheMetrics) Miss(cacheName string) {
+	if cacheMetrics != nil {
+		cacheMetrics.misses.Inc(cacheName)
+	}
+}
+
+// Hits returns the hit count for a cache
+func (cacheMetrics *CacheMetrics) Hits(cacheName string) float64 {
+	if cacheMetrics == nil {
+		return 0
+	}
+	return cacheMetrics.hits.Value(cacheName)
+}
+
+// HitRatios returns hits / (hits + misses) for every cache that has been looked up
+func (cacheMetrics *CacheMetrics) HitRatios() map[string]float64 {
+	ratios := make(map[string]float64)
+	if cacheMetrics == nil {
+		return ratios
+	}
+
+	hits := cacheMetrics.hits.values()
+	lookups := cacheMetrics.misses.values()
+	for cacheName, hitCount := range hits {
+		lookups[cacheName] += hitCount
+	}
+	for cacheName, lookupCount := range lookups {
+		ratios[cacheName] = hits[cacheName] / lookupCount
+	}
+	return ratios
+}
+
+// Misses returns the miss count for a cache
+func (cacheMetrics *CacheMetrics) Misses(cacheName string) float64 {
+	if cacheMetrics == nil {
+		return 0
+	}
+	return cacheMetrics.misses.Value(cacheName)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import "testing"
+
+// TestCacheMetrics tests hit and miss counting per cache name
+func TestCacheMetrics(t *testing.T) {
+	cacheMetrics := NewCacheMetrics(NewRegistry())
+
+	cacheMetrics.Miss(CacheSummoner)
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Miss(CacheMatch)
+
+	if cacheMetrics.Hits(CacheSummoner) != 2 || cacheMetrics.Misses(CacheSummoner) != 1 {
+		t.Errorf("Expected 2 hits and 1 miss for summoner, got %g and %g",
+			cacheMetrics.Hits(CacheSummoner), cacheMetrics.Misses(CacheSummoner))
+	}
+	if cacheMetrics.Misses(CacheMatch) != 1 {
+		t.Errorf("Expected 1 miss for match, got %g", cacheMetrics.Misses(CacheMatch))
+	}
+}
+
+// TestCacheMetrics_Nil tests that a nil CacheMetrics records nothing without panicking
+func TestCacheMetrics_Nil(t *testing.T) {
+	var cacheMetrics *CacheMetrics
+
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Miss(CacheSummoner)
+
+	if cacheMetrics.Hits(CacheSummoner) != 0 || cacheMetrics.Misses(CacheSummoner) != 0 {
+		t.Error("Expected nil CacheMetrics to report zero")
+	}
+}
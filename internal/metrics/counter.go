@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// labelSeparator joins label values into a series key; it cannot appear in valid UTF-8 text
+const labelSeparator = "\xff"
+
+// CounterVec is a family of monotonically increasing counters partitioned by label values
+type CounterVec struct {
+	metricName string
+	help       string
+	labelNames []string
+
+	mutex  sync.Mutex
+	series map[string]float64
+}
+
+// NewCounterVec creates a counter family and registers it with the registry
+func (registry *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	counterVec := &CounterVec{
+		metricName: name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]float64),
+	}
+	registry.register(counterVec)
+	return counterVec
+}
+
+// Inc adds one to the counter identified by labelValues
+func (counterVec *CounterVec) Inc(labelValues ...string) {
+	counterVec.Add(1, labelValues...)
+}
+
+// Add adds delta to the counter identified by labelValues. Negative deltas are ignored
+// because counters never decrease.
+func (counterVec *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := counterVec.seriesKey(labelValues)
+
+	counterVec.mutex.Lock()
+	counterVec.series[key] += delta
+	counterVec.mutex.Unlock()
+}
+
+// Value returns the current value of the counter identified by labelValues
+func (counterVec *CounterVec) Value(labelValues ...string) float64 {
+	key := counterVec.seriesKey(labelValues)
+
+	counterVec.mutex.Lock()
+	defer counterVec.mutex.Unlock()
+	return counterVec.series[key]
+}
+
+// seriesKey validates the label count and joins the values into a map key
+func (counterVec *CounterVec) seriesKey(labelValues []string) string {
+	return seriesKey(counterVec.metricName, counterVec.labelNames, labelValues)
+}
+
+// seriesKey validates that a metric family received one value per label name and joins the
+// values into a map key
+func seriesKey(metricName string, labelNames []string, labelValues []string) string {
+	if len(labelValues) != len(labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", metricName, len(labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, labelSeparator)
+}
+
+// values returns a copy of every series, keyed by its label values joined with ","
+func (counterVec *CounterVec) values() map[string]float64 {
+	counterVec.mutex.Lock()
+	defer counterVec.mutex.Unlock()
+
+	values := make(map[string]float64, len(counterVec.series))
+	for key, value := range counterVec.series {
+		values[strings.ReplaceAll(key, labelSeparator, ",")] = value
+	}
+	return values
+}
+
+// snapshot returns the series keyed by label values, or the plain value for an unlabeled counter
+func (counterVec *CounterVec) snapshot() interface{} {
+	if len(counterVec.labelNames) == 0 {
+		return counterVec.Value()
+	}
+	return counterVec.values()
+}
+
+// name returns the metric family name
+func (counterVec *CounterVec) name() string {
+	return counterVec.metricName
+}
+
+// writeTo renders the family with series sorted by label values
+func (counterVec *CounterVec) writeTo(writer *bufio.Writer) {
+	counterVec.mutex.Lock()
+	keys := make([]string, 0, len(counterVec.series))
+	for key := range counterVec.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]float64, len(keys))
+	for index, key := range keys {
+		values[index] = counterVec.series[key]
+	}
+	counterVec.mutex.Unlock()
+
+	writeHeader(writer, counterVec.metricName, counterVec.help, "counter")
+	for index, key := range keys {
+		var labelValues []string
+		if len(counterVec.labelNames) > 0 {
+			labelValues = strings.Split(key, labelSeparator)
+		}
+		fmt.Fprintf(writer, "%s%s %g\n", counterVec.metricName, formatLabels(counterVec.labelNames, labelValues), values[index])
+	}
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import "testing"
+
+// TestCounterVec tests incrementing and reading labeled counters
+func TestCounterVec(t *testing.T) {
+	counterVec := NewRegistry().NewCounterVec("test_total", "Test counter.", "cache")
+
+	counterVec.Inc("summoner")
+	counterVec.Add(2.5, "summoner")
+	counterVec.Add(-1, "summoner")
+	counterVec.Inc("match")
+
+	if counterVec.Value("summoner") != 3.5 {
+		t.Errorf("Expected summoner value 3.5, got %g", counterVec.Value("summoner"))
+	}
+	if counterVec.Value("match") != 1 {
+		t.Errorf("Expected match value 1, got %g", counterVec.Value("match"))
+	}
+	if counterVec.Value("analysis") != 0 {
+		t.Errorf("Expected unseen series to be 0, got %g", counterVec.Value("analysis"))
+	}
+}
+
+// TestCounterVec_WrongLabelCount tests that a label count mismatch panics
+func TestCounterVec_WrongLabelCount(t *testing.T) {
+	counterVec := NewRegistry().NewCounterVec("test_total", "Test counter.", "cache")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected label count mismatch to panic")
+		}
+	}()
+	counterVec.Inc("summoner", "extra")
+}
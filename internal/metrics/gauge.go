@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Gauge is a single value that can go up and down
+type Gauge struct {
+	metricName string
+	help       string
+
+	mutex sync.Mutex
+	value float64
+}
+
+// NewGauge creates a gauge and registers it with the registry
+func (registry *Registry) NewGauge(name string, help string) *Gauge {
+	gauge := &Gauge{metricName: name, help: help}
+	registry.register(gauge)
+	return gauge
+}
+
+// Inc adds one to the gauge
+func (gauge *Gauge) Inc() {
+	gauge.Add(1)
+}
+
+// Dec subtracts one from the gauge
+func (gauge *Gauge) Dec() {
+	gauge.Add(-1)
+}
+
+// Add adds delta, which may be negative, to the gauge
+func (gauge *Gauge) Add(delta float64) {
+	gauge.mutex.Lock()
+	gauge.value += delta
+	gauge.mutex.Unlock()
+}
+
+// Value returns the current value of the gauge
+func (gauge *Gauge) Value() float64 {
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+	return gauge.value
+}
+
+// name returns the metric name
+func (gauge *Gauge) name() string {
+	return gauge.metricName
+}
+
+// writeTo renders the gauge in the text exposition format
+func (gauge *Gauge) writeTo(writer *bufio.Writer) {
+	writeHeader(writer, gauge.metricName, gauge.help, "gauge")
+	fmt.Fprintf(writer, "%s %g\n", gauge.metricName, gauge.Value())
+}
+
+// snapshot returns the current value
+func (gauge *Gauge) snapshot() interface{} {
+	return gauge.Value()
+}
+
+// GaugeVec is a family of gauges partitioned by label values
+type GaugeVec struct {
+	metricName string
+	help       string
+	labelNames []string
+
+	mutex  sync.Mutex
+	series map[string]float64
+}
+
+// NewGaugeVec creates a gauge family and registers it with the registry
+func (registry *Registry) NewGaugeVec(name string, help string, labelNames ...string) *GaugeVec {
+	gaugeVec := &GaugeVec{
+		metricName: name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]float64),
+	}
+	registry.register(gaugeVec)
+	return gaugeVec
+}
+
+// Add adds delta, which may be negative, to the gauge identified by labelValues
+func (gaugeVec *GaugeVec) Add(delta float64, labelValues ...string) {
+	key := seriesKey(gaugeVec.metricName, gaugeVec.labelNames, labelValues)
+
+	gaugeVec.mutex.Lock()
+	gaugeVec.series[key] += delta
+	gaugeVec.mutex.Unlock()
+}
+
+// Set sets the gauge identified by labelValues to value
+func (gaugeVec *GaugeVec) Set(value float64, labelValues ...string) {
+	key := seriesKey(gaugeVec.metricName, gaugeVec.labelNames, labelValues)
+
+	gaugeVec.mutex.Lock()
+	gaugeVec.series[key] = value
+	gaugeVec.mutex.Unlock()
+}
+
+// Value returns the current value of the gauge identified by labelValues
+func (gaugeVec *GaugeVec) Value(labelValues ...string) float64 {
+	key := seriesKey(gaugeVec.metricName, gaugeVec.labelNames, labelValues)
+
+	gaugeVec.mutex.Lock()
+	defer gaugeVec.mutex.Unlock()
+	return gaugeVec.series[key]
+}
+
+// name returns the metric family name
+func (gaugeVec *GaugeVec) name() string {
+	return gaugeVec.metricName
+}
+
+// snapshot returns the series keyed by label values joined with ","
+func (gaugeVec *GaugeVec) snapshot() interface{} {
+	gaugeVec.mutex.Lock()
+	defer gaugeVec.mutex.Unlock()
+
+	values := make(map[string]float64, len(gaugeVec.series))
+	for key, value := range gaugeVec.series {
+		values[strings.ReplaceAll(key, labelSeparator, ",")] = value
+	}
+	return values
+}
+
+// writeTo renders the family with series sorted by label values
+func (gaugeVec *GaugeVec) writeTo(writer *bufio.Writer) {
+	gaugeVec.mutex.Lock()
+	keys := make([]string, 0, len(gaugeVec.series))
+	for key := range gaugeVec.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]float64, len(keys))
+	for index, key := range keys {
+		values[index] = gaugeVec.series[key]
+	}
+	gaugeVec.mutex.Unlock()
+
+	writeHeader(writer, gaugeVec.metricName, gaugeVec.help, "gauge")
+	for index, key := range keys {
+		fmt.Fprintf(writer, "%s%s %g\n", gaugeVec.metricName, formatLabels(gaugeVec.labelNames, strings.Split(key, labelSeparator)), values[index])
+	}
+}
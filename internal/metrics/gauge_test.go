@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestGauge tests that a gauge moves up and down and renders as a gauge
+func TestGauge(t *testing.T) {
+	gauge := NewRegistry().NewGauge("test_in_flight", "Test gauge.")
+
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+
+	if gauge.Value() != 1 {
+		t.Errorf("Expected gauge value 1, got %g", gauge.Value())
+	}
+
+	var output bytes.Buffer
+	writer := bufio.NewWriter(&output)
+	gauge.writeTo(writer)
+	writer.Flush()
+
+	if !strings.Contains(output.String(), "# TYPE test_in_flight gauge") || !strings.Contains(output.String(), "test_in_flight 1\n") {
+		t.Errorf("Unexpected gauge output:\n%s", output.String())
+	}
+}
+
+// TestGaugeVec tests that labeled gauges move independently and render one series per label value
+func TestGaugeVec(t *testing.T) {
+	gaugeVec := NewRegistry().NewGaugeVec("test_queue_depth", "Test gauge family.", "queue")
+
+	gaugeVec.Add(2, "api")
+	gaugeVec.Add(-1, "api")
+	gaugeVec.Add(5, "analyze")
+	gaugeVec.Set(3, "analyze")
+
+	if gaugeVec.Value("api") != 1 || gaugeVec.Value("analyze") != 3 {
+		t.Errorf("Expected api=1 and analyze=3, got api=%g analyze=%g", gaugeVec.Value("api"), gaugeVec.Value("analyze"))
+	}
+
+	var output bytes.Buffer
+	writer := bufio.NewWriter(&output)
+	gaugeVec.writeTo(writer)
+	writer.Flush()
+
+	expectedOutput := "# HELP test_queue_depth Test gauge family.\n# TYPE test_queue_depth gauge\n" +
+		"test_queue_depth{queue=\"analyze\"} 3\ntest_queue_depth{queue=\"api\"} 1\n"
+	if output.String() != expectedOutput {
+		t.Errorf("Unexpected gauge family output:\n%s", output.String())
+	}
+}
@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are bucket upper bounds, in seconds, suited to upstream call latencies
+var DefaultDurationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HistogramVec is a family of histograms partitioned by label values. Each series counts
+// observations into fixed buckets and tracks their sum and count.
+type HistogramVec struct {
+	metricName string
+	help       string
+	labelNames []string
+	buckets    []float64
+
+	mutex  sync.Mutex
+	series map[string]*histogramSeries
+}
+
+// histogramSeries holds the per-bucket (non-cumulative) counts, sum and count of one series
+type histogramSeries struct {
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// HistogramSnapshot is the JSON-friendly summary of one histogram series
+type HistogramSnapshot struct {
+	Count uint64  `json:"count"`
+	Sum   float64 `json:"sum"`
+}
+
+// NewHistogramVec creates a histogram family with the given bucket upper bounds (sorted
+// ascending; +Inf is implicit) and registers it with the registry
+func (registry *Registry) NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sortedBuckets := append([]float64(nil), buckets...)
+	sort.Float64s(sortedBuckets)
+
+	histogramVec := &HistogramVec{
+		metricName: name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    sortedBuckets,
+		series:     make(map[string]*histogramSeries),
+	}
+	registry.register(histogramVec)
+	return histogramVec
+}
+
+// Observe records value in the histogram identified by labelValues
+func (histogramVec *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := seriesKey(histogramVec.metricName, histogramVec.labelNames, labelValues)
+
+	histogramVec.mutex.Lock()
+	defer histogramVec.mutex.Unlock()
+
+	series, ok := histogramVec.series[key]
+	if !ok {
+		series = &histogramSeries{bucketCounts: make([]uint64, len(histogramVec.buckets))}
+		histogramVec.series[key] = series
+	}
+
+	if bucketIndex := sort.SearchFloat64s(histogramVec.buckets, value); bucketIndex < len(histogramVec.buckets) {
+		series.bucketCounts[bucketIndex]++
+	}
+	series.sum += value
+	series.count++
+}
+
+// Count returns the number of observations in the histogram identified by labelValues
+func (histogramVec *HistogramVec) Count(labelValues ...string) uint64 {
+	return histogramVec.Snapshot(labelValues...).Count
+}
+
+// Snapshot returns the count and sum of the histogram identified by labelValues
+func (histogramVec *HistogramVec) Snapshot(labelValues ...string) HistogramSnapshot {
+	key := seriesKey(histogramVec.metricName, histogramVec.labelNames, labelValues)
+
+	histogramVec.mutex.Lock()
+	defer histogramVec.mutex.Unlock()
+
+	series, ok := histogramVec.series[key]
+	if !ok {
+		return HistogramSnapshot{}
+	}
+	return HistogramSnapshot{Count: series.count, Sum: series.sum}
+}
+
+// snapshot returns count and sum per series keyed by label values, or a single summary for
+// an unlabeled histogram
+func (histogramVec *HistogramVec) snapshot() interface{} {
+	if len(histogramVec.labelNames) == 0 {
+		return histogramVec.Snapshot()
+	}
+
+	histogramVec.mutex.Lock()
+	defer histogramVec.mutex.Unlock()
+
+	values := make(map[string]HistogramSnapshot, len(histogramVec.series))
+	for key, series := range histogramVec.series {
+		values[strings.ReplaceAll(key, labelSeparator, ",")] = HistogramSnapshot{Count: series.count, Sum: series.sum}
+	}
+	return values
+}
+
+// name returns the metric family name
+func (histogramVec *HistogramVec) name() string {
+	return histogramVec.metricName
+}
+
+// writeTo renders cumulative buckets, sum and count for each series sorted by label values
+func (histogramVec *HistogramVec) writeTo(writer *bufio.Writer) {
+	histogramVec.mutex.Lock()
+	defer histogramVec.mutex.Unlock()
+
+	keys := make([]string, 0, len(histogramVec.series))
+	for key := range histogramVec.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(writer, histogramVec.metricName, histogramVec.help, "histogram")
+	bucketLabelNames := append(append([]string(nil), histogramVec.labelNames...), "le")
+	for _, key := range keys {
+		series := histogramVec.series[key]
+		var labelValues []string
+		if len(histogramVec.labelNames) > 0 {
+			labelValues = strings.Split(key, labelSeparator)
+		}
+
+		var cumulative uint64
+		for bucketIndex, upperBound := range histogramVec.buckets {
+			cumulative += series.bucketCounts[bucketIndex]
+			bucketLabelValues := append(append([]string(nil), labelValues...), strconv.FormatFloat(upperBound, 'g', -1, 64))
+			fmt.Fprintf(writer, "%s_bucket%s %d\n", histogramVec.metricName, formatLabels(bucketLabelNames, bucketLabelValues), cumulative)
+		}
+		infLabelValues := append(append([]string(nil), labelValues...), "+Inf")
+		fmt.Fprintf(writer, "%s_bucket%s %d\n", histogramVec.metricName, formatLabels(bucketLabelNames, infLabelValues), series.count)
+
+		labels := formatLabels(histogramVec.labelNames, labelValues)
+		fmt.Fprintf(writer, "%s_sum%s %g\n", histogramVec.metricName, labels, series.sum)
+		fmt.Fprintf(writer, "%s_count%s %d\n", histogramVec.metricName, labels, series.count)
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+// TestHistogramVec tests bucketing, sum and count of labeled histograms
+func TestHistogramVec(t *testing.T) {
+	histogramVec := NewRegistry().NewHistogramVec("test_duration_seconds", "Test durations.", []float64{1, 0.1}, "phase")
+
+	histogramVec.Observe(0.05, "connect")
+	histogramVec.Observe(0.1, "connect")
+	histogramVec.Observe(0.5, "connect")
+	histogramVec.Observe(3, "connect")
+	histogramVec.Observe(0.2, "dns")
+
+	if histogramVec.Count("connect") != 4 {
+		t.Errorf("Expected connect count 4, got %d", histogramVec.Count("connect"))
+	}
+	if histogramVec.Snapshot("connect").Sum != 3.65 {
+		t.Errorf("Expected connect sum 3.65, got %g", histogramVec.Snapshot("connect").Sum)
+	}
+	if histogramVec.Count("tls") != 0 {
+		t.Errorf("Expected unseen series count 0, got %d", histogramVec.Count("tls"))
+	}
+
+	var output bytes.Buffer
+	writer := bufio.NewWriter(&output)
+	histogramVec.writeTo(writer)
+	writer.Flush()
+
+	expected := `# HELP test_duration_seconds Test durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{phase="connect",le="0.1"} 2
+test_duration_seconds_bucket{phase="connect",le="1"} 3
+test_duration_seconds_bucket{phase="connect",le="+Inf"} 4
+test_duration_seconds_sum{phase="connect"} 3.65
+test_duration_seconds_count{phase="connect"} 4
+test_duration_seconds_bucket{phase="dns",le="0.1"} 0
+test_duration_seconds_bucket{phase="dns",le="1"} 1
+test_duration_seconds_bucket{phase="dns",le="+Inf"} 1
+test_duration_seconds_sum{phase="dns"} 0.2
+test_duration_seconds_count{phase="dns"} 1
+`
+	if output.String() != expected {
+		t.Errorf("Unexpected exposition output:\n%s", output.String())
+	}
+}
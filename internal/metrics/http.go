@@ -0,0 +1,74 @@
+package metrics
+
+import "strconv"
+
+// DefaultBodySizeBuckets are bucket upper bounds, in bytes, suited to JSON request bodies
+var DefaultBodySizeBuckets = []float64{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+
+// HTTPMetrics counts gateway requests by status class, tracks how many are in flight and
+// records request body sizes per endpoint. A nil *HTTPMetrics is valid and records nothing.
+type HTTPMetrics struct {
+	requests  *CounterVec
+	inFlight  *Gauge
+	bodySizes *HistogramVec
+}
+
+// NewHTTPMetrics registers the request counter, in-flight gauge and body size histogram with the registry
+func NewHTTPMetrics(registry *Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests:  registry.NewCounterVec("gateway_http_requests_total", "Requests handled by the gateway by response status class.", "status_class"),
+		inFlight:  registry.NewGauge("gateway_http_requests_in_flight", "Requests currently being handled."),
+		bodySizes: registry.NewHistogramVec("gateway_http_request_body_bytes", "Request body bytes read by API handlers, by endpoint.", DefaultBodySizeBuckets, "endpoint"),
+	}
+}
+
+// Started records a request entering the gateway
+func (httpMetrics *HTTPMetrics) Started() {
+	if httpMetrics != nil {
+		httpMetrics.inFlight.Inc()
+	}
+}
+
+// Finished records a request leaving the gateway with statusCode
+func (httpMetrics *HTTPMetrics) Finished(statusCode int) {
+	if httpMetrics != nil {
+		httpMetrics.inFlight.Dec()
+		httpMetrics.requests.Inc(StatusClass(statusCode))
+	}
+}
+
+// Requests returns the number of finished requests in a status class such as "2xx"
+func (httpMetrics *HTTPMetrics) Requests(statusClass string) float64 {
+	if httpMetrics == nil {
+		return 0
+	}
+	return httpMetrics.requests.Value(statusClass)
+}
+
+// InFlight returns the number of requests currently being handled
+func (httpMetrics *HTTPMetrics) InFlight() float64 {
+	if httpMetrics == nil {
+		return 0
+	}
+	return httpMetrics.inFlight.Value()
+}
+
+// ObserveRequestBody records the number of body bytes a request to endpoint read
+func (httpMetrics *HTTPMetrics) ObserveRequestBody(endpoint string, bytesRead int64) {
+	if httpMetrics != nil {
+		httpMetrics.bodySizes.Observe(float64(bytesRead), endpoint)
+	}
+}
+
+// RequestBodySizes returns the count and sum of body sizes recorded for endpoint
+func (httpMetrics *HTTPMetrics) RequestBodySizes(endpoint string) HistogramSnapshot {
+	if httpMetrics == nil {
+		return HistogramSnapshot{}
+	}
+	return httpMetrics.bodySizes.Snapshot(endpoint)
+}
+
+// StatusClass returns the class of an HTTP status code, e.g. "4xx" for 404
+func StatusClass(statusCode int) string {
+	return strconv.Itoa(statusCode/100) + "xx"
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// contentType is the Prometheus text exposition format version served by Handler
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// collector is a metric family that can render itself in the text exposition format and
+// as a JSON-friendly snapshot
+type collector interface {
+	name() string
+	writeTo(writer *bufio.Writer)
+	snapshot() interface{}
+}
+
+// Registry holds metric families and serves them in the Prometheus text format
+type Registry struct {
+	mutex      sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register adds a collector, panicking on duplicate names since that is a programming error
+func (registry *Registry) register(metric collector) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, exists := registry.collectors[metric.name()]; exists {
+		panic("metrics: duplicate metric name " + metric.name())
+	}
+	registry.collectors[metric.name()] = metric
+}
+
+// sortedCollectors returns the registered collectors sorted by name
+func (registry *Registry) sortedCollectors() []collector {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	names := make([]string, 0, len(registry.collectors))
+	for name := range registry.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, len(names))
+	for index, name := range names {
+		collectors[index] = registry.collectors[name]
+	}
+	return collectors
+}
+
+// Handler returns an http.Handler that writes every registered metric, sorted by name
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		collectors := registry.sortedCollectors()
+
+		responseWriter.Header().Set("Content-Type", contentType)
+		bufferedWriter := bufio.NewWriter(responseWriter)
+		for _, metric := range collectors {
+			metric.writeTo(bufferedWriter)
+		}
+		bufferedWriter.Flush()
+	})
+}
+
+// writeHeader writes the HELP and TYPE lines for a metric family
+func writeHeader(writer *bufio.Writer, name string, help string, metricType string) {
+	fmt.Fprintf(writer, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(writer, "# TYPE %s %s\n", name, metricType)
+}
+
+// formatLabels renders label pairs as {name="value",...}, or an empty string when there are none
+func formatLabels(labelNames []string, labelValues []string) string {
+	if len(labelNames) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(labelNames))
+	for index, labelName := range labelNames {
+		pairs[index] = labelName + `="` + escaper.Replace(labelValues[index]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRegistryHandler tests that registered metrics are rendered in the Prometheus text format
+func TestRegistryHandler(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("test_requests_total", "Requests handled.", "route")
+	registry.NewCounterVec("a_first_total", "Sorted ahead of test_requests_total.")
+
+	requests.Inc("/b")
+	requests.Add(2, "/a")
+
+	request, _ := http.NewRequest("GET", "/metrics", nil)
+	responseRecorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(responseRecorder, request)
+
+	if !strings.HasPrefix(responseRecorder.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected text/plain content type, got '%s'", responseRecorder.Header().Get("Content-Type"))
+	}
+
+	expected := `# HELP a_first_total Sorted ahead of test_requests_total.
+# TYPE a_first_total counter
+# HELP test_requests_total Requests handled.
+# TYPE test_requests_total counter
+test_requests_total{route="/a"} 2
+test_requests_total{route="/b"} 1
+`
+	if responseRecorder.Body.String() != expected {
+		t.Errorf("Unexpected exposition output:\n%s", responseRecorder.Body.String())
+	}
+}
+
+// TestRegistryDuplicateName tests that registering a metric name twice panics
+func TestRegistryDuplicateName(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("duplicate_total", "First.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	registry.NewCounterVec("duplicate_total", "Second.")
+}
+
+// TestFormatLabels tests label rendering and escaping
+func TestFormatLabels(t *testing.T) {
+	if formatLabels(nil, nil) != "" {
+		t.Error("Expected no labels to render as an empty string")
+	}
+
+	rendered := formatLabels([]string{"path", "quote"}, []string{`C:\tmp`, `say "hi"`})
+	expected := `{path="C:\\tmp",quote="say \"hi\""}`
+	if rendered != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, rendered)
+	}
+}
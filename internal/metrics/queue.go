@@ -0,0 +1,78 @@
+package metrics
+
+import "time"
+
+// Bulkhead names used as the "bulkhead" label on queue metrics
+const (
+	BulkheadAPI     = "api"
+	BulkheadAnalyze = "analyze"
+)
+
+// Reasons a queued request was rejected, used as the "reason" label on queue rejections
+const (
+	QueueRejectFull    = "queue_full"
+	QueueRejectTimeout = "timeout"
+)
+
+// QueueMetrics tracks requests waiting for a concurrency slot: the current queue depth, how
+// long requests waited, and rejections by reason. A nil *QueueMetrics is valid and records nothing.
+type QueueMetrics struct {
+	depth      *GaugeVec
+	waits      *HistogramVec
+	rejections *CounterVec
+}
+
+// NewQueueMetrics registers the queue depth gauge, wait histogram and rejection counter with the registry
+func NewQueueMetrics(registry *Registry) *QueueMetrics {
+	return &QueueMetrics{
+		depth:      registry.NewGaugeVec("gateway_queue_depth", "Requests currently waiting for a concurrency slot.", "bulkhead"),
+		waits:      registry.NewHistogramVec("gateway_queue_wait_seconds", "Time queued requests waited before getting a slot or being rejected.", DefaultDurationBuckets, "bulkhead"),
+		rejections: registry.NewCounterVec("gateway_queue_rejections_total", "Requests rejected with 503 because the queue was full or the wait timed out.", "bulkhead", "reason"),
+	}
+}
+
+// Enqueued records a request starting to wait
+func (queueMetrics *QueueMetrics) Enqueued(bulkhead string) {
+	if queueMetrics != nil {
+		queueMetrics.depth.Add(1, bulkhead)
+	}
+}
+
+// Dequeued records a request that stopped waiting after waited, whatever the outcome
+func (queueMetrics *QueueMetrics) Dequeued(bulkhead string, waited time.Duration) {
+	if queueMetrics != nil {
+		queueMetrics.depth.Add(-1, bulkhead)
+		queueMetrics.waits.Observe(waited.Seconds(), bulkhead)
+	}
+}
+
+// Rejected records a request rejected for reason
+func (queueMetrics *QueueMetrics) Rejected(bulkhead string, reason string) {
+	if queueMetrics != nil {
+		queueMetrics.rejections.Inc(bulkhead, reason)
+	}
+}
+
+// Depth returns the number of requests currently waiting
+func (queueMetrics *QueueMetrics) Depth(bulkhead string) float64 {
+	if queueMetrics == nil {
+		return 0
+	}
+	return queueMetrics.depth.Value(bulkhead)
+}
+
+// Waits returns how many queued waits have been observed
+func (queueMetrics *QueueMetrics) Waits(bulkhead string) uint64 {
+	if queueMetrics == nil {
+		return 0
+	}
+	return queueMetrics.waits.Count(bulkhead)
+}
+
+// Rejections returns the number of requests rejected for reason
+func (queueMetrics *QueueMetrics) Rejections(bulkhead string, reason string) float64 {
+	if queueMetrics == nil {
+		return 0
+	}
+	return queueMetrics.rejections.Value(bulkhead, reason)
+}
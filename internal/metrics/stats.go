@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Stats is the JSON snapshot served by StatsHandler
+type Stats struct {
+	// Metrics maps each registered metric name to its value, or for labeled families to a
+	// map from label values (joined with ",") to value
+	Metrics map[string]interface{} `json:"metrics"`
+	// CacheHitRatio maps each cache name to hits / (hits + misses)
+	CacheHitRatio map[string]float64 `json:"cacheHitRatio"`
+}
+
+// Snapshot returns the current value of every registered metric, keyed by metric name
+func (registry *Registry) Snapshot() map[string]interface{} {
+	snapshot := make(map[string]interface{})
+	for _, metric := range registry.sortedCollectors() {
+		snapshot[metric.name()] = metric.snapshot()
+	}
+	return snapshot
+}
+
+// StatsHandler returns an http.Handler serving the registry as JSON, for deployments that
+// do not scrape the Prometheus endpoint. Cache hit ratios are derived from cacheMetrics.
+func (registry *Registry) StatsHandler(cacheMetrics *CacheMetrics) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		stats := Stats{
+			Metrics:       registry.Snapshot(),
+			CacheHitRatio: cacheMetrics.HitRatios(),
+		}
+
+		responseWriter.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(responseWriter).Encode(stats)
+	})
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStatsHandler tests that the JSON snapshot reports request, upstream, cache and in-flight values
+func TestStatsHandler(t *testing.T) {
+	registry := NewRegistry()
+	httpMetrics := NewHTTPMetrics(registry)
+	upstreamMetrics := NewUpstreamMetrics(registry)
+	cacheMetrics := NewCacheMetrics(registry)
+
+	// Simulated traffic: three finished requests, one still in flight
+	for _, statusCode := range []int{http.StatusOK, http.StatusOK, http.StatusBadGateway} {
+		httpMetrics.Started()
+		httpMetrics.Finished(statusCode)
+	}
+	httpMetrics.Started()
+	upstreamMetrics.Error(UpstreamData)
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Hit(CacheSummoner)
+	cacheMetrics.Miss(CacheSummoner)
+
+	responseRecorder := httptest.NewRecorder()
+	registry.StatsHandler(cacheMetrics).ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got '%s'", contentType)
+	}
+
+	var stats struct {
+		Metrics struct {
+			Requests       map[string]float64 `json:"gateway_http_requests_total"`
+			InFlight       float64            `json:"gateway_http_requests_in_flight"`
+			UpstreamErrors map[string]float64 `json:"gateway_upstream_errors_total"`
+			CacheHits      map[string]float64 `json:"gateway_cache_hits_total"`
+		} `json:"metrics"`
+		CacheHitRatio map[string]float64 `json:"cacheHitRatio"`
+	}
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode stats: %v", err)
+	}
+
+	if stats.Metrics.Requests["2xx"] != 2 || stats.Metrics.Requests["5xx"] != 1 {
+		t.Errorf("Expected 2 2xx and 1 5xx requests, got %v", stats.Metrics.Requests)
+	}
+	if stats.Metrics.InFlight != 1 {
+		t.Errorf("Expected 1 request in flight, got %g", stats.Metrics.InFlight)
+	}
+	if stats.Metrics.UpstreamErrors[UpstreamData] != 1 {
+		t.Errorf("Expected 1 data upstream error, got %v", stats.Metrics.UpstreamErrors)
+	}
+	if stats.Metrics.CacheHits[CacheSummoner] != 3 {
+		t.Errorf("Expected 3 summoner cache hits, got %v", stats.Metrics.CacheHits)
+	}
+	if math.Abs(stats.CacheHitRatio[CacheSummoner]-0.75) > 1e-9 {
+		t.Errorf("Expected summoner hit ratio 0.75, got %v", stats.CacheHitRatio)
+	}
+}
+
+// TestStatsHandler_Empty tests that an unused registry still serves valid JSON
+func TestStatsHandler_Empty(t *testing.T) {
+	responseRecorder := httptest.NewRecorder()
+	NewRegistry().StatsHandler(nil).ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	var stats Stats
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode stats: %v", err)
+	}
+	if len(stats.Metrics) != 0 || len(stats.CacheHitRatio) != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
+
+// TestStatusClass tests status code grouping
+func TestStatusClass(t *testing.T) {
+	for statusCode, expected := range map[int]string{200: "2xx", 204: "2xx", 404: "4xx", 431: "4xx", 503: "5xx"} {
+		if got := StatusClass(statusCode); got != expected {
+			t.Errorf("Expected %d to be '%s', got '%s'", statusCode, expected, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Upstream service names used as the "service" label on upstream metrics
+const (
+	UpstreamData   = "data"
+	UpstreamCortex = "cortex"
+	UpstreamAuth   = "auth"
+)
+
+// StatusCodeError is the "status_code" label of upstream calls that got no response
+const StatusCodeError = "error"
+
+// UpstreamMetrics counts upstream call attempts by operation and status, failed calls and fallbacks to secondary instances by
+// service, reports the health score of each load-balanced endpoint and, when connection tracing
+// is enabled, records connection phase durations. A nil *UpstreamMetrics is valid and records nothing.
+type UpstreamMetrics struct {
+	calls          *CounterVec
+	errors         *CounterVec
+	fallbacks      *CounterVec
+	endpointScores *GaugeVec
+	phases         *HistogramVec
+}
+
+// NewUpstreamMetrics registers the upstream metric families with the registry
+func NewUpstreamMetrics(registry *Registry) *UpstreamMetrics {
+	return &UpstreamMetrics{
+		calls:          registry.NewCounterVec("gateway_upstream_requests_total", "Upstream call attempts by service, operation and response status code (\"error\" when no response was received).", "service", "operation", "status_code"),
+		errors:         registry.NewCounterVec("gateway_upstream_errors_total", "Upstream calls that failed to connect or returned a 5xx status.", "service"),
+		fallbacks:      registry.NewCounterVec("gateway_upstream_fallbacks_total", "Upstream calls retried on a fallback instance after the primary failed.", "service"),
+		endpointScores: registry.NewGaugeVec("gateway_upstream_endpoint_score", "Health score (0-1) of each load-balanced upstream endpoint, from its recent error rate and latency; higher scores receive more traffic.", "service", "endpoint"),
+		phases:         registry.NewHistogramVec("gateway_upstream_phase_duration_seconds", "Upstream call phase durations (dns, connect, tls, first_byte) when connection tracing is enabled.", DefaultDurationBuckets, "service", "phase"),
+	}
+}
+
+// Call records an upstream call attempt for an operation (e.g. "summoner") and the status code
+// it got; a zero statusCode means no response was received
+func (upstreamMetrics *UpstreamMetrics) Call(service string, operation string, statusCode int) {
+	if upstreamMetrics != nil {
+		upstreamMetrics.calls.Inc(service, operation, statusCodeLabel(statusCode))
+	}
+}
+
+// Calls returns the number of upstream call attempts with the given labels
+func (upstreamMetrics *UpstreamMetrics) Calls(service string, operation string, statusCode string) float64 {
+	if upstreamMetrics == nil {
+		return 0
+	}
+	return upstreamMetrics.calls.Value(service, operation, statusCode)
+}
+
+// statusCodeLabel returns the "status_code" label for a response status, or StatusCodeError for none
+func statusCodeLabel(statusCode int) string {
+	if statusCode == 0 {
+		return StatusCodeError
+	}
+	return strconv.Itoa(statusCode)
+}
+
+// Error records a failed call to an upstream service
+func (upstreamMetrics *UpstreamMetrics) Error(service string) {
+	if upstreamMetrics != nil {
+		upstreamMetrics.errors.Inc(service)
+	}
+}
+
+// Errors returns the failed call count for an upstream service
+func (upstreamMetrics *UpstreamMetrics) Errors(service string) float64 {
+	if upstreamMetrics == nil {
+		return 0
+	}
+	return upstreamMetrics.errors.Value(service)
+}
+
+// Fallback records a call that was retried on an upstream service's fallback instance
+func (upstreamMetrics *UpstreamMetrics) Fallback(service string) {
+	if upstreamMetrics != nil {
+		upstreamMetrics.fallbacks.Inc(service)
+	}
+}
+
+// Fallbacks returns the number of calls retried on an upstream service's fallback instance
+func (upstreamMetrics *UpstreamMetrics) Fallbacks(service string) float64 {
+	if upstreamMetrics == nil {
+		return 0
+	}
+	return upstreamMetrics.fallbacks.Value(service)
+}
+
+// SetEndpointScore records the current health score of a load-balanced upstream endpoint
+func (upstreamMetrics *UpstreamMetrics) SetEndpointScore(service string, endpoint string, score float64) {
+	if upstreamMetrics != nil {
+		upstreamMetrics.endpointScores.Set(score, service, endpoint)
+	}
+}
+
+// EndpointScore returns the last recorded health score of a load-balanced upstream endpoint
+func (upstreamMetrics *UpstreamMetrics) EndpointScore(service string, endpoint string) float64 {
+	if upstreamMetrics == nil {
+		return 0
+	}
+	return upstreamMetrics.endpointScores.Value(service, endpoint)
+}
+
+// ObservePhase records how long a connection phase of an upstream call took
+func (upstreamMetrics *UpstreamMetrics) ObservePhase(service string, phase string, duration time.Duration) {
+	if upstreamMetrics != nil {
+		upstreamMetrics.phases.Observe(duration.Seconds(), service, phase)
+	}
+}
+
+// PhaseCount returns how many durations were recorded for a connection phase of an upstream service
+func (upstreamMetrics *UpstreamMetrics) PhaseCount(service string, phase string) uint64 {
+	if upstreamMetrics == nil {
+		return 0
+	}
+	return upstreamMetrics.phases.Count(service, phase)
+}
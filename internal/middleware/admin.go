@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// AdminTokenHeader carries the shared secret for admin endpoints
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminTokenMiddleware protects admin endpoints with a shared token. When no token is
+// configured, admin endpoints are disabled and every request is rejected with 403.
+func AdminTokenMiddleware(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if adminToken == "" {
+				apierrors.WriteError(responseWriter, request, apierrors.Forbidden("Admin endpoints are disabled"))
+				return
+			}
+
+			providedToken := request.Header.Get(AdminTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(providedToken), []byte(adminToken)) != 1 {
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
+					apierrors.ErrCodeUnauthorized,
+					"A valid "+AdminTokenHeader+" header is required",
+					http.StatusUnauthorized,
+				))
+				return
+			}
+
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAdminTokenMiddleware tests admin token enforcement
+func TestAdminTokenMiddleware(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configured     string
+		provided       string
+		expectedStatus int
+	}{
+		{"valid token", "secret-token", "secret-token", http.StatusOK},
+		{"wrong token", "secret-token", "guess", http.StatusUnauthorized},
+		{"missing token", "secret-token", "", http.StatusUnauthorized},
+		{"admin disabled", "", "", http.StatusForbidden},
+		{"admin disabled ignores header", "", "anything", http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+			middleware := AdminTokenMiddleware(testCase.configured)(nextHandler)
+
+			request, _ := http.NewRequest("POST", "/admin/cache/warm", nil)
+			if testCase.provided != "" {
+				request.Header.Set(AdminTokenHeader, testCase.provided)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -9,23 +8,42 @@ import (
 	"time"
 
 	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
 	"github.com/google/uuid"
 )
 
 // AuthServiceClient handles communication with the auth service
 type AuthServiceClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	retryPolicy retryPolicy
+	// upstreamMetrics counts calls to the auth service; nil records nothing
+	upstreamMetrics *metrics.UpstreamMetrics
+}
+
+// AuthClientOption configures an AuthServiceClient
+type AuthClientOption func(*AuthServiceClient)
+
+// WithAuthUpstreamMetrics counts token validation calls as "auth" upstream calls
+func WithAuthUpstreamMetrics(upstreamMetrics *metrics.UpstreamMetrics) AuthClientOption {
+	return func(client *AuthServiceClient) {
+		client.upstreamMetrics = upstreamMetrics
+	}
 }
 
 // NewAuthServiceClient creates a new auth service client
-func NewAuthServiceClient(baseURL string) *AuthServiceClient {
-	return &AuthServiceClient{
+func NewAuthServiceClient(baseURL string, options ...AuthClientOption) *AuthServiceClient {
+	client := &AuthServiceClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
+		retryPolicy: defaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(client)
 	}
+	return client
 }
 
 // validateTokenRequest represents the request to validate a token
@@ -38,10 +56,11 @@ type validateTokenResponse struct {
 	Valid  bool   `json:"valid"`
 	UserID string `json:"userId,omitempty"`
 	Email  string `json:"email,omitempty"`
+	OrgID  string `json:"orgId,omitempty"`
 }
 
-// ValidateToken calls the auth service to validate a token
-func (client *AuthServiceClient) ValidateToken(token string) (*validateTokenResponse, error) {
+// ValidateToken calls the auth service to validate a token, giving up when ctx is done
+func (client *AuthServiceClient) ValidateToken(ctx context.Context, token string) (*validateTokenResponse, error) {
 	requestBody := validateTokenRequest{Token: token}
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
@@ -49,7 +68,7 @@ func (client *AuthServiceClient) ValidateToken(token string) (*validateTokenResp
 	}
 
 	url := client.baseURL + "/api/v1/auth/validate"
-	resp, err := client.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := postJSONWithRetry(ctx, client.httpClient, client.retryPolicy, client.upstreamMetrics, "validate", url, jsonData)
 	if err != nil {
 		return nil, err
 	}
@@ -63,15 +82,81 @@ func (client *AuthServiceClient) ValidateToken(token string) (*validateTokenResp
 	return &response, nil
 }
 
+const (
+	// userIDContextKey stores the token subject as a string
+	userIDContextKey contextKey = "userID"
+
+	// userUUIDContextKey stores the token subject parsed as a UUID, when it is one
+	userUUIDContextKey contextKey = "userUUID"
+)
+
+// authConfig holds the optional settings of the auth middlewares
+type authConfig struct {
+	requireUUIDUserID bool
+}
+
+// AuthOption configures optional AuthMiddleware and OptionalAuthMiddleware behavior
+type AuthOption func(*authConfig)
+
+// RequireUUIDUserID rejects tokens whose subject is not a UUID. By default any non-empty
+// subject is accepted, since service-account tokens may use other identifiers.
+func RequireUUIDUserID() AuthOption {
+	return func(config *authConfig) {
+		config.requireUUIDUserID = true
+	}
+}
+
+// newAuthConfig applies options over the defaults
+func newAuthConfig(options []AuthOption) *authConfig {
+	config := &authConfig{}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// contextWithUser stores the token's user ID (and UUID form, when it parses) and org in ctx.
+// ok is false when the subject is empty, or is not a UUID but config requires one.
+func contextWithUser(ctx context.Context, validationResult *validateTokenResponse, config *authConfig) (context.Context, bool) {
+	userUUID, err := uuid.Parse(validationResult.UserID)
+	if validationResult.UserID == "" || (err != nil && config.requireUUIDUserID) {
+		return ctx, false
+	}
+
+	ctx = context.WithValue(ctx, userIDContextKey, validationResult.UserID)
+	if err == nil {
+		ctx = context.WithValue(ctx, userUUIDContextKey, userUUID)
+	}
+	if validationResult.OrgID != "" {
+		ctx = context.WithValue(ctx, orgIDContextKey, validationResult.OrgID)
+	}
+	return ctx, true
+}
+
+// UserIDFromContext returns the authenticated user ID (the token subject), or an empty
+// string for unauthenticated requests
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDContextKey).(string)
+	return userID
+}
+
+// UserUUIDFromContext returns the authenticated user ID as a UUID. ok is false for
+// unauthenticated requests and for subjects that are not UUIDs, such as service accounts.
+func UserUUIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userUUID, ok := ctx.Value(userUUIDContextKey).(uuid.UUID)
+	return userUUID, ok
+}
+
 // AuthMiddleware creates middleware that validates JWT access tokens via auth service
-func AuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handler {
+func AuthMiddleware(authClient *AuthServiceClient, options ...AuthOption) func(http.Handler) http.Handler {
+	config := newAuthConfig(options)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 			// Extract Authorization header
 			authHeader := request.Header.Get("Authorization")
 
 			if authHeader == "" {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeUnauthorized,
 					"Authorization header is required",
 					http.StatusUnauthorized,
@@ -81,7 +166,7 @@ func AuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handl
 
 			// Check Bearer token format
 			if !strings.HasPrefix(authHeader, "Bearer ") {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeUnauthorized,
 					"Invalid authorization format. Use: Bearer <token>",
 					http.StatusUnauthorized,
@@ -93,14 +178,14 @@ func AuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handl
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 			// Validate token via auth service
-			validationResult, err := authClient.ValidateToken(tokenString)
+			validationResult, err := authClient.ValidateToken(request.Context(), tokenString)
 			if err != nil {
-				apierrors.WriteError(responseWriter, apierrors.InternalError("Failed to validate token"))
+				apierrors.WriteError(responseWriter, request, apierrors.InternalError("Failed to validate token"))
 				return
 			}
 
 			if !validationResult.Valid {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeInvalidToken,
 					"Invalid or expired access token",
 					http.StatusUnauthorized,
@@ -108,15 +193,12 @@ func AuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handl
 				return
 			}
 
-			// Parse user ID and add to context
-			userID, err := uuid.Parse(validationResult.UserID)
-			if err != nil {
-				apierrors.WriteError(responseWriter, apierrors.InternalError("Invalid user ID in token"))
+			// Add user ID (and org, if the auth service provided one) to request context
+			ctx, ok := contextWithUser(request.Context(), validationResult, config)
+			if !ok {
+				apierrors.WriteError(responseWriter, request, apierrors.InternalError("Invalid user ID in token"))
 				return
 			}
-
-			// Add user ID to request context
-			ctx := context.WithValue(request.Context(), "userID", userID)
 			request = request.WithContext(ctx)
 
 			// Proceed to next handler
@@ -127,7 +209,8 @@ func AuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handl
 
 // OptionalAuthMiddleware creates middleware that validates JWT tokens if present
 // but allows requests without tokens to proceed
-func OptionalAuthMiddleware(authClient *AuthServiceClient) func(http.Handler) http.Handler {
+func OptionalAuthMiddleware(authClient *AuthServiceClient, options ...AuthOption) func(http.Handler) http.Handler {
+	config := newAuthConfig(options)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 			// Extract Authorization header
@@ -147,24 +230,19 @@ func OptionalAuthMiddleware(authClient *AuthServiceClient) func(http.Handler) ht
 
 			// Extract and validate token
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			validationResult, err := authClient.ValidateToken(tokenString)
+			validationResult, err := authClient.ValidateToken(request.Context(), tokenString)
 			if err != nil || !validationResult.Valid {
 				// Token invalid, proceed without user context
 				next.ServeHTTP(responseWriter, request)
 				return
 			}
 
-			// Parse user ID
-			userID, err := uuid.Parse(validationResult.UserID)
-			if err != nil {
-				next.ServeHTTP(responseWriter, request)
-				return
+			// Add user ID (and org, if the auth service provided one) to request context;
+			// a subject rejected by RequireUUIDUserID proceeds without user context
+			if ctx, ok := contextWithUser(request.Context(), validationResult, config); ok {
+				request = request.WithContext(ctx)
 			}
 
-			// Add user ID to request context
-			ctx := context.WithValue(request.Context(), "userID", userID)
-			request = request.WithContext(ctx)
-
 			next.ServeHTTP(responseWriter, request)
 		})
 	}
@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestAuthClient creates an auth client with a fast retry policy for tests
+func newTestAuthClient(baseURL string) *AuthServiceClient {
+	client := NewAuthServiceClient(baseURL)
+	client.retryPolicy.baseDelay = time.Millisecond
+	return client
+}
+
+// TestValidateToken_RetriesTransientFailure tests that a flaky auth service succeeds on the second try
+func TestValidateToken_RetriesTransientFailure(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt32(&callCount, 1) == 1 {
+			http.Error(writer, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(validateTokenResponse{Valid: true, UserID: "0b9d6f38-7c1e-4f0a-9d1a-2f5e1c3b4a5d"})
+	}))
+	defer mockServer.Close()
+
+	client := newTestAuthClient(mockServer.URL)
+
+	result, err := client.ValidateToken(context.Background(), "test-token")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.Valid {
+		t.Error("Expected token to be valid after retry")
+	}
+
+	if atomic.LoadInt32(&callCount) != 2 {
+		t.Errorf("Expected 2 calls to auth service, got %d", callCount)
+	}
+}
+
+// TestValidateToken_DoesNotRetryForbidden tests that 403 responses are not retried
+func TestValidateToken_DoesNotRetryForbidden(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(writer).Encode(validateTokenResponse{Valid: false})
+	}))
+	defer mockServer.Close()
+
+	client := newTestAuthClient(mockServer.URL)
+
+	result, err := client.ValidateToken(context.Background(), "test-token")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Valid {
+		t.Error("Expected token to be invalid")
+	}
+
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 call to auth service, got %d", callCount)
+	}
+}
+
+// TestValidateToken_ConnectionError tests that connection errors are retried and then surfaced
+func TestValidateToken_ConnectionError(t *testing.T) {
+	client := newTestAuthClient("http://localhost:99999")
+
+	result, err := client.ValidateToken(context.Background(), "test-token")
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if result != nil {
+		t.Error("Expected result to be nil on error")
+	}
+}
+
+// TestValidateToken_GivesUpAfterMaxAttempts tests that persistent 5xx responses stop after max attempts
+func TestValidateToken_GivesUpAfterMaxAttempts(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := newTestAuthClient(mockServer.URL)
+	client.ValidateToken(context.Background(), "test-token")
+
+	if int(atomic.LoadInt32(&callCount)) != client.retryPolicy.maxAttempts {
+		t.Errorf("Expected %d calls to auth service, got %d", client.retryPolicy.maxAttempts, callCount)
+	}
+}
+
+// TestValidateToken_StopsWhenContextDone tests that a cancelled request stops the backoff
+// instead of sleeping through the remaining attempts
+func TestValidateToken_StopsWhenContextDone(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		http.Error(writer, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	client := newTestAuthClient(mockServer.URL)
+	// The backoff fits within the total timeout, so only the cancellation can cut it short
+	client.retryPolicy.baseDelay = 2 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := client.ValidateToken(ctx, "test-token"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected ValidateToken to return when the context ended, took %v", elapsed)
+	}
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 call to auth service, got %d", callCount)
+	}
+}
+
+// newValidatingAuthServer returns an auth service stub that accepts every token with userID as its subject
+func newValidatingAuthServer(userID string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(validateTokenResponse{Valid: true, UserID: userID})
+	}))
+}
+
+// TestAuthMiddleware_UserIDs tests that UUID and non-UUID subjects are both stored in the context
+func TestAuthMiddleware_UserIDs(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userID       string
+		expectedUUID bool
+	}{
+		{name: "uuid subject", userID: "0b9d6f38-7c1e-4f0a-9d1a-2f5e1c3b4a5d", expectedUUID: true},
+		{name: "service account subject", userID: "svc-match-importer", expectedUUID: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := newValidatingAuthServer(testCase.userID)
+			defer mockServer.Close()
+
+			var contextUserID string
+			var contextUUID uuid.UUID
+			var hasUUID bool
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				contextUserID = UserIDFromContext(request.Context())
+				contextUUID, hasUUID = UserUUIDFromContext(request.Context())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+			request.Header.Set("Authorization", "Bearer test-token")
+			responseRecorder := httptest.NewRecorder()
+
+			AuthMiddleware(newTestAuthClient(mockServer.URL))(nextHandler).ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", responseRecorder.Code)
+			}
+			if contextUserID != testCase.userID {
+				t.Errorf("Expected user ID '%s', got '%s'", testCase.userID, contextUserID)
+			}
+			if hasUUID != testCase.expectedUUID {
+				t.Errorf("Expected UUID present %v, got %v", testCase.expectedUUID, hasUUID)
+			}
+			if hasUUID && contextUUID.String() != testCase.userID {
+				t.Errorf("Expected UUID '%s', got '%s'", testCase.userID, contextUUID)
+			}
+		})
+	}
+}
+
+// TestAuthMiddleware_RequireUUIDUserID tests that a non-UUID subject is rejected when UUIDs are required
+func TestAuthMiddleware_RequireUUIDUserID(t *testing.T) {
+	mockServer := newValidatingAuthServer("svc-match-importer")
+	defer mockServer.Close()
+
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	request.Header.Set("Authorization", "Bearer test-token")
+	responseRecorder := httptest.NewRecorder()
+
+	AuthMiddleware(newTestAuthClient(mockServer.URL), RequireUUIDUserID())(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", responseRecorder.Code)
+	}
+	if nextCalled {
+		t.Error("Expected next handler not to be called")
+	}
+}
+
+// TestOptionalAuthMiddleware_NonUUIDSubject tests that optional auth keeps non-UUID subjects by default
+func TestOptionalAuthMiddleware_NonUUIDSubject(t *testing.T) {
+	mockServer := newValidatingAuthServer("svc-match-importer")
+	defer mockServer.Close()
+
+	var contextUserID string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contextUserID = UserIDFromContext(request.Context())
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	request.Header.Set("Authorization", "Bearer test-token")
+
+	OptionalAuthMiddleware(newTestAuthClient(mockServer.URL))(nextHandler).ServeHTTP(httptest.NewRecorder(), request)
+
+	if contextUserID != "svc-match-importer" {
+		t.Errorf("Expected user ID 'svc-match-importer', got '%s'", contextUserID)
+	}
+}
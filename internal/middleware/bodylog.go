@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultBodyLogMaxBytes caps how much of each body is logged
+	DefaultBodyLogMaxBytes = 4096
+
+	// redactedValue replaces the value of any redacted JSON field
+	redactedValue = `"[REDACTED]"`
+)
+
+// DefaultRedactedFields are JSON field names whose values are never logged
+var DefaultRedactedFields = []string{
+	"password",
+	"token",
+	"accessToken",
+	"refreshToken",
+	"apiKey",
+	"api_key",
+	"secret",
+	"authorization",
+}
+
+// BodyLogger logs request and response bodies at debug level, truncated to a size cap
+// and with secret fields redacted. Bodies are never logged unless the request logger
+// is at debug level or below.
+type BodyLogger struct {
+	maxBytes      int
+	redactPattern *regexp.Regexp
+}
+
+// NewBodyLogger creates a BodyLogger that logs at most maxBytes of each body and redacts
+// the values of the given JSON field names (matched case-insensitively)
+func NewBodyLogger(maxBytes int, redactFields []string) *BodyLogger {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBodyLogMaxBytes
+	}
+
+	quotedFields := make([]string, 0, len(redactFields))
+	for _, field := range redactFields {
+		if field = strings.TrimSpace(field); field != "" {
+			quotedFields = append(quotedFields, regexp.QuoteMeta(field))
+		}
+	}
+
+	bodyLogger := &BodyLogger{maxBytes: maxBytes}
+	if len(quotedFields) > 0 {
+		// Matches `"field": <value>` where value is a (possibly truncated) string or a bare
+		// scalar. Working on the raw text keeps redaction effective on truncated bodies that
+		// are no longer valid JSON.
+		bodyLogger.redactPattern = regexp.MustCompile(
+			`(?i)("(?:` + strings.Join(quotedFields, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`,
+		)
+	}
+
+	return bodyLogger
+}
+
+// Redact returns body with the values of redacted fields replaced
+func (bodyLogger *BodyLogger) Redact(body []byte) string {
+	if bodyLogger.redactPattern == nil {
+		return string(body)
+	}
+	return bodyLogger.redactPattern.ReplaceAllString(string(body), "${1}"+redactedValue)
+}
+
+// enabled reports whether logger would emit debug-level body logs
+func (bodyLogger *BodyLogger) enabled(logger zerolog.Logger) bool {
+	return bodyLogger != nil &&
+		zerolog.GlobalLevel() <= zerolog.DebugLevel &&
+		logger.GetLevel() <= zerolog.DebugLevel
+}
+
+// newCapture creates a buffer that keeps at most maxBytes of what is written to it
+func (bodyLogger *BodyLogger) newCapture() *cappedBuffer {
+	return &cappedBuffer{limit: bodyLogger.maxBytes}
+}
+
+// log writes the captured bodies as a single debug line
+func (bodyLogger *BodyLogger) log(logger zerolog.Logger, requestBody *cappedBuffer, responseBody *cappedBuffer) {
+	logger.Debug().
+		Str("request_body", bodyLogger.Redact(requestBody.buffer.Bytes())).
+		Bool("request_body_truncated", requestBody.truncated()).
+		Str("response_body", bodyLogger.Redact(responseBody.buffer.Bytes())).
+		Bool("response_body_truncated", responseBody.truncated()).
+		Msg("Request and response bodies")
+}
+
+// cappedBuffer records the first limit bytes written to it and counts the rest
+type cappedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+	total  int
+}
+
+// Write keeps bytes up to the limit and always reports the full write as successful
+func (capture *cappedBuffer) Write(data []byte) (int, error) {
+	capture.total += len(data)
+	if remaining := capture.limit - capture.buffer.Len(); remaining > 0 {
+		if len(data) > remaining {
+			capture.buffer.Write(data[:remaining])
+		} else {
+			capture.buffer.Write(data)
+		}
+	}
+	return len(data), nil
+}
+
+// truncated reports whether more bytes were written than were kept
+func (capture *cappedBuffer) truncated() bool {
+	return capture.total > capture.buffer.Len()
+}
+
+// teeReadCloser copies everything read from the wrapped body into a capture while
+// leaving the body fully readable by handlers
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// newTeeReadCloser wraps body so reads are also written to capture
+func newTeeReadCloser(body io.ReadCloser, capture io.Writer) io.ReadCloser {
+	return &teeReadCloser{Reader: io.TeeReader(body, capture), Closer: body}
+}
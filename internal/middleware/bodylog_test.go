@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// serveWithBodyLogging runs an echo handler behind body-logging middleware and returns the
+// bodies log line (nil when none was written) and the body the handler read
+func serveWithBodyLogging(t *testing.T, bodyLogger *BodyLogger, level zerolog.Level, requestBody string) (map[string]interface{}, string) {
+	t.Helper()
+
+	var logBuffer bytes.Buffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logBuffer).Level(level)
+	defer func() { log.Logger = originalLogger }()
+
+	var handlerRead string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		handlerRead = string(body)
+		writer.WriteHeader(http.StatusOK)
+		writer.Write([]byte(`{"puuid":"test-puuid","accessToken":"response-secret"}`))
+	})
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", strings.NewReader(requestBody))
+	NewLoggingMiddleware(bodyLogger)(nextHandler).ServeHTTP(httptest.NewRecorder(), request)
+
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log line: %v", err)
+		}
+		if entry["message"] == "Request and response bodies" {
+			return entry, handlerRead
+		}
+	}
+	return nil, handlerRead
+}
+
+// TestNewLoggingMiddleware_LogsBodiesWhenEnabled tests that bodies are logged, redacted, at debug level
+func TestNewLoggingMiddleware_LogsBodiesWhenEnabled(t *testing.T) {
+	requestBody := `{"gameName":"TestPlayer","password":"hunter2"}`
+	bodyLogger := NewBodyLogger(DefaultBodyLogMaxBytes, DefaultRedactedFields)
+
+	entry, handlerRead := serveWithBodyLogging(t, bodyLogger, zerolog.DebugLevel, requestBody)
+
+	if handlerRead != requestBody {
+		t.Errorf("Expected handler to read the full body, got '%s'", handlerRead)
+	}
+	if entry == nil {
+		t.Fatal("Expected bodies to be logged")
+	}
+
+	loggedRequest, _ := entry["request_body"].(string)
+	loggedResponse, _ := entry["response_body"].(string)
+
+	if !strings.Contains(loggedRequest, "TestPlayer") || strings.Contains(loggedRequest, "hunter2") {
+		t.Errorf("Expected redacted request body, got '%s'", loggedRequest)
+	}
+	if !strings.Contains(loggedResponse, "test-puuid") || strings.Contains(loggedResponse, "response-secret") {
+		t.Errorf("Expected redacted response body, got '%s'", loggedResponse)
+	}
+}
+
+// TestNewLoggingMiddleware_NoBodiesWhenDisabled tests that bodies are not logged without a body logger
+func TestNewLoggingMiddleware_NoBodiesWhenDisabled(t *testing.T) {
+	entry, _ := serveWithBodyLogging(t, nil, zerolog.DebugLevel, `{"gameName":"TestPlayer"}`)
+
+	if entry != nil {
+		t.Errorf("Expected no bodies log line, got %v", entry)
+	}
+}
+
+// TestNewLoggingMiddleware_NoBodiesAboveDebug tests that enabled body logging stays silent above debug level
+func TestNewLoggingMiddleware_NoBodiesAboveDebug(t *testing.T) {
+	bodyLogger := NewBodyLogger(DefaultBodyLogMaxBytes, DefaultRedactedFields)
+
+	entry, handlerRead := serveWithBodyLogging(t, bodyLogger, zerolog.InfoLevel, `{"gameName":"TestPlayer"}`)
+
+	if entry != nil {
+		t.Errorf("Expected no bodies log line at info level, got %v", entry)
+	}
+	if handlerRead != `{"gameName":"TestPlayer"}` {
+		t.Errorf("Expected handler to read the full body, got '%s'", handlerRead)
+	}
+}
+
+// TestNewLoggingMiddleware_TruncatesBodies tests that logged bodies are capped and flagged as truncated
+func TestNewLoggingMiddleware_TruncatesBodies(t *testing.T) {
+	requestBody := `{"gameName":"` + strings.Repeat("x", 100) + `"}`
+	bodyLogger := NewBodyLogger(16, nil)
+
+	entry, handlerRead := serveWithBodyLogging(t, bodyLogger, zerolog.DebugLevel, requestBody)
+
+	if handlerRead != requestBody {
+		t.Error("Expected handler to read the full body despite the log cap")
+	}
+	if entry == nil {
+		t.Fatal("Expected bodies to be logged")
+	}
+	if loggedRequest, _ := entry["request_body"].(string); len(loggedRequest) != 16 {
+		t.Errorf("Expected request body capped to 16 bytes, got %d", len(loggedRequest))
+	}
+	if entry["request_body_truncated"] != true {
+		t.Error("Expected request_body_truncated to be true")
+	}
+}
+
+// TestBodyLogger_Redact tests redaction of string, scalar and truncated values
+func TestBodyLogger_Redact(t *testing.T) {
+	bodyLogger := NewBodyLogger(DefaultBodyLogMaxBytes, []string{"password", "apiKey"})
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"string value", `{"password":"hunter2","name":"a"}`, `{"password":"[REDACTED]","name":"a"}`},
+		{"case insensitive", `{"APIKEY": "key"}`, `{"APIKEY": "[REDACTED]"}`},
+		{"escaped quote", `{"password":"a\"b"}`, `{"password":"[REDACTED]"}`},
+		{"numeric value", `{"password":1234}`, `{"password":"[REDACTED]"}`},
+		{"truncated value", `{"password":"hunt`, `{"password":"[REDACTED]"`},
+		{"unrelated field", `{"passwordHint":"x"}`, `{"passwordHint":"x"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if redacted := bodyLogger.Redact([]byte(testCase.body)); redacted != testCase.expected {
+				t.Errorf("Expected '%s', got '%s'", testCase.expected, redacted)
+			}
+		})
+	}
+}
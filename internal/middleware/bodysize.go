@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/gorilla/mux"
+)
+
+// countingReadCloser counts the bytes read through it, so the recorded size reflects what
+// the handler actually decoded rather than the declared Content-Length
+type countingReadCloser struct {
+	io.ReadCloser
+	bytesRead int64
+}
+
+// Read reads from the wrapped body and counts the bytes returned
+func (reader *countingReadCloser) Read(buffer []byte) (int, error) {
+	bytesRead, err := reader.ReadCloser.Read(buffer)
+	reader.bytesRead += int64(bytesRead)
+	return bytesRead, err
+}
+
+// RequestBodySizeMiddleware records how many body bytes each request read, labeled by the
+// matched route template (e.g. "/api/v1/analyze") to keep the label set bounded. Requests
+// without a body are not recorded. A nil httpMetrics disables it.
+func RequestBodySizeMiddleware(httpMetrics *metrics.HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if httpMetrics == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Body == nil || request.Body == http.NoBody {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			countingBody := &countingReadCloser{ReadCloser: request.Body}
+			request.Body = countingBody
+			defer func() {
+				httpMetrics.ObserveRequestBody(routeTemplate(request), countingBody.bytesRead)
+			}()
+
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, or the request path when
+// the request was not routed through mux
+func routeTemplate(request *http.Request) string {
+	if route := mux.CurrentRoute(request); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return request.URL.Path
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/gorilla/mux"
+)
+
+// TestRequestBodySizeMiddleware tests that the bytes read while decoding are observed under the route template
+func TestRequestBodySizeMiddleware(t *testing.T) {
+	httpMetrics := metrics.NewHTTPMetrics(metrics.NewRegistry())
+
+	router := mux.NewRouter()
+	router.Use(RequestBodySizeMiddleware(httpMetrics))
+	router.HandleFunc("/api/v1/analyze", func(writer http.ResponseWriter, request *http.Request) {
+		var body map[string]string
+		json.NewDecoder(request.Body).Decode(&body)
+		writer.WriteHeader(http.StatusOK)
+	}).Methods("POST")
+
+	requestBody := `{"gameName":"Faker","tagLine":"KR1","region":"kr"}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/analyze", strings.NewReader(requestBody)))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/analyze", strings.NewReader("{}")))
+
+	snapshot := httpMetrics.RequestBodySizes("/api/v1/analyze")
+	if snapshot.Count != 2 {
+		t.Errorf("Expected 2 body size observations, got %d", snapshot.Count)
+	}
+	if snapshot.Sum != float64(len(requestBody)+2) {
+		t.Errorf("Expected %d bytes observed, got %g", len(requestBody)+2, snapshot.Sum)
+	}
+}
+
+// TestRequestBodySizeMiddleware_NoBody tests that requests without a body are not observed
+func TestRequestBodySizeMiddleware_NoBody(t *testing.T) {
+	httpMetrics := metrics.NewHTTPMetrics(metrics.NewRegistry())
+	handler := RequestBodySizeMiddleware(httpMetrics)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/regions", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	if count := httpMetrics.RequestBodySizes("/api/v1/regions").Count; count != 0 {
+		t.Errorf("Expected no observations for a request without a body, got %d", count)
+	}
+}
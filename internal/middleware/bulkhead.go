@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// Bulkhead caps the number of requests processed concurrently. Requests beyond the cap
+// wait in a queue for up to queueTimeout before being rejected with 503; a zero
+// queueTimeout rejects them immediately. The queue is unbounded unless WithMaxQueued is set.
+type Bulkhead struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+	// maxQueued bounds the number of waiting requests; zero means unbounded
+	maxQueued int64
+	queued    atomic.Int64
+	// name labels this bulkhead's queue metrics (e.g. "api", "analyze")
+	name         string
+	queueMetrics *metrics.QueueMetrics
+}
+
+// BulkheadOption configures optional Bulkhead behavior
+type BulkheadOption func(*Bulkhead)
+
+// WithMaxQueued bounds how many requests may wait for a slot; requests arriving while the
+// queue is full get 503 at once instead of waiting. Zero leaves the queue unbounded.
+func WithMaxQueued(maxQueued int) BulkheadOption {
+	return func(bulkhead *Bulkhead) {
+		bulkhead.maxQueued = int64(maxQueued)
+	}
+}
+
+// WithQueueMetrics records queue depth, wait times and rejections under the bulkhead name
+func WithQueueMetrics(queueMetrics *metrics.QueueMetrics, name string) BulkheadOption {
+	return func(bulkhead *Bulkhead) {
+		bulkhead.queueMetrics = queueMetrics
+		bulkhead.name = name
+	}
+}
+
+// NewBulkhead creates a Bulkhead allowing maxConcurrent in-flight requests
+func NewBulkhead(maxConcurrent int, queueTimeout time.Duration, options ...BulkheadOption) *Bulkhead {
+	bulkhead := &Bulkhead{
+		slots:        make(chan struct{}, maxConcurrent),
+		queueTimeout: queueTimeout,
+	}
+	for _, option := range options {
+		option(bulkhead)
+	}
+	return bulkhead
+}
+
+// Queued returns the number of requests currently waiting for a slot
+func (bulkhead *Bulkhead) Queued() int {
+	return int(bulkhead.queued.Load())
+}
+
+// InFlight returns the number of slots currently held by requests
+func (bulkhead *Bulkhead) InFlight() int {
+	return len(bulkhead.slots)
+}
+
+// Middleware limits concurrency of the wrapped handler. While queued, a request whose client
+// disconnects abandons its position immediately so it never consumes a slot.
+func (bulkhead *Bulkhead) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if !bulkhead.acquire(responseWriter, request) {
+			return
+		}
+		defer func() { <-bulkhead.slots }()
+
+		next.ServeHTTP(responseWriter, request)
+	})
+}
+
+// acquire takes a slot, waiting in the queue if none is free. It returns false, having
+// already responded when a response is due, if the request did not get a slot.
+func (bulkhead *Bulkhead) acquire(responseWriter http.ResponseWriter, request *http.Request) bool {
+	// Take a free slot without queueing, so a zero queue timeout never races a free slot
+	select {
+	case bulkhead.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if queued := bulkhead.queued.Add(1); bulkhead.maxQueued > 0 && queued > bulkhead.maxQueued {
+		bulkhead.queued.Add(-1)
+		bulkhead.queueMetrics.Rejected(bulkhead.name, metrics.QueueRejectFull)
+		writeAtCapacity(responseWriter, request)
+		return false
+	}
+	bulkhead.queueMetrics.Enqueued(bulkhead.name)
+	queuedAt := time.Now()
+	defer func() {
+		bulkhead.queued.Add(-1)
+		bulkhead.queueMetrics.Dequeued(bulkhead.name, time.Since(queuedAt))
+	}()
+
+	queueTimer := time.NewTimer(bulkhead.queueTimeout)
+	defer queueTimer.Stop()
+
+	select {
+	case bulkhead.slots <- struct{}{}:
+		return true
+	case <-request.Context().Done():
+		// Client gave up while queued; nobody is listening for a response
+		logger := LoggerFromContext(request.Context())
+		logger.Debug().Msg("Request abandoned while waiting for a concurrency slot")
+		return false
+	case <-queueTimer.C:
+		bulkhead.queueMetrics.Rejected(bulkhead.name, metrics.QueueRejectTimeout)
+		writeAtCapacity(responseWriter, request)
+		return false
+	}
+}
+
+// writeAtCapacity rejects a request that could not get a concurrency slot with 503
+func writeAtCapacity(responseWriter http.ResponseWriter, request *http.Request) {
+	responseWriter.Header().Set("Retry-After", "1")
+	apierrors.WriteError(responseWriter, request, apierrors.ServiceUnavailable("Server is at capacity, please retry"))
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// TestBulkhead_AllowsWithinCapacity tests that requests under the cap pass through and release their slot
+func TestBulkhead_AllowsWithinCapacity(t *testing.T) {
+	bulkhead := NewBulkhead(1, time.Second)
+	nextCalled := false
+	handler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	}))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected request to reach the next handler")
+	}
+
+	if bulkhead.InFlight() != 0 {
+		t.Errorf("Expected slot to be released, got %d in flight", bulkhead.InFlight())
+	}
+}
+
+// TestBulkhead_QueueTimeout tests that a request waiting longer than the queue timeout gets 503
+func TestBulkhead_QueueTimeout(t *testing.T) {
+	bulkhead := NewBulkhead(1, 10*time.Millisecond)
+	bulkhead.slots <- struct{}{}
+
+	handler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected queued request to time out")
+	}))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, responseRecorder.Code)
+	}
+
+	if responseRecorder.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on capacity rejection")
+	}
+}
+
+// TestBulkhead_ClientCancelWhileQueued tests that a cancelled request abandons the queue without consuming a slot
+func TestBulkhead_ClientCancelWhileQueued(t *testing.T) {
+	bulkhead := NewBulkhead(1, time.Minute)
+
+	// Hold the only slot with an in-flight request
+	releaseFirst := make(chan struct{})
+	firstStarted := make(chan struct{})
+	blockingHandler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		close(firstStarted)
+		<-releaseFirst
+	}))
+	firstDone := make(chan struct{})
+	go func() {
+		request, _ := http.NewRequest("POST", "/api/v1/analyze", nil)
+		blockingHandler.ServeHTTP(httptest.NewRecorder(), request)
+		close(firstDone)
+	}()
+	<-firstStarted
+
+	// Queue a second request, then cancel it as if the client disconnected
+	queuedCalled := false
+	queuedHandler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		queuedCalled = true
+	}))
+	cancelContext, cancel := context.WithCancel(context.Background())
+	queuedRequest, _ := http.NewRequestWithContext(cancelContext, "POST", "/api/v1/analyze", nil)
+	queuedDone := make(chan struct{})
+	go func() {
+		queuedHandler.ServeHTTP(httptest.NewRecorder(), queuedRequest)
+		close(queuedDone)
+	}()
+	cancel()
+
+	select {
+	case <-queuedDone:
+	case <-time.After(time.Second):
+		t.Fatal("Expected cancelled request to leave the queue promptly")
+	}
+
+	if queuedCalled {
+		t.Error("Expected cancelled request not to reach the next handler")
+	}
+
+	if bulkhead.InFlight() != 1 {
+		t.Errorf("Expected only the original request to hold a slot, got %d in flight", bulkhead.InFlight())
+	}
+
+	close(releaseFirst)
+	<-firstDone
+
+	if bulkhead.InFlight() != 0 {
+		t.Errorf("Expected all slots to be free, got %d in flight", bulkhead.InFlight())
+	}
+}
+
+// TestBulkhead_ZeroQueueTimeout tests that a zero queue timeout admits free capacity and rejects excess at once
+func TestBulkhead_ZeroQueueTimeout(t *testing.T) {
+	bulkhead := NewBulkhead(1, 0)
+	handler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+	for attempt := 0; attempt < 20; attempt++ {
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, httptest.NewRequest("POST", "/api/v1/analyze", nil))
+		if responseRecorder.Code != http.StatusOK {
+			t.Fatalf("Attempt %d: expected status code %d with a free slot, got %d", attempt, http.StatusOK, responseRecorder.Code)
+		}
+	}
+
+	bulkhead.slots <- struct{}{}
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest("POST", "/api/v1/analyze", nil))
+	if responseRecorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d at capacity, got %d", http.StatusServiceUnavailable, responseRecorder.Code)
+	}
+}
+
+// TestBulkhead_QueueThenProceed tests that a queued request proceeds once a slot frees up and its wait is recorded
+func TestBulkhead_QueueThenProceed(t *testing.T) {
+	queueMetrics := metrics.NewQueueMetrics(metrics.NewRegistry())
+	bulkhead := NewBulkhead(1, time.Second, WithMaxQueued(1), WithQueueMetrics(queueMetrics, metrics.BulkheadAPI))
+	bulkhead.slots <- struct{}{}
+
+	queuedCalled := make(chan struct{})
+	handler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		close(queuedCalled)
+	}))
+
+	responseRecorder := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+		handler.ServeHTTP(responseRecorder, request)
+		close(done)
+	}()
+
+	// Free the slot once the request is waiting for it
+	for bulkhead.Queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if queueMetrics.Depth(metrics.BulkheadAPI) != 1 {
+		t.Errorf("Expected queue depth 1, got %g", queueMetrics.Depth(metrics.BulkheadAPI))
+	}
+	<-bulkhead.slots
+
+	<-done
+	select {
+	case <-queuedCalled:
+	default:
+		t.Fatal("Expected queued request to reach the next handler")
+	}
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if queueMetrics.Depth(metrics.BulkheadAPI) != 0 || queueMetrics.Waits(metrics.BulkheadAPI) != 1 {
+		t.Errorf("Expected an empty queue with one recorded wait, got depth %g and %d waits",
+			queueMetrics.Depth(metrics.BulkheadAPI), queueMetrics.Waits(metrics.BulkheadAPI))
+	}
+}
+
+// TestBulkhead_QueueFullRejects tests that requests arriving while the queue is full get 503 without waiting
+func TestBulkhead_QueueFullRejects(t *testing.T) {
+	queueMetrics := metrics.NewQueueMetrics(metrics.NewRegistry())
+	bulkhead := NewBulkhead(1, time.Minute, WithMaxQueued(1), WithQueueMetrics(queueMetrics, metrics.BulkheadAPI))
+	bulkhead.slots <- struct{}{}
+	bulkhead.queued.Store(1)
+
+	handler := bulkhead.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected request to be rejected while the queue is full")
+	}))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	responseRecorder := httptest.NewRecorder()
+	start := time.Now()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, responseRecorder.Code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected an immediate rejection, took %v", elapsed)
+	}
+	if queueMetrics.Rejections(metrics.BulkheadAPI, metrics.QueueRejectFull) != 1 {
+		t.Errorf("Expected 1 queue-full rejection, got %g", queueMetrics.Rejections(metrics.BulkheadAPI, metrics.QueueRejectFull))
+	}
+	if bulkhead.Queued() != 1 {
+		t.Errorf("Expected the rejected request not to change the queue, got %d queued", bulkhead.Queued())
+	}
+}
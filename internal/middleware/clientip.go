@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseCIDRs parses a list of CIDR ranges. Bare IPs are treated as single-host ranges.
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", value)
+			}
+			if ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// ClientIP determines the originating client IP for a request. X-Forwarded-For is only
+// honored when the direct peer is a trusted proxy; the rightmost untrusted hop is used so
+// clients cannot spoof their address by prepending entries.
+func ClientIP(request *http.Request, trustedProxies []*net.IPNet) net.IP {
+	remoteIP := parseRemoteIP(request.RemoteAddr)
+	if remoteIP == nil || !containsIP(trustedProxies, remoteIP) {
+		return remoteIP
+	}
+
+	forwardedFor := request.Header.Get("X-Forwarded-For")
+	if forwardedFor == "" {
+		return remoteIP
+	}
+
+	hops := strings.Split(forwardedFor, ",")
+	for index := len(hops) - 1; index >= 0; index-- {
+		hopIP := net.ParseIP(strings.TrimSpace(hops[index]))
+		if hopIP == nil {
+			// An unparseable hop cannot be trusted further; stop at the last known address
+			return remoteIP
+		}
+		if !containsIP(trustedProxies, hopIP) {
+			return hopIP
+		}
+		remoteIP = hopIP
+	}
+
+	return remoteIP
+}
+
+// parseRemoteIP extracts the IP from a host:port RemoteAddr
+func parseRemoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// containsIP reports whether ip falls within any of the given networks
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
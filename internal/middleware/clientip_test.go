@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestParseCIDRs tests parsing of CIDR ranges and bare IPs
+func TestParseCIDRs(t *testing.T) {
+	networks, err := ParseCIDRs([]string{"10.0.0.0/8", " 192.168.1.5 ", "", "::1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(networks) != 3 {
+		t.Fatalf("Expected 3 networks, got %d", len(networks))
+	}
+
+	if networks[1].String() != "192.168.1.5/32" {
+		t.Errorf("Expected bare IPv4 to become /32, got '%s'", networks[1].String())
+	}
+
+	if networks[2].String() != "::1/128" {
+		t.Errorf("Expected bare IPv6 to become /128, got '%s'", networks[2].String())
+	}
+}
+
+// TestParseCIDRs_Invalid tests that malformed entries are rejected
+func TestParseCIDRs_Invalid(t *testing.T) {
+	for _, value := range []string{"not-an-ip", "10.0.0.0/99"} {
+		if _, err := ParseCIDRs([]string{value}); err == nil {
+			t.Errorf("Expected error for '%s'", value)
+		}
+	}
+}
+
+// TestClientIP tests client IP resolution with and without trusted proxies
+func TestClientIP(t *testing.T) {
+	trustedProxies, _ := ParseCIDRs([]string{"10.0.0.0/8"})
+
+	testCases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		expectedIP   string
+	}{
+		{"direct client", "203.0.113.7:5000", "", "203.0.113.7"},
+		{"untrusted peer ignores header", "203.0.113.7:5000", "192.168.1.1", "203.0.113.7"},
+		{"trusted proxy uses header", "10.0.0.5:5000", "198.51.100.2", "198.51.100.2"},
+		{"spoofed leading entry ignored", "10.0.0.5:5000", "192.168.1.1, 198.51.100.2", "198.51.100.2"},
+		{"chained trusted proxies skipped", "10.0.0.5:5000", "198.51.100.2, 10.1.1.1", "198.51.100.2"},
+		{"trusted proxy without header", "10.0.0.5:5000", "", "10.0.0.5"},
+		{"malformed hop stops walk", "10.0.0.5:5000", "198.51.100.2, garbage", "10.0.0.5"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request, _ := http.NewRequest("GET", "/", nil)
+			request.RemoteAddr = testCase.remoteAddr
+			if testCase.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+			}
+
+			clientIP := ClientIP(request, trustedProxies)
+			if clientIP.String() != testCase.expectedIP {
+				t.Errorf("Expected client IP '%s', got '%s'", testCase.expectedIP, clientIP.String())
+			}
+		})
+	}
+}
@@ -11,8 +11,9 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		responseWriter.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 		responseWriter.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
-		// Handle preflight OPTIONS requests immediately
-		if request.Method == http.MethodOptions {
+		// Handle genuine preflight requests immediately. Plain OPTIONS requests
+		// fall through to the router so unknown paths still return 404/405.
+		if isPreflightRequest(request) {
 			responseWriter.WriteHeader(http.StatusOK)
 			return
 		}
@@ -20,3 +21,9 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(responseWriter, request)
 	})
 }
+
+// isPreflightRequest reports whether the request is a CORS preflight, which
+// browsers send as OPTIONS with an Access-Control-Request-Method header
+func isPreflightRequest(request *http.Request) bool {
+	return request.Method == http.MethodOptions && request.Header.Get("Access-Control-Request-Method") != ""
+}
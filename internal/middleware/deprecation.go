@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sunsetDateLayout is the short date form accepted for the sunset date
+const sunsetDateLayout = "2006-01-02"
+
+// DeprecatedRoute marks every path under PathPrefix as deprecated. Successor, when set, is
+// the prefix replacing it; the rest of the path is carried over in the Link header.
+type DeprecatedRoute struct {
+	PathPrefix string
+	Successor  string
+}
+
+// ParseDeprecatedRoutes parses a comma-separated list of deprecated path prefixes, each
+// optionally followed by "=successorPrefix", e.g. "/api/v1=/api/v2"
+func ParseDeprecatedRoutes(spec string) ([]DeprecatedRoute, error) {
+	var routes []DeprecatedRoute
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pathPrefix, successor, _ := strings.Cut(entry, "=")
+		pathPrefix = strings.TrimRight(strings.TrimSpace(pathPrefix), "/")
+		successor = strings.TrimRight(strings.TrimSpace(successor), "/")
+		if !strings.HasPrefix(pathPrefix, "/") {
+			return nil, fmt.Errorf("invalid deprecated route %q: path must start with /", entry)
+		}
+		if successor != "" && !strings.HasPrefix(successor, "/") {
+			return nil, fmt.Errorf("invalid deprecated route %q: successor must start with /", entry)
+		}
+
+		routes = append(routes, DeprecatedRoute{PathPrefix: pathPrefix, Successor: successor})
+	}
+
+	return routes, nil
+}
+
+// ParseSunsetDate parses a sunset date given as YYYY-MM-DD or RFC 3339. An empty value
+// returns the zero time, meaning no Sunset header is sent.
+func ParseSunsetDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if sunset, err := time.Parse(sunsetDateLayout, value); err == nil {
+		return sunset, nil
+	}
+	sunset, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sunset date %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return sunset, nil
+}
+
+// DeprecationMiddleware adds "Deprecation: true", a Sunset date (when sunset is non-zero) and
+// a successor-version Link header to responses for deprecated routes. The most specific
+// matching route wins. Request handling is otherwise unchanged.
+func DeprecationMiddleware(routes []DeprecatedRoute, sunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(routes) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if route, found := matchDeprecatedRoute(routes, request.URL.Path); found {
+				header := responseWriter.Header()
+				header.Set("Deprecation", "true")
+				if !sunset.IsZero() {
+					header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+				}
+				if route.Successor != "" {
+					successorPath := route.Successor + strings.TrimPrefix(request.URL.Path, route.PathPrefix)
+					header.Add("Link", "<"+successorPath+`>; rel="successor-version"`)
+				}
+			}
+
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
+
+// matchDeprecatedRoute returns the route with the longest prefix covering path. A prefix
+// only matches whole path segments, so "/api/v1" does not match "/api/v10".
+func matchDeprecatedRoute(routes []DeprecatedRoute, path string) (DeprecatedRoute, bool) {
+	var best DeprecatedRoute
+	found := false
+
+	for _, route := range routes {
+		if path != route.PathPrefix && !strings.HasPrefix(path, route.PathPrefix+"/") {
+			continue
+		}
+		if !found || len(route.PathPrefix) > len(best.PathPrefix) {
+			best = route
+			found = true
+		}
+	}
+
+	return best, found
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDeprecationMiddleware tests that deprecation headers are added only to configured routes
+func TestDeprecationMiddleware(t *testing.T) {
+	routes := []DeprecatedRoute{
+		{PathPrefix: "/api/v1", Successor: "/api/v2"},
+		{PathPrefix: "/api/v1/legacy"},
+	}
+	sunset := time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusTeapot)
+	})
+	handler := DeprecationMiddleware(routes, sunset)(nextHandler)
+
+	testCases := []struct {
+		name               string
+		path               string
+		expectedDeprecated bool
+		expectedLink       string
+	}{
+		{name: "deprecated route", path: "/api/v1/summoner", expectedDeprecated: true, expectedLink: `</api/v2/summoner>; rel="successor-version"`},
+		{name: "deprecated prefix itself", path: "/api/v1", expectedDeprecated: true, expectedLink: `</api/v2>; rel="successor-version"`},
+		{name: "more specific route without successor", path: "/api/v1/legacy/stats", expectedDeprecated: true},
+		{name: "successor route", path: "/api/v2/summoner", expectedDeprecated: false},
+		{name: "similar prefix", path: "/api/v10/summoner", expectedDeprecated: false},
+		{name: "root route", path: "/ready", expectedDeprecated: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			responseRecorder := httptest.NewRecorder()
+			handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, testCase.path, nil))
+
+			if responseRecorder.Code != http.StatusTeapot {
+				t.Errorf("Expected the handler's status %d, got %d", http.StatusTeapot, responseRecorder.Code)
+			}
+
+			header := responseRecorder.Header()
+			if testCase.expectedDeprecated {
+				if header.Get("Deprecation") != "true" {
+					t.Errorf("Expected Deprecation 'true', got '%s'", header.Get("Deprecation"))
+				}
+				if header.Get("Sunset") != "Mon, 01 Mar 2027 00:00:00 GMT" {
+					t.Errorf("Unexpected Sunset '%s'", header.Get("Sunset"))
+				}
+			} else if header.Get("Deprecation") != "" || header.Get("Sunset") != "" {
+				t.Errorf("Expected no deprecation headers, got Deprecation '%s' Sunset '%s'", header.Get("Deprecation"), header.Get("Sunset"))
+			}
+
+			if header.Get("Link") != testCase.expectedLink {
+				t.Errorf("Expected Link '%s', got '%s'", testCase.expectedLink, header.Get("Link"))
+			}
+		})
+	}
+}
+
+// TestDeprecationMiddleware_NoSunset tests that the Sunset header is omitted without a sunset date
+func TestDeprecationMiddleware_NoSunset(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+	handler := DeprecationMiddleware([]DeprecatedRoute{{PathPrefix: "/api/v1"}}, time.Time{})(nextHandler)
+
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil))
+
+	if responseRecorder.Header().Get("Deprecation") != "true" {
+		t.Error("Expected Deprecation header")
+	}
+	if _, exists := responseRecorder.Header()["Sunset"]; exists {
+		t.Error("Expected no Sunset header")
+	}
+}
+
+// TestParseDeprecatedRoutes tests parsing of the deprecated route list
+func TestParseDeprecatedRoutes(t *testing.T) {
+	routes, err := ParseDeprecatedRoutes(" /api/v1/=/api/v2 , /legacy ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []DeprecatedRoute{
+		{PathPrefix: "/api/v1", Successor: "/api/v2"},
+		{PathPrefix: "/legacy"},
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("Expected %d routes, got %d", len(expected), len(routes))
+	}
+	for index := range expected {
+		if routes[index] != expected[index] {
+			t.Errorf("Route %d: expected %+v, got %+v", index, expected[index], routes[index])
+		}
+	}
+
+	for _, invalid := range []string{"api/v1", "/api/v1=api/v2"} {
+		if _, err := ParseDeprecatedRoutes(invalid); err == nil {
+			t.Errorf("Expected error for '%s'", invalid)
+		}
+	}
+}
+
+// TestParseSunsetDate tests the accepted sunset date formats
+func TestParseSunsetDate(t *testing.T) {
+	expected := time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, value := range []string{"2027-03-01", "2027-03-01T00:00:00Z"} {
+		sunset, err := ParseSunsetDate(value)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", value, err)
+		}
+		if !sunset.Equal(expected) {
+			t.Errorf("Expected %v for '%s', got %v", expected, value, sunset)
+		}
+	}
+
+	if sunset, err := ParseSunsetDate(""); err != nil || !sunset.IsZero() {
+		t.Errorf("Expected zero time for empty value, got %v (%v)", sunset, err)
+	}
+	if _, err := ParseSunsetDate("next spring"); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+}
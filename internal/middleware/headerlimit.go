@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+const (
+	// DefaultMaxHeaderBytes caps the total size of request headers; Go's own default is 1MB
+	DefaultMaxHeaderBytes = 32 << 10
+
+	// DefaultMaxHeaderCount caps the number of request header fields
+	DefaultMaxHeaderCount = 100
+)
+
+// HeaderLimitMiddleware rejects requests carrying more than maxHeaderCount header fields
+// with 431 Request Header Fields Too Large. Repeated headers count once per value. The
+// overall header size is capped separately by http.Server.MaxHeaderBytes. A maxHeaderCount
+// of zero or less disables the check.
+func HeaderLimitMiddleware(maxHeaderCount int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxHeaderCount <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			headerCount := 0
+			for _, values := range request.Header {
+				headerCount += len(values)
+			}
+
+			if headerCount > maxHeaderCount {
+				logger := LoggerFromContext(request.Context())
+				logger.Warn().
+					Int("header_count", headerCount).
+					Int("max_header_count", maxHeaderCount).
+					Msg("Rejected request with too many headers")
+				apierrors.WriteError(responseWriter, request, apierrors.HeadersTooLarge("Too many request headers (limit "+strconv.Itoa(maxHeaderCount)+")"))
+				return
+			}
+
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// TestHeaderLimitMiddleware_TooManyHeaders tests that a request with many headers is rejected with 431
+func TestHeaderLimitMiddleware_TooManyHeaders(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+	for index := 0; index < 50; index++ {
+		request.Header.Set(fmt.Sprintf("X-Filler-%d", index), "value")
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	HeaderLimitMiddleware(20)(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected status 431, got %d", responseRecorder.Code)
+	}
+
+	if nextCalled {
+		t.Error("Expected next handler not to be called")
+	}
+
+	var errorResponse apierrors.ErrorResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+
+	if errorResponse.Error.Code != apierrors.ErrCodeHeadersTooLarge {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeHeadersTooLarge, errorResponse.Error.Code)
+	}
+}
+
+// TestHeaderLimitMiddleware_RepeatedValuesCount tests that each value of a repeated header is counted
+func TestHeaderLimitMiddleware_RepeatedValuesCount(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+
+	request := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	for index := 0; index < 5; index++ {
+		request.Header.Add("X-Repeated", "value")
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	HeaderLimitMiddleware(4)(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected status 431, got %d", responseRecorder.Code)
+	}
+}
+
+// TestHeaderLimitMiddleware_WithinLimit tests that normal requests pass through
+func TestHeaderLimitMiddleware_WithinLimit(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	request.Header.Set("X-API-Key", "test-key")
+	responseRecorder := httptest.NewRecorder()
+
+	HeaderLimitMiddleware(DefaultMaxHeaderCount)(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected next handler to be called")
+	}
+}
+
+// TestHeaderLimitMiddleware_Disabled tests that a non-positive limit disables the check
+func TestHeaderLimitMiddleware_Disabled(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	for index := 0; index < 200; index++ {
+		request.Header.Set(fmt.Sprintf("X-Filler-%d", index), "value")
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	HeaderLimitMiddleware(0)(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected next handler to be called")
+	}
+}
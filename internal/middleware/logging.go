@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -9,9 +10,11 @@ import (
 )
 
 // responseWriter is a wrapper around http.ResponseWriter that captures the status code
+// and, when body logging is enabled, a capped copy of the response body
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	body       *cappedBuffer
 }
 
 // newResponseWriter creates a new responseWriter
@@ -28,22 +31,89 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.ResponseWriter.WriteHeader(statusCode)
 }
 
-// LoggingMiddleware logs HTTP requests with detailed information
+// Write copies the response body into the capture buffer when one is set
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	if rw.body != nil {
+		rw.body.Write(data)
+	}
+	return rw.ResponseWriter.Write(data)
+}
+
+const (
+	// loggerContextKey stores the request-scoped logger in the request context
+	loggerContextKey contextKey = "logger"
+
+	// requestStartContextKey stores the time the request was received
+	requestStartContextKey contextKey = "requestStart"
+)
+
+// LoggerFromContext returns the request-scoped logger stored by LoggingMiddleware,
+// falling back to the global logger when none is present
+func LoggerFromContext(ctx context.Context) zerolog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(zerolog.Logger); ok {
+		return logger
+	}
+	return log.Logger
+}
+
+// RequestStartFromContext returns the time LoggingMiddleware received the request
+func RequestStartFromContext(ctx context.Context) (time.Time, bool) {
+	startTime, ok := ctx.Value(requestStartContextKey).(time.Time)
+	return startTime, ok
+}
+
+// LoggingMiddleware logs HTTP requests with detailed information and stores a child
+// logger pre-populated with the request ID, method, and path in the request context
 func LoggingMiddleware(next http.Handler) http.Handler {
+	return NewLoggingMiddleware(nil)(next)
+}
+
+// NewLoggingMiddleware returns LoggingMiddleware, additionally logging request and response
+// bodies through bodyLogger when it is non-nil and the logger is at debug level. Only the
+// part of the request body that the handler actually reads is logged.
+func NewLoggingMiddleware(bodyLogger *BodyLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return loggingHandler(next, bodyLogger)
+	}
+}
+
+// loggingHandler implements LoggingMiddleware with optional body logging
+func loggingHandler(next http.Handler, bodyLogger *BodyLogger) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		startTime := time.Now()
 
 		// Wrap the response writer to capture status code
 		wrappedWriter := newResponseWriter(writer)
 
-		// Log incoming request
-		log.Info().
+		// Build the request-scoped child logger
+		loggerContext := log.Logger.With().
 			Str("method", request.Method).
-			Str("path", request.URL.Path).
+			Str("path", request.URL.Path)
+		if requestID := RequestIDFromContext(request.Context()); requestID != "" {
+			loggerContext = loggerContext.Str("request_id", requestID)
+		}
+		requestLogger := loggerContext.Logger()
+
+		ctx := context.WithValue(request.Context(), loggerContextKey, requestLogger)
+		ctx = context.WithValue(ctx, requestStartContextKey, startTime)
+		request = request.WithContext(ctx)
+
+		// Log incoming request
+		requestLogger.Info().
 			Str("remote_addr", request.RemoteAddr).
 			Str("user_agent", request.UserAgent()).
 			Msg("Incoming request")
 
+		// Capture bodies only when they would actually be logged
+		var requestBody *cappedBuffer
+		if bodyLogger.enabled(requestLogger) {
+			requestBody = bodyLogger.newCapture()
+			wrappedWriter.body = bodyLogger.newCapture()
+			if request.Body != nil {
+				request.Body = newTeeReadCloser(request.Body, requestBody)
+			}
+		}
+
 		// Call the next handler
 		next.ServeHTTP(wrappedWriter, request)
 
@@ -56,20 +126,22 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 		switch {
 		case statusCode >= 500:
-			logEvent = log.Error()
+			logEvent = requestLogger.Error()
 		case statusCode >= 400:
-			logEvent = log.Warn()
+			logEvent = requestLogger.Warn()
 		default:
-			logEvent = log.Info()
+			logEvent = requestLogger.Info()
 		}
 
 		// Log request completion with details
 		logEvent.
-			Str("method", request.Method).
-			Str("path", request.URL.Path).
 			Int("status", statusCode).
 			Dur("duration", duration).
 			Str("duration_ms", duration.String()).
 			Msg("Request completed")
+
+		if requestBody != nil {
+			bodyLogger.log(requestLogger, requestBody, wrappedWriter.body)
+		}
 	})
 }
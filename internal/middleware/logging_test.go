@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
 // TestNewResponseWriter tests the responseWriter constructor
@@ -180,3 +186,58 @@ func TestLoggingMiddleware_DifferentMethods(t *testing.T) {
 		})
 	}
 }
+
+// TestLoggingMiddleware_ChildLoggerIncludesRequestID tests that the context logger carries request fields
+func TestLoggingMiddleware_ChildLoggerIncludesRequestID(t *testing.T) {
+	var logBuffer bytes.Buffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logBuffer)
+	defer func() { log.Logger = originalLogger }()
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		logger := LoggerFromContext(request.Context())
+		logger.Info().Msg("handler log line")
+	})
+
+	middleware := RequestIDMiddleware(LoggingMiddleware(nextHandler))
+
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", nil)
+	request.Header.Set(RequestIDHeader, "test-request-id")
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	var handlerLine map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log line: %v", err)
+		}
+		if entry["message"] == "handler log line" {
+			handlerLine = entry
+		}
+	}
+
+	if handlerLine == nil {
+		t.Fatal("Expected handler log line to be written")
+	}
+
+	if handlerLine["request_id"] != "test-request-id" {
+		t.Errorf("Expected request_id 'test-request-id', got '%v'", handlerLine["request_id"])
+	}
+
+	if handlerLine["method"] != "POST" || handlerLine["path"] != "/api/v1/analyze" {
+		t.Errorf("Expected method and path fields, got method=%v path=%v", handlerLine["method"], handlerLine["path"])
+	}
+}
+
+// TestLoggerFromContext_Fallback tests that the global logger is returned when none is stored
+func TestLoggerFromContext_Fallback(t *testing.T) {
+	request, _ := http.NewRequest("POST", "/health", nil)
+
+	logger := LoggerFromContext(request.Context())
+
+	if logger.GetLevel() != log.Logger.GetLevel() {
+		t.Error("Expected fallback logger to match the global logger")
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// InternalNetworkMiddleware restricts requests whose path starts with one of pathPrefixes to
+// clients in allowedNetworks, returning 403 otherwise. The client IP is resolved with
+// ClientIP so X-Forwarded-For is only trusted from trustedProxies. Other paths are unaffected.
+func InternalNetworkMiddleware(pathPrefixes []string, allowedNetworks []*net.IPNet, trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if !hasAnyPrefix(request.URL.Path, pathPrefixes) {
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			clientIP := ClientIP(request, trustedProxies)
+			if clientIP == nil || !containsIP(allowedNetworks, clientIP) {
+				log.Warn().
+					Str("path", request.URL.Path).
+					Str("client_ip", clientIP.String()).
+					Msg("Rejected request to internal-only route")
+				apierrors.WriteError(responseWriter, request, apierrors.Forbidden("This endpoint is only available from internal networks"))
+				return
+			}
+
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
+
+// hasAnyPrefix reports whether path starts with any of the given prefixes
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
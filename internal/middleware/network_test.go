@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestInternalNetworkMiddleware builds the middleware with a 10.0.0.0/8 internal network
+// and a single trusted load balancer at 172.16.0.1
+func newTestInternalNetworkMiddleware(t *testing.T, nextHandler http.Handler) http.Handler {
+	internalNetworks, err := ParseCIDRs([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("Failed to parse internal networks: %v", err)
+	}
+	trustedProxies, err := ParseCIDRs([]string{"172.16.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+
+	return InternalNetworkMiddleware([]string{"/admin", "/debug"}, internalNetworks, trustedProxies)(nextHandler)
+}
+
+// TestInternalNetworkMiddleware_ExternalForbidden tests that an external IP gets 403 on a restricted prefix
+func TestInternalNetworkMiddleware_ExternalForbidden(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected external request to be rejected")
+	})
+
+	middleware := newTestInternalNetworkMiddleware(t, nextHandler)
+
+	request, _ := http.NewRequest("GET", "/admin/cache", nil)
+	request.RemoteAddr = "203.0.113.7:5000"
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, responseRecorder.Code)
+	}
+
+	var errorResponse map[string]map[string]string
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errorResponse["error"]["code"] != "FORBIDDEN" {
+		t.Errorf("Expected error code 'FORBIDDEN', got '%s'", errorResponse["error"]["code"])
+	}
+}
+
+// TestInternalNetworkMiddleware_InternalAllowed tests that an internal IP passes through
+func TestInternalNetworkMiddleware_InternalAllowed(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	middleware := newTestInternalNetworkMiddleware(t, nextHandler)
+
+	request, _ := http.NewRequest("GET", "/debug/vars", nil)
+	request.RemoteAddr = "10.2.3.4:5000"
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected internal request to reach the next handler")
+	}
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
+
+// TestInternalNetworkMiddleware_ForwardedThroughTrustedProxy tests that the forwarded client IP
+// is used when the request arrives via a trusted proxy
+func TestInternalNetworkMiddleware_ForwardedThroughTrustedProxy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{"internal client", "10.9.9.9", http.StatusOK},
+		{"external client", "203.0.113.7", http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+			middleware := newTestInternalNetworkMiddleware(t, nextHandler)
+
+			request, _ := http.NewRequest("GET", "/admin/cache", nil)
+			request.RemoteAddr = "172.16.0.1:443"
+			request.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+// TestInternalNetworkMiddleware_SpoofedHeaderIgnored tests that X-Forwarded-For from an untrusted
+// peer cannot be used to claim an internal address
+func TestInternalNetworkMiddleware_SpoofedHeaderIgnored(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected spoofed request to be rejected")
+	})
+
+	middleware := newTestInternalNetworkMiddleware(t, nextHandler)
+
+	request, _ := http.NewRequest("GET", "/admin/cache", nil)
+	request.RemoteAddr = "203.0.113.7:5000"
+	request.Header.Set("X-Forwarded-For", "10.0.0.1")
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, responseRecorder.Code)
+	}
+}
+
+// TestInternalNetworkMiddleware_UnrestrictedPath tests that other paths are unaffected
+func TestInternalNetworkMiddleware_UnrestrictedPath(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	middleware := newTestInternalNetworkMiddleware(t, nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.RemoteAddr = "203.0.113.7:5000"
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected unrestricted path to reach the next handler")
+	}
+}
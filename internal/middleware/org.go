@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	// orgIDContextKey stores the organization ID in the request context
+	orgIDContextKey contextKey = "orgID"
+
+	// OrgIDHeader is the header clients use to scope requests to an organization
+	OrgIDHeader = "X-Org-ID"
+)
+
+// OrgMiddleware tags requests with an organization ID. When AuthMiddleware ran first and the
+// auth service returned an org, that org is authoritative and an X-Org-ID header naming a
+// different org is rejected with 403; the header is only trusted on its own when there is no
+// authenticated org. The ID must be a UUID. When required is true, requests without an
+// organization are rejected with 400.
+func OrgMiddleware(required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			headerOrgID := request.Header.Get(OrgIDHeader)
+			authenticatedOrgID := OrgIDFromContext(request.Context())
+
+			orgID := headerOrgID
+			if authenticatedOrgID != "" {
+				orgID = authenticatedOrgID
+			}
+
+			if orgID == "" {
+				if required {
+					apierrors.WriteError(responseWriter, request, apierrors.MissingFields("X-Org-ID header is required"))
+					return
+				}
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			if headerOrgID != "" {
+				parsedHeaderOrgID, err := uuid.Parse(headerOrgID)
+				if err != nil {
+					apierrors.WriteError(responseWriter, request, apierrors.ValidationFailed("X-Org-ID must be a valid UUID"))
+					return
+				}
+
+				if authenticatedOrgID != "" && !sameOrgID(parsedHeaderOrgID, authenticatedOrgID) {
+					apierrors.WriteError(responseWriter, request, apierrors.Forbidden("X-Org-ID does not match the authenticated organization"))
+					return
+				}
+			}
+
+			parsedOrgID, err := uuid.Parse(orgID)
+			if err != nil {
+				apierrors.WriteError(responseWriter, request, apierrors.ValidationFailed("X-Org-ID must be a valid UUID"))
+				return
+			}
+
+			// Store the canonical form and tag subsequent log lines with it
+			canonicalOrgID := parsedOrgID.String()
+			ctx := context.WithValue(request.Context(), orgIDContextKey, canonicalOrgID)
+			logger := LoggerFromContext(ctx).With().Str("org_id", canonicalOrgID).Logger()
+			ctx = context.WithValue(ctx, loggerContextKey, logger)
+
+			next.ServeHTTP(responseWriter, request.WithContext(ctx))
+		})
+	}
+}
+
+// sameOrgID reports whether the header org matches the authenticated org, ignoring UUID formatting
+func sameOrgID(headerOrgID uuid.UUID, authenticatedOrgID string) bool {
+	parsedAuthenticatedOrgID, err := uuid.Parse(authenticatedOrgID)
+	return err == nil && parsedAuthenticatedOrgID == headerOrgID
+}
+
+// OrgIDFromContext returns the organization ID stored in the context, or an empty string
+func OrgIDFromContext(ctx context.Context) string {
+	orgID, _ := ctx.Value(orgIDContextKey).(string)
+	return orgID
+}
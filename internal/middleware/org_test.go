@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testOrgID = "3f2c1b7e-9a4d-4e8f-b6a1-0c5d7e9f1a2b"
+
+// TestOrgMiddleware_Present tests that a valid org ID is stored in context
+func TestOrgMiddleware_Present(t *testing.T) {
+	var contextOrgID string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contextOrgID = OrgIDFromContext(request.Context())
+	})
+
+	middleware := OrgMiddleware(true)(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set(OrgIDHeader, testOrgID)
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+
+	if contextOrgID != testOrgID {
+		t.Errorf("Expected org ID '%s', got '%s'", testOrgID, contextOrgID)
+	}
+}
+
+// TestOrgMiddleware_AbsentRequired tests that a missing org ID is rejected when required
+func TestOrgMiddleware_AbsentRequired(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected request without org ID to be rejected")
+	})
+
+	middleware := OrgMiddleware(true)(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestOrgMiddleware_AbsentOptional tests that a missing org ID is allowed when not required
+func TestOrgMiddleware_AbsentOptional(t *testing.T) {
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+		if OrgIDFromContext(request.Context()) != "" {
+			t.Error("Expected no org ID in context")
+		}
+	})
+
+	middleware := OrgMiddleware(false)(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if !nextCalled {
+		t.Error("Expected request to reach the next handler")
+	}
+}
+
+// TestOrgMiddleware_Malformed tests that a non-UUID org ID is rejected
+func TestOrgMiddleware_Malformed(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected malformed org ID to be rejected")
+	})
+
+	middleware := OrgMiddleware(false)(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set(OrgIDHeader, "not-a-uuid")
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+	}
+}
+
+// TestOrgMiddleware_AuthenticatedOrg tests that the authenticated org is preferred and a
+// conflicting X-Org-ID header is rejected
+func TestOrgMiddleware_AuthenticatedOrg(t *testing.T) {
+	const otherOrgID = "8b1d4c2a-6e3f-4a5b-9c7d-1e2f3a4b5c6d"
+
+	testCases := []struct {
+		name           string
+		headerOrgID    string
+		expectedStatus int
+	}{
+		{name: "no header", headerOrgID: "", expectedStatus: http.StatusOK},
+		{name: "matching header", headerOrgID: strings.ToUpper(testOrgID), expectedStatus: http.StatusOK},
+		{name: "conflicting header", headerOrgID: otherOrgID, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var contextOrgID string
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				contextOrgID = OrgIDFromContext(request.Context())
+			})
+
+			middleware := OrgMiddleware(true)(nextHandler)
+
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			request = request.WithContext(context.WithValue(request.Context(), orgIDContextKey, testOrgID))
+			if testCase.headerOrgID != "" {
+				request.Header.Set(OrgIDHeader, testCase.headerOrgID)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+
+			if testCase.expectedStatus == http.StatusOK && contextOrgID != testOrgID {
+				t.Errorf("Expected authenticated org ID '%s', got '%s'", testOrgID, contextOrgID)
+			}
+		})
+	}
+}
+
+// TestRateLimitMiddleware_IncludesOrgID tests that the org ID is sent as part of the rate-limit subject
+func TestRateLimitMiddleware_IncludesOrgID(t *testing.T) {
+	var receivedOrgID string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var requestBody checkRateLimitRequest
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		receivedOrgID = requestBody.OrgID
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99})
+	}))
+	defer mockServer.Close()
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+	middleware := OrgMiddleware(false)(RateLimitMiddleware(newTestRateLimitClient(mockServer.URL))(nextHandler))
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set("X-API-Key", "test-api-key")
+	request.Header.Set(OrgIDHeader, testOrgID)
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if receivedOrgID != testOrgID {
+		t.Errorf("Expected org ID '%s' in rate-limit request, got '%s'", testOrgID, receivedOrgID)
+	}
+}
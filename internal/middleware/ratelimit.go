@@ -1,35 +1,66 @@
 package middleware
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
 )
 
+// DefaultAPIKeyHeader is the header the API key is read from when no headers are configured
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// apiKeyTierContextKey stores the API key tier reported by the auth service in the request context
+const apiKeyTierContextKey contextKey = "apiKeyTier"
+
 // RateLimitServiceClient handles communication with the auth service for rate limiting
 type RateLimitServiceClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	retryPolicy retryPolicy
+	// bypassKeyHashes holds SHA-256 digests of API keys that skip rate limiting
+	bypassKeyHashes map[string]bool
+	// upstreamMetrics counts calls to the auth service; nil records nothing
+	upstreamMetrics *metrics.UpstreamMetrics
+	// failurePolicy decides whether requests pass when the check itself fails
+	failurePolicy RateLimitFailurePolicy
 }
 
 // NewRateLimitServiceClient creates a new rate limit service client
-func NewRateLimitServiceClient(baseURL string) *RateLimitServiceClient {
-	return &RateLimitServiceClient{
+func NewRateLimitServiceClient(baseURL string, options ...RateLimitClientOption) *RateLimitServiceClient {
+	client := &RateLimitServiceClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
+		retryPolicy:   rateLimitRetryPolicy,
+		failurePolicy: RateLimitFailClosed,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// WithRateLimitUpstreamMetrics counts rate-limit checks as "auth" upstream calls
+func WithRateLimitUpstreamMetrics(upstreamMetrics *metrics.UpstreamMetrics) RateLimitClientOption {
+	return func(client *RateLimitServiceClient) {
+		client.upstreamMetrics = upstreamMetrics
 	}
 }
 
 // checkRateLimitRequest represents the request to check rate limit
 type checkRateLimitRequest struct {
 	APIKey string `json:"apiKey"`
+	OrgID  string `json:"orgId,omitempty"`
+	// Cost is how many requests' worth of quota to reserve; omitted for a single request
+	Cost int `json:"cost,omitempty"`
 }
 
 // checkRateLimitResponse represents the response from rate limit check
@@ -38,24 +69,41 @@ type checkRateLimitResponse struct {
 	Limit     int   `json:"limit"`
 	Remaining int   `json:"remaining"`
 	Reset     int64 `json:"reset"`
+	// Tier is the API key's plan (e.g. "free", "premium"); empty when the auth service does not report one
+	Tier string `json:"tier,omitempty"`
+}
+
+// CheckRateLimit calls the auth service to check rate limit. When orgID is set, the
+// rate-limit subject is scoped to that organization.
+func (client *RateLimitServiceClient) CheckRateLimit(ctx context.Context, apiKey string, orgID string) (*checkRateLimitResponse, error) {
+	return client.CheckRateLimitCost(ctx, apiKey, orgID, 1)
 }
 
-// CheckRateLimit calls the auth service to check rate limit
-func (client *RateLimitServiceClient) CheckRateLimit(apiKey string) (*checkRateLimitResponse, error) {
-	requestBody := checkRateLimitRequest{APIKey: apiKey}
+// CheckRateLimitCost checks the rate limit for a request costing cost requests' worth of
+// quota. The auth service reserves the whole cost or denies the request without consuming any.
+func (client *RateLimitServiceClient) CheckRateLimitCost(ctx context.Context, apiKey string, orgID string, cost int) (*checkRateLimitResponse, error) {
+	requestBody := checkRateLimitRequest{APIKey: apiKey, OrgID: orgID}
+	if cost > 1 {
+		requestBody.Cost = cost
+	}
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, err
 	}
 
 	url := client.baseURL + "/api/v1/ratelimit/check"
-	resp, err := client.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := postJSONWithRetry(ctx, client.httpClient, client.retryPolicy, client.upstreamMetrics, "ratelimit", url, jsonData)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// If auth service returns non-200, API key is invalid
+	// A 5xx that outlasted the retries is a failed check, not a verdict on the API key
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("rate limit check failed with status %d", resp.StatusCode)
+	}
+
+	// If auth service returns another non-200, API key is invalid
 	if resp.StatusCode != http.StatusOK {
 		return &checkRateLimitResponse{
 			Allowed:   false,
@@ -67,35 +115,59 @@ func (client *RateLimitServiceClient) CheckRateLimit(apiKey string) (*checkRateL
 
 	var response checkRateLimitResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("malformed rate limit response: %w", err)
 	}
 
 	return &response, nil
 }
 
-// RateLimitMiddleware creates middleware that enforces rate limiting via auth service
-func RateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(http.Handler) http.Handler {
+// apiKeyHeaderNames returns the configured API key headers, or DefaultAPIKeyHeader when none are set
+func apiKeyHeaderNames(headerNames []string) []string {
+	if len(headerNames) == 0 {
+		return []string{DefaultAPIKeyHeader}
+	}
+	return headerNames
+}
+
+// RateLimitMiddleware creates middleware that enforces rate limiting via auth service.
+// The API key is read from the first of headerNames present on the request, in order;
+// with no headerNames it is read from X-API-Key. During key rotation several keys may be
+// sent (comma-separated or repeated); the first valid, allowed key is used. Requests given
+// a cost with WithRateLimitCost reserve that much quota in a single check.
+func RateLimitMiddleware(rateLimitClient *RateLimitServiceClient, headerNames ...string) func(http.Handler) http.Handler {
+	headerNames = apiKeyHeaderNames(headerNames)
+	missingKeyMessage := fmt.Sprintf("API key is required. Include %s header in your request.", strings.Join(headerNames, " or "))
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
-			// Extract API key from header
-			apiKey := request.Header.Get("X-API-Key")
+			// Extract API keys from the configured headers
+			apiKeys := apiKeysFromRequest(request, headerNames)
 
 			// If no API key provided, reject the request
-			if apiKey == "" {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+			if len(apiKeys) == 0 {
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeMissingAPIKey,
-					"API key is required. Include X-API-Key header in your request.",
+					missingKeyMessage,
 					http.StatusUnauthorized,
 				))
 				return
 			}
 
-			// Check rate limit via auth service
-			rateLimitResult, err := rateLimitClient.CheckRateLimit(apiKey)
+			// Allowlisted keys (internal services, our dashboard) skip the auth-service check
+			if rateLimitClient.bypassesRateLimitAny(apiKeys) {
+				responseWriter.Header().Set(RateLimitBypassHeader, "true")
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			// Check rate limit via auth service, trying rotated keys in order
+			cost := rateLimitCostFromContext(request.Context())
+			rateLimitResult, err := rateLimitClient.checkRateLimitKeys(request.Context(), apiKeys, OrgIDFromContext(request.Context()), cost)
 			if err != nil {
-				apierrors.WriteError(responseWriter, apierrors.InternalError("Rate limit check failed"))
+				rateLimitClient.handleCheckFailure(responseWriter, request, next, err)
 				return
 			}
+			setRateLimitCostHeader(responseWriter, cost)
 
 			// Add rate limit headers to response
 			responseWriter.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimitResult.Limit))
@@ -104,7 +176,7 @@ func RateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(http.Hand
 
 			// If API key is invalid (Limit is 0), reject
 			if rateLimitResult.Limit == 0 {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeInvalidAPIKey,
 					"Invalid or inactive API key.",
 					http.StatusUnauthorized,
@@ -120,39 +192,55 @@ func RateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(http.Hand
 				}
 				responseWriter.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
 
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				message := fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", retryAfter)
+				if cost > 1 {
+					message = fmt.Sprintf("Rate limit exceeded: this request costs %d requests but only %d remain. Try again in %d seconds.", cost, rateLimitResult.Remaining, retryAfter)
+				}
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeRateLimitExceeded,
-					fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", retryAfter),
+					message,
 					http.StatusTooManyRequests,
 				))
 				return
 			}
 
-			// Request allowed, proceed to next handler
-			next.ServeHTTP(responseWriter, request)
+			// Request allowed, proceed to next handler with the key's tier for per-tier defaults
+			next.ServeHTTP(responseWriter, request.WithContext(withAPIKeyTier(request.Context(), rateLimitResult.Tier)))
 		})
 	}
 }
 
-// OptionalRateLimitMiddleware creates middleware that enforces rate limiting only if API key is provided
-func OptionalRateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(http.Handler) http.Handler {
+// OptionalRateLimitMiddleware creates middleware that enforces rate limiting only if API key is provided.
+// headerNames behaves as in RateLimitMiddleware.
+func OptionalRateLimitMiddleware(rateLimitClient *RateLimitServiceClient, headerNames ...string) func(http.Handler) http.Handler {
+	headerNames = apiKeyHeaderNames(headerNames)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
-			// Extract API key from header
-			apiKey := request.Header.Get("X-API-Key")
+			// Extract API keys from the configured headers
+			apiKeys := apiKeysFromRequest(request, headerNames)
 
 			// If no API key provided, allow request without rate limiting
-			if apiKey == "" {
+			if len(apiKeys) == 0 {
 				next.ServeHTTP(responseWriter, request)
 				return
 			}
 
-			// Check rate limit via auth service
-			rateLimitResult, err := rateLimitClient.CheckRateLimit(apiKey)
+			// Allowlisted keys (internal services, our dashboard) skip the auth-service check
+			if rateLimitClient.bypassesRateLimitAny(apiKeys) {
+				responseWriter.Header().Set(RateLimitBypassHeader, "true")
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			// Check rate limit via auth service, trying rotated keys in order
+			cost := rateLimitCostFromContext(request.Context())
+			rateLimitResult, err := rateLimitClient.checkRateLimitKeys(request.Context(), apiKeys, OrgIDFromContext(request.Context()), cost)
 			if err != nil {
-				apierrors.WriteError(responseWriter, apierrors.InternalError("Rate limit check failed"))
+				rateLimitClient.handleCheckFailure(responseWriter, request, next, err)
 				return
 			}
+			setRateLimitCostHeader(responseWriter, cost)
 
 			// Add rate limit headers to response
 			responseWriter.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimitResult.Limit))
@@ -161,7 +249,7 @@ func OptionalRateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(h
 
 			// If API key is invalid, reject
 			if rateLimitResult.Limit == 0 {
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeInvalidAPIKey,
 					"Invalid or inactive API key.",
 					http.StatusUnauthorized,
@@ -172,7 +260,7 @@ func OptionalRateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(h
 			// If rate limit exceeded, reject with 429
 			if !rateLimitResult.Allowed {
 				responseWriter.Header().Set("Retry-After", strconv.FormatInt(rateLimitResult.Reset, 10))
-				apierrors.WriteError(responseWriter, apierrors.NewAPIError(
+				apierrors.WriteError(responseWriter, request, apierrors.NewAPIError(
 					apierrors.ErrCodeRateLimitExceeded,
 					"Rate limit exceeded.",
 					http.StatusTooManyRequests,
@@ -180,7 +268,22 @@ func OptionalRateLimitMiddleware(rateLimitClient *RateLimitServiceClient) func(h
 				return
 			}
 
-			next.ServeHTTP(responseWriter, request)
+			next.ServeHTTP(responseWriter, request.WithContext(withAPIKeyTier(request.Context(), rateLimitResult.Tier)))
 		})
 	}
 }
+
+// withAPIKeyTier stores a non-empty API key tier in ctx
+func withAPIKeyTier(ctx context.Context, tier string) context.Context {
+	if tier == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, apiKeyTierContextKey, tier)
+}
+
+// APIKeyTierFromContext returns the API key tier reported by the auth service, or an empty
+// string when rate limiting did not run, was bypassed or reported no tier
+func APIKeyTierFromContext(ctx context.Context) string {
+	tier, _ := ctx.Value(apiKeyTierContextKey).(string)
+	return tier
+}
@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// newTestRateLimitClient creates a rate limit client with a fast retry policy for tests
+func newTestRateLimitClient(baseURL string) *RateLimitServiceClient {
+	client := NewRateLimitServiceClient(baseURL)
+	client.retryPolicy.baseDelay = time.Millisecond
+	return client
+}
+
+// dropConnection closes the connection behind writer without a response, simulating a
+// transient network failure
+func dropConnection(t *testing.T, writer http.ResponseWriter) {
+	connection, _, err := http.NewResponseController(writer).Hijack()
+	if err != nil {
+		t.Fatalf("Failed to hijack connection: %v", err)
+	}
+	connection.Close()
+}
+
+// TestCheckRateLimit_RetriesTransientFailure tests that a dropped connection succeeds on the second try
+func TestCheckRateLimit_RetriesTransientFailure(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt32(&callCount, 1) == 1 {
+			dropConnection(t, writer)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99})
+	}))
+	defer mockServer.Close()
+
+	client := newTestRateLimitClient(mockServer.URL)
+
+	result, err := client.CheckRateLimit(context.Background(), "test-api-key", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.Allowed || result.Limit != 100 {
+		t.Errorf("Expected allowed result from second attempt, got %+v", result)
+	}
+
+	if atomic.LoadInt32(&callCount) != 2 {
+		t.Errorf("Expected 2 calls to auth service, got %d", callCount)
+	}
+}
+
+// TestCheckRateLimit_CountsUpstreamCalls tests that each rate-limit check attempt is counted
+// as an auth upstream call labeled by status code
+func TestCheckRateLimit_CountsUpstreamCalls(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt32(&callCount, 1) == 1 {
+			dropConnection(t, writer)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99})
+	}))
+	defer mockServer.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	client := newTestRateLimitClient(mockServer.URL)
+	WithRateLimitUpstreamMetrics(upstreamMetrics)(client)
+
+	if _, err := client.CheckRateLimit(context.Background(), "test-api-key", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls := upstreamMetrics.Calls(metrics.UpstreamAuth, "ratelimit", metrics.StatusCodeError); calls != 1 {
+		t.Errorf("Expected 1 failed ratelimit call, got %g", calls)
+	}
+	if calls := upstreamMetrics.Calls(metrics.UpstreamAuth, "ratelimit", "200"); calls != 1 {
+		t.Errorf("Expected 1 ratelimit 200 call, got %g", calls)
+	}
+}
+
+// TestCheckRateLimit_DoesNotRetryUnauthorized tests that 4xx responses are not retried
+func TestCheckRateLimit_DoesNotRetryUnauthorized(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	client := newTestRateLimitClient(mockServer.URL)
+
+	result, err := client.CheckRateLimit(context.Background(), "invalid-api-key", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Allowed || result.Limit != 0 {
+		t.Errorf("Expected denied result, got %+v", result)
+	}
+
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 call to auth service, got %d", callCount)
+	}
+}
+
+// TestCheckRateLimit_DoesNotRetryDenied tests that a valid-but-denied response is not retried
+func TestCheckRateLimit_DoesNotRetryDenied(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: false, Limit: 100, Remaining: 0})
+	}))
+	defer mockServer.Close()
+
+	client := newTestRateLimitClient(mockServer.URL)
+
+	result, err := client.CheckRateLimit(context.Background(), "test-api-key", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Allowed {
+		t.Error("Expected rate limit to be denied")
+	}
+
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 call to auth service, got %d", callCount)
+	}
+}
+
+// TestCheckRateLimit_DoesNotRetryServerError tests that a 5xx is not retried, since the
+// failed check may already have consumed quota, and is returned as a failed check
+func TestCheckRateLimit_DoesNotRetryServerError(t *testing.T) {
+	var callCount int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := newTestRateLimitClient(mockServer.URL)
+	if _, err := client.CheckRateLimit(context.Background(), "test-api-key", ""); err == nil {
+		t.Error("Expected an error for a 5xx response")
+	}
+
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 call to auth service, got %d", callCount)
+	}
+}
+
+// newAPIKeyEchoServer returns an auth service stub that allows every request and records the API key it saw
+func newAPIKeyEchoServer(receivedKey *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var checkRequest checkRateLimitRequest
+		json.NewDecoder(request.Body).Decode(&checkRequest)
+		*receivedKey = checkRequest.APIKey
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99})
+	}))
+}
+
+// TestRateLimitMiddleware_CustomHeader tests that the API key is read from configured headers in order
+func TestRateLimitMiddleware_CustomHeader(t *testing.T) {
+	var receivedKey string
+	mockServer := newAPIKeyEchoServer(&receivedKey)
+	defer mockServer.Close()
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	middleware := RateLimitMiddleware(newTestRateLimitClient(mockServer.URL), "Api-Key", "X-Api-Token")(nextHandler)
+
+	testCases := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+		expectedKey    string
+	}{
+		{"first configured header", map[string]string{"Api-Key": "key-one"}, http.StatusOK, "key-one"},
+		{"second configured header", map[string]string{"X-Api-Token": "key-two"}, http.StatusOK, "key-two"},
+		{"first header wins", map[string]string{"Api-Key": "key-one", "X-Api-Token": "key-two"}, http.StatusOK, "key-one"},
+		{"default header not accepted", map[string]string{"X-API-Key": "key-default"}, http.StatusUnauthorized, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			receivedKey = ""
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			for name, value := range testCase.headers {
+				request.Header.Set(name, value)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if receivedKey != testCase.expectedKey {
+				t.Errorf("Expected auth service to receive key '%s', got '%s'", testCase.expectedKey, receivedKey)
+			}
+		})
+	}
+}
+
+// TestRateLimitMiddleware_DefaultHeader tests that X-API-Key is used when no headers are configured
+func TestRateLimitMiddleware_DefaultHeader(t *testing.T) {
+	var receivedKey string
+	mockServer := newAPIKeyEchoServer(&receivedKey)
+	defer mockServer.Close()
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+	middleware := RateLimitMiddleware(newTestRateLimitClient(mockServer.URL))(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.Header.Set(DefaultAPIKeyHeader, "test-api-key")
+	middleware.ServeHTTP(httptest.NewRecorder(), request)
+
+	if receivedKey != "test-api-key" {
+		t.Errorf("Expected auth service to receive 'test-api-key', got '%s'", receivedKey)
+	}
+}
+
+// TestRateLimitMiddleware_StoresTier tests that the tier reported by the auth service is available to handlers
+func TestRateLimitMiddleware_StoresTier(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99, Tier: "premium"})
+	}))
+	defer mockServer.Close()
+
+	var tier string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tier = APIKeyTierFromContext(request.Context())
+	})
+	middleware := RateLimitMiddleware(newTestRateLimitClient(mockServer.URL))(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/analyze", nil)
+	request.Header.Set(DefaultAPIKeyHeader, "test-api-key")
+	middleware.ServeHTTP(httptest.NewRecorder(), request)
+
+	if tier != "premium" {
+		t.Errorf("Expected tier 'premium', got '%s'", tier)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RateLimitBypassHeader is set to "true" on responses to allowlisted API keys that skipped
+// the auth-service rate-limit check
+const RateLimitBypassHeader = "X-RateLimit-Bypass"
+
+// RateLimitClientOption configures a RateLimitServiceClient
+type RateLimitClientOption func(*RateLimitServiceClient)
+
+// WithBypassKeyHashes allowlists API keys, identified by their SHA-256 hex digest (see
+// HashAPIKey), that skip rate limiting entirely. Keys are configured by hash so the
+// plaintext keys never appear in the gateway's environment.
+func WithBypassKeyHashes(keyHashes ...string) RateLimitClientOption {
+	return func(client *RateLimitServiceClient) {
+		if client.bypassKeyHashes == nil {
+			client.bypassKeyHashes = make(map[string]bool, len(keyHashes))
+		}
+		for _, keyHash := range keyHashes {
+			client.bypassKeyHashes[strings.ToLower(keyHash)] = true
+		}
+	}
+}
+
+// HashAPIKey returns the lowercase SHA-256 hex digest of an API key, the form used by
+// WithBypassKeyHashes
+func HashAPIKey(apiKey string) string {
+	digest := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(digest[:])
+}
+
+// ParseBypassKeyHashes parses a comma-separated list of SHA-256 hex digests. An empty value
+// allowlists no keys.
+func ParseBypassKeyHashes(value string) ([]string, error) {
+	var keyHashes []string
+	for _, entry := range strings.Split(value, ",") {
+		keyHash := strings.ToLower(strings.TrimSpace(entry))
+		if keyHash == "" {
+			continue
+		}
+		if decoded, err := hex.DecodeString(keyHash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid API key hash %q: expected a SHA-256 hex digest", entry)
+		}
+		keyHashes = append(keyHashes, keyHash)
+	}
+	return keyHashes, nil
+}
+
+// bypassesRateLimit reports whether apiKey is allowlisted to skip rate limiting
+func (client *RateLimitServiceClient) bypassesRateLimit(apiKey string) bool {
+	return len(client.bypassKeyHashes) > 0 && client.bypassKeyHashes[HashAPIKey(apiKey)]
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRateLimitMiddleware_BypassKeys tests that allowlisted keys skip the auth-service check while other keys do not
+func TestRateLimitMiddleware_BypassKeys(t *testing.T) {
+	var receivedKey string
+	mockServer := newAPIKeyEchoServer(&receivedKey)
+	defer mockServer.Close()
+
+	client := newTestRateLimitClient(mockServer.URL)
+	WithBypassKeyHashes(strings.ToUpper(HashAPIKey("dashboard-key")))(client)
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	middleware := RateLimitMiddleware(client)(nextHandler)
+
+	testCases := []struct {
+		name           string
+		apiKey         string
+		expectedStatus int
+		expectedKey    string
+		expectedBypass string
+	}{
+		{"allowlisted key", "dashboard-key", http.StatusOK, "", "true"},
+		{"normal key", "public-key", http.StatusOK, "public-key", ""},
+		{"missing key", "", http.StatusUnauthorized, "", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			receivedKey = ""
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			if testCase.apiKey != "" {
+				request.Header.Set(DefaultAPIKeyHeader, testCase.apiKey)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if receivedKey != testCase.expectedKey {
+				t.Errorf("Expected auth service to receive key '%s', got '%s'", testCase.expectedKey, receivedKey)
+			}
+			if bypass := responseRecorder.Header().Get(RateLimitBypassHeader); bypass != testCase.expectedBypass {
+				t.Errorf("Expected %s '%s', got '%s'", RateLimitBypassHeader, testCase.expectedBypass, bypass)
+			}
+		})
+	}
+}
+
+// TestParseBypassKeyHashes tests parsing of the allowlisted API key hash list
+func TestParseBypassKeyHashes(t *testing.T) {
+	keyHash := HashAPIKey("dashboard-key")
+
+	keyHashes, err := ParseBypassKeyHashes(" " + strings.ToUpper(keyHash) + " ,")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keyHashes) != 1 || keyHashes[0] != keyHash {
+		t.Errorf("Expected [%s], got %v", keyHash, keyHashes)
+	}
+
+	if keyHashes, err := ParseBypassKeyHashes(""); err != nil || len(keyHashes) != 0 {
+		t.Errorf("Expected no hashes for an empty value, got %v (%v)", keyHashes, err)
+	}
+
+	for _, invalid := range []string{"dashboard-key", keyHash[:32], keyHash + "00"} {
+		if _, err := ParseBypassKeyHashes(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// rateLimitCostContextKey stores how many requests' worth of quota a request consumes
+	rateLimitCostContextKey contextKey = "rateLimitCost"
+
+	// RateLimitCostHeader reports the quota a request consumed when it cost more than one request
+	RateLimitCostHeader = "X-RateLimit-Cost"
+)
+
+// WithRateLimitCost returns ctx with the quota the request should reserve in its rate-limit
+// check, e.g. the number of players in a batch. The auth service reserves the whole cost
+// atomically or denies the request, so a batch is never cut off partway by its quota.
+// Costs of 1 or less leave ctx unchanged.
+func WithRateLimitCost(ctx context.Context, cost int) context.Context {
+	if cost <= 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, rateLimitCostContextKey, cost)
+}
+
+// rateLimitCostFromContext returns the quota the request reserves, 1 unless set with WithRateLimitCost
+func rateLimitCostFromContext(ctx context.Context) int {
+	if cost, ok := ctx.Value(rateLimitCostContextKey).(int); ok {
+		return cost
+	}
+	return 1
+}
+
+// setRateLimitCostHeader reports a cost above one request in X-RateLimit-Cost
+func setRateLimitCostHeader(responseWriter http.ResponseWriter, cost int) {
+	if cost > 1 {
+		responseWriter.Header().Set(RateLimitCostHeader, strconv.Itoa(cost))
+	}
+}
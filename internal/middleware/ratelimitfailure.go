@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// RateLimitDegradedHeader is set to "true" on requests let through without a rate-limit
+// check because the auth service could not be consulted (fail-open policy)
+const RateLimitDegradedHeader = "X-RateLimit-Degraded"
+
+// RateLimitFailurePolicy decides what happens to a request when its rate-limit check fails:
+// the auth service is unreachable, keeps erroring after retries, or answers with malformed JSON
+type RateLimitFailurePolicy string
+
+const (
+	// RateLimitFailClosed rejects the request with 500 (default)
+	RateLimitFailClosed RateLimitFailurePolicy = "closed"
+	// RateLimitFailOpen lets the request through unmetered, so an auth-service outage or
+	// serialization bug does not block all traffic
+	RateLimitFailOpen RateLimitFailurePolicy = "open"
+)
+
+// ParseRateLimitFailurePolicy parses RATE_LIMIT_FAILURE_POLICY; an empty value selects closed
+func ParseRateLimitFailurePolicy(value string) (RateLimitFailurePolicy, error) {
+	switch RateLimitFailurePolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RateLimitFailClosed:
+		return RateLimitFailClosed, nil
+	case RateLimitFailOpen:
+		return RateLimitFailOpen, nil
+	default:
+		return "", fmt.Errorf("invalid rate limit failure policy %q (expected closed or open)", value)
+	}
+}
+
+// WithRateLimitFailurePolicy sets how requests are treated when the rate-limit check fails
+func WithRateLimitFailurePolicy(policy RateLimitFailurePolicy) RateLimitClientOption {
+	return func(client *RateLimitServiceClient) {
+		client.failurePolicy = policy
+	}
+}
+
+// handleCheckFailure applies the failure policy to a request whose rate-limit check failed:
+// fail-open passes it to next with X-RateLimit-Degraded, fail-closed rejects it
+func (client *RateLimitServiceClient) handleCheckFailure(responseWriter http.ResponseWriter, request *http.Request, next http.Handler, err error) {
+	logger := LoggerFromContext(request.Context())
+
+	if client.failurePolicy == RateLimitFailOpen {
+		logger.Warn().Err(err).Msg("Rate limit check failed; allowing request (fail-open)")
+		responseWriter.Header().Set(RateLimitDegradedHeader, "true")
+		next.ServeHTTP(responseWriter, request)
+		return
+	}
+
+	logger.Error().Err(err).Msg("Rate limit check failed; rejecting request (fail-closed)")
+	apierrors.WriteError(responseWriter, request, apierrors.InternalError("Rate limit check failed"))
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseRateLimitFailurePolicy tests parsing of RATE_LIMIT_FAILURE_POLICY values
+func TestParseRateLimitFailurePolicy(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    RateLimitFailurePolicy
+		expectError bool
+	}{
+		{"", RateLimitFailClosed, false},
+		{"closed", RateLimitFailClosed, false},
+		{" OPEN ", RateLimitFailOpen, false},
+		{"sometimes", "", true},
+	}
+
+	for _, testCase := range testCases {
+		policy, err := ParseRateLimitFailurePolicy(testCase.value)
+		if (err != nil) != testCase.expectError {
+			t.Errorf("ParseRateLimitFailurePolicy(%q): expected error=%v, got %v", testCase.value, testCase.expectError, err)
+		}
+		if policy != testCase.expected {
+			t.Errorf("ParseRateLimitFailurePolicy(%q): expected '%s', got '%s'", testCase.value, testCase.expected, policy)
+		}
+	}
+}
+
+// TestRateLimitMiddleware_MalformedResponse tests that garbage JSON from the auth service
+// follows the failure policy: rejected when closed, served unmetered when open
+func TestRateLimitMiddleware_MalformedResponse(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`{"allowed": tru`))
+	}))
+	defer mockServer.Close()
+
+	assertFailurePolicyApplied(t, mockServer.URL)
+}
+
+// TestRateLimitMiddleware_ServerErrorResponse tests that an auth service still answering 5xx
+// once retries are exhausted follows the failure policy instead of denying the API key
+func TestRateLimitMiddleware_ServerErrorResponse(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.Error(writer, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	assertFailurePolicyApplied(t, mockServer.URL)
+}
+
+// assertFailurePolicyApplied checks that a failing rate-limit check against authServiceURL is
+// rejected when failing closed and served unmetered when failing open
+func assertFailurePolicyApplied(t *testing.T, authServiceURL string) {
+	t.Helper()
+
+	testCases := []struct {
+		name             string
+		policy           RateLimitFailurePolicy
+		expectedStatus   int
+		expectNextCalled bool
+	}{
+		{"fail closed", RateLimitFailClosed, http.StatusInternalServerError, false},
+		{"fail open", RateLimitFailOpen, http.StatusOK, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := newTestRateLimitClient(authServiceURL)
+			WithRateLimitFailurePolicy(testCase.policy)(client)
+
+			nextCalled := false
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				nextCalled = true
+				writer.WriteHeader(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+			request.Header.Set(DefaultAPIKeyHeader, "test-api-key")
+			responseRecorder := httptest.NewRecorder()
+			RateLimitMiddleware(client)(nextHandler).ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if nextCalled != testCase.expectNextCalled {
+				t.Errorf("Expected next handler called=%v, got %v", testCase.expectNextCalled, nextCalled)
+			}
+
+			degraded := responseRecorder.Header().Get(RateLimitDegradedHeader) == "true"
+			if degraded != (testCase.policy == RateLimitFailOpen) {
+				t.Errorf("Expected %s only when failing open, got '%s'", RateLimitDegradedHeader, responseRecorder.Header().Get(RateLimitDegradedHeader))
+			}
+		})
+	}
+}
+
+// TestRateLimitMiddleware_UnreachableFailsOpen tests that an unreachable auth service is treated like malformed JSON
+func TestRateLimitMiddleware_UnreachableFailsOpen(t *testing.T) {
+	mockServer := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := mockServer.URL
+	mockServer.Close()
+
+	client := newTestRateLimitClient(unreachableURL)
+	WithRateLimitFailurePolicy(RateLimitFailOpen)(client)
+
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		nextCalled = true
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+	request.Header.Set(DefaultAPIKeyHeader, "test-api-key")
+	OptionalRateLimitMiddleware(client)(nextHandler).ServeHTTP(httptest.NewRecorder(), request)
+
+	if !nextCalled {
+		t.Error("Expected the request to be served when failing open")
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// maxAPIKeysPerRequest bounds how many rotated keys one request may present, so a request
+// cannot fan out into many auth-service checks
+const maxAPIKeysPerRequest = 3
+
+// apiKeysFromRequest returns the API keys sent in the first of headerNames present on the
+// request. During key rotation clients may send several keys, comma-separated and/or as
+// repeated header values; keys are returned in order, without duplicates, up to
+// maxAPIKeysPerRequest.
+func apiKeysFromRequest(request *http.Request, headerNames []string) []string {
+	for _, headerName := range headerNames {
+		var apiKeys []string
+		seen := make(map[string]bool)
+
+		for _, value := range request.Header.Values(headerName) {
+			for _, apiKey := range strings.Split(value, ",") {
+				apiKey = strings.TrimSpace(apiKey)
+				if apiKey == "" || seen[apiKey] || len(apiKeys) == maxAPIKeysPerRequest {
+					continue
+				}
+				seen[apiKey] = true
+				apiKeys = append(apiKeys, apiKey)
+			}
+		}
+
+		if len(apiKeys) > 0 {
+			return apiKeys
+		}
+	}
+	return nil
+}
+
+// bypassesRateLimitAny reports whether any of the presented keys is allowlisted
+func (client *RateLimitServiceClient) bypassesRateLimitAny(apiKeys []string) bool {
+	for _, apiKey := range apiKeys {
+		if client.bypassesRateLimit(apiKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRateLimitKeys checks the presented keys in order, each asking to reserve cost, and
+// returns the result of the first valid key that is allowed. Invalid keys are skipped; when
+// no valid key is allowed, the first valid key's (rate-limited) result is returned, and when
+// every key is invalid, the last invalid result. A failed check is returned as an error
+// without trying further keys.
+func (client *RateLimitServiceClient) checkRateLimitKeys(ctx context.Context, apiKeys []string, orgID string, cost int) (*checkRateLimitResponse, error) {
+	var invalidResult, deniedResult *checkRateLimitResponse
+
+	for _, apiKey := range apiKeys {
+		result, err := client.CheckRateLimitCost(ctx, apiKey, orgID, cost)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case result.Limit == 0:
+			invalidResult = result
+		case result.Allowed:
+			return result, nil
+		case deniedResult == nil:
+			deniedResult = result
+		}
+	}
+
+	if deniedResult != nil {
+		return deniedResult, nil
+	}
+	return invalidResult, nil
+}
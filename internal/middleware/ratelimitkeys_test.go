@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// newKeyRotationServer returns an auth stub that only knows "new-key", recording every key checked
+func newKeyRotationServer(checkedKeys *[]string, mutex *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var checkRequest checkRateLimitRequest
+		json.NewDecoder(request.Body).Decode(&checkRequest)
+
+		mutex.Lock()
+		*checkedKeys = append(*checkedKeys, checkRequest.APIKey)
+		mutex.Unlock()
+
+		if checkRequest.APIKey != "new-key" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(checkRateLimitResponse{Allowed: true, Limit: 100, Remaining: 99, Tier: "premium"})
+	}))
+}
+
+// TestRateLimitMiddleware_RotatedKeys tests that an invalid key is skipped in favor of a valid one in the same header
+func TestRateLimitMiddleware_RotatedKeys(t *testing.T) {
+	var checkedKeys []string
+	var mutex sync.Mutex
+	mockServer := newKeyRotationServer(&checkedKeys, &mutex)
+	defer mockServer.Close()
+
+	var tier string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tier = APIKeyTierFromContext(request.Context())
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+	request.Header.Set(DefaultAPIKeyHeader, "old-key, new-key")
+	responseRecorder := httptest.NewRecorder()
+	RateLimitMiddleware(newTestRateLimitClient(mockServer.URL))(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	if tier != "premium" {
+		t.Errorf("Expected the valid key's tier 'premium', got '%s'", tier)
+	}
+	if responseRecorder.Header().Get("X-RateLimit-Remaining") != "99" {
+		t.Errorf("Expected the valid key's rate limit headers, got remaining '%s'", responseRecorder.Header().Get("X-RateLimit-Remaining"))
+	}
+	if !reflect.DeepEqual(checkedKeys, []string{"old-key", "new-key"}) {
+		t.Errorf("Expected keys checked in order, got %v", checkedKeys)
+	}
+}
+
+// TestRateLimitMiddleware_RotatedKeysAllInvalid tests that the request is rejected when every key is invalid
+func TestRateLimitMiddleware_RotatedKeysAllInvalid(t *testing.T) {
+	var checkedKeys []string
+	var mutex sync.Mutex
+	mockServer := newKeyRotationServer(&checkedKeys, &mutex)
+	defer mockServer.Close()
+
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected the request not to be served")
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+	request.Header.Add(DefaultAPIKeyHeader, "old-key")
+	request.Header.Add(DefaultAPIKeyHeader, "revoked-key")
+	responseRecorder := httptest.NewRecorder()
+	RateLimitMiddleware(newTestRateLimitClient(mockServer.URL))(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, responseRecorder.Code)
+	}
+	if len(checkedKeys) != 2 {
+		t.Errorf("Expected both repeated header values to be checked, got %v", checkedKeys)
+	}
+}
+
+// TestAPIKeysFromRequest tests splitting, de-duplication, the key cap and header priority
+func TestAPIKeysFromRequest(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil)
+	request.Header.Add("X-Api-Token", "token-key")
+	request.Header.Add(DefaultAPIKeyHeader, "a, b,,a")
+	request.Header.Add(DefaultAPIKeyHeader, "c,d")
+
+	apiKeys := apiKeysFromRequest(request, []string{DefaultAPIKeyHeader, "X-Api-Token"})
+	if !reflect.DeepEqual(apiKeys, []string{"a", "b", "c"}) {
+		t.Errorf("Expected [a b c], got %v", apiKeys)
+	}
+
+	if apiKeys := apiKeysFromRequest(request, []string{"Api-Key", "X-Api-Token"}); !reflect.DeepEqual(apiKeys, []string{"token-key"}) {
+		t.Errorf("Expected the first present header to be used, got %v", apiKeys)
+	}
+}
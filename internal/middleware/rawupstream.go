@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// RawUpstreamQueryParam opts a request into receiving upstream error responses unmodified
+const RawUpstreamQueryParam = "passthrough"
+
+// rawUpstreamContextKey marks a request whose upstream errors should be passed through as-is
+const rawUpstreamContextKey contextKey = "rawUpstream"
+
+// RawUpstreamMiddleware enables passthrough mode for requests sending ?passthrough=true.
+// Raw upstream responses can leak internal details, so the mode is restricted to callers
+// holding the admin token; without it (or when no admin token is configured) the request
+// is rejected rather than silently served remapped errors.
+func RawUpstreamMiddleware(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if request.URL.Query().Get(RawUpstreamQueryParam) != "true" {
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			if adminToken == "" {
+				apierrors.WriteError(responseWriter, request, apierrors.Forbidden("Upstream passthrough mode is disabled"))
+				return
+			}
+
+			providedToken := request.Header.Get(AdminTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(providedToken), []byte(adminToken)) != 1 {
+				apierrors.WriteError(responseWriter, request, apierrors.Forbidden(
+					"Upstream passthrough mode requires a valid "+AdminTokenHeader+" header",
+				))
+				return
+			}
+
+			ctx := context.WithValue(request.Context(), rawUpstreamContextKey, true)
+			next.ServeHTTP(responseWriter, request.WithContext(ctx))
+		})
+	}
+}
+
+// RawUpstreamRequested reports whether the request was granted upstream passthrough mode
+func RawUpstreamRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(rawUpstreamContextKey).(bool)
+	return requested
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRawUpstreamMiddleware tests that passthrough mode is gated by the admin token
+func TestRawUpstreamMiddleware(t *testing.T) {
+	testCases := []struct {
+		name              string
+		configured        string
+		provided          string
+		path              string
+		expectedStatus    int
+		expectPassthrough bool
+	}{
+		{"not requested", "secret-token", "", "/api/v1/summoner", http.StatusOK, false},
+		{"not requested with false", "secret-token", "secret-token", "/api/v1/summoner?passthrough=false", http.StatusOK, false},
+		{"valid token", "secret-token", "secret-token", "/api/v1/summoner?passthrough=true", http.StatusOK, true},
+		{"wrong token", "secret-token", "guess", "/api/v1/summoner?passthrough=true", http.StatusForbidden, false},
+		{"missing token", "secret-token", "", "/api/v1/summoner?passthrough=true", http.StatusForbidden, false},
+		{"admin disabled", "", "anything", "/api/v1/summoner?passthrough=true", http.StatusForbidden, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var passthrough bool
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				passthrough = RawUpstreamRequested(request.Context())
+			})
+			middleware := RawUpstreamMiddleware(testCase.configured)(nextHandler)
+
+			request, _ := http.NewRequest("POST", testCase.path, nil)
+			if testCase.provided != "" {
+				request.Header.Set(AdminTokenHeader, testCase.provided)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if passthrough != testCase.expectPassthrough {
+				t.Errorf("Expected passthrough %v, got %v", testCase.expectPassthrough, passthrough)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// contextKey is an unexported type for context keys defined in this package
+type contextKey string
+
+const (
+	// requestIDContextKey stores the request ID in the request context
+	requestIDContextKey contextKey = "requestID"
+
+	// RequestIDHeader is the header used to accept and echo request IDs
+	RequestIDHeader = "X-Request-ID"
+)
+
+// RequestIDMiddleware assigns each request an ID, reusing the inbound X-Request-ID
+// header when present, and echoes it back on the response
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return RequestIDHeaderMiddleware(RequestIDHeader)(next)
+}
+
+// RequestIDHeaderMiddleware is RequestIDMiddleware with a configurable header name (e.g.
+// X-Correlation-ID), used both to accept an inbound ID and to echo it back. An empty name
+// means X-Request-ID.
+func RequestIDHeaderMiddleware(headerName string) func(http.Handler) http.Handler {
+	if headerName == "" {
+		headerName = RequestIDHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			requestID := request.Header.Get(headerName)
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+
+			responseWriter.Header().Set(headerName, requestID)
+
+			ctx := context.WithValue(request.Context(), requestIDContextKey, requestID)
+			next.ServeHTTP(responseWriter, request.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID stored in the context, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRequestIDMiddleware_GeneratesID tests that a request ID is generated when none is provided
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	var contextRequestID string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contextRequestID = RequestIDFromContext(request.Context())
+	})
+
+	middleware := RequestIDMiddleware(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/health", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if contextRequestID == "" {
+		t.Fatal("Expected request ID to be stored in context")
+	}
+
+	if responseRecorder.Header().Get(RequestIDHeader) != contextRequestID {
+		t.Errorf("Expected response header '%s', got '%s'", contextRequestID, responseRecorder.Header().Get(RequestIDHeader))
+	}
+}
+
+// TestRequestIDMiddleware_ReusesInboundID tests that an inbound request ID is preserved
+func TestRequestIDMiddleware_ReusesInboundID(t *testing.T) {
+	var contextRequestID string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contextRequestID = RequestIDFromContext(request.Context())
+	})
+
+	middleware := RequestIDMiddleware(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/health", nil)
+	request.Header.Set(RequestIDHeader, "client-request-id")
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if contextRequestID != "client-request-id" {
+		t.Errorf("Expected request ID 'client-request-id', got '%s'", contextRequestID)
+	}
+
+	if responseRecorder.Header().Get(RequestIDHeader) != "client-request-id" {
+		t.Errorf("Expected response header 'client-request-id', got '%s'", responseRecorder.Header().Get(RequestIDHeader))
+	}
+}
+
+// TestRequestIDHeaderMiddleware_CustomHeader tests that a configured header name is used to
+// accept and echo request IDs instead of X-Request-ID
+func TestRequestIDHeaderMiddleware_CustomHeader(t *testing.T) {
+	var contextRequestID string
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contextRequestID = RequestIDFromContext(request.Context())
+	})
+
+	middleware := RequestIDHeaderMiddleware("X-Correlation-ID")(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/health", nil)
+	request.Header.Set("X-Correlation-ID", "correlation-id")
+	request.Header.Set(RequestIDHeader, "ignored-request-id")
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if contextRequestID != "correlation-id" {
+		t.Errorf("Expected request ID 'correlation-id', got '%s'", contextRequestID)
+	}
+	if got := responseRecorder.Header().Get("X-Correlation-ID"); got != "correlation-id" {
+		t.Errorf("Expected X-Correlation-ID 'correlation-id', got '%s'", got)
+	}
+	if got := responseRecorder.Header().Get(RequestIDHeader); got != "" {
+		t.Errorf("Expected no %s header, got '%s'", RequestIDHeader, got)
+	}
+}
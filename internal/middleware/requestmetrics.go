@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// RequestMetricsMiddleware counts requests by response status class and tracks the number
+// in flight. A nil httpMetrics disables it.
+func RequestMetricsMiddleware(httpMetrics *metrics.HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if httpMetrics == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			wrappedWriter := newResponseWriter(writer)
+
+			httpMetrics.Started()
+			defer func() {
+				httpMetrics.Finished(wrappedWriter.statusCode)
+			}()
+
+			next.ServeHTTP(wrappedWriter, request)
+		})
+	}
+}
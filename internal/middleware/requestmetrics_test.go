@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// TestRequestMetricsMiddleware tests that requests are counted by status class and leave no in-flight count
+func TestRequestMetricsMiddleware(t *testing.T) {
+	httpMetrics := metrics.NewHTTPMetrics(metrics.NewRegistry())
+
+	var inFlightDuringRequest float64
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		inFlightDuringRequest = httpMetrics.InFlight()
+		if request.URL.Path == "/missing" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.Write([]byte("ok"))
+	})
+	handler := RequestMetricsMiddleware(httpMetrics)(nextHandler)
+
+	for _, path := range []string{"/ready", "/ready", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if inFlightDuringRequest != 1 {
+		t.Errorf("Expected 1 request in flight while handling, got %g", inFlightDuringRequest)
+	}
+	if httpMetrics.InFlight() != 0 {
+		t.Errorf("Expected 0 requests in flight afterwards, got %g", httpMetrics.InFlight())
+	}
+	if httpMetrics.Requests("2xx") != 2 || httpMetrics.Requests("4xx") != 1 {
+		t.Errorf("Expected 2 2xx and 1 4xx requests, got %g and %g", httpMetrics.Requests("2xx"), httpMetrics.Requests("4xx"))
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/backoff"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// retryPolicy controls how calls to the auth service are retried on transient failures
+type retryPolicy struct {
+	// maxAttempts is the total number of attempts, including the first
+	maxAttempts int
+	// baseDelay is the backoff before the first retry; it doubles on each retry
+	baseDelay time.Duration
+	// totalTimeout bounds the combined time spent across all attempts
+	totalTimeout time.Duration
+	// retryServerErrors retries 5xx responses as well as connection errors. It is off for
+	// calls that consume quota, since a 5xx may come after the auth service recorded the call.
+	retryServerErrors bool
+}
+
+// defaultRetryPolicy retries a couple of times within the auth clients' 5s budget
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts:       3,
+	baseDelay:         100 * time.Millisecond,
+	totalTimeout:      5 * time.Second,
+	retryServerErrors: true,
+}
+
+// rateLimitRetryPolicy retries rate-limit checks only on connection errors, because each
+// check that reaches the auth service may consume quota
+var rateLimitRetryPolicy = retryPolicy{
+	maxAttempts:  3,
+	baseDelay:    100 * time.Millisecond,
+	totalTimeout: 5 * time.Second,
+}
+
+// postJSONWithRetry posts JSON to the given URL, retrying connection errors (and 5xx
+// responses when the policy allows) with exponential backoff and jitter. All attempts share
+// one context bounded by the policy's total timeout and by ctx, so a cancelled request stops
+// both the call in flight and the backoff. 4xx responses (including 401/403) are returned
+// as-is. Each attempt is counted as an auth-service call for the given operation.
+func postJSONWithRetry(ctx context.Context, httpClient *http.Client, policy retryPolicy, upstreamMetrics *metrics.UpstreamMetrics, operation string, url string, jsonData []byte) (*http.Response, error) {
+	callContext, cancel := context.WithTimeout(ctx, policy.totalTimeout)
+
+	var response *http.Response
+	var err error
+
+	for attempt := 0; attempt < policy.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := backoff.Delay(policy.baseDelay, attempt)
+			if deadline, ok := callContext.Deadline(); ok && time.Until(deadline) < delay {
+				break
+			}
+
+			// Discard the previous transient response before retrying
+			if response != nil {
+				response.Body.Close()
+				response = nil
+			}
+
+			if err = backoff.Wait(callContext, delay); err != nil {
+				break
+			}
+		}
+
+		response, err = postJSON(callContext, httpClient, url, jsonData)
+		recordAuthCall(upstreamMetrics, operation, response, err)
+		if !isTransientFailure(response, err, policy) {
+			break
+		}
+	}
+
+	if response == nil {
+		cancel()
+		return nil, err
+	}
+
+	// The call context must outlive this function until the caller has read the body
+	response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+	return response, err
+}
+
+// postJSON sends a single JSON POST bound to ctx
+func postJSON(ctx context.Context, httpClient *http.Client, url string, jsonData []byte) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return httpClient.Do(request)
+}
+
+// cancelOnClose releases a call's context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the call context
+func (body *cancelOnClose) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
+
+// recordAuthCall counts an auth-service call attempt by operation and status code
+func recordAuthCall(upstreamMetrics *metrics.UpstreamMetrics, operation string, response *http.Response, err error) {
+	if err != nil {
+		upstreamMetrics.Call(metrics.UpstreamAuth, operation, 0)
+		return
+	}
+	upstreamMetrics.Call(metrics.UpstreamAuth, operation, response.StatusCode)
+}
+
+// isTransientFailure reports whether a call failed in a way the policy retries
+func isTransientFailure(response *http.Response, err error, policy retryPolicy) bool {
+	if err != nil {
+		return true
+	}
+	return policy.retryServerErrors && response.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+const (
+	// SchemaVersionHeader carries the request schema version the client was built against
+	SchemaVersionHeader = "X-API-Schema"
+
+	// CurrentSchemaVersion is assumed for requests that do not send X-API-Schema
+	CurrentSchemaVersion = "1"
+
+	// schemaVersionContextKey stores the negotiated schema version in the request context
+	schemaVersionContextKey contextKey = "schemaVersion"
+)
+
+// ParseSchemaVersions parses a comma-separated list of accepted schema versions. Versions
+// are positive integers and the list must include CurrentSchemaVersion, since requests
+// without the header are treated as the current version. An empty spec accepts only the
+// current version.
+func ParseSchemaVersions(spec string) ([]string, error) {
+	var versions []string
+	includesCurrent := false
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if version, err := strconv.Atoi(entry); err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid schema version %q: expected a positive integer", entry)
+		}
+		if entry == CurrentSchemaVersion {
+			includesCurrent = true
+		}
+		versions = append(versions, entry)
+	}
+
+	if versions == nil {
+		return []string{CurrentSchemaVersion}, nil
+	}
+	if !includesCurrent {
+		return nil, fmt.Errorf("schema versions %q must include the current version %s", spec, CurrentSchemaVersion)
+	}
+	return versions, nil
+}
+
+// SchemaVersionMiddleware rejects requests whose X-API-Schema version is not in
+// acceptedVersions with 400 UNSUPPORTED_SCHEMA_VERSION, so breaking request changes can be
+// gated by version. Requests without the header use CurrentSchemaVersion. The version in
+// effect is echoed on the response and stored in the request context.
+func SchemaVersionMiddleware(acceptedVersions []string) func(http.Handler) http.Handler {
+	accepted := make(map[string]bool, len(acceptedVersions))
+	for _, version := range acceptedVersions {
+		accepted[version] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			version := strings.TrimSpace(request.Header.Get(SchemaVersionHeader))
+			if version == "" {
+				version = CurrentSchemaVersion
+			}
+
+			if !accepted[version] {
+				apierrors.WriteError(responseWriter, request, apierrors.UnsupportedSchemaVersion(fmt.Sprintf(
+					"Unsupported %s version %q; supported versions: %s",
+					SchemaVersionHeader, version, strings.Join(acceptedVersions, ", "),
+				)))
+				return
+			}
+
+			responseWriter.Header().Set(SchemaVersionHeader, version)
+			ctx := context.WithValue(request.Context(), schemaVersionContextKey, version)
+			next.ServeHTTP(responseWriter, request.WithContext(ctx))
+		})
+	}
+}
+
+// SchemaVersionFromContext returns the request schema version stored by
+// SchemaVersionMiddleware, or CurrentSchemaVersion when none is present
+func SchemaVersionFromContext(ctx context.Context) string {
+	if version, ok := ctx.Value(schemaVersionContextKey).(string); ok {
+		return version
+	}
+	return CurrentSchemaVersion
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+// TestParseSchemaVersions tests parsing of the accepted schema versions list
+func TestParseSchemaVersions(t *testing.T) {
+	testCases := []struct {
+		name             string
+		spec             string
+		expectedVersions []string
+		expectError      bool
+	}{
+		{"empty defaults to current", "", []string{"1"}, false},
+		{"multiple", " 1, 2 ,", []string{"1", "2"}, false},
+		{"missing current", "2", nil, true},
+		{"not a number", "1,v2", nil, true},
+		{"zero", "0,1", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			versions, err := ParseSchemaVersions(testCase.spec)
+			if (err != nil) != testCase.expectError {
+				t.Fatalf("Expected error %v, got %v", testCase.expectError, err)
+			}
+			if len(versions) != len(testCase.expectedVersions) {
+				t.Fatalf("Expected versions %v, got %v", testCase.expectedVersions, versions)
+			}
+			for index, version := range versions {
+				if version != testCase.expectedVersions[index] {
+					t.Errorf("Expected versions %v, got %v", testCase.expectedVersions, versions)
+				}
+			}
+		})
+	}
+}
+
+// TestSchemaVersionMiddleware tests supported, unsupported and absent X-API-Schema headers
+func TestSchemaVersionMiddleware(t *testing.T) {
+	testCases := []struct {
+		name            string
+		header          string
+		expectedStatus  int
+		expectedVersion string
+	}{
+		{"supported", "2", http.StatusOK, "2"},
+		{"unsupported", "3", http.StatusBadRequest, ""},
+		{"absent defaults to current", "", http.StatusOK, CurrentSchemaVersion},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var contextVersion string
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				contextVersion = SchemaVersionFromContext(request.Context())
+			})
+			middleware := SchemaVersionMiddleware([]string{"1", "2"})(nextHandler)
+
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			if testCase.header != "" {
+				request.Header.Set(SchemaVersionHeader, testCase.header)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+
+			if testCase.expectedStatus != http.StatusOK {
+				var errorResponse apierrors.ErrorResponse
+				if err := json.NewDecoder(responseRecorder.Body).Decode(&errorResponse); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if errorResponse.Error.Code != apierrors.ErrCodeUnsupportedSchema {
+					t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeUnsupportedSchema, errorResponse.Error.Code)
+				}
+				return
+			}
+
+			if contextVersion != testCase.expectedVersion {
+				t.Errorf("Expected context version '%s', got '%s'", testCase.expectedVersion, contextVersion)
+			}
+			if responseRecorder.Header().Get(SchemaVersionHeader) != testCase.expectedVersion {
+				t.Errorf("Expected echoed version '%s', got '%s'", testCase.expectedVersion, responseRecorder.Header().Get(SchemaVersionHeader))
+			}
+		})
+	}
+}
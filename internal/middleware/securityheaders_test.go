@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSecurityHeadersMiddleware tests that the default security headers are set on a normal
+// response and that HSTS is only sent over TLS
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tls          bool
+		expectedHSTS string
+	}{
+		{"plain HTTP", false, ""},
+		{"TLS", true, DefaultStrictTransportSecurity},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(http.StatusOK)
+			})
+			middleware := SecurityHeadersMiddleware(DefaultSecurityHeaders())(nextHandler)
+
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			if testCase.tls {
+				request.TLS = &tls.ConnectionState{}
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			expectedHeaders := map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "no-referrer",
+				"Strict-Transport-Security": testCase.expectedHSTS,
+			}
+			for header, expected := range expectedHeaders {
+				if got := responseRecorder.Header().Get(header); got != expected {
+					t.Errorf("Expected %s '%s', got '%s'", header, expected, got)
+				}
+			}
+		})
+	}
+}
+
+// TestSecurityHeadersMiddleware_Configured tests custom values and disabled headers
+func TestSecurityHeadersMiddleware_Configured(t *testing.T) {
+	headers := SecurityHeaders{
+		ContentTypeOptions:      SecurityHeaderValue("", DefaultContentTypeOptions),
+		FrameOptions:            SecurityHeaderValue("SAMEORIGIN", DefaultFrameOptions),
+		ReferrerPolicy:          SecurityHeaderValue("none", DefaultReferrerPolicy),
+		StrictTransportSecurity: SecurityHeaderValue("max-age=600", DefaultStrictTransportSecurity),
+	}
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	middleware := SecurityHeadersMiddleware(headers)(nextHandler)
+
+	request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+	request.TLS = &tls.ConnectionState{}
+	responseRecorder := httptest.NewRecorder()
+
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if got := responseRecorder.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("Expected the default X-Content-Type-Options, got '%s'", got)
+	}
+	if got := responseRecorder.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+		t.Errorf("Expected X-Frame-Options 'SAMEORIGIN', got '%s'", got)
+	}
+	if _, present := responseRecorder.Header()["Referrer-Policy"]; present {
+		t.Error("Expected Referrer-Policy to be disabled")
+	}
+	if got := responseRecorder.Header().Get("Strict-Transport-Security"); got != "max-age=600" {
+		t.Errorf("Expected Strict-Transport-Security 'max-age=600', got '%s'", got)
+	}
+}
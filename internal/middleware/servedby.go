@@ -0,0 +1,22 @@
+package middleware
+
+import "net/http"
+
+// ServedByHeader names the gateway region or datacenter that served the response
+const ServedByHeader = "X-Served-By"
+
+// ServedByMiddleware sets X-Served-By to gatewayRegion on every response, so latency in a
+// multi-region deployment can be traced to the gateway that handled the request. An empty
+// region disables the header.
+func ServedByMiddleware(gatewayRegion string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if gatewayRegion == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			responseWriter.Header().Set(ServedByHeader, gatewayRegion)
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestServedByMiddleware tests that X-Served-By is set only when a gateway region is configured
+func TestServedByMiddleware(t *testing.T) {
+	testCases := []struct {
+		name           string
+		gatewayRegion  string
+		expectedHeader string
+	}{
+		{"configured", "eu-west-1", "eu-west-1"},
+		{"unset", "", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			})
+			middleware := ServedByMiddleware(testCase.gatewayRegion)(nextHandler)
+
+			request, _ := http.NewRequest("POST", "/api/v1/summoner", nil)
+			responseRecorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(responseRecorder, request)
+
+			if _, present := responseRecorder.Header()[ServedByHeader]; present != (testCase.expectedHeader != "") {
+				t.Errorf("Expected %s present to be %v", ServedByHeader, testCase.expectedHeader != "")
+			}
+			if got := responseRecorder.Header().Get(ServedByHeader); got != testCase.expectedHeader {
+				t.Errorf("Expected %s '%s', got '%s'", ServedByHeader, testCase.expectedHeader, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the signed request (see SignRequestBody)
+	SignatureHeader = "X-Signature"
+	// TimestampHeader carries the Unix time in seconds at which the request was signed
+	TimestampHeader = "X-Timestamp"
+
+	// DefaultSignatureMaxAge is how far X-Timestamp may be from the gateway's clock, in
+	// either direction, before a signed request is rejected as a possible replay
+	DefaultSignatureMaxAge = 5 * time.Minute
+
+	// maxSignedBodyBytes bounds how much of a body is buffered to verify its signature
+	maxSignedBodyBytes = 1 << 20
+)
+
+// SignRequestBody returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret. The
+// timestamp is part of the signed message so it cannot be refreshed to replay an old body.
+func SignRequestBody(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequestSigningMiddleware requires server-to-server callers to sign requests whose path
+// starts with one of pathPrefixes: X-Signature must be SignRequestBody(secret, X-Timestamp,
+// body) and X-Timestamp must be within maxAge of now. Failures get 401 INVALID_SIGNATURE.
+// The verified body is restored for the next handler. Other paths are unaffected, and the
+// middleware is a no-op when secret or pathPrefixes is empty.
+func RequestSigningMiddleware(secret []byte, pathPrefixes []string, maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(secret) == 0 || len(pathPrefixes) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if !hasAnyPrefix(request.URL.Path, pathPrefixes) {
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+
+			signature := request.Header.Get(SignatureHeader)
+			timestamp := request.Header.Get(TimestampHeader)
+			if signature == "" || timestamp == "" {
+				apierrors.WriteError(responseWriter, request, apierrors.InvalidSignature("X-Signature and X-Timestamp headers are required"))
+				return
+			}
+
+			signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				apierrors.WriteError(responseWriter, request, apierrors.InvalidSignature("X-Timestamp must be a Unix time in seconds"))
+				return
+			}
+			if age := time.Since(time.Unix(signedAt, 0)); age > maxAge || age < -maxAge {
+				log.Warn().
+					Str("path", request.URL.Path).
+					Str("timestamp", timestamp).
+					Msg("Rejected signed request outside the timestamp window")
+				apierrors.WriteError(responseWriter, request, apierrors.InvalidSignature("X-Timestamp is outside the allowed window"))
+				return
+			}
+
+			var body []byte
+			if request.Body != nil {
+				body, err = io.ReadAll(io.LimitReader(request.Body, maxSignedBodyBytes+1))
+				if err != nil {
+					apierrors.WriteError(responseWriter, request, apierrors.InvalidRequestBody("Failed to read request body"))
+					return
+				}
+				if len(body) > maxSignedBodyBytes {
+					apierrors.WriteError(responseWriter, request, apierrors.RequestBodyTooLarge("Request body is too large to verify its signature"))
+					return
+				}
+			}
+
+			expected := SignRequestBody(secret, timestamp, body)
+			if !hmac.Equal([]byte(signature), []byte(expected)) {
+				log.Warn().Str("path", request.URL.Path).Msg("Rejected request with an invalid signature")
+				apierrors.WriteError(responseWriter, request, apierrors.InvalidSignature("Request signature does not match"))
+				return
+			}
+
+			request.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(responseWriter, request)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+)
+
+var testSigningSecret = []byte("shared-secret")
+
+// newSignedRequest builds a request signed at signedAt with the test secret
+func newSignedRequest(path string, body string, signedAt time.Time) *http.Request {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, SignRequestBody(testSigningSecret, timestamp, []byte(body)))
+	return request
+}
+
+// TestRequestSigningMiddleware tests valid, tampered, stale and unsigned requests
+func TestRequestSigningMiddleware(t *testing.T) {
+	body := `{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`
+
+	tampered := newSignedRequest("/api/v1/summoner", body, time.Now())
+	tampered.Body = io.NopCloser(strings.NewReader(strings.Replace(body, "TestPlayer", "OtherPlayer", 1)))
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", strings.NewReader(body))
+
+	testCases := []struct {
+		name           string
+		request        *http.Request
+		expectedStatus int
+	}{
+		{"valid", newSignedRequest("/api/v1/summoner", body, time.Now()), http.StatusOK},
+		{"tampered body", tampered, http.StatusUnauthorized},
+		{"stale timestamp", newSignedRequest("/api/v1/summoner", body, time.Now().Add(-10*time.Minute)), http.StatusUnauthorized},
+		{"future timestamp", newSignedRequest("/api/v1/summoner", body, time.Now().Add(10*time.Minute)), http.StatusUnauthorized},
+		{"unsigned", unsigned, http.StatusUnauthorized},
+		{"unenforced route", httptest.NewRequest(http.MethodPost, "/health", nil), http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var receivedBody string
+			nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				data, _ := io.ReadAll(request.Body)
+				receivedBody = string(data)
+				writer.WriteHeader(http.StatusOK)
+			})
+			middleware := RequestSigningMiddleware(testSigningSecret, []string{"/api/v1"}, DefaultSignatureMaxAge)(nextHandler)
+
+			responseRecorder := httptest.NewRecorder()
+			middleware.ServeHTTP(responseRecorder, testCase.request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, responseRecorder.Code)
+			}
+			if testCase.expectedStatus == http.StatusUnauthorized {
+				var errorResponse apierrors.ErrorResponse
+				json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+				if errorResponse.Error.Code != apierrors.ErrCodeInvalidSignature {
+					t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeInvalidSignature, errorResponse.Error.Code)
+				}
+			}
+			if testCase.name == "valid" && receivedBody != body {
+				t.Errorf("Expected the verified body to reach the handler, got '%s'", receivedBody)
+			}
+		})
+	}
+}
+
+// TestRequestSigningMiddleware_OversizedBody tests that a body too large to buffer is
+// rejected with 413 REQUEST_BODY_TOO_LARGE before reaching the handler
+func TestRequestSigningMiddleware_OversizedBody(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("Expected the oversized request to be rejected")
+	})
+	middleware := RequestSigningMiddleware(testSigningSecret, []string{"/api/v1"}, DefaultSignatureMaxAge)(nextHandler)
+
+	request := newSignedRequest("/api/v1/summoner", strings.Repeat("a", maxSignedBodyBytes+1), time.Now())
+	responseRecorder := httptest.NewRecorder()
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, responseRecorder.Code)
+	}
+
+	var errorResponse apierrors.ErrorResponse
+	json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != apierrors.ErrCodeBodyTooLarge {
+		t.Errorf("Expected error code '%s', got '%s'", apierrors.ErrCodeBodyTooLarge, errorResponse.Error.Code)
+	}
+}
+
+// TestRequestSigningMiddleware_Disabled tests that no secret leaves every route unaffected
+func TestRequestSigningMiddleware_Disabled(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	middleware := RequestSigningMiddleware(nil, []string{"/api/v1"}, DefaultSignatureMaxAge)(nextHandler)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/summoner", strings.NewReader("{}"))
+	responseRecorder := httptest.NewRecorder()
+	middleware.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+}
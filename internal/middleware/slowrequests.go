@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SlowRequest is a sample of a request that took at least the slow-request threshold
+type SlowRequest struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	DurationMs int64     `json:"durationMs"`
+	RequestID  string    `json:"requestId,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// SlowRequestsResponse is the JSON structure served by SlowRequestRecorder.Handler
+type SlowRequestsResponse struct {
+	ThresholdMs  int64         `json:"thresholdMs"`
+	SlowRequests []SlowRequest `json:"slowRequests"`
+}
+
+// SlowRequestRecorder keeps the most recent requests that exceeded a latency threshold in a
+// fixed-size ring buffer, so the worst recent requests can be inspected without tracing
+type SlowRequestRecorder struct {
+	threshold time.Duration
+
+	mutex   sync.Mutex
+	samples []SlowRequest
+	next    int
+	full    bool
+}
+
+// NewSlowRequestRecorder creates a recorder keeping up to capacity requests that took at
+// least threshold. A capacity below 1 is treated as 1.
+func NewSlowRequestRecorder(threshold time.Duration, capacity int) *SlowRequestRecorder {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SlowRequestRecorder{
+		threshold: threshold,
+		samples:   make([]SlowRequest, capacity),
+	}
+}
+
+// Middleware times each request and records it when it meets the threshold. Only the path
+// is kept, never the query string, so samples cannot leak credentials passed as parameters.
+func (recorder *SlowRequestRecorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		startTime := time.Now()
+		wrappedWriter := newResponseWriter(responseWriter)
+
+		next.ServeHTTP(wrappedWriter, request)
+
+		duration := time.Since(startTime)
+		if duration < recorder.threshold {
+			return
+		}
+		recorder.record(SlowRequest{
+			Method:     request.Method,
+			Path:       request.URL.Path,
+			Status:     wrappedWriter.statusCode,
+			DurationMs: duration.Milliseconds(),
+			RequestID:  RequestIDFromContext(request.Context()),
+			Time:       startTime,
+		})
+	})
+}
+
+// record stores a sample, overwriting the oldest once the buffer is full
+func (recorder *SlowRequestRecorder) record(sample SlowRequest) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.samples[recorder.next] = sample
+	recorder.next = (recorder.next + 1) % len(recorder.samples)
+	if recorder.next == 0 {
+		recorder.full = true
+	}
+}
+
+// Samples returns the buffered slow requests, slowest first
+func (recorder *SlowRequestRecorder) Samples() []SlowRequest {
+	recorder.mutex.Lock()
+	count := recorder.next
+	if recorder.full {
+		count = len(recorder.samples)
+	}
+	samples := append([]SlowRequest(nil), recorder.samples[:count]...)
+	recorder.mutex.Unlock()
+
+	sort.SliceStable(samples, func(left, right int) bool {
+		return samples[left].DurationMs > samples[right].DurationMs
+	})
+	return samples
+}
+
+// Handler serves the buffered slow requests as JSON, slowest first
+func (recorder *SlowRequestRecorder) Handler() http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		responseWriter.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(responseWriter).Encode(SlowRequestsResponse{
+			ThresholdMs:  recorder.threshold.Milliseconds(),
+			SlowRequests: recorder.Samples(),
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestSlowRequestRecorder tests that only requests over the threshold are kept and the buffer is bounded
+func TestSlowRequestRecorder(t *testing.T) {
+	recorder := NewSlowRequestRecorder(20*time.Millisecond, 3)
+
+	delays := map[string]time.Duration{
+		"/fast":   0,
+		"/slow-1": 25 * time.Millisecond,
+		"/slow-2": 60 * time.Millisecond,
+		"/slow-3": 30 * time.Millisecond,
+		"/slow-4": 40 * time.Millisecond,
+	}
+	handler := RequestIDMiddleware(recorder.Middleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(delays[request.URL.Path])
+		writer.WriteHeader(http.StatusTeapot)
+	})))
+
+	for _, path := range []string{"/fast", "/slow-1", "/slow-2", "/fast", "/slow-3", "/slow-4"} {
+		request, _ := http.NewRequest("POST", path+"?apiKey=secret", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	samples := recorder.Samples()
+	if len(samples) != 3 {
+		t.Fatalf("Expected the buffer to hold 3 samples, got %d", len(samples))
+	}
+
+	// The oldest slow request (/slow-1) was overwritten; the rest are ordered slowest first
+	expectedPaths := []string{"/slow-2", "/slow-4", "/slow-3"}
+	for index, sample := range samples {
+		if sample.Path != expectedPaths[index] {
+			t.Errorf("Expected sample %d to be '%s', got '%s'", index, expectedPaths[index], sample.Path)
+		}
+		if sample.Status != http.StatusTeapot || sample.Method != "POST" || sample.RequestID == "" {
+			t.Errorf("Expected method, status and request ID to be recorded, got %+v", sample)
+		}
+	}
+}
+
+// TestSlowRequestRecorder_Handler tests that the samples are served as JSON
+func TestSlowRequestRecorder_Handler(t *testing.T) {
+	recorder := NewSlowRequestRecorder(0, 5)
+	for index := 0; index < 2; index++ {
+		recorder.record(SlowRequest{Method: "POST", Path: fmt.Sprintf("/path-%d", index), DurationMs: int64(index)})
+	}
+
+	request, _ := http.NewRequest("GET", "/debug/slow", nil)
+	responseRecorder := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(responseRecorder, request)
+
+	var response SlowRequestsResponse
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.SlowRequests) != 2 || response.SlowRequests[0].Path != "/path-1" {
+		t.Errorf("Expected 2 samples slowest first, got %+v", response.SlowRequests)
+	}
+}
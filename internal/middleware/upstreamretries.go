@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+const (
+	// upstreamRetriesContextKey stores the per-request retry counter in the request context
+	upstreamRetriesContextKey contextKey = "upstreamRetries"
+
+	// UpstreamRetriesHeader reports how many upstream calls were retried while handling a
+	// request, summed across all upstream calls (e.g. summoner, matches and analysis)
+	UpstreamRetriesHeader = "X-Upstream-Retries"
+)
+
+// upstreamRetriesWriter sets the X-Upstream-Retries header just before the response is written
+type upstreamRetriesWriter struct {
+	http.ResponseWriter
+	retries     *atomic.Int64
+	wroteHeader bool
+}
+
+// WriteHeader adds the retries header and calls the underlying WriteHeader
+func (retriesWriter *upstreamRetriesWriter) WriteHeader(statusCode int) {
+	if !retriesWriter.wroteHeader {
+		retriesWriter.wroteHeader = true
+		if retries := retriesWriter.retries.Load(); retries > 0 {
+			retriesWriter.Header().Set(UpstreamRetriesHeader, strconv.FormatInt(retries, 10))
+		}
+	}
+	retriesWriter.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ensures the retries header is set before the first body bytes
+func (retriesWriter *upstreamRetriesWriter) Write(data []byte) (int, error) {
+	if !retriesWriter.wroteHeader {
+		retriesWriter.WriteHeader(http.StatusOK)
+	}
+	return retriesWriter.ResponseWriter.Write(data)
+}
+
+// UpstreamRetriesMiddleware counts the upstream retries performed while handling a request
+// and returns the total in the X-Upstream-Retries response header. The header is omitted
+// when no call was retried.
+func UpstreamRetriesMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		retries := &atomic.Int64{}
+		ctx := context.WithValue(request.Context(), upstreamRetriesContextKey, retries)
+
+		next.ServeHTTP(&upstreamRetriesWriter{ResponseWriter: responseWriter, retries: retries}, request.WithContext(ctx))
+	})
+}
+
+// RecordUpstreamRetry counts one retried upstream call for the request. It is a no-op when
+// the context carries no retry counter.
+func RecordUpstreamRetry(ctx context.Context) {
+	if retries, ok := ctx.Value(upstreamRetriesContextKey).(*atomic.Int64); ok {
+		retries.Add(1)
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUpstreamRetriesMiddleware_SumsRetries tests that retries from several upstream calls are summed
+func TestUpstreamRetriesMiddleware_SumsRetries(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		RecordUpstreamRetry(request.Context())
+		RecordUpstreamRetry(request.Context())
+		RecordUpstreamRetry(request.Context())
+		writer.Write([]byte("ok"))
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/analyze", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	UpstreamRetriesMiddleware(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if got := responseRecorder.Header().Get(UpstreamRetriesHeader); got != "3" {
+		t.Errorf("Expected %s '3', got '%s'", UpstreamRetriesHeader, got)
+	}
+}
+
+// TestUpstreamRetriesMiddleware_NoRetries tests that the header is omitted when nothing was retried
+func TestUpstreamRetriesMiddleware_NoRetries(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	UpstreamRetriesMiddleware(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if _, exists := responseRecorder.Header()[UpstreamRetriesHeader]; exists {
+		t.Errorf("Expected no %s header", UpstreamRetriesHeader)
+	}
+}
+
+// TestRecordUpstreamRetry_WithoutCounter tests that recording without the middleware is a no-op
+func TestRecordUpstreamRetry_WithoutCounter(t *testing.T) {
+	RecordUpstreamRetry(context.Background())
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// upstreamTraceContextKey stores the per-request upstream trace in the request context
+	upstreamTraceContextKey contextKey = "upstreamTrace"
+
+	// UpstreamTraceHeader lists the upstream request IDs that served a response, in call
+	// order, as "service=id" pairs, e.g. "data=abc, cortex=def"
+	UpstreamTraceHeader = "X-Upstream-Trace"
+
+	// UpstreamRequestIDHeader is an alternative header upstreams may report their request ID in
+	UpstreamRequestIDHeader = "X-Upstream-Request-ID"
+)
+
+// upstreamTrace accumulates upstream request IDs; upstream calls may run concurrently
+type upstreamTrace struct {
+	mutex   sync.Mutex
+	entries []string
+}
+
+// headerValue joins the recorded entries, or returns an empty string when there are none
+func (trace *upstreamTrace) headerValue() string {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	return strings.Join(trace.entries, ", ")
+}
+
+// upstreamTraceWriter sets the X-Upstream-Trace header just before the response is written
+type upstreamTraceWriter struct {
+	http.ResponseWriter
+	trace       *upstreamTrace
+	wroteHeader bool
+}
+
+// WriteHeader adds the trace header and calls the underlying WriteHeader
+func (traceWriter *upstreamTraceWriter) WriteHeader(statusCode int) {
+	if !traceWriter.wroteHeader {
+		traceWriter.wroteHeader = true
+		if value := traceWriter.trace.headerValue(); value != "" {
+			traceWriter.Header().Set(UpstreamTraceHeader, value)
+		}
+	}
+	traceWriter.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ensures the trace header is set before the first body bytes
+func (traceWriter *upstreamTraceWriter) Write(data []byte) (int, error) {
+	if !traceWriter.wroteHeader {
+		traceWriter.WriteHeader(http.StatusOK)
+	}
+	return traceWriter.ResponseWriter.Write(data)
+}
+
+// UpstreamTraceMiddleware collects the request IDs reported by upstream services while
+// handling a request and returns them in the X-Upstream-Trace response header
+func UpstreamTraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		trace := &upstreamTrace{}
+		ctx := context.WithValue(request.Context(), upstreamTraceContextKey, trace)
+
+		next.ServeHTTP(&upstreamTraceWriter{ResponseWriter: responseWriter, trace: trace}, request.WithContext(ctx))
+	})
+}
+
+// RecordUpstreamRequestID adds an upstream request ID to the request's trace. It is a no-op
+// when the ID is empty or the context carries no trace.
+func RecordUpstreamRequestID(ctx context.Context, service string, requestID string) {
+	trace, ok := ctx.Value(upstreamTraceContextKey).(*upstreamTrace)
+	if !ok || requestID == "" {
+		return
+	}
+
+	trace.mutex.Lock()
+	trace.entries = append(trace.entries, service+"="+requestID)
+	trace.mutex.Unlock()
+}
+
+// UpstreamRequestID returns the request ID an upstream reported on its response, from
+// X-Request-ID or, failing that, X-Upstream-Request-ID
+func UpstreamRequestID(response *http.Response) string {
+	if requestID := response.Header.Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+	return response.Header.Get(UpstreamRequestIDHeader)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUpstreamTraceMiddleware_SetsHeader tests that recorded upstream IDs are listed in call order
+func TestUpstreamTraceMiddleware_SetsHeader(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		RecordUpstreamRequestID(request.Context(), "data", "data-1")
+		RecordUpstreamRequestID(request.Context(), "cortex", "cortex-1")
+		writer.Write([]byte("ok"))
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/analyze", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	UpstreamTraceMiddleware(nextHandler).ServeHTTP(responseRecorder, request)
+
+	expected := "data=data-1, cortex=cortex-1"
+	if got := responseRecorder.Header().Get(UpstreamTraceHeader); got != expected {
+		t.Errorf("Expected %s '%s', got '%s'", UpstreamTraceHeader, expected, got)
+	}
+}
+
+// TestUpstreamTraceMiddleware_NoUpstreamCalls tests that the header is omitted when nothing was recorded
+func TestUpstreamTraceMiddleware_NoUpstreamCalls(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		RecordUpstreamRequestID(request.Context(), "data", "")
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	UpstreamTraceMiddleware(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if _, exists := responseRecorder.Header()[UpstreamTraceHeader]; exists {
+		t.Errorf("Expected no %s header", UpstreamTraceHeader)
+	}
+}
+
+// TestRecordUpstreamRequestID_WithoutTrace tests that recording without the middleware is a no-op
+func TestRecordUpstreamRequestID_WithoutTrace(t *testing.T) {
+	RecordUpstreamRequestID(context.Background(), "data", "data-1")
+}
+
+// TestUpstreamRequestID tests reading the upstream ID from either supported header
+func TestUpstreamRequestID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{name: "request id header", headers: map[string]string{RequestIDHeader: "abc"}, expected: "abc"},
+		{name: "upstream request id header", headers: map[string]string{UpstreamRequestIDHeader: "def"}, expected: "def"},
+		{name: "request id preferred", headers: map[string]string{RequestIDHeader: "abc", UpstreamRequestIDHeader: "def"}, expected: "abc"},
+		{name: "none", headers: map[string]string{}, expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			response := &http.Response{Header: http.Header{}}
+			for name, value := range testCase.headers {
+				response.Header.Set(name, value)
+			}
+
+			if got := UpstreamRequestID(response); got != testCase.expected {
+				t.Errorf("Expected '%s', got '%s'", testCase.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	// warningsContextKey stores the per-request warning collector in the request context
+	warningsContextKey contextKey = "warnings"
+
+	// WarningHeader carries non-fatal problems with an otherwise successful response, one
+	// "199 opgl-gateway <quoted message>" value per warning
+	WarningHeader = "Warning"
+)
+
+// warningCollector accumulates warnings; handlers may add them from concurrent goroutines
+type warningCollector struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+// snapshot returns a copy of the collected warnings
+func (collector *warningCollector) snapshot() []string {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	return append([]string(nil), collector.messages...)
+}
+
+// warningsWriter sets the Warning headers just before a successful response is written
+type warningsWriter struct {
+	http.ResponseWriter
+	collector   *warningCollector
+	wroteHeader bool
+}
+
+// WriteHeader adds a Warning header per collected warning on non-error responses and calls
+// the underlying WriteHeader
+func (writer *warningsWriter) WriteHeader(statusCode int) {
+	if !writer.wroteHeader {
+		writer.wroteHeader = true
+		if statusCode < http.StatusBadRequest {
+			for _, message := range writer.collector.snapshot() {
+				writer.Header().Add(WarningHeader, "199 opgl-gateway "+strconv.Quote(message))
+			}
+		}
+	}
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ensures the Warning headers are set before the first body bytes
+func (writer *warningsWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		writer.WriteHeader(http.StatusOK)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+// WarningsMiddleware lets handlers report non-fatal issues with AddWarning. Warnings are
+// returned as Warning headers on successful responses; wrapped envelopes also list them in
+// meta.warnings (see WarningsFromContext).
+func WarningsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		collector := &warningCollector{}
+		ctx := context.WithValue(request.Context(), warningsContextKey, collector)
+
+		next.ServeHTTP(&warningsWriter{ResponseWriter: responseWriter, collector: collector}, request.WithContext(ctx))
+	})
+}
+
+// AddWarning records a non-fatal issue to report with the response. It is a no-op when the
+// context carries no warning collector.
+func AddWarning(ctx context.Context, message string) {
+	collector, ok := ctx.Value(warningsContextKey).(*warningCollector)
+	if !ok || message == "" {
+		return
+	}
+
+	collector.mutex.Lock()
+	collector.messages = append(collector.messages, message)
+	collector.mutex.Unlock()
+}
+
+// WarningsFromContext returns the warnings added so far, in order, or nil when there are none
+func WarningsFromContext(ctx context.Context) []string {
+	collector, ok := ctx.Value(warningsContextKey).(*warningCollector)
+	if !ok {
+		return nil
+	}
+	return collector.snapshot()
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWarningsMiddleware tests that added warnings become Warning headers on successful responses only
+func TestWarningsMiddleware(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		expectedHeaders int
+	}{
+		{"success", http.StatusOK, 2},
+		{"error", http.StatusBadGateway, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var contextWarnings []string
+			handler := WarningsMiddleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				AddWarning(request.Context(), "first")
+				AddWarning(request.Context(), "")
+				AddWarning(request.Context(), "second")
+				contextWarnings = WarningsFromContext(request.Context())
+				writer.WriteHeader(testCase.statusCode)
+			}))
+
+			responseRecorder := httptest.NewRecorder()
+			handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil))
+
+			if len(contextWarnings) != 2 || contextWarnings[0] != "first" || contextWarnings[1] != "second" {
+				t.Errorf("Expected warnings [first second], got %v", contextWarnings)
+			}
+			if headers := responseRecorder.Header().Values(WarningHeader); len(headers) != testCase.expectedHeaders {
+				t.Errorf("Expected %d Warning headers, got %q", testCase.expectedHeaders, headers)
+			}
+		})
+	}
+}
+
+// TestAddWarning_NoCollector tests that AddWarning is a no-op outside WarningsMiddleware
+func TestAddWarning_NoCollector(t *testing.T) {
+	ctx := context.Background()
+	AddWarning(ctx, "ignored")
+
+	if warnings := WarningsFromContext(ctx); warnings != nil {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultResponseWriteTimeout is how long writing a response may take once it has started
+const DefaultResponseWriteTimeout = 30 * time.Second
+
+// writeDeadlineWriter sets the connection write deadline when the response starts being written
+type writeDeadlineWriter struct {
+	http.ResponseWriter
+	controller  *http.ResponseController
+	timeout     time.Duration
+	wroteHeader bool
+}
+
+// WriteHeader starts the write deadline and calls the underlying WriteHeader
+func (deadlineWriter *writeDeadlineWriter) WriteHeader(statusCode int) {
+	if !deadlineWriter.wroteHeader {
+		deadlineWriter.wroteHeader = true
+		if err := deadlineWriter.controller.SetWriteDeadline(time.Now().Add(deadlineWriter.timeout)); err != nil {
+			log.Debug().Err(err).Msg("Response write deadline not supported by the connection")
+		}
+	}
+	deadlineWriter.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ensures the write deadline is set before the first body bytes
+func (deadlineWriter *writeDeadlineWriter) Write(data []byte) (int, error) {
+	if !deadlineWriter.wroteHeader {
+		deadlineWriter.WriteHeader(http.StatusOK)
+	}
+	return deadlineWriter.ResponseWriter.Write(data)
+}
+
+// WriteDeadlineMiddleware bounds how long writing each response may take, so a client that
+// stops reading cannot pin the handler goroutine on a blocked socket. The deadline starts
+// when the response is first written, so time spent waiting on upstreams (bounded by their
+// own timeouts) does not count against it; once it passes, writes fail and the connection
+// is closed. It must wrap the server's own ResponseWriter, i.e. be the outermost
+// middleware. A timeout of 0 or less disables the deadline.
+func WriteDeadlineMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			next.ServeHTTP(&writeDeadlineWriter{
+				ResponseWriter: responseWriter,
+				controller:     http.NewResponseController(responseWriter),
+				timeout:        timeout,
+			}, request)
+		})
+	}
+}
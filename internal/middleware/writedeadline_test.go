@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestWriteDeadlineMiddleware_SlowClient tests that writes to a client that stops reading fail once the deadline passes
+func TestWriteDeadlineMiddleware_SlowClient(t *testing.T) {
+	writeResult := make(chan error, 1)
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// Write far more than the socket buffers can hold, so writes block on the stalled client
+		chunk := bytes.Repeat([]byte("x"), 64*1024)
+		for written := 0; written < 256*1024*1024; written += len(chunk) {
+			if _, err := writer.Write(chunk); err != nil {
+				writeResult <- err
+				return
+			}
+		}
+		writeResult <- nil
+	})
+	server := httptest.NewServer(WriteDeadlineMiddleware(100 * time.Millisecond)(nextHandler))
+	defer server.Close()
+
+	// A raw connection that sends a request and never reads the response
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET /api/v1/summoner HTTP/1.1\r\nHost: %s\r\n\r\n", server.Listener.Addr())
+
+	select {
+	case err := <-writeResult:
+		if err == nil {
+			t.Fatal("Expected the write to a stalled client to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the write deadline to unblock the handler")
+	}
+}
+
+// TestWriteDeadlineMiddleware_Disabled tests that a zero timeout leaves responses untouched
+func TestWriteDeadlineMiddleware_Disabled(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if _, wrapped := writer.(*writeDeadlineWriter); wrapped {
+			t.Error("Expected the writer not to be wrapped when the deadline is disabled")
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	responseRecorder := httptest.NewRecorder()
+	WriteDeadlineMiddleware(0)(nextHandler).ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d, got %d", http.StatusNoContent, responseRecorder.Code)
+	}
+}
+
+// TestWriteDeadlineMiddleware_FastClient tests that responses written within the deadline are delivered
+func TestWriteDeadlineMiddleware_FastClient(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(`{"status":"ok"}`))
+	})
+	server := httptest.NewServer(WriteDeadlineMiddleware(time.Second)(nextHandler))
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, response.StatusCode)
+	}
+}
@@ -10,6 +10,8 @@ type Summoner struct {
 	Name          string `json:"name"`
 	ProfileIconID int    `json:"profileIconId"`
 	SummonerLevel int64  `json:"summonerLevel"`
+	// RevisionDate is when the summoner last changed, in Unix milliseconds, when opgl-data reports it
+	RevisionDate int64 `json:"revisionDate,omitempty"`
 }
 
 // SummonerResponse represents summoner data returned to external clients
@@ -32,6 +34,60 @@ type Match struct {
 	Participants []Participant `json:"participants"`
 }
 
+// MatchTimeline is the minute-by-minute progression of a match, returned by opgl-data
+// separately from the match summary
+type MatchTimeline struct {
+	MatchID string `json:"matchId"`
+	// FrameInterval is the time between frames, in milliseconds (60000 for Riot timelines)
+	FrameInterval int64           `json:"frameInterval"`
+	Frames        []TimelineFrame `json:"frames"`
+}
+
+// TimelineFrame is the state of every participant at one point in a match, plus the events
+// since the previous frame
+type TimelineFrame struct {
+	// Timestamp is the time since the match started, in milliseconds
+	Timestamp int64 `json:"timestamp"`
+	// ParticipantFrames is keyed by participant ID ("1" to "10")
+	ParticipantFrames map[string]ParticipantFrame `json:"participantFrames"`
+	// Events are passed through as-is; their fields depend on the event type
+	Events []map[string]interface{} `json:"events"`
+}
+
+// ParticipantFrame is one participant's gold, experience and farm at a timeline frame
+type ParticipantFrame struct {
+	ParticipantID       int `json:"participantId"`
+	Level               int `json:"level"`
+	CurrentGold         int `json:"currentGold"`
+	TotalGold           int `json:"totalGold"`
+	XP                  int `json:"xp"`
+	MinionsKilled       int `json:"minionsKilled"`
+	JungleMinionsKilled int `json:"jungleMinionsKilled"`
+}
+
+// MatchCursor identifies the last match of a page for cursor-based pagination
+type MatchCursor struct {
+	// Timestamp is the game creation time of the last match, in Unix milliseconds
+	Timestamp int64 `json:"timestamp"`
+	// MatchID is the ID of the last match, used to break timestamp ties
+	MatchID string `json:"matchId"`
+}
+
+// MatchFilter holds optional constraints forwarded to opgl-data with match history requests
+type MatchFilter struct {
+	// Cursor resumes pagination after the identified match
+	Cursor *MatchCursor `json:"cursor,omitempty"`
+	// StartTime and EndTime bound match creation time, in Unix seconds; nil means unbounded
+	StartTime *int64 `json:"startTime,omitempty"`
+	EndTime   *int64 `json:"endTime,omitempty"`
+}
+
+// MatchPage wraps a page of matches with the cursor for the following page
+type MatchPage struct {
+	Matches    []Match `json:"matches"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
 // Participant represents a player's performance in a specific match
 type Participant struct {
 	PUUID                       string `json:"puuid"`
@@ -50,11 +106,136 @@ type Participant struct {
 	TeamPosition                string `json:"teamPosition"`
 }
 
+// BatchError describes why a single entry in a batch request failed
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BatchMatchResult holds the matches, or the error, for a single player in a batch request
+type BatchMatchResult struct {
+	Region  string      `json:"region"`
+	PUUID   string      `json:"puuid"`
+	Matches []Match     `json:"matches,omitempty"`
+	Error   *BatchError `json:"error,omitempty"`
+}
+
+// BatchMatchResponse contains per-player results in the same order as the request
+type BatchMatchResponse struct {
+	Results []BatchMatchResult `json:"results"`
+}
+
 // AnalysisResult contains the complete analysis for a player
 type AnalysisResult struct {
 	PlayerStats      interface{} `json:"playerStats"`
 	ImprovementAreas interface{} `json:"improvementAreas"`
 	AnalyzedAt       time.Time   `json:"analyzedAt"`
+	// Degraded is set when the analysis ran on incomplete inputs; DegradedReasons says why
+	Degraded        bool     `json:"degraded,omitempty"`
+	DegradedReasons []string `json:"degradedReasons,omitempty"`
+}
+
+// AnalyzeDryRunResponse is returned by analyze with dryRun=true: the inputs resolved and
+// opgl-cortex was reachable, but no analysis was run
+type AnalyzeDryRunResponse struct {
+	WouldAnalyze bool      `json:"wouldAnalyze"`
+	Summoner     *Summoner `json:"summoner"`
+}
+
+// Comparison places two players' recent performance side by side, in request order
+type Comparison struct {
+	Players []ComparedPlayer `json:"players"`
+	// SharedMatches lists matches both players appeared in (only when both were found)
+	SharedMatches []SharedMatch `json:"sharedMatches"`
+}
+
+// ComparedPlayer holds one side of a comparison. Summoner and Stats are null when the player
+// could not be loaded; Error then says why.
+type ComparedPlayer struct {
+	Region   string       `json:"region"`
+	GameName string       `json:"gameName"`
+	TagLine  string       `json:"tagLine"`
+	Summoner *Summoner    `json:"summoner"`
+	Stats    *PlayerStats `json:"stats"`
+	Error    *BatchError  `json:"error,omitempty"`
+}
+
+// PlayerStats aggregates a player's performance over a set of matches
+type PlayerStats struct {
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	WinRate    float64 `json:"winRate"`
+	AvgKills   float64 `json:"avgKills"`
+	AvgDeaths  float64 `json:"avgDeaths"`
+	AvgAssists float64 `json:"avgAssists"`
+	// KDA is (kills + assists) / deaths, with deaths counted as at least 1
+	KDA float64 `json:"kda"`
+	// Champions is ordered by games played, most played first
+	Champions []ChampionStats `json:"champions"`
+}
+
+// ChampionStats aggregates a player's games on one champion
+type ChampionStats struct {
+	ChampionID   int     `json:"championId"`
+	ChampionName string  `json:"championName"`
+	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	WinRate      float64 `json:"winRate"`
+}
+
+// SharedMatch is a match both compared players appeared in
+type SharedMatch struct {
+	MatchID string `json:"matchId"`
+	// SameTeam is true when the players were teammates and false when they were opponents
+	SameTeam bool `json:"sameTeam"`
+}
+
+// PlayerExistsResponse reports whether a Riot ID resolves to a player
+type PlayerExistsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// FieldValidity is the validation outcome of a single request field. Checked is false when
+// a partial validation skipped the field because it was empty.
+type FieldValidity struct {
+	Checked bool   `json:"checked"`
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+}
+
+// SummonerValidationResponse reports whether a summoner request is valid overall and per field
+type SummonerValidationResponse struct {
+	Valid   bool                     `json:"valid"`
+	Partial bool                     `json:"partial"`
+	Fields  map[string]FieldValidity `json:"fields"`
+}
+
+// PlayerProfile combines a player's summoner, recent matches, and ranked stats. Matches and
+// RankedStats are null when their lookup failed; Warnings then explains what is missing.
+type PlayerProfile struct {
+	Summoner    *Summoner     `json:"summoner"`
+	Matches     []Match       `json:"matches"`
+	RankedStats []RankedStats `json:"rankedStats"`
+	Warnings    []string      `json:"warnings,omitempty"`
+}
+
+// LiveGame is the game a player is currently in, as reported by opgl-data
+type LiveGame struct {
+	GameID   int64  `json:"gameId"`
+	GameMode string `json:"gameMode"`
+	QueueID  int    `json:"queueId"`
+	// GameStartTime is when the game started, in Unix milliseconds (0 while in champion select)
+	GameStartTime int64 `json:"gameStartTime"`
+	// ChampionID is the champion the player is playing
+	ChampionID int `json:"championId"`
+}
+
+// SummonerWithLiveGame is a summoner enriched with its live-game status; LiveGame is null
+// when the player is not in a game
+type SummonerWithLiveGame struct {
+	Summoner
+	LiveGame *LiveGame `json:"liveGame"`
 }
 
 // RankedStats represents a player's ranked statistics for a specific queue
@@ -77,3 +258,18 @@ type RankedStats struct {
 type RankedStatsResponse struct {
 	RankedStats []RankedStats `json:"rankedStats"`
 }
+
+// CacheWarmFailure describes a player that could not be cached during warming
+type CacheWarmFailure struct {
+	Region   string      `json:"region"`
+	GameName string      `json:"gameName"`
+	TagLine  string      `json:"tagLine"`
+	Error    *BatchError `json:"error"`
+}
+
+// CacheWarmResponse summarizes a cache warming run
+type CacheWarmResponse struct {
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Failures  []CacheWarmFailure `json:"failures,omitempty"`
+}
@@ -0,0 +1,63 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// ErrMalformedCursor is returned when a cursor token cannot be decoded
+var ErrMalformedCursor = errors.New("malformed cursor")
+
+// cursorPayload is the JSON structure encoded inside an opaque cursor token
+type cursorPayload struct {
+	Timestamp int64  `json:"t"`
+	MatchID   string `json:"id"`
+}
+
+// EncodeMatchCursor encodes the position of the last match on a page as an opaque token
+func EncodeMatchCursor(cursor models.MatchCursor) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Timestamp: cursor.Timestamp,
+		MatchID:   cursor.MatchID,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeMatchCursor decodes an opaque cursor token produced by EncodeMatchCursor
+func DecodeMatchCursor(token string) (*models.MatchCursor, error) {
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrMalformedCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return nil, ErrMalformedCursor
+	}
+
+	if payload.Timestamp <= 0 || payload.MatchID == "" {
+		return nil, ErrMalformedCursor
+	}
+
+	return &models.MatchCursor{
+		Timestamp: payload.Timestamp,
+		MatchID:   payload.MatchID,
+	}, nil
+}
+
+// NextMatchCursor returns the cursor for the page following the given matches, or an
+// empty string when the page was not full and there are no further matches
+func NextMatchCursor(matches []models.Match, pageSize int) string {
+	if len(matches) == 0 || len(matches) < pageSize {
+		return ""
+	}
+
+	lastMatch := matches[len(matches)-1]
+	return EncodeMatchCursor(models.MatchCursor{
+		Timestamp: lastMatch.GameCreation.UnixMilli(),
+		MatchID:   lastMatch.MatchID,
+	})
+}
@@ -0,0 +1,77 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestEncodeDecodeMatchCursor tests that a cursor survives an encode/decode round trip
+func TestEncodeDecodeMatchCursor(t *testing.T) {
+	original := models.MatchCursor{Timestamp: 1700000000000, MatchID: "NA1_4567"}
+
+	token := EncodeMatchCursor(original)
+	if token == "" {
+		t.Fatal("Expected non-empty cursor token")
+	}
+
+	decoded, err := DecodeMatchCursor(token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if *decoded != original {
+		t.Errorf("Expected %+v, got %+v", original, *decoded)
+	}
+}
+
+// TestDecodeMatchCursor_Malformed tests that malformed tokens are rejected
+func TestDecodeMatchCursor_Malformed(t *testing.T) {
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!not-base64!!!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("plain text"))},
+		{"missing match id", base64.RawURLEncoding.EncodeToString([]byte(`{"t":1700000000000}`))},
+		{"missing timestamp", base64.RawURLEncoding.EncodeToString([]byte(`{"id":"NA1_1"}`))},
+		{"negative timestamp", base64.RawURLEncoding.EncodeToString([]byte(`{"t":-5,"id":"NA1_1"}`))},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := DecodeMatchCursor(testCase.token); err != ErrMalformedCursor {
+				t.Errorf("Expected ErrMalformedCursor, got %v", err)
+			}
+		})
+	}
+}
+
+// TestNextMatchCursor tests next cursor generation for full and partial pages
+func TestNextMatchCursor(t *testing.T) {
+	gameCreation := time.UnixMilli(1700000000000)
+	matches := []models.Match{
+		{MatchID: "NA1_2", GameCreation: gameCreation.Add(time.Hour)},
+		{MatchID: "NA1_1", GameCreation: gameCreation},
+	}
+
+	token := NextMatchCursor(matches, 2)
+	decoded, err := DecodeMatchCursor(token)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding next cursor: %v", err)
+	}
+
+	if decoded.MatchID != "NA1_1" || decoded.Timestamp != gameCreation.UnixMilli() {
+		t.Errorf("Expected cursor for last match, got %+v", decoded)
+	}
+
+	if NextMatchCursor(matches, 5) != "" {
+		t.Error("Expected no next cursor for a partial page")
+	}
+
+	if NextMatchCursor(nil, 5) != "" {
+		t.Error("Expected no next cursor for an empty page")
+	}
+}
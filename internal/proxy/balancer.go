@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// endpointHealthAlpha weights the latest call in each endpoint's error rate and latency
+	// averages (EWMA); higher values react faster to changes
+	endpointHealthAlpha = 0.2
+	// endpointLatencyReference is the latency that halves an endpoint's score
+	endpointLatencyReference = 100 * time.Millisecond
+	// minEndpointScore keeps a failing endpoint receiving a trickle of traffic, so it is
+	// noticed when it recovers
+	minEndpointScore = 0.05
+)
+
+// WithDataReplicas adds opgl-data replicas alongside the primary data service URL. Each call
+// is sent to one endpoint chosen at random, weighted by its health score, so replicas that
+// recently failed or slowed down receive less traffic. Empty URLs are ignored.
+func WithDataReplicas(replicaURLs []string) Option {
+	return func(proxy *ServiceProxy) {
+		endpointURLs := []string{proxy.dataServiceURL}
+		for _, replicaURL := range replicaURLs {
+			if replicaURL = strings.TrimSuffix(strings.TrimSpace(replicaURL), "/"); replicaURL != "" {
+				endpointURLs = append(endpointURLs, replicaURL)
+			}
+		}
+		if len(endpointURLs) > 1 {
+			proxy.dataBalancer = newEndpointBalancer(endpointURLs)
+		}
+	}
+}
+
+// endpointHealth is the recent error rate and latency of one upstream endpoint
+type endpointHealth struct {
+	baseURL string
+	// errorRate is the EWMA of failed calls (connection errors and 5xx), from 0 to 1
+	errorRate float64
+	// latency is the EWMA of response times; zero until a response is observed
+	latency time.Duration
+}
+
+// score rates the endpoint from minEndpointScore to 1: lower error rates and latencies score higher
+func (health *endpointHealth) score() float64 {
+	latencyFactor := 1 + float64(health.latency)/float64(endpointLatencyReference)
+	return max((1-health.errorRate)/latencyFactor, minEndpointScore)
+}
+
+// endpointBalancer picks among equivalent upstream endpoints at random, weighted by health score
+type endpointBalancer struct {
+	mutex     sync.Mutex
+	endpoints []*endpointHealth
+	// randomFloat returns a value in [0, 1); replaced in tests for determinism
+	randomFloat func() float64
+}
+
+// newEndpointBalancer creates a balancer over baseURLs, all starting equally healthy
+func newEndpointBalancer(baseURLs []string) *endpointBalancer {
+	endpoints := make([]*endpointHealth, len(baseURLs))
+	for index, baseURL := range baseURLs {
+		endpoints[index] = &endpointHealth{baseURL: baseURL}
+	}
+	return &endpointBalancer{endpoints: endpoints, randomFloat: rand.Float64}
+}
+
+// pick returns the base URL of an endpoint chosen with probability proportional to its score
+func (balancer *endpointBalancer) pick() string {
+	balancer.mutex.Lock()
+	defer balancer.mutex.Unlock()
+
+	scores := make([]float64, len(balancer.endpoints))
+	total := 0.0
+	for index, endpoint := range balancer.endpoints {
+		scores[index] = endpoint.score()
+		total += scores[index]
+	}
+
+	target := balancer.randomFloat() * total
+	for index, score := range scores {
+		if target < score {
+			return balancer.endpoints[index].baseURL
+		}
+		target -= score
+	}
+	return balancer.endpoints[len(balancer.endpoints)-1].baseURL
+}
+
+// record folds a call's outcome into the health of the endpoint url belongs to and returns
+// that endpoint's base URL and new score; found is false for URLs of no endpoint. A zero
+// statusCode means no response was received, which counts as a failure without a latency.
+func (balancer *endpointBalancer) record(url string, statusCode int, duration time.Duration) (baseURL string, score float64, found bool) {
+	balancer.mutex.Lock()
+	defer balancer.mutex.Unlock()
+
+	for _, endpoint := range balancer.endpoints {
+		if !strings.HasPrefix(url, endpoint.baseURL+"/") {
+			continue
+		}
+
+		failed := 0.0
+		if statusCode == 0 || statusCode >= 500 {
+			failed = 1
+		}
+		endpoint.errorRate += endpointHealthAlpha * (failed - endpoint.errorRate)
+
+		if statusCode != 0 {
+			if endpoint.latency == 0 {
+				endpoint.latency = duration
+			} else {
+				endpoint.latency += time.Duration(endpointHealthAlpha * float64(duration-endpoint.latency))
+			}
+		}
+
+		return endpoint.baseURL, endpoint.score(), true
+	}
+	return "", 0, false
+}
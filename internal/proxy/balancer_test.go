@@ -0,0 +1,211 @@
+package proxy
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// TestEndpointBalancer_Score tests that failures and latency lower an endpoint's score, down to the floor
+func TestEndpointBalancer_Score(t *testing.T) {
+	balancer := newEndpointBalancer([]string{"http://data-a", "http://data-b"})
+
+	_, fastScore, _ := balancer.record("http://data-a/api/v1/summoner", http.StatusOK, 10*time.Millisecond)
+	_, slowScore, _ := balancer.record("http://data-b/api/v1/summoner", http.StatusOK, 300*time.Millisecond)
+	if fastScore <= slowScore {
+		t.Errorf("Expected the faster endpoint to score higher, got fast=%g slow=%g", fastScore, slowScore)
+	}
+
+	previousScore := fastScore
+	for attempt := 0; attempt < 30; attempt++ {
+		_, score, _ := balancer.record("http://data-a/api/v1/summoner", 0, 0)
+		if score > previousScore {
+			t.Fatalf("Expected the score to keep falling on failures, went from %g to %g", previousScore, score)
+		}
+		previousScore = score
+	}
+	if previousScore != minEndpointScore {
+		t.Errorf("Expected a repeatedly failing endpoint to reach the floor %g, got %g", minEndpointScore, previousScore)
+	}
+
+	if _, _, found := balancer.record("http://data-c/api/v1/summoner", http.StatusOK, 0); found {
+		t.Error("Expected no endpoint for an unknown URL")
+	}
+}
+
+// TestWithDataReplicas_ShiftsTrafficFromFailingEndpoint tests that an endpoint that keeps
+// erroring receives progressively less traffic, and that scores are exported as metrics
+func TestWithDataReplicas_ShiftsTrafficFromFailingEndpoint(t *testing.T) {
+	var failingCalls, healthyCalls atomic.Int64
+	failingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		failingCalls.Add(1)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		healthyCalls.Add(1)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer healthyServer.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(failingServer.URL, "http://localhost:99999",
+		WithDataReplicas([]string{healthyServer.URL}), WithUpstreamMetrics(upstreamMetrics))
+	proxy.dataBalancer.randomFloat = rand.New(rand.NewSource(1)).Float64
+
+	// Send three rounds of calls and count how many reached the failing endpoint in each
+	const callsPerRound = 50
+	failingShare := make([]int64, 3)
+	for round := range failingShare {
+		before := failingCalls.Load()
+		for call := 0; call < callsPerRound; call++ {
+			if response, err := proxy.ForwardToDataService(context.Background(), "/api/v1/ranked", []byte("{}")); err == nil {
+				response.Body.Close()
+			}
+		}
+		failingShare[round] = failingCalls.Load() - before
+	}
+
+	if failingShare[0] <= failingShare[2] {
+		t.Errorf("Expected the failing endpoint to get less traffic over time, got %v per round", failingShare)
+	}
+	if failingShare[2] > callsPerRound/5 {
+		t.Errorf("Expected the failing endpoint to get under 20%% of the last round, got %d of %d", failingShare[2], callsPerRound)
+	}
+	if failingShare[2] == 0 {
+		t.Error("Expected the failing endpoint to still be probed")
+	}
+
+	failingScore := upstreamMetrics.EndpointScore(metrics.UpstreamData, failingServer.URL)
+	healthyScore := upstreamMetrics.EndpointScore(metrics.UpstreamData, healthyServer.URL)
+	if failingScore >= healthyScore || healthyScore == 0 {
+		t.Errorf("Expected the failing endpoint to score lower, got failing=%g healthy=%g", failingScore, healthyScore)
+	}
+}
+
+// TestCheckDataHealth_Replicas tests that the health check probes every data endpoint, is
+// healthy while any endpoint is, and leaves the balancer's health scores untouched
+func TestCheckDataHealth_Replicas(t *testing.T) {
+	var failingPings, healthyPings atomic.Int64
+	failingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		failingPings.Add(1)
+		http.Error(writer, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		healthyPings.Add(1)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer healthyServer.Close()
+
+	proxy := NewServiceProxy(failingServer.URL, "http://localhost:99999", WithDataReplicas([]string{healthyServer.URL}))
+
+	if err := proxy.CheckDataHealth(context.Background()); err != nil {
+		t.Errorf("Expected healthy while a replica is healthy, got %v", err)
+	}
+	if failingPings.Load() != 1 || healthyPings.Load() != 1 {
+		t.Errorf("Expected each endpoint to be probed once, got failing=%d healthy=%d", failingPings.Load(), healthyPings.Load())
+	}
+	for _, endpoint := range proxy.dataBalancer.endpoints {
+		if endpoint.errorRate != 0 || endpoint.latency != 0 {
+			t.Errorf("Expected probes to leave %s's health untouched, got %+v", endpoint.baseURL, endpoint)
+		}
+	}
+
+	allFailing := NewServiceProxy(failingServer.URL, "http://localhost:99999", WithDataReplicas([]string{failingServer.URL + "/"}))
+	if err := allFailing.CheckDataHealth(context.Background()); err == nil {
+		t.Error("Expected an error when no data endpoint is healthy")
+	}
+}
+
+// TestWithDataReplicas_None tests that without replicas all data calls go to the primary
+func TestWithDataReplicas_None(t *testing.T) {
+	proxy := NewServiceProxy("http://data-primary", "http://cortex", WithDataReplicas([]string{" ", ""}))
+
+	if proxy.dataBalancer != nil {
+		t.Fatal("Expected no balancer without replicas")
+	}
+	if url := proxy.dataURL("/api/v1/summoner"); url != "http://data-primary/api/v1/summoner" {
+		t.Errorf("Expected the primary URL, got '%s'", url)
+	}
+}
+
+// TestWithDataReplicas_CanceledCallKeepsScore tests that a call cut short by the caller's
+// context is not held against the endpoint it was sent to
+func TestWithDataReplicas_CanceledCallKeepsScore(t *testing.T) {
+	release := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-request.Context().Done():
+		case <-release:
+		}
+	}))
+	defer slowServer.Close()
+	defer close(release)
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(slowServer.URL, "http://localhost:99999",
+		WithDataReplicas([]string{slowServer.URL + "/replica"}), WithUpstreamMetrics(upstreamMetrics))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := proxy.ForwardToDataService(ctx, "/api/v1/ranked", []byte("{}")); err == nil {
+		t.Fatal("Expected an error for a canceled call")
+	}
+
+	for _, endpoint := range proxy.dataBalancer.endpoints {
+		if endpoint.errorRate != 0 || endpoint.score() != 1 {
+			t.Errorf("Expected %s to keep a full score, got error rate %g and score %g", endpoint.baseURL, endpoint.errorRate, endpoint.score())
+		}
+	}
+	if errorCount := upstreamMetrics.Errors(metrics.UpstreamData); errorCount != 0 {
+		t.Errorf("Expected no upstream errors counted, got %g", errorCount)
+	}
+}
+
+// TestWithDataReplicas_RetryPicksEndpointAgain tests that each retry attempt is balanced
+// independently instead of going back to the endpoint that just failed
+func TestWithDataReplicas_RetryPicksEndpointAgain(t *testing.T) {
+	var failingCalls, healthyCalls atomic.Int64
+	failingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		failingCalls.Add(1)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		healthyCalls.Add(1)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer healthyServer.Close()
+
+	proxy := NewServiceProxy(failingServer.URL, "http://localhost:99999",
+		WithDataReplicas([]string{healthyServer.URL}), WithRetries(3, time.Millisecond, time.Second))
+	proxy.wait = func(ctx context.Context, delay time.Duration) error { return nil }
+	// The first pick lands on the failing primary; the retry draws from the top of the range,
+	// which falls on the healthy replica once the primary's score has dropped
+	picks := []float64{0, 0.9}
+	proxy.dataBalancer.randomFloat = func() float64 {
+		next := picks[0]
+		picks = picks[1:]
+		return next
+	}
+
+	response, err := proxy.ForwardToDataService(context.Background(), "/api/v1/ranked", []byte("{}"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected the retry to reach the healthy replica, got status %d", response.StatusCode)
+	}
+	if failingCalls.Load() != 1 || healthyCalls.Load() != 1 {
+		t.Errorf("Expected one call per endpoint, got failing=%d healthy=%d", failingCalls.Load(), healthyCalls.Load())
+	}
+}
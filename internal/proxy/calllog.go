@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// Outcomes reported in the "outcome" field of upstream call log lines
+const (
+	outcomeSuccess     = "success"
+	outcomeClientError = "client_error"
+	outcomeServerError = "server_error"
+	outcomeUnreachable = "unreachable"
+)
+
+// logUpstreamCall logs one upstream attempt on the request's context logger, so a failed
+// lookup can be traced to the upstream status behind it. Successes are logged at debug,
+// connection errors and 4xx responses at warn, and 5xx responses at error.
+func logUpstreamCall(ctx context.Context, service string, call UpstreamCall, url string, statusCode int, duration time.Duration, err error) {
+	logger := middleware.LoggerFromContext(ctx)
+
+	var event *zerolog.Event
+	outcome := outcomeSuccess
+	switch {
+	case err != nil:
+		event = logger.Warn().Err(err)
+		outcome = outcomeUnreachable
+	case statusCode >= http.StatusInternalServerError:
+		event = logger.Error()
+		outcome = outcomeServerError
+	case statusCode >= http.StatusBadRequest:
+		event = logger.Warn()
+		outcome = outcomeClientError
+	default:
+		event = logger.Debug()
+	}
+
+	event = event.
+		Str("upstream_service", service).
+		Str("upstream_operation", call.operation()).
+		Str("upstream_url", url).
+		Dur("duration", duration).
+		Str("outcome", outcome)
+	if err != nil {
+		event.Msg("Upstream request failed")
+		return
+	}
+	event.Int("upstream_status", statusCode).Msg("Upstream request completed")
+}
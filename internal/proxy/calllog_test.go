@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// captureLogs redirects the global logger, used when a context carries no child logger,
+// into a buffer for the duration of a test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var logBuffer bytes.Buffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logBuffer)
+	t.Cleanup(func() { log.Logger = originalLogger })
+	return &logBuffer
+}
+
+// findLogLine returns the first JSON log line with the given message
+func findLogLine(t *testing.T, logBuffer *bytes.Buffer, message string) map[string]interface{} {
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log line: %v", err)
+		}
+		if entry["message"] == message {
+			return entry
+		}
+	}
+	return nil
+}
+
+// TestLogUpstreamCall_FailedCall tests that a failed summoner lookup logs a warning with the upstream status
+func TestLogUpstreamCall_FailedCall(t *testing.T) {
+	logBuffer := captureLogs(t)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+	if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "Unknown", "NA1"); err == nil {
+		t.Fatal("Expected an error for a 404 upstream response")
+	}
+
+	entry := findLogLine(t, logBuffer, "Upstream request completed")
+	if entry == nil {
+		t.Fatalf("Expected an upstream call log line, got %s", logBuffer.String())
+	}
+	if entry["level"] != "warn" {
+		t.Errorf("Expected level 'warn', got '%v'", entry["level"])
+	}
+	if entry["upstream_status"] != float64(http.StatusNotFound) {
+		t.Errorf("Expected upstream_status 404, got '%v'", entry["upstream_status"])
+	}
+	if entry["outcome"] != outcomeClientError || entry["upstream_operation"] != "summoner" {
+		t.Errorf("Expected outcome client_error for summoner, got %v", entry)
+	}
+	if entry["upstream_url"] != mockServer.URL+"/api/v1/summoner" {
+		t.Errorf("Expected upstream_url of the summoner endpoint, got '%v'", entry["upstream_url"])
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("Expected a duration field")
+	}
+}
+
+// TestLogUpstreamCall_Levels tests the log level chosen for each outcome
+func TestLogUpstreamCall_Levels(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		expectedLevel   string
+		expectedOutcome string
+	}{
+		{"success", http.StatusOK, "debug", outcomeSuccess},
+		{"client error", http.StatusBadRequest, "warn", outcomeClientError},
+		{"server error", http.StatusBadGateway, "error", outcomeServerError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logBuffer := captureLogs(t)
+			logUpstreamCall(context.Background(), "data", CallGetRankedStats, "http://data/api/v1/ranked", testCase.statusCode, 0, nil)
+
+			entry := findLogLine(t, logBuffer, "Upstream request completed")
+			if entry == nil {
+				t.Fatal("Expected an upstream call log line")
+			}
+			if entry["level"] != testCase.expectedLevel || entry["outcome"] != testCase.expectedOutcome {
+				t.Errorf("Expected %s/%s, got %v/%v", testCase.expectedLevel, testCase.expectedOutcome, entry["level"], entry["outcome"])
+			}
+		})
+	}
+}
+
+// TestLogUpstreamCall_Unreachable tests that connection errors are logged as warnings without a status
+func TestLogUpstreamCall_Unreachable(t *testing.T) {
+	logBuffer := captureLogs(t)
+
+	proxy := NewServiceProxy("http://127.0.0.1:1", "http://localhost:8082")
+	proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+
+	entry := findLogLine(t, logBuffer, "Upstream request failed")
+	if entry == nil {
+		t.Fatalf("Expected a failed upstream call log line, got %s", logBuffer.String())
+	}
+	if entry["level"] != "warn" || entry["outcome"] != outcomeUnreachable {
+		t.Errorf("Expected warn/unreachable, got %v/%v", entry["level"], entry["outcome"])
+	}
+	if _, ok := entry["upstream_status"]; ok {
+		t.Error("Expected no upstream_status for a connection error")
+	}
+}
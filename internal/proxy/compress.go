@@ -0,0 +1,38 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// gzipContentEncoding is the Content-Encoding sent with compressed request bodies
+const gzipContentEncoding = "gzip"
+
+// WithAnalyzeCompression gzips AnalyzePlayer request bodies larger than thresholdBytes and
+// sends them with Content-Encoding: gzip. Analyze payloads carry the full match history and
+// compress well; other calls are small and always sent as-is. Only enable this when
+// opgl-cortex accepts gzip-encoded requests. A threshold of 0 or less disables compression.
+func WithAnalyzeCompression(thresholdBytes int) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.analyzeCompressionThreshold = thresholdBytes
+	}
+}
+
+// encodeRequestBody returns the body to send for an upstream call and its Content-Encoding,
+// gzipping AnalyzePlayer bodies above the configured threshold. If compression fails the
+// plain body is sent.
+func (proxy *ServiceProxy) encodeRequestBody(call UpstreamCall, jsonData []byte) ([]byte, string) {
+	if call != CallAnalyzePlayer || proxy.analyzeCompressionThreshold <= 0 || len(jsonData) <= proxy.analyzeCompressionThreshold {
+		return jsonData, ""
+	}
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	if _, err := gzipWriter.Write(jsonData); err != nil {
+		return jsonData, ""
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return jsonData, ""
+	}
+	return compressed.Bytes(), gzipContentEncoding
+}
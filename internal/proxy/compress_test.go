@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestAnalyzePlayer_Compression tests that analyze bodies are gzipped above the threshold and plain below it
+func TestAnalyzePlayer_Compression(t *testing.T) {
+	smallMatches := []models.Match{{MatchID: "NA1_1"}}
+	largeMatches := make([]models.Match, 100)
+	for index := range largeMatches {
+		largeMatches[index] = models.Match{MatchID: fmt.Sprintf("NA1_%d", index), GameMode: "CLASSIC"}
+	}
+
+	testCases := []struct {
+		name             string
+		options          []Option
+		matches          []models.Match
+		expectCompressed bool
+	}{
+		{"above threshold", []Option{WithAnalyzeCompression(1024)}, largeMatches, true},
+		{"below threshold", []Option{WithAnalyzeCompression(1024)}, smallMatches, false},
+		{"disabled", nil, largeMatches, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var contentEncoding string
+			var requestBody map[string]interface{}
+			fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				contentEncoding = request.Header.Get("Content-Encoding")
+
+				var bodyReader io.Reader = request.Body
+				if contentEncoding == "gzip" {
+					gzipReader, err := gzip.NewReader(request.Body)
+					if err != nil {
+						t.Fatalf("Expected a valid gzip body: %v", err)
+					}
+					bodyReader = gzipReader
+				}
+				if err := json.NewDecoder(bodyReader).Decode(&requestBody); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+
+				return newCannedResponse(http.StatusOK, `{"analyzedAt":"2024-01-01T00:00:00Z"}`), nil
+			})
+
+			options := append([]Option{WithTransport(fakeTransport)}, testCase.options...)
+			proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", options...)
+
+			if _, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, testCase.matches, ""); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if (contentEncoding == "gzip") != testCase.expectCompressed {
+				t.Errorf("Expected compressed %v, got Content-Encoding '%s'", testCase.expectCompressed, contentEncoding)
+			}
+
+			matches, _ := requestBody["matches"].([]interface{})
+			if len(matches) != len(testCase.matches) {
+				t.Errorf("Expected %d matches in the decoded body, got %d", len(testCase.matches), len(matches))
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"fmt"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// EmptyMatchesPolicy selects how a player without matches is reported. opgl-data answers
+// either 200 with an empty array or 404 for the same situation; the policy makes the
+// gateway consistent.
+type EmptyMatchesPolicy string
+
+const (
+	// EmptyMatchesAsEmpty reports no matches as 200 with [] (default)
+	EmptyMatchesAsEmpty EmptyMatchesPolicy = "empty"
+	// EmptyMatchesAsNotFound reports no matches as 404 MATCHES_NOT_FOUND
+	EmptyMatchesAsNotFound EmptyMatchesPolicy = "not_found"
+)
+
+// ParseEmptyMatchesPolicy parses an EMPTY_MATCHES_POLICY value, defaulting to empty when unset
+func ParseEmptyMatchesPolicy(value string) (EmptyMatchesPolicy, error) {
+	switch EmptyMatchesPolicy(value) {
+	case "", EmptyMatchesAsEmpty:
+		return EmptyMatchesAsEmpty, nil
+	case EmptyMatchesAsNotFound:
+		return EmptyMatchesAsNotFound, nil
+	default:
+		return "", fmt.Errorf("invalid empty matches policy %q: expected empty or not_found", value)
+	}
+}
+
+// WithEmptyMatchesPolicy sets how match lookups report a player without matches
+func WithEmptyMatchesPolicy(policy EmptyMatchesPolicy) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.emptyMatchesPolicy = policy
+	}
+}
+
+// noMatches returns the result for a match lookup that found nothing under the configured
+// policy. A page requested past the end of a cursor-paginated history is always empty
+// rather than not found, so clients can stop paginating normally.
+func (proxy *ServiceProxy) noMatches(filter models.MatchFilter, upstreamStatus int, upstreamBody []byte) ([]models.Match, error) {
+	if proxy.emptyMatchesPolicy == EmptyMatchesAsNotFound && filter.Cursor == nil {
+		notFound := apierrors.MatchesNotFound("No matches found for this player")
+		if upstreamBody != nil {
+			notFound.WithUpstream(upstreamStatus, upstreamBody)
+		}
+		return nil, notFound
+	}
+	return []models.Match{}, nil
+}
+
+// noMatchIDs returns the result for a match ID lookup that found nothing, under the same
+// policy as noMatches
+func (proxy *ServiceProxy) noMatchIDs(upstreamStatus int, upstreamBody []byte) ([]string, error) {
+	if _, err := proxy.noMatches(models.MatchFilter{}, upstreamStatus, upstreamBody); err != nil {
+		return nil, err
+	}
+	return []string{}, nil
+}
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// TestParseEmptyMatchesPolicy tests parsing of the EMPTY_MATCHES_POLICY setting
+func TestParseEmptyMatchesPolicy(t *testing.T) {
+	testCases := []struct {
+		value          string
+		expectedPolicy EmptyMatchesPolicy
+		expectError    bool
+	}{
+		{"", EmptyMatchesAsEmpty, false},
+		{"empty", EmptyMatchesAsEmpty, false},
+		{"not_found", EmptyMatchesAsNotFound, false},
+		{"404", "", true},
+	}
+
+	for _, testCase := range testCases {
+		policy, err := ParseEmptyMatchesPolicy(testCase.value)
+		if (err != nil) != testCase.expectError {
+			t.Errorf("Value %q: expected error %v, got %v", testCase.value, testCase.expectError, err)
+		}
+		if policy != testCase.expectedPolicy {
+			t.Errorf("Value %q: expected policy '%s', got '%s'", testCase.value, testCase.expectedPolicy, policy)
+		}
+	}
+}
+
+// TestEmptyMatchesPolicy tests that empty-200 and 404 upstream responses are normalized under each policy
+func TestEmptyMatchesPolicy(t *testing.T) {
+	fetchByPUUID := func(proxy *ServiceProxy, filter models.MatchFilter) ([]models.Match, error) {
+		return proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, filter)
+	}
+	fetchByRiotID := func(proxy *ServiceProxy, filter models.MatchFilter) ([]models.Match, error) {
+		return proxy.GetMatchesByRiotID(context.Background(), "na", "TestPlayer", "NA1", 20, filter)
+	}
+
+	testCases := []struct {
+		name           string
+		policy         EmptyMatchesPolicy
+		fetch          func(proxy *ServiceProxy, filter models.MatchFilter) ([]models.Match, error)
+		filter         models.MatchFilter
+		upstreamStatus int
+		upstreamBody   string
+		expectNotFound bool
+	}{
+		{"empty policy, puuid empty 200", EmptyMatchesAsEmpty, fetchByPUUID, models.MatchFilter{}, http.StatusOK, `[]`, false},
+		{"empty policy, puuid null 200", EmptyMatchesAsEmpty, fetchByPUUID, models.MatchFilter{}, http.StatusOK, `null`, false},
+		{"empty policy, puuid 404", EmptyMatchesAsEmpty, fetchByPUUID, models.MatchFilter{}, http.StatusNotFound, `{"error":"no matches"}`, false},
+		{"empty policy, riot id empty 200", EmptyMatchesAsEmpty, fetchByRiotID, models.MatchFilter{}, http.StatusOK, `[]`, false},
+		{"not found policy, puuid empty 200", EmptyMatchesAsNotFound, fetchByPUUID, models.MatchFilter{}, http.StatusOK, `[]`, true},
+		{"not found policy, puuid 404", EmptyMatchesAsNotFound, fetchByPUUID, models.MatchFilter{}, http.StatusNotFound, `{"error":"no matches"}`, true},
+		{"not found policy, riot id empty 200", EmptyMatchesAsNotFound, fetchByRiotID, models.MatchFilter{}, http.StatusOK, `[]`, true},
+		{"not found policy, page past the end", EmptyMatchesAsNotFound, fetchByPUUID, models.MatchFilter{Cursor: &models.MatchCursor{MatchID: "NA1_1"}}, http.StatusOK, `[]`, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				return newCannedResponse(testCase.upstreamStatus, testCase.upstreamBody), nil
+			})
+			proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+				WithTransport(fakeTransport),
+				WithEmptyMatchesPolicy(testCase.policy),
+			)
+
+			matches, err := testCase.fetch(proxy, testCase.filter)
+
+			if testCase.expectNotFound {
+				apiError, ok := err.(*apierrors.APIError)
+				if !ok || apiError.Code != apierrors.ErrCodeMatchesNotFound {
+					t.Fatalf("Expected MATCHES_NOT_FOUND, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if matches == nil || len(matches) != 0 {
+				t.Errorf("Expected a non-nil empty slice, got %#v", matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// WithFallbackCortex sets a secondary opgl-cortex base URL that analysis requests are sent
+// to when the primary cannot be reached or answers with a 5xx. 4xx responses are the
+// client's fault and are returned from the primary as-is. The cortex path prefix applies to
+// both URLs. An empty URL disables the fallback.
+func WithFallbackCortex(fallbackURL string) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.fallbackCortexURL = strings.TrimSuffix(fallbackURL, "/")
+	}
+}
+
+// postCortexWithFallback posts to a cortex path on the primary instance and, when that
+// fails with a connection error or 5xx, once more on the fallback instance. The caller is
+// responsible for closing the returned response body.
+func (proxy *ServiceProxy) postCortexWithFallback(ctx context.Context, call UpstreamCall, path string, jsonData []byte) (*http.Response, error) {
+	response, err := proxy.postWithRetry(ctx, call, fixedTarget(proxy.cortexURL(path)), jsonData)
+	if proxy.fallbackCortexURL == "" || ctx.Err() != nil {
+		return response, err
+	}
+	if err == nil && response.StatusCode < http.StatusInternalServerError {
+		return response, nil
+	}
+
+	logger := middleware.LoggerFromContext(ctx)
+	logEvent := logger.Warn().Str("fallback_url", proxy.fallbackCortexURL)
+	if err != nil {
+		logEvent = logEvent.Err(err)
+	} else {
+		logEvent = logEvent.Int("primary_status", response.StatusCode)
+		response.Body.Close()
+	}
+	logEvent.Msg("Primary cortex failed; retrying analysis on the fallback instance")
+	proxy.upstreamMetrics.Fallback(metrics.UpstreamCortex)
+
+	return proxy.postWithRetry(ctx, call, fixedTarget(proxy.fallbackCortexURL+proxy.cortexPathPrefix+path), jsonData)
+}
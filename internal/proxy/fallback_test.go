@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newAnalysisServer returns a cortex stub that serves a fixed analysis and counts its calls
+func newAnalysisServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(calls, 1)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(models.AnalysisResult{PlayerStats: map[string]interface{}{"winRate": 0.5}})
+	}))
+}
+
+// TestAnalyzePlayer_FallbackCortex tests that the fallback serves analyses the primary fails with a connection error or 5xx, but not 4xx
+func TestAnalyzePlayer_FallbackCortex(t *testing.T) {
+	unreachableServer := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachableServer.URL
+	unreachableServer.Close()
+
+	testCases := []struct {
+		name                  string
+		primaryStatus         int
+		primaryUnreachable    bool
+		expectedFallbackCalls int32
+		expectedErrorCode     apierrors.ErrorCode
+	}{
+		{"primary 5xx", http.StatusServiceUnavailable, false, 1, ""},
+		{"primary unreachable", 0, true, 1, ""},
+		{"primary 4xx", http.StatusBadRequest, false, 0, apierrors.ErrCodeInvalidRequestBody},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(testCase.primaryStatus)
+				writer.Write([]byte(`{"error":"primary failed"}`))
+			}))
+			defer primaryServer.Close()
+			primaryURL := primaryServer.URL
+			if testCase.primaryUnreachable {
+				primaryURL = unreachableURL
+			}
+
+			var fallbackCalls int32
+			fallbackServer := newAnalysisServer(&fallbackCalls)
+			defer fallbackServer.Close()
+
+			upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+			proxy := NewServiceProxy("http://data.invalid", primaryURL, WithFallbackCortex(fallbackServer.URL), WithUpstreamMetrics(upstreamMetrics))
+
+			result, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, []models.Match{}, "en")
+
+			if fallbackCalls != testCase.expectedFallbackCalls {
+				t.Errorf("Expected %d fallback calls, got %d", testCase.expectedFallbackCalls, fallbackCalls)
+			}
+			if upstreamMetrics.Fallbacks(metrics.UpstreamCortex) != float64(testCase.expectedFallbackCalls) {
+				t.Errorf("Expected fallback metric %d, got %g", testCase.expectedFallbackCalls, upstreamMetrics.Fallbacks(metrics.UpstreamCortex))
+			}
+
+			if testCase.expectedErrorCode != "" {
+				apiError, ok := err.(*apierrors.APIError)
+				if !ok || apiError.Code != testCase.expectedErrorCode {
+					t.Fatalf("Expected %s error, got %v", testCase.expectedErrorCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the fallback to serve the analysis, got %v", err)
+			}
+			if playerStats, ok := result.PlayerStats.(map[string]interface{}); !ok || playerStats["winRate"] != 0.5 {
+				t.Errorf("Expected fallback analysis result, got %+v", result)
+			}
+		})
+	}
+}
+
+// TestAnalyzePlayer_NoFallbackConfigured tests that a failing primary is reported when no fallback is set
+func TestAnalyzePlayer_NoFallbackConfigured(t *testing.T) {
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primaryServer.Close()
+
+	proxy := NewServiceProxy("http://data.invalid", primaryServer.URL)
+	if _, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, []models.Match{}, ""); err == nil {
+		t.Error("Expected an error from the failing primary")
+	}
+}
@@ -0,0 +1,143 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errInjectedFault is returned for calls failed by fault injection
+var errInjectedFault = errors.New("injected fault")
+
+// FaultRule describes synthetic faults applied to calls to one upstream
+type FaultRule struct {
+	// Latency is added before every call
+	Latency time.Duration
+	// ErrorRate is the fraction of calls (0 to 1) that fail with a transport error
+	ErrorRate float64
+}
+
+// FaultRules holds the fault rule for each upstream
+type FaultRules struct {
+	Data   FaultRule
+	Cortex FaultRule
+}
+
+// ParseFaultSpec parses a fault injection spec such as "data:latency=500ms,cortex:error=0.1".
+// Each entry is "target:setting=value" where target is data or cortex and setting is latency
+// (a duration) or error (a fraction between 0 and 1).
+func ParseFaultSpec(spec string) (FaultRules, error) {
+	var rules FaultRules
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, setting, found := strings.Cut(entry, ":")
+		if !found {
+			return FaultRules{}, fmt.Errorf("invalid fault %q: expected target:setting=value", entry)
+		}
+
+		var rule *FaultRule
+		switch target {
+		case "data":
+			rule = &rules.Data
+		case "cortex":
+			rule = &rules.Cortex
+		default:
+			return FaultRules{}, fmt.Errorf("invalid fault %q: unknown target %q", entry, target)
+		}
+
+		key, value, found := strings.Cut(setting, "=")
+		if !found {
+			return FaultRules{}, fmt.Errorf("invalid fault %q: expected setting=value", entry)
+		}
+
+		switch key {
+		case "latency":
+			latency, err := time.ParseDuration(value)
+			if err != nil || latency < 0 {
+				return FaultRules{}, fmt.Errorf("invalid fault %q: latency must be a non-negative duration", entry)
+			}
+			rule.Latency = latency
+		case "error":
+			errorRate, err := strconv.ParseFloat(value, 64)
+			if err != nil || errorRate < 0 || errorRate > 1 {
+				return FaultRules{}, fmt.Errorf("invalid fault %q: error rate must be between 0 and 1", entry)
+			}
+			rule.ErrorRate = errorRate
+		default:
+			return FaultRules{}, fmt.Errorf("invalid fault %q: unknown setting %q", entry, key)
+		}
+	}
+
+	return rules, nil
+}
+
+// WithFaultInjection adds synthetic latency and errors to upstream calls for chaos testing.
+// Without this option the proxy's transport is untouched, so there is no overhead.
+func WithFaultInjection(rules FaultRules) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.faultRules = &rules
+	}
+}
+
+// faultTransport wraps a RoundTripper, applying the fault rule for the upstream being called
+type faultTransport struct {
+	next             http.RoundTripper
+	dataServiceURL   string
+	cortexServiceURL string
+	rules            FaultRules
+	// randomFloat returns a value in [0, 1); replaced in tests for determinism
+	randomFloat func() float64
+}
+
+// RoundTrip applies latency and error injection before delegating to the wrapped transport
+func (transport *faultTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	rule := transport.ruleFor(request.URL.String())
+
+	if rule.Latency > 0 {
+		timer := time.NewTimer(rule.Latency)
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		}
+	}
+
+	if rule.ErrorRate > 0 && transport.randomFloat() < rule.ErrorRate {
+		return nil, errInjectedFault
+	}
+
+	return transport.next.RoundTrip(request)
+}
+
+// ruleFor returns the fault rule for the upstream a URL belongs to
+func (transport *faultTransport) ruleFor(url string) FaultRule {
+	switch {
+	case strings.HasPrefix(url, transport.dataServiceURL):
+		return transport.rules.Data
+	case strings.HasPrefix(url, transport.cortexServiceURL):
+		return transport.rules.Cortex
+	default:
+		return FaultRule{}
+	}
+}
+
+// newFaultTransport wraps next with the given fault rules
+func newFaultTransport(next http.RoundTripper, dataServiceURL string, cortexServiceURL string, rules FaultRules) *faultTransport {
+	return &faultTransport{
+		next:             next,
+		dataServiceURL:   dataServiceURL,
+		cortexServiceURL: cortexServiceURL,
+		rules:            rules,
+		randomFloat:      rand.Float64,
+	}
+}
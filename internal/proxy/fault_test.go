@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestParseFaultSpec tests parsing of a multi-target fault spec
+func TestParseFaultSpec(t *testing.T) {
+	rules, err := ParseFaultSpec("data:latency=500ms, cortex:error=0.1,data:error=0.25")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rules.Data.Latency != 500*time.Millisecond || rules.Data.ErrorRate != 0.25 {
+		t.Errorf("Unexpected data rule: %+v", rules.Data)
+	}
+
+	if rules.Cortex.Latency != 0 || rules.Cortex.ErrorRate != 0.1 {
+		t.Errorf("Unexpected cortex rule: %+v", rules.Cortex)
+	}
+}
+
+// TestParseFaultSpec_Invalid tests that malformed specs are rejected
+func TestParseFaultSpec_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"latency=500ms",
+		"auth:latency=1s",
+		"data:latency",
+		"data:latency=soon",
+		"data:error=1.5",
+		"data:jitter=1s",
+	} {
+		if _, err := ParseFaultSpec(spec); err == nil {
+			t.Errorf("Expected error for spec '%s'", spec)
+		}
+	}
+}
+
+// TestFaultInjection_DisabledLeavesTransport tests that the transport is untouched without the option
+func TestFaultInjection_DisabledLeavesTransport(t *testing.T) {
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal")
+
+	if _, wrapped := proxy.httpClient.Transport.(*faultTransport); wrapped {
+		t.Error("Expected no fault transport when fault injection is not enabled")
+	}
+}
+
+// TestFaultInjection_ErrorRateObserved tests that the configured error rate is observed over many calls
+func TestFaultInjection_ErrorRateObserved(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newCannedResponse(http.StatusOK, `{"puuid":"test-puuid"}`), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithFaultInjection(FaultRules{Data: FaultRule{ErrorRate: 0.2}}),
+		WithTransport(fakeTransport),
+	)
+
+	// Seed the fault transport so the observed rate is deterministic
+	random := rand.New(rand.NewSource(42))
+	proxy.httpClient.Transport.(*faultTransport).randomFloat = random.Float64
+
+	const totalCalls = 2000
+	failures := 0
+	for call := 0; call < totalCalls; call++ {
+		if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err != nil {
+			failures++
+		}
+	}
+
+	observedRate := float64(failures) / totalCalls
+	if math.Abs(observedRate-0.2) > 0.03 {
+		t.Errorf("Expected error rate near 0.2, observed %.3f", observedRate)
+	}
+}
+
+// TestFaultInjection_TargetsOnlyConfiguredUpstream tests that faults apply only to their upstream
+func TestFaultInjection_TargetsOnlyConfiguredUpstream(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newCannedResponse(http.StatusOK, `{"puuid":"test-puuid"}`), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithTransport(fakeTransport),
+		WithFaultInjection(FaultRules{Cortex: FaultRule{ErrorRate: 1}}),
+	)
+
+	if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err != nil {
+		t.Errorf("Expected data call to be unaffected, got %v", err)
+	}
+
+	if _, err := proxy.AnalyzePlayer(context.Background(), nil, nil, ""); err == nil {
+		t.Error("Expected cortex call to fail with an injected fault")
+	}
+}
+
+// TestFaultInjection_Latency tests that configured latency is added and respects cancellation
+func TestFaultInjection_Latency(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newCannedResponse(http.StatusOK, `{"puuid":"test-puuid"}`), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithTransport(fakeTransport),
+		WithFaultInjection(FaultRules{Data: FaultRule{Latency: 30 * time.Millisecond}}),
+	)
+
+	startTime := time.Now()
+	if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if time.Since(startTime) < 30*time.Millisecond {
+		t.Error("Expected injected latency to delay the call")
+	}
+
+	cancelledContext, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := proxy.GetSummonerByRiotID(cancelledContext, "na", "TestPlayer", "NA1"); err == nil {
+		t.Error("Expected cancelled call to fail")
+	}
+}
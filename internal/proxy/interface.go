@@ -1,19 +1,47 @@
 package proxy
 
-import "github.com/OPGLOL/opgl-gateway-service/internal/models"
+import (
+	"context"
+	"net/http"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
 
 // ServiceProxyInterface defines the interface for service proxy operations
 // This interface enables mocking in tests
 type ServiceProxyInterface interface {
 	// GetSummonerByRiotID retrieves summoner data from opgl-data service using Riot ID
-	GetSummonerByRiotID(region string, gameName string, tagLine string) (*models.Summoner, error)
+	GetSummonerByRiotID(ctx context.Context, region string, gameName string, tagLine string) (*models.Summoner, error)
 
 	// GetMatchesByRiotID retrieves match history from opgl-data service using Riot ID
-	GetMatchesByRiotID(region string, gameName string, tagLine string, count int) ([]models.Match, error)
+	GetMatchesByRiotID(ctx context.Context, region string, gameName string, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error)
 
 	// GetMatchesByPUUID retrieves match history from opgl-data service using PUUID
-	GetMatchesByPUUID(region string, puuid string, count int) ([]models.Match, error)
+	GetMatchesByPUUID(ctx context.Context, region string, puuid string, count int, filter models.MatchFilter) ([]models.Match, error)
+
+	// GetMatchIDs retrieves the IDs of a player's recent matches from opgl-data service using PUUID
+	GetMatchIDs(ctx context.Context, region string, puuid string, count int) ([]string, error)
+
+	// GetRankedStats retrieves ranked queue entries from opgl-data service using PUUID
+	GetRankedStats(ctx context.Context, region string, puuid string) ([]models.RankedStats, error)
+
+	// GetMatchTimeline retrieves the minute-by-minute timeline of a match from opgl-data service
+	GetMatchTimeline(ctx context.Context, region string, matchID string) (*models.MatchTimeline, error)
+
+	// GetActiveGame retrieves the game a player is currently in from opgl-data service,
+	// returning nil when the player is not in a game
+	GetActiveGame(ctx context.Context, region string, puuid string) (*models.LiveGame, error)
+
+	// AnalyzePlayer sends analysis request to opgl-cortex-engine, asking for text in locale
+	AnalyzePlayer(ctx context.Context, summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error)
+
+	// CheckCortexHealth pings opgl-cortex-engine without running an analysis
+	CheckCortexHealth(ctx context.Context) error
+
+	// CheckDataHealth pings opgl-data-service
+	CheckDataHealth(ctx context.Context) error
 
-	// AnalyzePlayer sends analysis request to opgl-cortex-engine
-	AnalyzePlayer(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error)
+	// ForwardToDataService forwards a raw JSON body to an opgl-data path and returns the
+	// upstream response unmodified. The caller is responsible for closing the response body.
+	ForwardToDataService(ctx context.Context, path string, body []byte) (*http.Response, error)
 }
@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// outboundProxyDirect disables any egress proxy, including one set in the environment
+const outboundProxyDirect = "direct"
+
+// OutboundProxyFunc selects the egress proxy for an upstream request, as http.Transport.Proxy does
+type OutboundProxyFunc func(*http.Request) (*url.URL, error)
+
+// ParseOutboundProxy parses an UPSTREAM_PROXY value. An empty value returns nil, keeping the
+// default of honoring HTTP_PROXY, HTTPS_PROXY and NO_PROXY; "direct" bypasses any proxy;
+// anything else must be an absolute proxy URL used for every upstream call.
+func ParseOutboundProxy(value string) (OutboundProxyFunc, error) {
+	switch value {
+	case "":
+		return nil, nil
+	case outboundProxyDirect:
+		return func(*http.Request) (*url.URL, error) { return nil, nil }, nil
+	}
+
+	proxyURL, err := url.Parse(value)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid upstream proxy %q: expected direct or an absolute URL", value)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// WithOutboundProxy overrides how the egress proxy is chosen for upstream calls. By default
+// the transport honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY via http.ProxyFromEnvironment.
+// The override is applied to a copy of the transport, so a transport passed to
+// WithTransport is never modified; transports other than *http.Transport are left as-is.
+func WithOutboundProxy(outboundProxy OutboundProxyFunc) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.outboundProxy = outboundProxy
+	}
+}
+
+// applyOutboundProxy installs the configured outbound proxy on the HTTP transport
+func (proxy *ServiceProxy) applyOutboundProxy() {
+	if proxy.outboundProxy == nil {
+		return
+	}
+	transport, ok := proxy.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = proxy.outboundProxy
+	proxy.httpClient.Transport = transport
+}
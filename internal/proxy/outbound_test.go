@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// TestOutboundProxy_DefaultHonorsEnvironment tests that the default transport's Proxy is http.ProxyFromEnvironment
+func TestOutboundProxy_DefaultHonorsEnvironment(t *testing.T) {
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal")
+
+	transport, ok := proxy.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", proxy.httpClient.Transport)
+	}
+	if transport.Proxy == nil {
+		t.Fatal("Expected the transport Proxy func to be set")
+	}
+	if reflect.ValueOf(transport.Proxy).Pointer() != reflect.ValueOf(http.ProxyFromEnvironment).Pointer() {
+		t.Error("Expected the transport Proxy func to be http.ProxyFromEnvironment")
+	}
+}
+
+// TestOutboundProxy_Override tests that UPSTREAM_PROXY values override the environment proxy
+func TestOutboundProxy_Override(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectedProxy string
+	}{
+		{"explicit proxy", "http://egress.internal:3128", "http://egress.internal:3128"},
+		{"direct", "direct", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			outboundProxy, err := ParseOutboundProxy(testCase.value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithOutboundProxy(outboundProxy))
+
+			transport := proxy.httpClient.Transport.(*http.Transport)
+			request, _ := http.NewRequest("POST", "http://data.internal/api/v1/summoner", nil)
+			proxyURL, err := transport.Proxy(request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := ""
+			if proxyURL != nil {
+				got = proxyURL.String()
+			}
+			if got != testCase.expectedProxy {
+				t.Errorf("Expected proxy '%s', got '%s'", testCase.expectedProxy, got)
+			}
+		})
+	}
+}
+
+// TestOutboundProxy_LeavesInjectedTransport tests that the override does not mutate a transport passed to WithTransport
+func TestOutboundProxy_LeavesInjectedTransport(t *testing.T) {
+	injectedTransport := &http.Transport{}
+	outboundProxy, _ := ParseOutboundProxy("direct")
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithTransport(injectedTransport),
+		WithOutboundProxy(outboundProxy),
+	)
+
+	if injectedTransport.Proxy != nil {
+		t.Error("Expected the injected transport to be left unmodified")
+	}
+	if proxy.httpClient.Transport.(*http.Transport).Proxy == nil {
+		t.Error("Expected the proxy's copy of the transport to use the override")
+	}
+}
+
+// TestParseOutboundProxy_Invalid tests that relative or malformed proxy URLs are rejected
+func TestParseOutboundProxy_Invalid(t *testing.T) {
+	for _, value := range []string{"egress.internal:3128", "://bad", "/relative"} {
+		if _, err := ParseOutboundProxy(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
@@ -2,11 +2,21 @@ package proxy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/OPGLOL/opgl-gateway-service/internal/backoff"
 	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
 	"github.com/OPGLOL/opgl-gateway-service/internal/models"
 )
 
@@ -14,21 +24,141 @@ import (
 type ServiceProxy struct {
 	dataServiceURL   string
 	cortexServiceURL string
+	dataPathPrefix   string
+	cortexPathPrefix string
 	httpClient       *http.Client
+	retryPolicy      retryPolicy
+	idempotentCalls  map[UpstreamCall]bool
+	faultRules       *FaultRules
+	upstreamMetrics  *metrics.UpstreamMetrics
+	connectionTrace  bool
+	outboundProxy    OutboundProxyFunc
+	// dataBalancer spreads data calls over the primary and its replicas, when replicas are configured
+	dataBalancer *endpointBalancer
+	// fallbackCortexURL is an optional secondary cortex instance used when the primary fails
+	fallbackCortexURL string
+	// emptyMatchesPolicy controls whether a player without matches is reported as [] or 404
+	emptyMatchesPolicy EmptyMatchesPolicy
+	// analyzeCompressionThreshold is the body size above which analyze requests are gzipped (0 disables)
+	analyzeCompressionThreshold int
+	// wait sleeps between retries; replaced in tests to avoid real delays
+	wait func(ctx context.Context, delay time.Duration) error
+}
+
+// Option configures optional ServiceProxy behavior
+type Option func(*ServiceProxy)
+
+// WithTransport replaces the default tuned transport used for upstream calls.
+// Tests use this to inject a RoundTripper that returns canned responses.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.httpClient.Transport = transport
+	}
+}
+
+// WithUpstreamMetrics counts upstream call attempts by service, operation and status code, and
+// failed upstream calls (connection errors and 5xx responses) by service
+func WithUpstreamMetrics(upstreamMetrics *metrics.UpstreamMetrics) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.upstreamMetrics = upstreamMetrics
+	}
+}
+
+// WithPathPrefixes sets base paths prepended to every upstream path, for deployments where
+// opgl-data or opgl-cortex is mounted below a prefix (e.g. "/data-service")
+func WithPathPrefixes(dataPathPrefix string, cortexPathPrefix string) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.dataPathPrefix = normalizePathPrefix(dataPathPrefix)
+		proxy.cortexPathPrefix = normalizePathPrefix(cortexPathPrefix)
+	}
+}
+
+// normalizePathPrefix ensures a non-empty prefix has a leading slash and no trailing slash
+func normalizePathPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// dataURL builds the full opgl-data URL for an upstream path, on an endpoint picked by health
+// when replicas are configured
+func (proxy *ServiceProxy) dataURL(path string) string {
+	baseURL := proxy.dataServiceURL
+	if proxy.dataBalancer != nil {
+		baseURL = proxy.dataBalancer.pick()
+	}
+	return baseURL + proxy.dataPathPrefix + path
+}
+
+// dataTarget returns a function building the opgl-data URL for an upstream path, so that
+// each retry attempt picks an endpoint by health again rather than reusing a failing one
+func (proxy *ServiceProxy) dataTarget(path string) func() string {
+	return func() string {
+		return proxy.dataURL(path)
+	}
+}
+
+// fixedTarget returns a function that always yields url, for upstreams without replicas
+func fixedTarget(url string) func() string {
+	return func() string {
+		return url
+	}
+}
+
+// cortexURL builds the full opgl-cortex URL for an upstream path
+func (proxy *ServiceProxy) cortexURL(path string) string {
+	return proxy.cortexServiceURL + proxy.cortexPathPrefix + path
 }
 
 // NewServiceProxy creates a new ServiceProxy instance
-func NewServiceProxy(dataServiceURL string, cortexServiceURL string) *ServiceProxy {
-	return &ServiceProxy{
+func NewServiceProxy(dataServiceURL string, cortexServiceURL string, options ...Option) *ServiceProxy {
+	proxy := &ServiceProxy{
 		dataServiceURL:   dataServiceURL,
 		cortexServiceURL: cortexServiceURL,
-		httpClient:       &http.Client{},
+		httpClient: &http.Client{
+			Transport: newDefaultTransport(),
+		},
+		idempotentCalls:    defaultIdempotentCalls(),
+		emptyMatchesPolicy: EmptyMatchesAsEmpty,
+		wait:               backoff.Wait,
+	}
+
+	for _, option := range options {
+		option(proxy)
+	}
+	proxy.applyOutboundProxy()
+
+	// Wrap after all options so fault injection applies on top of any custom transport
+	if proxy.faultRules != nil {
+		proxy.httpClient.Transport = newFaultTransport(proxy.httpClient.Transport, proxy.dataServiceURL, proxy.cortexServiceURL, *proxy.faultRules)
+	}
+
+	return proxy
+}
+
+// newDefaultTransport creates the transport used for upstream calls, tuned to
+// keep a pool of warm connections to the small set of internal services. Like the
+// standard library's default transport, it honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func newDefaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
 	}
 }
 
 // GetSummonerByRiotID retrieves summoner data from opgl-data service using Riot ID
-func (proxy *ServiceProxy) GetSummonerByRiotID(region string, gameName string, tagLine string) (*models.Summoner, error) {
-	url := proxy.dataServiceURL + "/api/v1/summoner"
+func (proxy *ServiceProxy) GetSummonerByRiotID(ctx context.Context, region string, gameName string, tagLine string) (*models.Summoner, error) {
+	target := proxy.dataTarget("/api/v1/summoner")
 
 	requestBody := map[string]string{
 		"region":   region,
@@ -41,12 +171,16 @@ func (proxy *ServiceProxy) GetSummonerByRiotID(region string, gameName string, t
 		return nil, apierrors.InternalError("Failed to prepare request")
 	}
 
-	response, err := proxy.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	response, err := proxy.postWithRetry(ctx, CallGetSummonerByRiotID, target, jsonData)
 	if err != nil {
-		return nil, apierrors.DataServiceError("Unable to connect to data service")
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
 	}
 	defer response.Body.Close()
 
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
 	// Handle different status codes from data service
 	if response.StatusCode != http.StatusOK {
 		return nil, proxy.handleDataServiceError(response, gameName, tagLine)
@@ -61,8 +195,8 @@ func (proxy *ServiceProxy) GetSummonerByRiotID(region string, gameName string, t
 }
 
 // GetMatchesByRiotID retrieves match history from opgl-data service using Riot ID
-func (proxy *ServiceProxy) GetMatchesByRiotID(region string, gameName string, tagLine string, count int) ([]models.Match, error) {
-	url := proxy.dataServiceURL + "/api/v1/matches"
+func (proxy *ServiceProxy) GetMatchesByRiotID(ctx context.Context, region string, gameName string, tagLine string, count int, filter models.MatchFilter) ([]models.Match, error) {
+	target := proxy.dataTarget("/api/v1/matches")
 
 	requestBody := map[string]interface{}{
 		"region":   region,
@@ -70,18 +204,23 @@ func (proxy *ServiceProxy) GetMatchesByRiotID(region string, gameName string, ta
 		"tagLine":  tagLine,
 		"count":    count,
 	}
+	applyMatchFilter(requestBody, filter)
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, apierrors.InternalError("Failed to prepare request")
 	}
 
-	response, err := proxy.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	response, err := proxy.postWithRetry(ctx, CallGetMatchesByRiotID, target, jsonData)
 	if err != nil {
-		return nil, apierrors.DataServiceError("Unable to connect to data service")
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
 	}
 	defer response.Body.Close()
 
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
 	// Handle different status codes from data service
 	if response.StatusCode != http.StatusOK {
 		return nil, proxy.handleDataServiceError(response, gameName, tagLine)
@@ -91,31 +230,45 @@ func (proxy *ServiceProxy) GetMatchesByRiotID(region string, gameName string, ta
 	if err := json.NewDecoder(response.Body).Decode(&matches); err != nil {
 		return nil, apierrors.InternalError("Failed to process match data")
 	}
+	if len(matches) == 0 {
+		return proxy.noMatches(filter, response.StatusCode, nil)
+	}
 
 	return matches, nil
 }
 
 // GetMatchesByPUUID retrieves match history from opgl-data service using PUUID (internal use)
-func (proxy *ServiceProxy) GetMatchesByPUUID(region string, puuid string, count int) ([]models.Match, error) {
-	url := proxy.dataServiceURL + "/api/v1/matches"
+func (proxy *ServiceProxy) GetMatchesByPUUID(ctx context.Context, region string, puuid string, count int, filter models.MatchFilter) ([]models.Match, error) {
+	target := proxy.dataTarget("/api/v1/matches")
 
 	requestBody := map[string]interface{}{
 		"region": region,
 		"puuid":  puuid,
 		"count":  count,
 	}
+	applyMatchFilter(requestBody, filter)
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, apierrors.InternalError("Failed to prepare request")
 	}
 
-	response, err := proxy.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	response, err := proxy.postWithRetry(ctx, CallGetMatchesByPUUID, target, jsonData)
 	if err != nil {
-		return nil, apierrors.DataServiceError("Unable to connect to data service")
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
 	}
 	defer response.Body.Close()
 
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
+	// A PUUID is already resolved, so a 404 means the player has no matches
+	if response.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(response.Body)
+		return proxy.noMatches(filter, response.StatusCode, body)
+	}
+
 	// Handle different status codes from data service
 	if response.StatusCode != http.StatusOK {
 		return nil, proxy.handleDataServiceErrorByPUUID(response)
@@ -125,29 +278,215 @@ func (proxy *ServiceProxy) GetMatchesByPUUID(region string, puuid string, count
 	if err := json.NewDecoder(response.Body).Decode(&matches); err != nil {
 		return nil, apierrors.InternalError("Failed to process match data")
 	}
+	if len(matches) == 0 {
+		return proxy.noMatches(filter, response.StatusCode, nil)
+	}
 
 	return matches, nil
 }
 
-// AnalyzePlayer sends analysis request to opgl-cortex-engine
-func (proxy *ServiceProxy) AnalyzePlayer(summoner *models.Summoner, matches []models.Match) (*models.AnalysisResult, error) {
+// GetMatchIDs retrieves the IDs of a player's most recent matches from opgl-data service
+// using PUUID, without the match data. A player without matches follows the empty-matches
+// policy like GetMatchesByPUUID.
+func (proxy *ServiceProxy) GetMatchIDs(ctx context.Context, region string, puuid string, count int) ([]string, error) {
+	target := proxy.dataTarget("/api/v1/matches/ids")
+
+	requestBody := map[string]interface{}{
+		"region": region,
+		"puuid":  puuid,
+		"count":  count,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, apierrors.InternalError("Failed to prepare request")
+	}
+
+	response, err := proxy.postWithRetry(ctx, CallGetMatchIDs, target, jsonData)
+	if err != nil {
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+	defer response.Body.Close()
+
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
+	// A PUUID is already resolved, so a 404 means the player has no matches
+	if response.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(response.Body)
+		return proxy.noMatchIDs(response.StatusCode, body)
+	}
+
+	// Handle different status codes from data service
+	if response.StatusCode != http.StatusOK {
+		return nil, proxy.handleDataServiceErrorByPUUID(response)
+	}
+
+	var matchIDs []string
+	if err := json.NewDecoder(response.Body).Decode(&matchIDs); err != nil {
+		return nil, apierrors.InternalError("Failed to process match IDs")
+	}
+	if len(matchIDs) == 0 {
+		return proxy.noMatchIDs(response.StatusCode, nil)
+	}
+
+	return matchIDs, nil
+}
+
+// GetRankedStats retrieves ranked queue entries from opgl-data service using PUUID
+func (proxy *ServiceProxy) GetRankedStats(ctx context.Context, region string, puuid string) ([]models.RankedStats, error) {
+	target := proxy.dataTarget("/api/v1/ranked")
+
+	requestBody := map[string]string{
+		"region": region,
+		"puuid":  puuid,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, apierrors.InternalError("Failed to prepare request")
+	}
+
+	response, err := proxy.postWithRetry(ctx, CallGetRankedStats, target, jsonData)
+	if err != nil {
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+	defer response.Body.Close()
+
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
+	// A player with no ranked entries is unranked rather than an error
+	if response.StatusCode == http.StatusNotFound {
+		return []models.RankedStats{}, nil
+	}
+
+	// Handle different status codes from data service
+	if response.StatusCode != http.StatusOK {
+		return nil, proxy.handleDataServiceErrorByPUUID(response)
+	}
+
+	var rankedStatsResponse models.RankedStatsResponse
+	if err := json.NewDecoder(response.Body).Decode(&rankedStatsResponse); err != nil {
+		return nil, apierrors.InternalError("Failed to process ranked data")
+	}
+
+	return rankedStatsResponse.RankedStats, nil
+}
+
+// GetActiveGame retrieves the game a player is currently in from opgl-data service. A 404
+// means the player is not in a game and is returned as nil rather than an error.
+func (proxy *ServiceProxy) GetActiveGame(ctx context.Context, region string, puuid string) (*models.LiveGame, error) {
+	target := proxy.dataTarget("/api/v1/active-game")
+
+	requestBody := map[string]string{
+		"region": region,
+		"puuid":  puuid,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, apierrors.InternalError("Failed to prepare request")
+	}
+
+	response, err := proxy.postWithRetry(ctx, CallGetActiveGame, target, jsonData)
+	if err != nil {
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+	defer response.Body.Close()
+
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
+	// A player who is not in a game is not an error
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	// Handle different status codes from data service
+	if response.StatusCode != http.StatusOK {
+		return nil, proxy.handleDataServiceErrorByPUUID(response)
+	}
+
+	var liveGame models.LiveGame
+	if err := json.NewDecoder(response.Body).Decode(&liveGame); err != nil {
+		return nil, apierrors.InternalError("Failed to process live game data")
+	}
+
+	return &liveGame, nil
+}
+
+// GetMatchTimeline retrieves the minute-by-minute timeline of a match from opgl-data service
+func (proxy *ServiceProxy) GetMatchTimeline(ctx context.Context, region string, matchID string) (*models.MatchTimeline, error) {
+	target := proxy.dataTarget("/api/v1/match/timeline")
+
+	requestBody := map[string]string{
+		"region":  region,
+		"matchId": matchID,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, apierrors.InternalError("Failed to prepare request")
+	}
+
+	response, err := proxy.postWithRetry(ctx, CallGetMatchTimeline, target, jsonData)
+	if err != nil {
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+	defer response.Body.Close()
+
+	if apiError := checkJSONResponse(ctx, response, apierrors.DataServiceError); apiError != nil {
+		return nil, apiError
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(response.Body)
+		return nil, apierrors.MatchNotFound(matchID).WithUpstream(response.StatusCode, body)
+	}
+
+	// Handle different status codes from data service
+	if response.StatusCode != http.StatusOK {
+		return nil, proxy.handleDataServiceErrorByPUUID(response)
+	}
+
+	var timeline models.MatchTimeline
+	if err := json.NewDecoder(response.Body).Decode(&timeline); err != nil {
+		return nil, apierrors.InternalError("Failed to process timeline data")
+	}
+
+	return &timeline, nil
+}
+
+// AnalyzePlayer sends analysis request to opgl-cortex-engine. A non-empty locale is sent
+// so cortex writes coaching text in that language.
+func (proxy *ServiceProxy) AnalyzePlayer(ctx context.Context, summoner *models.Summoner, matches []models.Match, locale string) (*models.AnalysisResult, error) {
 	requestBody := map[string]interface{}{
 		"summoner": summoner,
 		"matches":  matches,
 	}
+	if locale != "" {
+		requestBody["locale"] = locale
+	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, apierrors.InternalError("Failed to prepare request")
 	}
 
-	url := proxy.cortexServiceURL + "/api/v1/analyze"
-	response, err := proxy.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	response, err := proxy.postCortexWithFallback(ctx, CallAnalyzePlayer, "/api/v1/analyze", jsonData)
 	if err != nil {
-		return nil, apierrors.CortexServiceError("Unable to connect to analysis service")
+		return nil, connectionError(err, apierrors.CortexServiceError("Unable to connect to analysis service"))
 	}
 	defer response.Body.Close()
 
+	if apiError := checkJSONResponse(ctx, response, apierrors.CortexServiceError); apiError != nil {
+		return nil, apiError
+	}
+
 	// Handle different status codes from cortex service
 	if response.StatusCode != http.StatusOK {
 		return nil, proxy.handleCortexServiceError(response)
@@ -161,17 +500,245 @@ func (proxy *ServiceProxy) AnalyzePlayer(summoner *models.Summoner, matches []mo
 	return &analysisResult, nil
 }
 
+// CheckCortexHealth pings the opgl-cortex-engine health endpoint without running an analysis.
+// Any non-2xx response, or failure to connect, is reported as a cortex service error.
+func (proxy *ServiceProxy) CheckCortexHealth(ctx context.Context) error {
+	statusCode, err := proxy.probe(ctx, CallCheckCortexHealth, proxy.cortexURL("/health"))
+	if err != nil {
+		return connectionError(err, apierrors.CortexServiceError("Unable to connect to analysis service"))
+	}
+
+	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		return apierrors.CortexServiceError(fmt.Sprintf("Analysis service is not healthy (status %d)", statusCode))
+	}
+
+	return nil
+}
+
+// CheckDataHealth pings the health endpoint of every opgl-data endpoint (the primary and any
+// replicas) concurrently. It succeeds when at least one is healthy, since the balancer can
+// then route traffic to it; otherwise the primary's failure (a non-2xx response, or failure to
+// connect) is reported as a data service error. The probes do not affect replica health scores.
+func (proxy *ServiceProxy) CheckDataHealth(ctx context.Context) error {
+	baseURLs := proxy.dataBaseURLs()
+	endpointErrors := make([]error, len(baseURLs))
+
+	var waitGroup sync.WaitGroup
+	for index, baseURL := range baseURLs {
+		waitGroup.Add(1)
+		go func(index int, url string) {
+			defer waitGroup.Done()
+			endpointErrors[index] = proxy.checkDataEndpointHealth(ctx, url)
+		}(index, baseURL+proxy.dataPathPrefix+"/health")
+	}
+	waitGroup.Wait()
+
+	for _, err := range endpointErrors {
+		if err == nil {
+			return nil
+		}
+	}
+	return endpointErrors[0]
+}
+
+// checkDataEndpointHealth pings one opgl-data health URL
+func (proxy *ServiceProxy) checkDataEndpointHealth(ctx context.Context, url string) error {
+	statusCode, err := proxy.probe(ctx, CallCheckDataHealth, url)
+	if err != nil {
+		return connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+
+	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		return apierrors.DataServiceError(fmt.Sprintf("Data service is not healthy (status %d)", statusCode))
+	}
+
+	return nil
+}
+
+// dataBaseURLs returns the base URL of every opgl-data endpoint, the primary first
+func (proxy *ServiceProxy) dataBaseURLs() []string {
+	if proxy.dataBalancer == nil {
+		return []string{proxy.dataServiceURL}
+	}
+
+	baseURLs := make([]string, len(proxy.dataBalancer.endpoints))
+	for index, endpoint := range proxy.dataBalancer.endpoints {
+		baseURLs[index] = endpoint.baseURL
+	}
+	return baseURLs
+}
+
+// applyMatchFilter adds the optional match filter fields to an opgl-data request body
+func applyMatchFilter(requestBody map[string]interface{}, filter models.MatchFilter) {
+	if filter.Cursor != nil {
+		requestBody["cursor"] = filter.Cursor
+	}
+	if filter.StartTime != nil {
+		requestBody["startTime"] = *filter.StartTime
+	}
+	if filter.EndTime != nil {
+		requestBody["endTime"] = *filter.EndTime
+	}
+}
+
+// post sends a JSON POST request to an upstream service, bound to the caller's context. A
+// non-empty contentEncoding declares that the body is already encoded (e.g. gzip). Each
+// attempt is counted by service, the call's operation and the response status code, and
+// logged with its duration and outcome.
+func (proxy *ServiceProxy) post(ctx context.Context, call UpstreamCall, url string, jsonData []byte, contentEncoding string) (*http.Response, error) {
+	requestContext := ctx
+	var trace *connectionTrace
+	if proxy.connectionTrace {
+		trace = newConnectionTrace()
+		requestContext = trace.withContext(ctx)
+	}
+
+	request, err := http.NewRequestWithContext(requestContext, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if contentEncoding != "" {
+		request.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	service := proxy.serviceName(url)
+
+	startTime := time.Now()
+	response, err := proxy.httpClient.Do(request)
+	if trace != nil {
+		proxy.recordConnectionTrace(ctx, service, url, trace)
+	}
+	if err != nil {
+		proxy.upstreamMetrics.Call(service, call.operation(), 0)
+		// A call cut short by the caller's own cancellation or deadline says nothing about
+		// the upstream, so it is neither counted as an upstream error nor held against the endpoint
+		if ctx.Err() == nil {
+			proxy.upstreamMetrics.Error(service)
+			proxy.recordEndpointHealth(service, url, 0, time.Since(startTime))
+		}
+		logUpstreamCall(ctx, service, call, url, 0, time.Since(startTime), err)
+		return nil, err
+	}
+	proxy.recordEndpointHealth(service, url, response.StatusCode, time.Since(startTime))
+	logUpstreamCall(ctx, service, call, url, response.StatusCode, time.Since(startTime), nil)
+
+	proxy.upstreamMetrics.Call(service, call.operation(), response.StatusCode)
+	if response.StatusCode >= http.StatusInternalServerError {
+		proxy.upstreamMetrics.Error(service)
+	}
+	middleware.RecordUpstreamRequestID(ctx, service, middleware.UpstreamRequestID(response))
+
+	return response, nil
+}
+
+// probe sends an empty JSON POST to a health endpoint and returns the response status, with
+// the body drained so the connection goes back to the pool. Unlike post, a probe is logged
+// but not counted in upstream call or error metrics and does not affect replica health
+// scores: probes run on a schedule rather than for client traffic, and would skew both.
+func (proxy *ServiceProxy) probe(ctx context.Context, call UpstreamCall, url string) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("{}"))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	service := proxy.serviceName(url)
+
+	startTime := time.Now()
+	response, err := proxy.httpClient.Do(request)
+	if err != nil {
+		logUpstreamCall(ctx, service, call, url, 0, time.Since(startTime), err)
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+	logUpstreamCall(ctx, service, call, url, response.StatusCode, time.Since(startTime), nil)
+
+	return response.StatusCode, nil
+}
+
+// recordEndpointHealth updates the health score of the load-balanced data endpoint a call
+// went to and reports it in gateway_upstream_endpoint_score
+func (proxy *ServiceProxy) recordEndpointHealth(service string, url string, statusCode int, duration time.Duration) {
+	if proxy.dataBalancer == nil || service != metrics.UpstreamData {
+		return
+	}
+	if baseURL, score, found := proxy.dataBalancer.record(url, statusCode, duration); found {
+		proxy.upstreamMetrics.SetEndpointScore(service, baseURL, score)
+	}
+}
+
+// serviceName labels an upstream URL as "cortex" or "data" for tracing and metrics
+func (proxy *ServiceProxy) serviceName(url string) string {
+	if strings.HasPrefix(url, proxy.cortexServiceURL) || (proxy.fallbackCortexURL != "" && strings.HasPrefix(url, proxy.fallbackCortexURL)) {
+		return metrics.UpstreamCortex
+	}
+	return metrics.UpstreamData
+}
+
+// ForwardToDataService forwards a raw JSON body to an opgl-data path and returns the
+// upstream response unmodified. The caller is responsible for closing the response body.
+func (proxy *ServiceProxy) ForwardToDataService(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	response, err := proxy.postWithRetry(ctx, CallForwardToDataService, proxy.dataTarget(path), body)
+	if err != nil {
+		return nil, connectionError(err, apierrors.DataServiceError("Unable to connect to data service"))
+	}
+
+	// Passthrough routes may legitimately return non-JSON bodies, so the response is not checked
+	return response, nil
+}
+
+// maxLoggedBodyBytes limits how much of an unexpected upstream body is logged
+const maxLoggedBodyBytes = 512
+
+// checkJSONResponse rejects 2xx and 5xx upstream responses that declare a non-JSON
+// Content-Type, such as an HTML error page from an intermediate reverse proxy: a 2xx body is
+// about to be decoded and a 5xx body formatted into the error. The start of the body is logged
+// for debugging but never included in the client-facing error. Other statuses (e.g. a plain
+// text 404 from http.NotFound) are left to the caller's status mapping, and responses without
+// a Content-Type are given the benefit of the doubt.
+func checkJSONResponse(ctx context.Context, response *http.Response, newError func(message string) *apierrors.APIError) *apierrors.APIError {
+	if response.StatusCode >= http.StatusMultipleChoices && response.StatusCode < http.StatusInternalServerError {
+		return nil
+	}
+
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" || isJSONContentType(contentType) {
+		return nil
+	}
+
+	bodySnippet, _ := io.ReadAll(io.LimitReader(response.Body, maxLoggedBodyBytes))
+	logger := middleware.LoggerFromContext(ctx)
+	logger.Warn().
+		Int("upstream_status", response.StatusCode).
+		Str("content_type", contentType).
+		Str("body_snippet", string(bodySnippet)).
+		Msg("Upstream returned non-JSON response")
+
+	return newError("upstream returned non-JSON response")
+}
+
+// isJSONContentType reports whether a Content-Type header denotes JSON (application/json or +json)
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 // handleDataServiceError converts data service HTTP errors to APIErrors
 func (proxy *ServiceProxy) handleDataServiceError(response *http.Response, gameName string, tagLine string) *apierrors.APIError {
 	body, _ := io.ReadAll(response.Body)
 
 	switch response.StatusCode {
 	case http.StatusNotFound:
-		return apierrors.PlayerNotFound(gameName, tagLine)
+		return apierrors.PlayerNotFound(gameName, tagLine).WithUpstream(response.StatusCode, body)
 	case http.StatusBadRequest:
-		return apierrors.InvalidRequestBody(string(body))
+		return apierrors.InvalidRequestBody(string(body)).WithUpstream(response.StatusCode, body)
 	default:
-		return apierrors.DataServiceError("Data service error: " + string(body))
+		return apierrors.DataServiceError("Data service error: "+string(body)).WithUpstream(response.StatusCode, body)
 	}
 }
 
@@ -181,11 +748,11 @@ func (proxy *ServiceProxy) handleDataServiceErrorByPUUID(response *http.Response
 
 	switch response.StatusCode {
 	case http.StatusNotFound:
-		return apierrors.MatchesNotFound("No matches found for this player")
+		return apierrors.MatchesNotFound("No matches found for this player").WithUpstream(response.StatusCode, body)
 	case http.StatusBadRequest:
-		return apierrors.InvalidRequestBody(string(body))
+		return apierrors.InvalidRequestBody(string(body)).WithUpstream(response.StatusCode, body)
 	default:
-		return apierrors.DataServiceError("Data service error: " + string(body))
+		return apierrors.DataServiceError("Data service error: "+string(body)).WithUpstream(response.StatusCode, body)
 	}
 }
 
@@ -195,8 +762,8 @@ func (proxy *ServiceProxy) handleCortexServiceError(response *http.Response) *ap
 
 	switch response.StatusCode {
 	case http.StatusBadRequest:
-		return apierrors.InvalidRequestBody(string(body))
+		return apierrors.InvalidRequestBody(string(body)).WithUpstream(response.StatusCode, body)
 	default:
-		return apierrors.CortexServiceError("Analysis service error: " + string(body))
+		return apierrors.CortexServiceError("Analysis service error: "+string(body)).WithUpstream(response.StatusCode, body)
 	}
 }
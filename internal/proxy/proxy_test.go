@@ -1,11 +1,18 @@
 package proxy
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
 	"github.com/OPGLOL/opgl-gateway-service/internal/models"
 )
 
@@ -61,7 +68,7 @@ func TestGetSummonerByRiotID_Success(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	summoner, err := proxy.GetSummonerByRiotID("na", "TestPlayer", "NA1")
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -81,7 +88,7 @@ func TestGetSummonerByRiotID_ServerError(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	summoner, err := proxy.GetSummonerByRiotID("na", "TestPlayer", "NA1")
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -97,7 +104,7 @@ func TestGetSummonerByRiotID_ConnectionError(t *testing.T) {
 	// Use invalid URL to simulate connection error
 	proxy := NewServiceProxy("http://localhost:99999", "http://localhost:8082")
 
-	summoner, err := proxy.GetSummonerByRiotID("na", "TestPlayer", "NA1")
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -118,7 +125,7 @@ func TestGetSummonerByRiotID_InvalidJSON(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	summoner, err := proxy.GetSummonerByRiotID("na", "TestPlayer", "NA1")
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
 
 	if err == nil {
 		t.Error("Expected error for invalid JSON, got nil")
@@ -148,7 +155,7 @@ func TestGetMatchesByRiotID_Success(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	matches, err := proxy.GetMatchesByRiotID("na", "TestPlayer", "NA1", 10)
+	matches, err := proxy.GetMatchesByRiotID(context.Background(), "na", "TestPlayer", "NA1", 10, models.MatchFilter{})
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -168,7 +175,7 @@ func TestGetMatchesByRiotID_ServerError(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	matches, err := proxy.GetMatchesByRiotID("na", "TestPlayer", "NA1", 10)
+	matches, err := proxy.GetMatchesByRiotID(context.Background(), "na", "TestPlayer", "NA1", 10, models.MatchFilter{})
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -193,7 +200,7 @@ func TestGetMatchesByPUUID_Success(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	matches, err := proxy.GetMatchesByPUUID("na", "test-puuid", 20)
+	matches, err := proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, models.MatchFilter{})
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -213,7 +220,7 @@ func TestGetMatchesByPUUID_ServerError(t *testing.T) {
 
 	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
 
-	matches, err := proxy.GetMatchesByPUUID("na", "test-puuid", 20)
+	matches, err := proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, models.MatchFilter{})
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -246,7 +253,7 @@ func TestAnalyzePlayer_Success(t *testing.T) {
 	summoner := &models.Summoner{PUUID: "test-puuid"}
 	matches := []models.Match{{MatchID: "NA1_123"}}
 
-	result, err := proxy.AnalyzePlayer(summoner, matches)
+	result, err := proxy.AnalyzePlayer(context.Background(), summoner, matches, "")
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -257,6 +264,121 @@ func TestAnalyzePlayer_Success(t *testing.T) {
 	}
 }
 
+// TestAnalyzePlayer_Locale tests that the locale is included in the cortex request body
+func TestAnalyzePlayer_Locale(t *testing.T) {
+	var requestBody map[string]interface{}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(models.AnalysisResult{})
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy("http://localhost:8081", mockServer.URL)
+
+	if _, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, nil, "ko"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requestBody["locale"] != "ko" {
+		t.Errorf("Expected locale 'ko' in cortex request body, got %v", requestBody["locale"])
+	}
+}
+
+// TestUpstreamTrace_ListsUpstreamRequestIDs tests that upstream request IDs reach X-Upstream-Trace
+func TestUpstreamTrace_ListsUpstreamRequestIDs(t *testing.T) {
+	dataServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Request-ID", "data-req-1")
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(models.Summoner{PUUID: "test-puuid"})
+	}))
+	defer dataServer.Close()
+
+	cortexServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Upstream-Request-ID", "cortex-req-1")
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(models.AnalysisResult{})
+	}))
+	defer cortexServer.Close()
+
+	proxy := NewServiceProxy(dataServer.URL, cortexServer.URL)
+
+	handler := middleware.UpstreamTraceMiddleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		summoner, err := proxy.GetSummonerByRiotID(request.Context(), "na1", "Faker", "KR1")
+		if err != nil {
+			t.Fatalf("Unexpected summoner error: %v", err)
+		}
+		if _, err := proxy.AnalyzePlayer(request.Context(), summoner, []models.Match{}, ""); err != nil {
+			t.Fatalf("Unexpected analyze error: %v", err)
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/api/v1/analyze", nil))
+
+	expected := "data=data-req-1, cortex=cortex-req-1"
+	if got := responseRecorder.Header().Get(middleware.UpstreamTraceHeader); got != expected {
+		t.Errorf("Expected X-Upstream-Trace '%s', got '%s'", expected, got)
+	}
+}
+
+// TestUpstreamMetrics_CountsErrors tests that 5xx responses and connection failures are counted per service
+func TestUpstreamMetrics_CountsErrors(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:99999", WithUpstreamMetrics(upstreamMetrics))
+
+	proxy.GetSummonerByRiotID(context.Background(), "na1", "Faker", "KR1")
+	proxy.AnalyzePlayer(context.Background(), &models.Summoner{}, nil, "")
+
+	if upstreamMetrics.Errors(metrics.UpstreamData) != 1 {
+		t.Errorf("Expected 1 data error, got %g", upstreamMetrics.Errors(metrics.UpstreamData))
+	}
+	if upstreamMetrics.Errors(metrics.UpstreamCortex) != 1 {
+		t.Errorf("Expected 1 cortex error, got %g", upstreamMetrics.Errors(metrics.UpstreamCortex))
+	}
+}
+
+// TestUpstreamMetrics_CountsCallsByOperationAndStatus tests that upstream calls are labeled by
+// service, operation and status code
+func TestUpstreamMetrics_CountsCallsByOperationAndStatus(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.URL.Path == "/api/v1/summoner" {
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		json.NewEncoder(writer).Encode([]models.Match{{MatchID: "NA1_123"}})
+	}))
+	defer mockServer.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:99999", WithUpstreamMetrics(upstreamMetrics))
+
+	proxy.GetSummonerByRiotID(context.Background(), "na1", "Faker", "KR1")
+	proxy.GetMatchesByRiotID(context.Background(), "na1", "Faker", "KR1", 10, models.MatchFilter{})
+	proxy.AnalyzePlayer(context.Background(), &models.Summoner{}, nil, "")
+
+	if calls := upstreamMetrics.Calls(metrics.UpstreamData, "summoner", "404"); calls != 1 {
+		t.Errorf("Expected 1 summoner 404 call, got %g", calls)
+	}
+	if calls := upstreamMetrics.Calls(metrics.UpstreamData, "matches", "200"); calls != 1 {
+		t.Errorf("Expected 1 matches 200 call, got %g", calls)
+	}
+	if calls := upstreamMetrics.Calls(metrics.UpstreamData, "summoner", "200"); calls != 0 {
+		t.Errorf("Expected no summoner 200 calls, got %g", calls)
+	}
+	if calls := upstreamMetrics.Calls(metrics.UpstreamCortex, "analyze", metrics.StatusCodeError); calls != 1 {
+		t.Errorf("Expected 1 failed analyze call, got %g", calls)
+	}
+}
+
 // TestAnalyzePlayer_ServerError tests server error handling
 func TestAnalyzePlayer_ServerError(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -269,7 +391,7 @@ func TestAnalyzePlayer_ServerError(t *testing.T) {
 	summoner := &models.Summoner{PUUID: "test-puuid"}
 	matches := []models.Match{{MatchID: "NA1_123"}}
 
-	result, err := proxy.AnalyzePlayer(summoner, matches)
+	result, err := proxy.AnalyzePlayer(context.Background(), summoner, matches, "")
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -287,7 +409,7 @@ func TestAnalyzePlayer_ConnectionError(t *testing.T) {
 	summoner := &models.Summoner{PUUID: "test-puuid"}
 	matches := []models.Match{{MatchID: "NA1_123"}}
 
-	result, err := proxy.AnalyzePlayer(summoner, matches)
+	result, err := proxy.AnalyzePlayer(context.Background(), summoner, matches, "")
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -306,3 +428,714 @@ func TestServiceProxyImplementsInterface(t *testing.T) {
 		t.Error("ServiceProxy should implement ServiceProxyInterface")
 	}
 }
+
+// roundTripperFunc adapts a function into an http.RoundTripper
+type roundTripperFunc func(request *http.Request) (*http.Response, error)
+
+func (roundTripper roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return roundTripper(request)
+}
+
+// newCannedResponse builds a JSON response for use in fake round trippers
+func newCannedResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// TestNewServiceProxy_DefaultTransport tests that the tuned transport is used by default
+func TestNewServiceProxy_DefaultTransport(t *testing.T) {
+	proxy := NewServiceProxy("http://localhost:8081", "http://localhost:8082")
+
+	transport, ok := proxy.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected default transport to be *http.Transport, got %T", proxy.httpClient.Transport)
+	}
+
+	if transport.MaxIdleConnsPerHost <= 0 {
+		t.Error("Expected default transport to keep idle connections per host")
+	}
+}
+
+// TestWithTransport_FakeRoundTripper tests proxy calls against an injected RoundTripper without a test server
+func TestWithTransport_FakeRoundTripper(t *testing.T) {
+	var requestedURL string
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		requestedURL = request.URL.String()
+		return newCannedResponse(http.StatusOK, `{"puuid":"fake-puuid","name":"TestPlayer"}`), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requestedURL != "http://data.internal/api/v1/summoner" {
+		t.Errorf("Expected request to 'http://data.internal/api/v1/summoner', got '%s'", requestedURL)
+	}
+
+	if summoner.PUUID != "fake-puuid" {
+		t.Errorf("Expected PUUID 'fake-puuid', got '%s'", summoner.PUUID)
+	}
+}
+
+// TestWithTransport_FakeRoundTripperNotFound tests error mapping with a canned 404 response
+func TestWithTransport_FakeRoundTripperNotFound(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newCannedResponse(http.StatusNotFound, `{"error":"not found"}`), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodePlayerNotFound {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodePlayerNotFound, apiError.Code)
+	}
+}
+
+// TestForwardToDataService_Success tests that the raw body is forwarded to the given path
+func TestForwardToDataService_Success(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v1/ranked" {
+			t.Errorf("Expected path '/api/v1/ranked', got '%s'", request.URL.Path)
+		}
+
+		body, _ := io.ReadAll(request.Body)
+		if string(body) != `{"region":"na"}` {
+			t.Errorf("Expected forwarded body, got '%s'", string(body))
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusAccepted)
+		writer.Write([]byte(`{"ok":true}`))
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+
+	response, err := proxy.ForwardToDataService(context.Background(), "/api/v1/ranked", []byte(`{"region":"na"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected upstream status %d to be preserved, got %d", http.StatusAccepted, response.StatusCode)
+	}
+}
+
+// TestForwardToDataService_ConnectionError tests connection error handling
+func TestForwardToDataService_ConnectionError(t *testing.T) {
+	proxy := NewServiceProxy("http://localhost:99999", "http://localhost:8082")
+
+	response, err := proxy.ForwardToDataService(context.Background(), "/api/v1/ranked", []byte(`{}`))
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if response != nil {
+		t.Error("Expected response to be nil on error")
+	}
+}
+
+// TestGetMatchesByPUUID_ForwardsCursor tests that the pagination cursor is forwarded to opgl-data
+func TestGetMatchesByPUUID_ForwardsCursor(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var requestBody map[string]interface{}
+		json.NewDecoder(request.Body).Decode(&requestBody)
+
+		cursor, ok := requestBody["cursor"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected cursor object in request body, got %v", requestBody["cursor"])
+		}
+
+		if cursor["matchId"] != "NA1_123" || cursor["timestamp"] != float64(1700000000000) {
+			t.Errorf("Unexpected forwarded cursor: %v", cursor)
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte("[]"))
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+
+	filter := models.MatchFilter{Cursor: &models.MatchCursor{Timestamp: 1700000000000, MatchID: "NA1_123"}}
+	if _, err := proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, filter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// TestGetMatchesByRiotID_ForwardsTimeRange tests that startTime/endTime bounds are forwarded to opgl-data
+func TestGetMatchesByRiotID_ForwardsTimeRange(t *testing.T) {
+	var requestBody map[string]interface{}
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		return newCannedResponse(http.StatusOK, "[]"), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	startTime := int64(1700000000)
+	endTime := int64(1700600000)
+	filter := models.MatchFilter{StartTime: &startTime, EndTime: &endTime}
+	if _, err := proxy.GetMatchesByRiotID(context.Background(), "na", "TestPlayer", "NA1", 20, filter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requestBody["startTime"] != float64(startTime) || requestBody["endTime"] != float64(endTime) {
+		t.Errorf("Expected forwarded time range, got startTime=%v endTime=%v", requestBody["startTime"], requestBody["endTime"])
+	}
+}
+
+// TestGetMatchesByPUUID_OmitsUnsetTimeRange tests that absent bounds are not sent upstream
+func TestGetMatchesByPUUID_OmitsUnsetTimeRange(t *testing.T) {
+	var requestBody map[string]interface{}
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		return newCannedResponse(http.StatusOK, "[]"), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	if _, err := proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, models.MatchFilter{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, present := requestBody["startTime"]; present {
+		t.Error("Expected startTime to be omitted when unset")
+	}
+	if _, present := requestBody["endTime"]; present {
+		t.Error("Expected endTime to be omitted when unset")
+	}
+}
+
+// TestWithPathPrefixes_AppliedToEachMethod tests that configured prefixes are prepended to every upstream URL
+func TestWithPathPrefixes_AppliedToEachMethod(t *testing.T) {
+	var requestedURLs []string
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		requestedURLs = append(requestedURLs, request.URL.String())
+		if strings.HasSuffix(request.URL.Path, "/matches") {
+			return newCannedResponse(http.StatusOK, "[]"), nil
+		}
+		return newCannedResponse(http.StatusOK, "{}"), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithTransport(fakeTransport),
+		WithPathPrefixes("/data-service/", "cortex"),
+	)
+
+	ctx := context.Background()
+	proxy.GetSummonerByRiotID(ctx, "na", "TestPlayer", "NA1")
+	proxy.GetMatchesByRiotID(ctx, "na", "TestPlayer", "NA1", 20, models.MatchFilter{})
+	proxy.GetMatchesByPUUID(ctx, "na", "test-puuid", 20, models.MatchFilter{})
+	proxy.AnalyzePlayer(ctx, &models.Summoner{}, nil, "")
+	if response, err := proxy.ForwardToDataService(ctx, "/api/v1/ranked", []byte(`{}`)); err == nil {
+		response.Body.Close()
+	}
+
+	expectedURLs := []string{
+		"http://data.internal/data-service/api/v1/summoner",
+		"http://data.internal/data-service/api/v1/matches",
+		"http://data.internal/data-service/api/v1/matches",
+		"http://cortex.internal/cortex/api/v1/analyze",
+		"http://data.internal/data-service/api/v1/ranked",
+	}
+
+	if len(requestedURLs) != len(expectedURLs) {
+		t.Fatalf("Expected %d upstream calls, got %d: %v", len(expectedURLs), len(requestedURLs), requestedURLs)
+	}
+
+	for index, expectedURL := range expectedURLs {
+		if requestedURLs[index] != expectedURL {
+			t.Errorf("Call %d: expected URL '%s', got '%s'", index, expectedURL, requestedURLs[index])
+		}
+	}
+}
+
+// TestNormalizePathPrefix tests normalization of configured path prefixes
+func TestNormalizePathPrefix(t *testing.T) {
+	testCases := map[string]string{
+		"":               "",
+		"/":              "",
+		"data-service":   "/data-service",
+		"/data-service/": "/data-service",
+		"/a/b":           "/a/b",
+	}
+
+	for input, expected := range testCases {
+		if actual := normalizePathPrefix(input); actual != expected {
+			t.Errorf("normalizePathPrefix(%q): expected '%s', got '%s'", input, expected, actual)
+		}
+	}
+}
+
+// htmlErrorPage is a typical reverse proxy error page
+const htmlErrorPage = `<html><head><title>502 Bad Gateway</title></head><body><center><h1>502 Bad Gateway</h1></center><hr><center>nginx</center></body></html>`
+
+// newHTMLResponse builds an HTML response for use in fake round trippers
+func newHTMLResponse(statusCode int) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(htmlErrorPage)),
+	}
+}
+
+// TestNonJSONResponse_HTMLErrorPage tests that an HTML error page yields a clean error without the HTML
+func TestNonJSONResponse_HTMLErrorPage(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newHTMLResponse(http.StatusBadGateway), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodeDataServiceError {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeDataServiceError, apiError.Code)
+	}
+
+	if apiError.Message != "upstream returned non-JSON response" {
+		t.Errorf("Expected clean non-JSON message, got '%s'", apiError.Message)
+	}
+}
+
+// TestNonJSONResponse_HTMLWithSuccessStatus tests that an HTML 200 is not decoded as data
+func TestNonJSONResponse_HTMLWithSuccessStatus(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newHTMLResponse(http.StatusOK), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	_, err := proxy.GetMatchesByPUUID(context.Background(), "na", "test-puuid", 20, models.MatchFilter{})
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if strings.Contains(apiError.Message, "<html>") {
+		t.Errorf("Expected HTML to be kept out of the error message, got '%s'", apiError.Message)
+	}
+}
+
+// TestNonJSONResponse_Cortex tests that cortex HTML responses map to a cortex service error
+func TestNonJSONResponse_Cortex(t *testing.T) {
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return newHTMLResponse(http.StatusBadGateway), nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	_, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{}, nil, "")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodeCortexServiceError {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeCortexServiceError, apiError.Code)
+	}
+}
+
+// TestNonJSONResponse_PlainTextNotFound tests that a text/plain 404, as written by
+// http.NotFound, still maps to the not-found errors rather than a service error
+func TestNonJSONResponse_PlainTextNotFound(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.NotFound(writer, request)
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:99999")
+
+	_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+	if apiError, ok := err.(*apierrors.APIError); !ok || apiError.Code != apierrors.ErrCodePlayerNotFound {
+		t.Errorf("Expected %s for a summoner, got %v", apierrors.ErrCodePlayerNotFound, err)
+	}
+
+	_, err = proxy.GetMatchTimeline(context.Background(), "na", "NA1_1")
+	if apiError, ok := err.(*apierrors.APIError); !ok || apiError.Code != apierrors.ErrCodeMatchNotFound {
+		t.Errorf("Expected %s for a timeline, got %v", apierrors.ErrCodeMatchNotFound, err)
+	}
+}
+
+// TestForwardToDataService_NonJSON tests that passthrough responses are returned whatever their content type
+func TestForwardToDataService_NonJSON(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/csv")
+		writer.Write([]byte("champion,games\nAhri,12\n"))
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:99999")
+
+	response, err := proxy.ForwardToDataService(context.Background(), "/api/v1/export", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Expected the response to be forwarded, got %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.Header.Get("Content-Type") != "text/csv" {
+		t.Errorf("Expected Content-Type 'text/csv', got '%s'", response.Header.Get("Content-Type"))
+	}
+}
+
+// TestIsJSONContentType tests JSON content type detection
+func TestIsJSONContentType(t *testing.T) {
+	testCases := map[string]bool{
+		"application/json":                 true,
+		"application/json; charset=utf-8":  true,
+		"application/problem+json":         true,
+		"text/html; charset=utf-8":         false,
+		"text/plain":                       false,
+		"not a; valid;; media type=\"open": false,
+	}
+
+	for contentType, expected := range testCases {
+		if actual := isJSONContentType(contentType); actual != expected {
+			t.Errorf("isJSONContentType(%q): expected %v, got %v", contentType, expected, actual)
+		}
+	}
+}
+
+// TestCheckCortexHealth tests the cortex health ping for healthy, unhealthy and unreachable upstreams
+func TestCheckCortexHealth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{"healthy", http.StatusOK, false},
+		{"unhealthy", http.StatusServiceUnavailable, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if request.URL.Path != "/health" {
+					t.Errorf("Expected path '/health', got '%s'", request.URL.Path)
+				}
+				writer.WriteHeader(testCase.status)
+			}))
+			defer mockServer.Close()
+
+			proxy := NewServiceProxy("http://localhost:8081", mockServer.URL)
+			err := proxy.CheckCortexHealth(context.Background())
+
+			if (err != nil) != testCase.expectError {
+				t.Errorf("Expected error=%v, got %v", testCase.expectError, err)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		proxy := NewServiceProxy("http://localhost:8081", "http://127.0.0.1:1")
+		err := proxy.CheckCortexHealth(context.Background())
+
+		apiError, ok := err.(*apierrors.APIError)
+		if !ok || apiError.Code != apierrors.ErrCodeCortexServiceError {
+			t.Errorf("Expected cortex service error, got %v", err)
+		}
+	})
+}
+
+// TestCheckDataHealth tests the data service health ping for healthy, unhealthy and unreachable upstreams
+func TestCheckDataHealth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{"healthy", http.StatusOK, false},
+		{"unhealthy", http.StatusServiceUnavailable, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if request.URL.Path != "/health" {
+					t.Errorf("Expected path '/health', got '%s'", request.URL.Path)
+				}
+				writer.WriteHeader(testCase.status)
+			}))
+			defer mockServer.Close()
+
+			proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+			err := proxy.CheckDataHealth(context.Background())
+
+			if (err != nil) != testCase.expectError {
+				t.Errorf("Expected error=%v, got %v", testCase.expectError, err)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		proxy := NewServiceProxy("http://127.0.0.1:1", "http://localhost:8082")
+		err := proxy.CheckDataHealth(context.Background())
+
+		apiError, ok := err.(*apierrors.APIError)
+		if !ok || apiError.Code != apierrors.ErrCodeDataServiceError {
+			t.Errorf("Expected data service error, got %v", err)
+		}
+	})
+}
+
+// TestGetRankedStats tests ranked lookups, including unranked players reported as 404
+func TestGetRankedStats(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		body          string
+		expectedCount int
+		expectError   bool
+	}{
+		{"ranked", http.StatusOK, `{"rankedStats":[{"queueType":"RANKED_SOLO_5x5","tier":"GOLD"}]}`, 1, false},
+		{"unranked", http.StatusNotFound, `{"error":"not found"}`, 0, false},
+		{"upstream error", http.StatusInternalServerError, `{"error":"boom"}`, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if request.URL.Path != "/api/v1/ranked" {
+					t.Errorf("Expected path '/api/v1/ranked', got '%s'", request.URL.Path)
+				}
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(testCase.status)
+				writer.Write([]byte(testCase.body))
+			}))
+			defer mockServer.Close()
+
+			proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+			rankedStats, err := proxy.GetRankedStats(context.Background(), "na", "test-puuid")
+
+			if (err != nil) != testCase.expectError {
+				t.Fatalf("Expected error=%v, got %v", testCase.expectError, err)
+			}
+			if !testCase.expectError && (rankedStats == nil || len(rankedStats) != testCase.expectedCount) {
+				t.Errorf("Expected %d ranked entries, got %v", testCase.expectedCount, rankedStats)
+			}
+		})
+	}
+}
+
+// TestGetMatchIDs tests that a player's match IDs are fetched from opgl-data as a plain list
+func TestGetMatchIDs(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/match_ids.json")
+	if err != nil {
+		t.Fatalf("Failed to read match IDs fixture: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v1/matches/ids" {
+			t.Errorf("Expected path '/api/v1/matches/ids', got '%s'", request.URL.Path)
+		}
+
+		var requestBody map[string]interface{}
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		if requestBody["puuid"] != "test-puuid" || requestBody["region"] != "na" || requestBody["count"] != float64(3) {
+			t.Errorf("Unexpected request body: %v", requestBody)
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write(fixture)
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+	matchIDs, err := proxy.GetMatchIDs(context.Background(), "na", "test-puuid", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedIDs := []string{"NA1_4567890125", "NA1_4567890124", "NA1_4567890123"}
+	if strings.Join(matchIDs, ",") != strings.Join(expectedIDs, ",") {
+		t.Errorf("Expected match IDs %v, got %v", expectedIDs, matchIDs)
+	}
+}
+
+// TestGetMatchIDs_NoMatches tests that a 404 follows the empty-matches policy
+func TestGetMatchIDs_NoMatches(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte(`{"error":"no matches"}`))
+	}))
+	defer mockServer.Close()
+
+	matchIDs, err := NewServiceProxy(mockServer.URL, "http://localhost:8082").GetMatchIDs(context.Background(), "na", "test-puuid", 20)
+	if err != nil || matchIDs == nil || len(matchIDs) != 0 {
+		t.Errorf("Expected an empty list by default, got %v, %v", matchIDs, err)
+	}
+
+	notFoundProxy := NewServiceProxy(mockServer.URL, "http://localhost:8082", WithEmptyMatchesPolicy(EmptyMatchesAsNotFound))
+	_, err = notFoundProxy.GetMatchIDs(context.Background(), "na", "test-puuid", 20)
+	if apiErr, ok := err.(*apierrors.APIError); !ok || apiErr.Code != apierrors.ErrCodeMatchesNotFound {
+		t.Errorf("Expected MATCHES_NOT_FOUND under the not_found policy, got %v", err)
+	}
+}
+
+// TestGetActiveGame tests that an active game is decoded and a 404 means the player is not in a game
+func TestGetActiveGame(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         int
+		body           string
+		expectedGameID int64
+		expectNil      bool
+	}{
+		{"in game", http.StatusOK, `{"gameId":4567890123,"gameMode":"CLASSIC","queueId":420,"championId":103}`, 4567890123, false},
+		{"not in game", http.StatusNotFound, `{"error":"not in game"}`, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if request.URL.Path != "/api/v1/active-game" {
+					t.Errorf("Expected path '/api/v1/active-game', got '%s'", request.URL.Path)
+				}
+
+				var requestBody map[string]string
+				json.NewDecoder(request.Body).Decode(&requestBody)
+				if requestBody["puuid"] != "test-puuid" || requestBody["region"] != "na" {
+					t.Errorf("Unexpected request body: %v", requestBody)
+				}
+
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(testCase.status)
+				writer.Write([]byte(testCase.body))
+			}))
+			defer mockServer.Close()
+
+			proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+			liveGame, err := proxy.GetActiveGame(context.Background(), "na", "test-puuid")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if testCase.expectNil {
+				if liveGame != nil {
+					t.Errorf("Expected no live game, got %+v", liveGame)
+				}
+				return
+			}
+			if liveGame == nil || liveGame.GameID != testCase.expectedGameID || liveGame.QueueID != 420 {
+				t.Errorf("Unexpected live game: %+v", liveGame)
+			}
+		})
+	}
+}
+
+// TestGetActiveGame_ServerError tests that opgl-data failures other than 404 are errors
+func TestGetActiveGame_ServerError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+	if _, err := proxy.GetActiveGame(context.Background(), "na", "test-puuid"); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+}
+
+// TestGetMatchTimeline tests that a timeline from opgl-data is decoded into a MatchTimeline
+func TestGetMatchTimeline(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/match_timeline.json")
+	if err != nil {
+		t.Fatalf("Failed to read timeline fixture: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v1/match/timeline" {
+			t.Errorf("Expected path '/api/v1/match/timeline', got '%s'", request.URL.Path)
+		}
+
+		var requestBody map[string]string
+		json.NewDecoder(request.Body).Decode(&requestBody)
+		if requestBody["matchId"] != "NA1_4567890123" || requestBody["region"] != "na" {
+			t.Errorf("Unexpected request body: %v", requestBody)
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(fixture)
+	}))
+	defer mockServer.Close()
+
+	proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+	timeline, err := proxy.GetMatchTimeline(context.Background(), "na", "NA1_4567890123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if timeline.MatchID != "NA1_4567890123" || timeline.FrameInterval != 60000 {
+		t.Errorf("Unexpected timeline header: %+v", timeline)
+	}
+	if len(timeline.Frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %d", len(timeline.Frames))
+	}
+
+	participantFrame := timeline.Frames[1].ParticipantFrames["1"]
+	if participantFrame.Level != 2 || participantFrame.TotalGold != 650 || participantFrame.MinionsKilled != 4 {
+		t.Errorf("Unexpected participant frame: %+v", participantFrame)
+	}
+	if len(timeline.Frames[1].Events) != 2 || timeline.Frames[1].Events[0]["type"] != "ITEM_PURCHASED" {
+		t.Errorf("Unexpected events: %v", timeline.Frames[1].Events)
+	}
+}
+
+// TestGetMatchTimeline_Errors tests how opgl-data error statuses are mapped for timelines
+func TestGetMatchTimeline_Errors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		status       int
+		expectedCode apierrors.ErrorCode
+	}{
+		{"not found", http.StatusNotFound, apierrors.ErrCodeMatchNotFound},
+		{"bad request", http.StatusBadRequest, apierrors.ErrCodeInvalidRequestBody},
+		{"upstream error", http.StatusInternalServerError, apierrors.ErrCodeDataServiceError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(testCase.status)
+				writer.Write([]byte(`{"error":"failed"}`))
+			}))
+			defer mockServer.Close()
+
+			proxy := NewServiceProxy(mockServer.URL, "http://localhost:8082")
+			_, err := proxy.GetMatchTimeline(context.Background(), "na", "NA1_4567890123")
+
+			apiErr, ok := err.(*apierrors.APIError)
+			if !ok {
+				t.Fatalf("Expected *APIError, got %T", err)
+			}
+			if apiErr.Code != testCase.expectedCode {
+				t.Errorf("Expected error code '%s', got '%s'", testCase.expectedCode, apiErr.Code)
+			}
+		})
+	}
+}
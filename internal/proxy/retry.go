@@ -0,0 +1,193 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/backoff"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// retryPolicy controls how upstream calls are retried. The zero value disables retries.
+type retryPolicy struct {
+	// maxAttempts is the total number of attempts, including the first
+	maxAttempts int
+	// baseDelay is the backoff before the first retry; it doubles on each retry
+	baseDelay time.Duration
+	// maxRetryAfter caps how long an upstream 429 Retry-After is honored before failing fast
+	maxRetryAfter time.Duration
+}
+
+// WithRetries enables retries of upstream calls. Connection errors and 5xx responses are
+// retried with exponential backoff and jitter; 429 responses honor the upstream Retry-After
+// header, failing fast with ServiceUnavailable if it exceeds maxRetryAfter.
+func WithRetries(maxAttempts int, baseDelay time.Duration, maxRetryAfter time.Duration) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.retryPolicy = retryPolicy{
+			maxAttempts:   maxAttempts,
+			baseDelay:     baseDelay,
+			maxRetryAfter: maxRetryAfter,
+		}
+	}
+}
+
+// UpstreamCall identifies a proxy method whose upstream request may be retried
+type UpstreamCall string
+
+const (
+	CallGetSummonerByRiotID  UpstreamCall = "GetSummonerByRiotID"
+	CallGetMatchesByRiotID   UpstreamCall = "GetMatchesByRiotID"
+	CallGetMatchesByPUUID    UpstreamCall = "GetMatchesByPUUID"
+	CallGetMatchIDs          UpstreamCall = "GetMatchIDs"
+	CallGetRankedStats       UpstreamCall = "GetRankedStats"
+	CallGetMatchTimeline     UpstreamCall = "GetMatchTimeline"
+	CallGetActiveGame        UpstreamCall = "GetActiveGame"
+	CallAnalyzePlayer        UpstreamCall = "AnalyzePlayer"
+	CallForwardToDataService UpstreamCall = "ForwardToDataService"
+	CallCheckDataHealth      UpstreamCall = "CheckDataHealth"
+	CallCheckCortexHealth    UpstreamCall = "CheckCortexHealth"
+	CallWarmConnection       UpstreamCall = "WarmConnection"
+)
+
+// operation returns the "operation" metric label for a call. Calls that hit the same
+// upstream endpoint share an operation (e.g. both match lookups are "matches").
+func (call UpstreamCall) operation() string {
+	switch call {
+	case CallGetSummonerByRiotID:
+		return "summoner"
+	case CallGetMatchesByRiotID, CallGetMatchesByPUUID:
+		return "matches"
+	case CallGetMatchIDs:
+		return "match_ids"
+	case CallGetRankedStats:
+		return "ranked"
+	case CallGetMatchTimeline:
+		return "timeline"
+	case CallGetActiveGame:
+		return "active_game"
+	case CallAnalyzePlayer:
+		return "analyze"
+	case CallForwardToDataService:
+		return "passthrough"
+	case CallCheckDataHealth, CallCheckCortexHealth:
+		return "health"
+	case CallWarmConnection:
+		return "keepalive"
+	default:
+		return string(call)
+	}
+}
+
+// defaultIdempotentCalls lists the calls that are safe to retry. Every upstream call is a
+// POST, so idempotency is declared per call rather than derived from the HTTP method.
+// AnalyzePlayer is left out because opgl-cortex may record each analysis it runs; calls
+// missing from the map are never retried.
+func defaultIdempotentCalls() map[UpstreamCall]bool {
+	return map[UpstreamCall]bool{
+		CallGetSummonerByRiotID:  true,
+		CallGetMatchesByRiotID:   true,
+		CallGetMatchesByPUUID:    true,
+		CallGetMatchIDs:          true,
+		CallGetRankedStats:       true,
+		CallGetMatchTimeline:     true,
+		CallGetActiveGame:        true,
+		CallAnalyzePlayer:        false,
+		CallForwardToDataService: true,
+	}
+}
+
+// WithIdempotentCall overrides whether an upstream call is safe to retry, e.g. to enable
+// retries of AnalyzePlayer against a cortex deployment known to be side-effect free
+func WithIdempotentCall(call UpstreamCall, idempotent bool) Option {
+	return func(proxy *ServiceProxy) {
+		proxy.idempotentCalls[call] = idempotent
+	}
+}
+
+// postWithRetry sends a POST via post, retrying according to the proxy's retry policy when
+// the call is idempotent. The URL is taken from target on every attempt, so retries of
+// load-balanced calls can land on a different endpoint. Once attempts are exhausted the
+// last response or error is returned for normal mapping.
+func (proxy *ServiceProxy) postWithRetry(ctx context.Context, call UpstreamCall, target func() string, jsonData []byte) (*http.Response, error) {
+	body, contentEncoding := proxy.encodeRequestBody(call, jsonData)
+	if !proxy.idempotentCalls[call] {
+		return proxy.post(ctx, call, target(), body, contentEncoding)
+	}
+
+	policy := proxy.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		response, err := proxy.post(ctx, call, target(), body, contentEncoding)
+		if attempt >= policy.maxAttempts || ctx.Err() != nil {
+			return response, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil || response.StatusCode >= http.StatusInternalServerError:
+			delay = backoff.Delay(policy.baseDelay, attempt)
+		case response.StatusCode == http.StatusTooManyRequests:
+			retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+			if !ok {
+				delay = backoff.Delay(policy.baseDelay, attempt)
+				break
+			}
+			if retryAfter > policy.maxRetryAfter {
+				response.Body.Close()
+				return nil, apierrors.ServiceUnavailable("Upstream service is rate limited; retry later")
+			}
+			delay = retryAfter
+		default:
+			return response, nil
+		}
+
+		// Discard the transient response before retrying
+		if response != nil {
+			response.Body.Close()
+		}
+
+		if err := proxy.wait(ctx, delay); err != nil {
+			return nil, err
+		}
+		middleware.RecordUpstreamRetry(ctx)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given as delta-seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryTime, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := retryTime.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+// connectionError returns err if it is already an APIError (e.g. a fail-fast from the retry
+// policy), otherwise the given fallback describing a failure to reach the upstream
+func connectionError(err error, fallback *apierrors.APIError) error {
+	var apiError *apierrors.APIError
+	if errors.As(err, &apiError) {
+		return apiError
+	}
+	return fallback
+}
@@ -0,0 +1,283 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+)
+
+// newRetryingTestProxy builds a proxy with retries enabled that records waits instead of sleeping
+func newRetryingTestProxy(transport http.RoundTripper, maxRetryAfter time.Duration, waits *[]time.Duration) *ServiceProxy {
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+		WithTransport(transport),
+		WithRetries(3, 10*time.Millisecond, maxRetryAfter),
+	)
+	proxy.wait = func(ctx context.Context, delay time.Duration) error {
+		*waits = append(*waits, delay)
+		return nil
+	}
+	return proxy
+}
+
+// TestPostWithRetry_HonorsRetryAfter tests that a 429 with Retry-After is retried after the upstream delay
+func TestPostWithRetry_HonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			response := newCannedResponse(http.StatusTooManyRequests, `{"error":"rate limited"}`)
+			response.Header.Set("Retry-After", "2")
+			return response, nil
+		}
+		return newCannedResponse(http.StatusOK, `{"puuid":"retried-puuid"}`), nil
+	})
+
+	var waits []time.Duration
+	proxy := newRetryingTestProxy(fakeTransport, 5*time.Second, &waits)
+
+	summoner, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if summoner.PUUID != "retried-puuid" {
+		t.Errorf("Expected PUUID 'retried-puuid', got '%s'", summoner.PUUID)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+
+	if len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("Expected a single 2s wait honoring Retry-After, got %v", waits)
+	}
+}
+
+// TestPostWithRetry_RetryAfterExceedsCap tests that a Retry-After beyond the cap fails fast with ServiceUnavailable
+func TestPostWithRetry_RetryAfterExceedsCap(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		response := newCannedResponse(http.StatusTooManyRequests, `{"error":"rate limited"}`)
+		response.Header.Set("Retry-After", "30")
+		return response, nil
+	})
+
+	var waits []time.Duration
+	proxy := newRetryingTestProxy(fakeTransport, 5*time.Second, &waits)
+
+	_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodeServiceUnavailable {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeServiceUnavailable, apiError.Code)
+	}
+
+	if attempts != 1 || len(waits) != 0 {
+		t.Errorf("Expected to fail fast without waiting, got %d attempts and waits %v", attempts, waits)
+	}
+}
+
+// TestPostWithRetry_ServerErrorBackoff tests that 5xx responses are retried until attempts run out
+func TestPostWithRetry_ServerErrorBackoff(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		return newCannedResponse(http.StatusServiceUnavailable, `{"error":"down"}`), nil
+	})
+
+	var waits []time.Duration
+	proxy := newRetryingTestProxy(fakeTransport, 5*time.Second, &waits)
+
+	_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodeDataServiceError {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeDataServiceError, apiError.Code)
+	}
+
+	if attempts != 3 || len(waits) != 2 {
+		t.Errorf("Expected 3 attempts with 2 waits, got %d attempts and waits %v", attempts, waits)
+	}
+}
+
+// TestPostWithRetry_ReportsRetriesHeader tests that a call failing once then succeeding is reported as X-Upstream-Retries: 1
+func TestPostWithRetry_ReportsRetriesHeader(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return newCannedResponse(http.StatusBadGateway, `{"error":"flaky"}`), nil
+		}
+		return newCannedResponse(http.StatusOK, `{"puuid":"retried-puuid"}`), nil
+	})
+
+	var waits []time.Duration
+	proxy := newRetryingTestProxy(fakeTransport, 5*time.Second, &waits)
+
+	handler := middleware.UpstreamRetriesMiddleware(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if _, err := proxy.GetSummonerByRiotID(request.Context(), "na", "TestPlayer", "NA1"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/api/v1/summoner", nil))
+
+	if got := responseRecorder.Header().Get(middleware.UpstreamRetriesHeader); got != "1" {
+		t.Errorf("Expected %s '1', got '%s'", middleware.UpstreamRetriesHeader, got)
+	}
+}
+
+// TestPostWithRetry_DisabledByDefault tests that a 429 is surfaced immediately when retries are not enabled
+func TestPostWithRetry_DisabledByDefault(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		response := newCannedResponse(http.StatusTooManyRequests, `{"error":"rate limited"}`)
+		response.Header.Set("Retry-After", "1")
+		return response, nil
+	})
+
+	proxy := NewServiceProxy("http://data.internal", "http://cortex.internal", WithTransport(fakeTransport))
+
+	if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts)
+	}
+}
+
+// TestPostWithRetry_NonIdempotentCallNotRetried tests that AnalyzePlayer is attempted once even with retries enabled
+func TestPostWithRetry_NonIdempotentCallNotRetried(t *testing.T) {
+	attempts := 0
+	fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		return newCannedResponse(http.StatusServiceUnavailable, `{"error":"down"}`), nil
+	})
+
+	var waits []time.Duration
+	proxy := newRetryingTestProxy(fakeTransport, 5*time.Second, &waits)
+
+	_, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, nil, "")
+
+	apiError, ok := err.(*apierrors.APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+
+	if apiError.Code != apierrors.ErrCodeCortexServiceError {
+		t.Errorf("Expected code '%s', got '%s'", apierrors.ErrCodeCortexServiceError, apiError.Code)
+	}
+
+	if attempts != 1 || len(waits) != 0 {
+		t.Errorf("Expected a single attempt without waiting, got %d attempts and waits %v", attempts, waits)
+	}
+}
+
+// TestPostWithRetry_IdempotentCallOverride tests that WithIdempotentCall enables and disables retries per call
+func TestPostWithRetry_IdempotentCallOverride(t *testing.T) {
+	testCases := []struct {
+		name             string
+		call             UpstreamCall
+		idempotent       bool
+		invoke           func(proxy *ServiceProxy) error
+		expectedAttempts int
+	}{
+		{
+			name:       "analyze marked idempotent",
+			call:       CallAnalyzePlayer,
+			idempotent: true,
+			invoke: func(proxy *ServiceProxy) error {
+				_, err := proxy.AnalyzePlayer(context.Background(), &models.Summoner{PUUID: "test-puuid"}, nil, "")
+				return err
+			},
+			expectedAttempts: 3,
+		},
+		{
+			name:       "summoner marked non-idempotent",
+			call:       CallGetSummonerByRiotID,
+			idempotent: false,
+			invoke: func(proxy *ServiceProxy) error {
+				_, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1")
+				return err
+			},
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			attempts := 0
+			fakeTransport := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				attempts++
+				return newCannedResponse(http.StatusServiceUnavailable, `{"error":"down"}`), nil
+			})
+
+			proxy := NewServiceProxy("http://data.internal", "http://cortex.internal",
+				WithTransport(fakeTransport),
+				WithRetries(3, 10*time.Millisecond, 5*time.Second),
+				WithIdempotentCall(testCase.call, testCase.idempotent),
+			)
+			proxy.wait = func(ctx context.Context, delay time.Duration) error { return nil }
+
+			if err := testCase.invoke(proxy); err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if attempts != testCase.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", testCase.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+// TestParseRetryAfter tests parsing of delta-seconds and HTTP-date Retry-After values
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		value         string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{"seconds", "3", 3 * time.Second, true},
+		{"http date", now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"empty", "", 0, false},
+		{"negative", "-1", 0, false},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(testCase.value, now)
+
+			if ok != testCase.expectedOK {
+				t.Errorf("Expected ok=%v, got %v", testCase.expectedOK, ok)
+			}
+
+			if delay != testCase.expectedDelay {
+				t.Errorf("Expected delay %v, got %v", testCase.expectedDelay, delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+)
+
+// Connection phases timed by connection tracing
+const (
+	PhaseDNS       = "dns"
+	PhaseConnect   = "connect"
+	PhaseTLS       = "tls"
+	PhaseFirstByte = "first_byte"
+)
+
+// WithConnectionTrace times the DNS lookup, TCP connect, TLS handshake and time to first
+// response byte of every upstream call, logging them at debug level and recording them in
+// the upstream metrics. Phases skipped by a reused keep-alive connection are not recorded.
+// Tracing adds per-call overhead, so it is off unless this option is set.
+func WithConnectionTrace() Option {
+	return func(proxy *ServiceProxy) {
+		proxy.connectionTrace = true
+	}
+}
+
+// connectionTrace collects phase durations for a single upstream call. httptrace hooks may
+// run on other goroutines (e.g. parallel dials), so access is synchronized.
+type connectionTrace struct {
+	start time.Time
+
+	mutex        sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	durations    map[string]time.Duration
+}
+
+// newConnectionTrace starts timing an upstream call
+func newConnectionTrace() *connectionTrace {
+	return &connectionTrace{
+		start:     time.Now(),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+// withContext returns ctx carrying the httptrace hooks that feed this trace
+func (trace *connectionTrace) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			trace.markStart(&trace.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			trace.markDone(PhaseDNS, &trace.dnsStart)
+		},
+		ConnectStart: func(network string, address string) {
+			trace.markStart(&trace.connectStart)
+		},
+		ConnectDone: func(network string, address string, err error) {
+			if err == nil {
+				trace.markDone(PhaseConnect, &trace.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			trace.markStart(&trace.tlsStart)
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				trace.markDone(PhaseTLS, &trace.tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			trace.markDone(PhaseFirstByte, &trace.start)
+		},
+	})
+}
+
+// markStart records the start of a phase, keeping the earliest start when a hook fires more than once
+func (trace *connectionTrace) markStart(phaseStart *time.Time) {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+
+	if phaseStart.IsZero() {
+		*phaseStart = time.Now()
+	}
+}
+
+// markDone records a phase duration measured from *phaseStart, keeping the first completion
+func (trace *connectionTrace) markDone(phase string, phaseStart *time.Time) {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+
+	if _, recorded := trace.durations[phase]; recorded || phaseStart.IsZero() {
+		return
+	}
+	trace.durations[phase] = time.Since(*phaseStart)
+}
+
+// phases returns a copy of the recorded phase durations
+func (trace *connectionTrace) phases() map[string]time.Duration {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+
+	durations := make(map[string]time.Duration, len(trace.durations))
+	for phase, duration := range trace.durations {
+		durations[phase] = duration
+	}
+	return durations
+}
+
+// recordConnectionTrace logs the phases of a finished upstream call at debug level and
+// records them in the upstream metrics
+func (proxy *ServiceProxy) recordConnectionTrace(ctx context.Context, service string, url string, trace *connectionTrace) {
+	phases := trace.phases()
+
+	logger := middleware.LoggerFromContext(ctx)
+	event := logger.Debug().Str("upstream_service", service).Str("upstream_url", url)
+	for _, phase := range []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseFirstByte} {
+		duration, recorded := phases[phase]
+		if !recorded {
+			continue
+		}
+		proxy.upstreamMetrics.ObservePhase(service, phase, duration)
+		event = event.Dur(phase, duration)
+	}
+	event.Msg("Upstream connection trace")
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// TestConnectionTrace_RecordsPhases tests that the trace hooks fire against stub servers and
+// their durations are recorded per phase
+func TestConnectionTrace_RecordsPhases(t *testing.T) {
+	summonerHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`{"puuid":"test-puuid"}`))
+	})
+
+	testCases := []struct {
+		name           string
+		newServer      func(http.Handler) *httptest.Server
+		serverURL      func(server *httptest.Server) string
+		expectedPhases []string
+		skippedPhases  []string
+	}{
+		{
+			name:      "plain http by hostname",
+			newServer: httptest.NewServer,
+			serverURL: func(server *httptest.Server) string {
+				return strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+			},
+			expectedPhases: []string{PhaseDNS, PhaseConnect, PhaseFirstByte},
+			skippedPhases:  []string{PhaseTLS},
+		},
+		{
+			name:           "tls by ip",
+			newServer:      httptest.NewTLSServer,
+			serverURL:      func(server *httptest.Server) string { return server.URL },
+			expectedPhases: []string{PhaseConnect, PhaseTLS, PhaseFirstByte},
+			skippedPhases:  []string{PhaseDNS},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := testCase.newServer(summonerHandler)
+			defer server.Close()
+
+			upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+			proxy := NewServiceProxy(testCase.serverURL(server), "http://cortex.internal",
+				WithTransport(server.Client().Transport),
+				WithUpstreamMetrics(upstreamMetrics),
+				WithConnectionTrace(),
+			)
+
+			if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for _, phase := range testCase.expectedPhases {
+				if count := upstreamMetrics.PhaseCount(metrics.UpstreamData, phase); count != 1 {
+					t.Errorf("Expected one %s observation, got %d", phase, count)
+				}
+			}
+			for _, phase := range testCase.skippedPhases {
+				if count := upstreamMetrics.PhaseCount(metrics.UpstreamData, phase); count != 0 {
+					t.Errorf("Expected no %s observation, got %d", phase, count)
+				}
+			}
+		})
+	}
+}
+
+// TestConnectionTrace_DisabledByDefault tests that no phases are recorded without WithConnectionTrace
+func TestConnectionTrace_DisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`{"puuid":"test-puuid"}`))
+	}))
+	defer server.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(server.URL, "http://cortex.internal", WithUpstreamMetrics(upstreamMetrics))
+
+	if _, err := proxy.GetSummonerByRiotID(context.Background(), "na", "TestPlayer", "NA1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := upstreamMetrics.PhaseCount(metrics.UpstreamData, PhaseFirstByte); count != 0 {
+		t.Errorf("Expected no phase observations, got %d", count)
+	}
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"context"
+	"time"
+)
+
+// RunConnectionWarmer pings the health endpoint of every upstream (each opgl-data endpoint,
+// opgl-cortex and its fallback) every interval, so pooled connections are reused before the
+// transport's idle timeout closes them and the first request after a quiet period does not
+// pay for a new connect and TLS handshake. Pings run one at a time, so only one pooled
+// connection per upstream host is kept warm; connections opened for concurrent bursts still
+// expire when idle. Pings are not counted in upstream metrics and do not affect replica health
+// scores. It returns when ctx is cancelled; a non-positive interval disables it.
+func (proxy *ServiceProxy) RunConnectionWarmer(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			proxy.warmConnections(ctx, interval)
+		}
+	}
+}
+
+// warmConnections pings each upstream once, bounding the whole round by timeout so a hung
+// upstream cannot stall the next round. Failures are logged by probe and otherwise ignored.
+func (proxy *ServiceProxy) warmConnections(ctx context.Context, timeout time.Duration) {
+	roundContext, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, url := range proxy.warmURLs() {
+		proxy.probe(roundContext, CallWarmConnection, url)
+	}
+}
+
+// warmURLs returns the health URL of every upstream endpoint the proxy sends requests to
+func (proxy *ServiceProxy) warmURLs() []string {
+	var urls []string
+	for _, baseURL := range proxy.dataBaseURLs() {
+		urls = append(urls, baseURL+proxy.dataPathPrefix+"/health")
+	}
+	urls = append(urls, proxy.cortexURL("/health"))
+	if proxy.fallbackCortexURL != "" {
+		urls = append(urls, proxy.fallbackCortexURL+proxy.cortexPathPrefix+"/health")
+	}
+	return urls
+}
@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
+)
+
+// newWarmerStub starts a server that counts health pings and new connections
+func newWarmerStub(pings *atomic.Int64, connections *atomic.Int64) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/health" {
+			pings.Add(1)
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	return server
+}
+
+// TestRunConnectionWarmer tests that every upstream is pinged on the interval over a reused
+// connection, and that the warmer stops when its context is cancelled
+func TestRunConnectionWarmer(t *testing.T) {
+	var dataPings, dataConnections, cortexPings, cortexConnections atomic.Int64
+	dataServer := newWarmerStub(&dataPings, &dataConnections)
+	defer dataServer.Close()
+	cortexServer := newWarmerStub(&cortexPings, &cortexConnections)
+	defer cortexServer.Close()
+
+	proxy := NewServiceProxy(dataServer.URL, cortexServer.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		proxy.RunConnectionWarmer(ctx, 20*time.Millisecond)
+		close(stopped)
+	}()
+
+	time.Sleep(110 * time.Millisecond)
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the warmer to stop after cancellation")
+	}
+
+	for name, pings := range map[string]int64{"data": dataPings.Load(), "cortex": cortexPings.Load()} {
+		if pings < 3 || pings > 6 {
+			t.Errorf("Expected %s to be pinged about every 20ms (3-6 times), got %d", name, pings)
+		}
+	}
+	if dataConnections.Load() != 1 || cortexConnections.Load() != 1 {
+		t.Errorf("Expected each upstream connection to be reused, got data=%d cortex=%d connections", dataConnections.Load(), cortexConnections.Load())
+	}
+
+	pingsAtStop := dataPings.Load()
+	time.Sleep(50 * time.Millisecond)
+	if dataPings.Load() != pingsAtStop {
+		t.Errorf("Expected no pings after cancellation, got %d more", dataPings.Load()-pingsAtStop)
+	}
+}
+
+// TestWarmConnections_NoHealthOrErrorMetrics tests that failing pings neither count as
+// upstream errors nor lower a replica's health score
+func TestWarmConnections_NoHealthOrErrorMetrics(t *testing.T) {
+	failingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.Error(writer, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+
+	upstreamMetrics := metrics.NewUpstreamMetrics(metrics.NewRegistry())
+	proxy := NewServiceProxy(failingServer.URL, failingServer.URL,
+		WithDataReplicas([]string{failingServer.URL + "/replica"}), WithUpstreamMetrics(upstreamMetrics))
+
+	proxy.warmConnections(context.Background(), time.Second)
+
+	if errorCount := upstreamMetrics.Errors(metrics.UpstreamData); errorCount != 0 {
+		t.Errorf("Expected no data upstream errors, got %g", errorCount)
+	}
+	for _, endpoint := range proxy.dataBalancer.endpoints {
+		if endpoint.errorRate != 0 {
+			t.Errorf("Expected %s's error rate to be untouched, got %g", endpoint.baseURL, endpoint.errorRate)
+		}
+	}
+}
+
+// TestRunConnectionWarmer_Disabled tests that a zero interval returns immediately without pinging
+func TestRunConnectionWarmer_Disabled(t *testing.T) {
+	var pings, connections atomic.Int64
+	server := newWarmerStub(&pings, &connections)
+	defer server.Close()
+
+	NewServiceProxy(server.URL, server.URL).RunConnectionWarmer(context.Background(), 0)
+
+	if pings.Load() != 0 {
+		t.Errorf("Expected no pings when disabled, got %d", pings.Load())
+	}
+}
+
+// TestWarmURLs tests that every data replica and the cortex fallback are warmed
+func TestWarmURLs(t *testing.T) {
+	proxy := NewServiceProxy("http://data-a", "http://cortex",
+		WithDataReplicas([]string{"http://data-b"}), WithFallbackCortex("http://cortex-fallback"),
+		WithPathPrefixes("/data-service", ""))
+
+	expected := []string{
+		"http://data-a/data-service/health",
+		"http://data-b/data-service/health",
+		"http://cortex/health",
+		"http://cortex-fallback/health",
+	}
+	urls := proxy.warmURLs()
+	if len(urls) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, urls)
+	}
+	for index, url := range urls {
+		if url != expected[index] {
+			t.Errorf("URL %d: expected '%s', got '%s'", index, expected[index], url)
+		}
+	}
+}
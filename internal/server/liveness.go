@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LivenessProbe detects a wedged process that still accepts connections. A background loop
+// periodically runs a trivial check (for example, taking a shared lock) with a timeout and
+// records when it last succeeded; the process is unhealthy once that timestamp goes stale.
+type LivenessProbe struct {
+	check      func(ctx context.Context) error
+	interval   time.Duration
+	timeout    time.Duration
+	staleAfter time.Duration
+
+	lastHealthy atomic.Int64
+	inFlight    atomic.Bool
+}
+
+// NewLivenessProbe creates a probe that runs check every interval, failing it after timeout,
+// and reports unhealthy when no check has succeeded for staleAfter. The probe starts healthy.
+func NewLivenessProbe(check func(ctx context.Context) error, interval time.Duration, timeout time.Duration, staleAfter time.Duration) *LivenessProbe {
+	probe := &LivenessProbe{
+		check:      check,
+		interval:   interval,
+		timeout:    timeout,
+		staleAfter: staleAfter,
+	}
+	probe.lastHealthy.Store(time.Now().UnixNano())
+	return probe
+}
+
+// Run performs checks until ctx is done
+func (probe *LivenessProbe) Run(ctx context.Context) {
+	ticker := time.NewTicker(probe.interval)
+	defer ticker.Stop()
+
+	for {
+		probe.runCheck(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runCheck runs one check, recording success if it completes within the timeout. A check
+// that never returns is abandoned rather than waited on, and no new check is started until
+// it does, so a deadlock cannot pile up goroutines.
+func (probe *LivenessProbe) runCheck(ctx context.Context) {
+	if !probe.inFlight.CompareAndSwap(false, true) {
+		log.Warn().Msg("Liveness check still blocked from a previous run")
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, probe.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer probe.inFlight.Store(false)
+		done <- probe.check(checkCtx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Warn().Err(err).Msg("Liveness check failed")
+			return
+		}
+		probe.lastHealthy.Store(time.Now().UnixNano())
+	case <-checkCtx.Done():
+		log.Error().Dur("timeout", probe.timeout).Msg("Liveness check timed out; possible deadlock")
+	}
+}
+
+// LastHealthy returns when a check last succeeded
+func (probe *LivenessProbe) LastHealthy() time.Time {
+	return time.Unix(0, probe.lastHealthy.Load())
+}
+
+// IsHealthy reports whether a check has succeeded within the stale threshold
+func (probe *LivenessProbe) IsHealthy() bool {
+	return time.Since(probe.LastHealthy()) <= probe.staleAfter
+}
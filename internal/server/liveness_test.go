@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLivenessProbe_StartsHealthy tests that a new probe is healthy before any check runs
+func TestLivenessProbe_StartsHealthy(t *testing.T) {
+	probe := NewLivenessProbe(func(ctx context.Context) error { return nil }, time.Hour, time.Second, time.Minute)
+
+	if !probe.IsHealthy() {
+		t.Error("Expected new probe to be healthy")
+	}
+}
+
+// TestLivenessProbe_StaleTimestamp tests that a probe is unhealthy once its last success is stale
+func TestLivenessProbe_StaleTimestamp(t *testing.T) {
+	probe := NewLivenessProbe(func(ctx context.Context) error { return nil }, time.Hour, time.Second, time.Minute)
+	probe.lastHealthy.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	if probe.IsHealthy() {
+		t.Error("Expected probe with stale timestamp to be unhealthy")
+	}
+
+	// A successful check refreshes the timestamp
+	probe.runCheck(context.Background())
+
+	if !probe.IsHealthy() {
+		t.Error("Expected probe to be healthy after a successful check")
+	}
+}
+
+// TestLivenessProbe_DeadlockedCheck tests that a blocked check times out without refreshing the timestamp
+func TestLivenessProbe_DeadlockedCheck(t *testing.T) {
+	var lock sync.Mutex
+	lock.Lock()
+	defer lock.Unlock()
+
+	probe := NewLivenessProbe(func(ctx context.Context) error {
+		lock.Lock()
+		lock.Unlock()
+		return nil
+	}, time.Hour, 10*time.Millisecond, time.Minute)
+	staleTimestamp := time.Now().Add(-2 * time.Minute)
+	probe.lastHealthy.Store(staleTimestamp.UnixNano())
+
+	probe.runCheck(context.Background())
+
+	if probe.IsHealthy() {
+		t.Error("Expected probe to stay unhealthy when the check is blocked")
+	}
+
+	// While the first check is still blocked, further runs are skipped rather than piling up
+	probe.runCheck(context.Background())
+	if !probe.inFlight.Load() {
+		t.Error("Expected the blocked check to still be in flight")
+	}
+}
+
+// TestLivenessProbe_FailedCheck tests that a check returning an error does not refresh the timestamp
+func TestLivenessProbe_FailedCheck(t *testing.T) {
+	probe := NewLivenessProbe(func(ctx context.Context) error { return errors.New("check failed") }, time.Hour, time.Second, time.Minute)
+	probe.lastHealthy.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	probe.runCheck(context.Background())
+
+	if probe.IsHealthy() {
+		t.Error("Expected probe to stay unhealthy after a failed check")
+	}
+}
+
+// TestLivenessProbe_Run tests that Run checks periodically and stops when the context ends
+func TestLivenessProbe_Run(t *testing.T) {
+	probe := NewLivenessProbe(func(ctx context.Context) error { return nil }, time.Millisecond, time.Second, time.Minute)
+	probe.lastHealthy.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		probe.Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after the context was cancelled")
+	}
+
+	if !probe.IsHealthy() {
+		t.Error("Expected Run to refresh the timestamp")
+	}
+}
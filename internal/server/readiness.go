@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Readiness tracks whether the gateway should receive new traffic from the load balancer
+type Readiness struct {
+	draining atomic.Bool
+}
+
+// NewReadiness creates a Readiness in the ready state
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// IsReady reports whether the gateway is accepting new traffic
+func (readiness *Readiness) IsReady() bool {
+	return !readiness.draining.Load()
+}
+
+// Drain marks the gateway as not ready and then waits for delay (or until ctx is done) so
+// the load balancer can deregister it while in-flight and late-routed requests are still served
+func (readiness *Readiness) Drain(ctx context.Context, delay time.Duration) {
+	readiness.draining.Store(true)
+
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestReadiness_DefaultReady tests that a new Readiness reports ready
+func TestReadiness_DefaultReady(t *testing.T) {
+	readiness := NewReadiness()
+
+	if !readiness.IsReady() {
+		t.Error("Expected new readiness to be ready")
+	}
+}
+
+// TestReadiness_DrainWaitsForDelay tests that Drain flips readiness immediately and blocks for the delay
+func TestReadiness_DrainWaitsForDelay(t *testing.T) {
+	readiness := NewReadiness()
+	drainDone := make(chan struct{})
+
+	go func() {
+		readiness.Drain(context.Background(), 50*time.Millisecond)
+		close(drainDone)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if readiness.IsReady() {
+		t.Error("Expected readiness to be not ready during drain")
+	}
+
+	select {
+	case <-drainDone:
+		t.Error("Expected Drain to still be waiting")
+	default:
+	}
+
+	<-drainDone
+	if readiness.IsReady() {
+		t.Error("Expected readiness to stay not ready after drain")
+	}
+}
+
+// TestReadiness_DrainCancelled tests that a cancelled context cuts the drain delay short
+func TestReadiness_DrainCancelled(t *testing.T) {
+	readiness := NewReadiness()
+	cancelledContext, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	startTime := time.Now()
+	readiness.Drain(cancelledContext, time.Minute)
+
+	if time.Since(startTime) > time.Second {
+		t.Error("Expected Drain to return promptly when the context is cancelled")
+	}
+}
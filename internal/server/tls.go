@@ -0,0 +1,38 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// secureCipherSuites are the TLS 1.2 suites allowed: forward-secret ECDHE key exchange
+// with AEAD ciphers only. TLS 1.3 suites are not configurable and are always secure.
+var secureCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// ParseTLSVersion converts a configured minimum version ("1.2" or "1.3") to its tls constant.
+// An empty value defaults to TLS 1.2; older versions are rejected.
+func ParseTLSVersion(value string) (uint16, error) {
+	switch value {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS minimum version %q (must be 1.2 or 1.3)", value)
+	}
+}
+
+// NewTLSConfig creates the server TLS configuration with the given minimum protocol version
+func NewTLSConfig(minVersion uint16) *tls.Config {
+	return &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: secureCipherSuites,
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseTLSVersion tests parsing of configured minimum TLS versions
+func TestParseTLSVersion(t *testing.T) {
+	testCases := []struct {
+		value           string
+		expectedVersion uint16
+		expectError     bool
+	}{
+		{"", tls.VersionTLS12, false},
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"1.1", 0, true},
+		{"1.0", 0, true},
+		{"tls13", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		version, err := ParseTLSVersion(testCase.value)
+
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("Expected error for '%s'", testCase.value)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", testCase.value, err)
+		}
+		if version != testCase.expectedVersion {
+			t.Errorf("Expected version %x for '%s', got %x", testCase.expectedVersion, testCase.value, version)
+		}
+	}
+}
+
+// newTLSTestServer starts a TLS test server using the gateway TLS configuration
+func newTLSTestServer(minVersion uint16) *httptest.Server {
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	testServer.TLS = NewTLSConfig(minVersion)
+	testServer.StartTLS()
+	return testServer
+}
+
+// TestNewTLSConfig_RejectsTLS11 tests that a TLS 1.1 handshake is rejected
+func TestNewTLSConfig_RejectsTLS11(t *testing.T) {
+	testServer := newTLSTestServer(tls.VersionTLS12)
+	defer testServer.Close()
+
+	connection, err := tls.Dial("tcp", testServer.Listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		MaxVersion:         tls.VersionTLS11,
+	})
+	if err == nil {
+		connection.Close()
+		t.Fatal("Expected TLS 1.1 handshake to be rejected")
+	}
+}
+
+// TestNewTLSConfig_AcceptsTLS12 tests that a TLS 1.2 handshake succeeds with the configured suites
+func TestNewTLSConfig_AcceptsTLS12(t *testing.T) {
+	testServer := newTLSTestServer(tls.VersionTLS12)
+	defer testServer.Close()
+
+	connection, err := tls.Dial("tcp", testServer.Listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+	})
+	if err != nil {
+		t.Fatalf("Expected TLS 1.2 handshake to succeed, got %v", err)
+	}
+	defer connection.Close()
+
+	if connection.ConnectionState().Version != tls.VersionTLS12 {
+		t.Errorf("Expected negotiated version TLS 1.2, got %x", connection.ConnectionState().Version)
+	}
+}
+
+// TestNewTLSConfig_MinTLS13RejectsTLS12 tests that a 1.3 minimum rejects TLS 1.2 clients
+func TestNewTLSConfig_MinTLS13RejectsTLS12(t *testing.T) {
+	testServer := newTLSTestServer(tls.VersionTLS13)
+	defer testServer.Close()
+
+	connection, err := tls.Dial("tcp", testServer.Listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+	})
+	if err == nil {
+		connection.Close()
+		t.Fatal("Expected TLS 1.2 handshake to be rejected when minimum is 1.3")
+	}
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultAnalyzeMode is used when an analyze request does not set a mode
+const DefaultAnalyzeMode = "standard"
+
+// DefaultMaxAnalyzeCount is the default cap on analyze "count". It is lower than the 100
+// allowed for raw match requests because cortex cannot usefully analyze that many matches.
+const DefaultMaxAnalyzeCount = 50
+
+// AnalyzeMode describes an allowed value of the analyze "mode" option
+type AnalyzeMode struct {
+	Description string
+	// MatchCount is how many recent matches are analyzed when the request sets no count
+	MatchCount int
+}
+
+// AnalyzeModes is the registry of allowed analyze modes. Supporting a new mode only needs
+// an entry here; validation and the handler read it from this map.
+var AnalyzeModes = map[string]AnalyzeMode{
+	"standard": {Description: "Full analysis of recent match history", MatchCount: 20},
+	"quick":    {Description: "Faster analysis of the last few matches", MatchCount: 5},
+	"deep":     {Description: "In-depth analysis of a longer match history", MatchCount: 50},
+}
+
+// AnalyzeOptions holds the optional analyze settings, sent alongside the Riot ID fields
+type AnalyzeOptions struct {
+	// Mode selects an entry of AnalyzeModes; empty means DefaultAnalyzeMode
+	Mode string `json:"mode,omitempty"`
+	// Count overrides the mode's number of matches analyzed
+	Count IntegerField `json:"count"`
+	// Partial controls whether analysis may proceed without matches when the match fetch
+	// exceeds the gateway's soft deadline; nil means allowed
+	Partial *bool `json:"partial,omitempty"`
+}
+
+// ValidateAnalyzeOptions validates every analyze option. count may not exceed maxCount, the
+// analyze-specific limit, in addition to the general match count limits.
+func ValidateAnalyzeOptions(options *AnalyzeOptions, maxCount int) *ValidationResult {
+	result := &ValidationResult{}
+
+	if options.Mode != "" {
+		if _, found := AnalyzeModes[options.Mode]; !found {
+			result.AddError("mode", "invalid mode. Valid modes: "+strings.Join(analyzeModeNames(), ", "))
+		}
+	}
+
+	if count, err := options.Count.Int(); err == nil && count > maxCount {
+		result.AddError("count", "count cannot exceed "+strconv.Itoa(maxCount)+" for analyze")
+	} else {
+		validateCountField(options.Count, result)
+	}
+
+	return result
+}
+
+// AnalyzeMode returns the selected mode, falling back to DefaultAnalyzeMode
+func (options *AnalyzeOptions) AnalyzeMode() AnalyzeMode {
+	if mode, found := AnalyzeModes[options.Mode]; found {
+		return mode
+	}
+	return AnalyzeModes[DefaultAnalyzeMode]
+}
+
+// MatchCount returns the number of matches to analyze: the explicit count if set, otherwise the mode's default
+func (options *AnalyzeOptions) MatchCount() int {
+	if count, err := options.Count.Int(); err == nil && count > 0 {
+		return count
+	}
+	return options.AnalyzeMode().MatchCount
+}
+
+// AllowsPartial reports whether a degraded analysis without matches is acceptable
+func (options *AnalyzeOptions) AllowsPartial() bool {
+	return options.Partial == nil || *options.Partial
+}
+
+// analyzeModeNames returns the registered mode names in sorted order
+func analyzeModeNames() []string {
+	names := make([]string, 0, len(AnalyzeModes))
+	for name := range AnalyzeModes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// decodeAnalyzeRequest decodes an analyze request body, failing the test on error
+func decodeAnalyzeRequest(t *testing.T, body string) *AnalyzeRequest {
+	t.Helper()
+	var request AnalyzeRequest
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("Failed to decode %s: %v", body, err)
+	}
+	return &request
+}
+
+// TestValidateAnalyzeOptions tests valid and invalid values of every analyze option
+func TestValidateAnalyzeOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          string
+		isValid       bool
+		expectedError string
+	}{
+		{"no options", `{}`, true, ""},
+		{"standard mode", `{"mode":"standard"}`, true, ""},
+		{"quick mode", `{"mode":"quick"}`, true, ""},
+		{"unknown mode", `{"mode":"turbo"}`, false, "invalid mode. Valid modes: deep, quick, standard"},
+		{"mode is case sensitive", `{"mode":"QUICK"}`, false, "invalid mode"},
+		{"valid count", `{"count":10}`, true, ""},
+		{"negative count", `{"count":-1}`, false, "count cannot be negative"},
+		{"count at analyze limit", `{"count":50}`, true, ""},
+		{"count valid for matches but not analyze", `{"count":51}`, false, "count cannot exceed 50 for analyze"},
+		{"count too large", `{"count":101}`, false, "count cannot exceed 50 for analyze"},
+		{"non-integer count", `{"count":"ten"}`, false, "count must be an integer"},
+		{"partial true", `{"partial":true}`, true, ""},
+		{"partial false", `{"partial":false}`, true, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := decodeAnalyzeRequest(t, testCase.body)
+
+			result := ValidateAnalyzeOptions(&request.AnalyzeOptions, DefaultMaxAnalyzeCount)
+
+			if result.IsValid() != testCase.isValid {
+				t.Fatalf("Expected valid=%v, got errors: %s", testCase.isValid, result.GetErrorMessages())
+			}
+			if testCase.expectedError != "" && !strings.Contains(result.GetErrorMessages(), testCase.expectedError) {
+				t.Errorf("Expected error '%s', got '%s'", testCase.expectedError, result.GetErrorMessages())
+			}
+		})
+	}
+}
+
+// TestValidateAnalyzeOptions_PartialType tests that a non-boolean partial fails to decode
+func TestValidateAnalyzeOptions_PartialType(t *testing.T) {
+	var request AnalyzeRequest
+	if err := json.Unmarshal([]byte(`{"partial":"yes"}`), &request); err == nil {
+		t.Error("Expected a decode error for a non-boolean partial")
+	}
+}
+
+// TestAnalyzeOptions_MatchCount tests that an explicit count overrides the mode default
+func TestAnalyzeOptions_MatchCount(t *testing.T) {
+	testCases := []struct {
+		body     string
+		expected int
+	}{
+		{`{}`, AnalyzeModes[DefaultAnalyzeMode].MatchCount},
+		{`{"mode":"quick"}`, AnalyzeModes["quick"].MatchCount},
+		{`{"mode":"quick","count":12}`, 12},
+		{`{"count":0}`, AnalyzeModes[DefaultAnalyzeMode].MatchCount},
+	}
+
+	for _, testCase := range testCases {
+		request := decodeAnalyzeRequest(t, testCase.body)
+		if count := request.MatchCount(); count != testCase.expected {
+			t.Errorf("%s: expected match count %d, got %d", testCase.body, testCase.expected, count)
+		}
+	}
+}
+
+// TestAnalyzeOptions_AllowsPartial tests that partial defaults to allowed
+func TestAnalyzeOptions_AllowsPartial(t *testing.T) {
+	testCases := map[string]bool{
+		`{}`:                true,
+		`{"partial":true}`:  true,
+		`{"partial":false}`: false,
+	}
+
+	for body, expected := range testCases {
+		if allowed := decodeAnalyzeRequest(t, body).AllowsPartial(); allowed != expected {
+			t.Errorf("%s: expected AllowsPartial=%v, got %v", body, expected, allowed)
+		}
+	}
+}
+
+// TestValidateAnalyzeRequest_IgnoresOptions tests that identity validation does not check options
+func TestValidateAnalyzeRequest_IgnoresOptions(t *testing.T) {
+	request := decodeAnalyzeRequest(t, `{"region":"na","gameName":"TestPlayer","tagLine":"NA1","mode":"turbo"}`)
+
+	if result := ValidateAnalyzeRequest(request); !result.IsValid() {
+		t.Errorf("Expected identity validation to pass, got '%s'", result.GetErrorMessages())
+	}
+}
+
+// TestValidateAnalyzeOptions_CountLimit tests that the analyze count limit is configurable independently of matches
+func TestValidateAnalyzeOptions_CountLimit(t *testing.T) {
+	request := decodeAnalyzeRequest(t, `{"count":30}`)
+
+	matchResult := &ValidationResult{}
+	validateCountField(request.Count, matchResult)
+	if !matchResult.IsValid() {
+		t.Fatalf("Expected count 30 to be valid for matches, got: %s", matchResult.GetErrorMessages())
+	}
+
+	result := ValidateAnalyzeOptions(&request.AnalyzeOptions, 25)
+	if result.IsValid() {
+		t.Fatal("Expected count 30 to exceed an analyze limit of 25")
+	}
+	if !strings.Contains(result.GetErrorMessages(), "count cannot exceed 25 for analyze") {
+		t.Errorf("Expected analyze-specific limit in error, got '%s'", result.GetErrorMessages())
+	}
+
+	if result := ValidateAnalyzeOptions(&request.AnalyzeOptions, 100); !result.IsValid() {
+		t.Errorf("Expected count 30 to be valid with an analyze limit of 100, got: %s", result.GetErrorMessages())
+	}
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when Accept-Language is absent or names no supported locale
+const DefaultLocale = "en"
+
+// SupportedLocales lists the languages opgl-cortex-engine can write analysis text in
+var SupportedLocales = map[string]bool{
+	"en": true,
+	"es": true,
+	"fr": true,
+	"de": true,
+	"pt": true,
+	"ko": true,
+	"ja": true,
+	"zh": true,
+}
+
+// languagePreference is one entry of an Accept-Language header
+type languagePreference struct {
+	tag     string
+	quality float64
+}
+
+// NegotiateLocale picks the supported locale the client prefers from an Accept-Language
+// header such as "ko-KR,ko;q=0.9,en;q=0.8". Region subtags are dropped ("ko-KR" matches
+// "ko") and entries with q=0 are ignored. Unknown or malformed values fall back to DefaultLocale.
+func NegotiateLocale(acceptLanguage string) string {
+	for _, preference := range parseAcceptLanguage(acceptLanguage) {
+		language := strings.ToLower(strings.SplitN(preference.tag, "-", 2)[0])
+		if SupportedLocales[language] {
+			return language
+		}
+	}
+	return DefaultLocale
+}
+
+// parseAcceptLanguage returns the header's entries ordered by descending quality, keeping
+// header order for equal qualities
+func parseAcceptLanguage(acceptLanguage string) []languagePreference {
+	var preferences []languagePreference
+
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(entry, ";")
+		tag := strings.TrimSpace(parts[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, parameter := range parts[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(parameter), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+		preferences = append(preferences, languagePreference{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(left, right int) bool {
+		return preferences[left].quality > preferences[right].quality
+	})
+
+	return preferences
+}
@@ -0,0 +1,31 @@
+package validation
+
+import "testing"
+
+// TestNegotiateLocale tests Accept-Language negotiation and fallback to the default locale
+func TestNegotiateLocale(t *testing.T) {
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "empty header", acceptLanguage: "", expected: DefaultLocale},
+		{name: "exact match", acceptLanguage: "ko", expected: "ko"},
+		{name: "region subtag dropped", acceptLanguage: "pt-BR", expected: "pt"},
+		{name: "uppercase tag", acceptLanguage: "FR-ca", expected: "fr"},
+		{name: "first supported entry", acceptLanguage: "nl-NL, de;q=0.9, en;q=0.8", expected: "de"},
+		{name: "highest quality wins", acceptLanguage: "en;q=0.5, ja;q=0.9", expected: "ja"},
+		{name: "zero quality ignored", acceptLanguage: "es;q=0, fr;q=0.1", expected: "fr"},
+		{name: "unknown locale", acceptLanguage: "xx-YY", expected: DefaultLocale},
+		{name: "wildcard only", acceptLanguage: "*", expected: DefaultLocale},
+		{name: "malformed quality", acceptLanguage: "ko;q=abc, zh;q=0.5", expected: "zh"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := NegotiateLocale(testCase.acceptLanguage); got != testCase.expected {
+				t.Errorf("Expected locale '%s', got '%s'", testCase.expected, got)
+			}
+		})
+	}
+}
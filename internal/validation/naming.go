@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// unmarshalAcceptingSnakeCase decodes a JSON object into target, also accepting snake_case
+// spellings of camelCase field names (e.g. game_name for gameName). When both spellings are
+// present the camelCase value wins. target must not itself implement json.Unmarshaler with
+// this function, or decoding would recurse; callers pass a method-less alias type.
+func unmarshalAcceptingSnakeCase(data []byte, target interface{}) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		// Not an object (or null): let the standard decoder produce its usual result or error
+		return json.Unmarshal(data, target)
+	}
+
+	renamed := false
+	for key, value := range fields {
+		if !strings.Contains(key, "_") {
+			continue
+		}
+		renamed = true
+		delete(fields, key)
+
+		camelKey := snakeToCamel(key)
+		if _, exists := fields[camelKey]; !exists {
+			fields[camelKey] = value
+		}
+	}
+
+	if !renamed {
+		return json.Unmarshal(data, target)
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, target)
+}
+
+// snakeToCamel converts a snake_case name to camelCase, e.g. "tag_line" to "tagLine"
+func snakeToCamel(name string) string {
+	parts := strings.Split(name, "_")
+	var builder strings.Builder
+	builder.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
+
+// UnmarshalJSON decodes a SummonerRequest, accepting snake_case field names
+func (request *SummonerRequest) UnmarshalJSON(data []byte) error {
+	type plain SummonerRequest
+	return unmarshalAcceptingSnakeCase(data, (*plain)(request))
+}
+
+// UnmarshalJSON decodes a MatchRequest, accepting snake_case field names
+func (request *MatchRequest) UnmarshalJSON(data []byte) error {
+	type plain MatchRequest
+	return unmarshalAcceptingSnakeCase(data, (*plain)(request))
+}
+
+// UnmarshalJSON decodes an AnalyzeRequest, accepting snake_case field names
+func (request *AnalyzeRequest) UnmarshalJSON(data []byte) error {
+	type plain AnalyzeRequest
+	return unmarshalAcceptingSnakeCase(data, (*plain)(request))
+}
+
+// UnmarshalJSON decodes a ProfileRequest, accepting snake_case field names
+func (request *ProfileRequest) UnmarshalJSON(data []byte) error {
+	type plain ProfileRequest
+	return unmarshalAcceptingSnakeCase(data, (*plain)(request))
+}
+
+// UnmarshalJSON decodes a BatchMatchItem, accepting snake_case field names
+func (item *BatchMatchItem) UnmarshalJSON(data []byte) error {
+	type plain BatchMatchItem
+	return unmarshalAcceptingSnakeCase(data, (*plain)(item))
+}
+
+// UnmarshalJSON decodes a CacheWarmRequest, accepting snake_case field names
+func (request *CacheWarmRequest) UnmarshalJSON(data []byte) error {
+	type plain CacheWarmRequest
+	return unmarshalAcceptingSnakeCase(data, (*plain)(request))
+}
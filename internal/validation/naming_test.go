@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestSummonerRequest_SnakeCase tests that snake_case bodies decode into SummonerRequest
+func TestSummonerRequest_SnakeCase(t *testing.T) {
+	var request SummonerRequest
+	err := json.Unmarshal([]byte(`{"region":"na","game_name":"TestPlayer","tag_line":"NA1"}`), &request)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Region != "na" || request.GameName != "TestPlayer" || request.TagLine != "NA1" {
+		t.Errorf("Unexpected decoded request: %+v", request)
+	}
+}
+
+// TestSummonerRequest_CamelCasePrecedence tests that camelCase wins when both spellings are sent
+func TestSummonerRequest_CamelCasePrecedence(t *testing.T) {
+	var request SummonerRequest
+	err := json.Unmarshal([]byte(`{"region":"na","game_name":"SnakeName","gameName":"CamelName","tag_line":"NA1"}`), &request)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.GameName != "CamelName" {
+		t.Errorf("Expected camelCase gameName to win, got '%s'", request.GameName)
+	}
+	if request.TagLine != "NA1" {
+		t.Errorf("Expected snake_case tag_line to be used, got '%s'", request.TagLine)
+	}
+}
+
+// TestSummonerRequest_CamelCaseUnchanged tests that camelCase bodies still decode as before
+func TestSummonerRequest_CamelCaseUnchanged(t *testing.T) {
+	var request SummonerRequest
+	err := json.Unmarshal([]byte(`{"region":"na","gameName":"TestPlayer","tagLine":"NA1"}`), &request)
+
+	if err != nil || request.GameName != "TestPlayer" || request.TagLine != "NA1" {
+		t.Errorf("Unexpected result: %+v, %v", request, err)
+	}
+}
+
+// TestSummonerRequest_InvalidJSON tests that malformed bodies still fail to decode
+func TestSummonerRequest_InvalidJSON(t *testing.T) {
+	var request SummonerRequest
+
+	if err := json.Unmarshal([]byte(`["not","an","object"]`), &request); err == nil {
+		t.Error("Expected an error decoding a JSON array")
+	}
+}
+
+// TestMatchRequest_SnakeCase tests snake_case decoding of nested types and pointer fields
+func TestMatchRequest_SnakeCase(t *testing.T) {
+	var request MatchRequest
+	err := json.Unmarshal([]byte(`{"region":"na","puuid":"test-puuid","count":5,"start_time":100,"end_time":200}`), &request)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.StartTime == nil || *request.StartTime != 100 || request.EndTime == nil || *request.EndTime != 200 {
+		t.Errorf("Expected start_time/end_time to decode, got %v/%v", request.StartTime, request.EndTime)
+	}
+	if count, err := request.Count.Int(); err != nil || count != 5 {
+		t.Errorf("Expected count 5, got %d (%v)", count, err)
+	}
+}
+
+// TestCacheWarmRequest_SnakeCase tests snake_case decoding of a request with nested players
+func TestCacheWarmRequest_SnakeCase(t *testing.T) {
+	var request CacheWarmRequest
+	err := json.Unmarshal([]byte(`{"org_id":"org","players":[{"region":"na","game_name":"TestPlayer","tag_line":"NA1"}]}`), &request)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.OrgID != "org" || len(request.Players) != 1 || request.Players[0].GameName != "TestPlayer" {
+		t.Errorf("Unexpected decoded request: %+v", request)
+	}
+}
+
+// TestSnakeToCamel tests snake_case to camelCase conversion
+func TestSnakeToCamel(t *testing.T) {
+	testCases := map[string]string{
+		"game_name":  "gameName",
+		"tag_line":   "tagLine",
+		"org_id":     "orgId",
+		"start_time": "startTime",
+		"double__":   "double",
+	}
+
+	for input, expected := range testCases {
+		if result := snakeToCamel(input); result != expected {
+			t.Errorf("snakeToCamel(%q): expected '%s', got '%s'", input, expected, result)
+		}
+	}
+}
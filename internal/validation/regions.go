@@ -0,0 +1,80 @@
+package validation
+
+import "strings"
+
+// Routing clusters group platform regions for Riot's regional (account and match) APIs
+const (
+	RoutingAmericas = "americas"
+	RoutingEurope   = "europe"
+	RoutingAsia     = "asia"
+	RoutingSEA      = "sea"
+)
+
+// RegionNames maps each region in ValidRegions to its display name
+var RegionNames = map[string]string{
+	"na":   "North America",
+	"euw":  "Europe West",
+	"eune": "Europe Nordic & East",
+	"kr":   "Korea",
+	"jp":   "Japan",
+	"br":   "Brazil",
+	"lan":  "Latin America North",
+	"las":  "Latin America South",
+	"oce":  "Oceania",
+	"tr":   "Turkey",
+	"ru":   "Russia",
+	"ph":   "Philippines",
+	"sg":   "Singapore",
+	"th":   "Thailand",
+	"tw":   "Taiwan",
+	"vn":   "Vietnam",
+}
+
+// RegionToRouting maps each region in ValidRegions to the routing cluster serving its
+// match history
+var RegionToRouting = map[string]string{
+	"na":   RoutingAmericas,
+	"br":   RoutingAmericas,
+	"lan":  RoutingAmericas,
+	"las":  RoutingAmericas,
+	"euw":  RoutingEurope,
+	"eune": RoutingEurope,
+	"tr":   RoutingEurope,
+	"ru":   RoutingEurope,
+	"kr":   RoutingAsia,
+	"jp":   RoutingAsia,
+	"oce":  RoutingSEA,
+	"ph":   RoutingSEA,
+	"sg":   RoutingSEA,
+	"th":   RoutingSEA,
+	"tw":   RoutingSEA,
+	"vn":   RoutingSEA,
+}
+
+// RegionAliases maps Riot platform codes (as used in Riot API hosts, e.g. "na1") to the
+// canonical short region codes in ValidRegions
+var RegionAliases = map[string]string{
+	"na1":  "na",
+	"euw1": "euw",
+	"eun1": "eune",
+	"jp1":  "jp",
+	"br1":  "br",
+	"la1":  "lan",
+	"la2":  "las",
+	"oc1":  "oce",
+	"tr1":  "tr",
+	"ph2":  "ph",
+	"sg2":  "sg",
+	"th2":  "th",
+	"tw2":  "tw",
+	"vn2":  "vn",
+}
+
+// ResolveRegionAlias returns the canonical region for a platform code (case-insensitively),
+// or the region unchanged when it is not an alias
+func ResolveRegionAlias(region string) string {
+	if canonicalRegion, ok := RegionAliases[strings.ToLower(region)]; ok {
+		return canonicalRegion
+	}
+	return region
+}
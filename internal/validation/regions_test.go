@@ -0,0 +1,36 @@
+package validation
+
+import "testing"
+
+// TestRegionTablesCoverValidRegions tests that every valid region has a name and routing cluster
+func TestRegionTablesCoverValidRegions(t *testing.T) {
+	for region := range ValidRegions {
+		if RegionNames[region] == "" {
+			t.Errorf("Expected a display name for region '%s'", region)
+		}
+		if RegionToRouting[region] == "" {
+			t.Errorf("Expected a routing cluster for region '%s'", region)
+		}
+	}
+
+	if len(RegionNames) != len(ValidRegions) || len(RegionToRouting) != len(ValidRegions) {
+		t.Errorf("Expected region tables to match the %d valid regions, got %d names and %d routings",
+			len(ValidRegions), len(RegionNames), len(RegionToRouting))
+	}
+}
+
+// TestResolveRegionAlias tests that platform codes map to valid regions and other values pass through
+func TestResolveRegionAlias(t *testing.T) {
+	for alias, region := range RegionAliases {
+		if !ValidRegions[region] {
+			t.Errorf("Expected alias '%s' to map to a valid region, got '%s'", alias, region)
+		}
+	}
+
+	if region := ResolveRegionAlias("EUW1"); region != "euw" {
+		t.Errorf("Expected 'EUW1' to resolve to 'euw', got '%s'", region)
+	}
+	if region := ResolveRegionAlias("Atlantis"); region != "Atlantis" {
+		t.Errorf("Expected non-alias to be returned unchanged, got '%s'", region)
+	}
+}
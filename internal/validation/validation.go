@@ -1,8 +1,17 @@
 package validation
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/pagination"
+	"github.com/google/uuid"
 )
 
 // ValidRegions contains all valid Riot API region codes
@@ -25,6 +34,25 @@ var ValidRegions = map[string]bool{
 	"vn":   true, // Vietnam
 }
 
+// Input length bounds, checked before any case folding, decoding or regex matching so that
+// absurdly long values are rejected in constant time
+const (
+	// maxRegionLength is well above any region code, so near-misses still get the list of valid regions
+	maxRegionLength = 32
+	// maxCursorLength comfortably exceeds any cursor EncodeMatchCursor produces
+	maxCursorLength = 256
+	// maxMatchIDLength comfortably exceeds a platform prefix plus a 64-bit game ID
+	maxMatchIDLength = 32
+)
+
+// Field patterns are compiled once; every caller bounds the input length first
+var (
+	validGameNamePattern = regexp.MustCompile(`^[a-zA-Z0-9 _]+$`)
+	validTagLinePattern  = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	validPUUIDPattern    = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	validMatchIDPattern  = regexp.MustCompile(`^[A-Z0-9]{2,5}_[0-9]+$`)
+)
+
 // ValidationError represents a single validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -49,6 +77,11 @@ func (validationResult *ValidationResult) AddError(field string, message string)
 	})
 }
 
+// Merge appends the errors of other to the result
+func (validationResult *ValidationResult) Merge(other *ValidationResult) {
+	validationResult.Errors = append(validationResult.Errors, other.Errors...)
+}
+
 // GetErrorMessages returns all error messages as a single string
 func (validationResult *ValidationResult) GetErrorMessages() string {
 	messages := make([]string, len(validationResult.Errors))
@@ -58,6 +91,49 @@ func (validationResult *ValidationResult) GetErrorMessages() string {
 	return strings.Join(messages, "; ")
 }
 
+// errNotInteger is returned when an IntegerField does not hold a JSON integer
+var errNotInteger = errors.New("value must be an integer")
+
+// IntegerField holds the raw JSON for an integer request field. Decoding accepts any JSON
+// value so that floats or strings are reported through validation with a clear message
+// instead of failing the whole body with a generic decode error.
+type IntegerField []byte
+
+// UnmarshalJSON stores the raw JSON value for later validation
+func (field *IntegerField) UnmarshalJSON(data []byte) error {
+	*field = append((*field)[:0], data...)
+	return nil
+}
+
+// MarshalJSON writes the raw JSON value back out, or null when unset
+func (field IntegerField) MarshalJSON() ([]byte, error) {
+	if len(field) == 0 {
+		return []byte("null"), nil
+	}
+	return field, nil
+}
+
+// IsSet reports whether the field was sent with a non-null value
+func (field IntegerField) IsSet() bool {
+	trimmed := bytes.TrimSpace(field)
+	return len(trimmed) != 0 && string(trimmed) != "null"
+}
+
+// Int returns the integer value, treating an absent or null field as 0
+func (field IntegerField) Int() (int, error) {
+	trimmed := bytes.TrimSpace(field)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(string(trimmed))
+	if err != nil {
+		return 0, errNotInteger
+	}
+
+	return value, nil
+}
+
 // SummonerRequest represents the request body for summoner lookup
 type SummonerRequest struct {
 	Region   string `json:"region"`
@@ -67,18 +143,76 @@ type SummonerRequest struct {
 
 // MatchRequest represents the request body for match history lookup
 type MatchRequest struct {
+	Region   string       `json:"region"`
+	GameName string       `json:"gameName"`
+	TagLine  string       `json:"tagLine"`
+	PUUID    string       `json:"puuid"`
+	Count    IntegerField `json:"count"`
+	// Cursor enables cursor pagination when present; an empty string requests the first page
+	Cursor *string `json:"cursor,omitempty"`
+	// StartTime and EndTime optionally bound match creation time, in Unix seconds
+	StartTime *int64 `json:"startTime,omitempty"`
+	EndTime   *int64 `json:"endTime,omitempty"`
+}
+
+// AnalyzeRequest represents the request body for player analysis
+type AnalyzeRequest struct {
 	Region   string `json:"region"`
 	GameName string `json:"gameName"`
 	TagLine  string `json:"tagLine"`
-	PUUID    string `json:"puuid"`
-	Count    int    `json:"count"`
+	// AnalyzeOptions fields (mode, count, partial) sit alongside the Riot ID in the body
+	AnalyzeOptions
 }
 
-// AnalyzeRequest represents the request body for player analysis
-type AnalyzeRequest struct {
+// ProfileRequest represents the request body for a combined player profile lookup
+type ProfileRequest struct {
 	Region   string `json:"region"`
 	GameName string `json:"gameName"`
 	TagLine  string `json:"tagLine"`
+	// Count is the number of recent matches to include (default 20)
+	Count IntegerField `json:"count"`
+}
+
+// MatchTimelineRequest represents the request body for a match timeline lookup
+type MatchTimelineRequest struct {
+	Region  string `json:"region"`
+	MatchID string `json:"matchId"`
+}
+
+// MatchIDsRequest represents the request body for listing a player's recent match IDs
+type MatchIDsRequest struct {
+	Region string       `json:"region"`
+	PUUID  string       `json:"puuid"`
+	Count  IntegerField `json:"count"`
+}
+
+// BatchMatchItem represents a single player entry in a batch match request
+type BatchMatchItem struct {
+	Region string       `json:"region"`
+	PUUID  string       `json:"puuid"`
+	Count  IntegerField `json:"count"`
+}
+
+// BatchMatchRequest represents the request body for fetching matches for multiple players
+type BatchMatchRequest struct {
+	Players []BatchMatchItem `json:"players"`
+}
+
+// ComparePlayerCount is the number of players a head-to-head comparison takes
+const ComparePlayerCount = 2
+
+// CompareRequest represents the request body for comparing two players head-to-head
+type CompareRequest struct {
+	Players []SummonerRequest `json:"players"`
+	// Count is the number of recent matches compared per player (default 20)
+	Count IntegerField `json:"count"`
+}
+
+// CacheWarmRequest represents the request body for pre-populating the summoner cache
+type CacheWarmRequest struct {
+	// OrgID optionally scopes the warmed entries to an organization's cache keys
+	OrgID   string            `json:"orgId"`
+	Players []SummonerRequest `json:"players"`
 }
 
 // ValidateSummonerRequest validates a summoner request
@@ -92,26 +226,71 @@ func ValidateSummonerRequest(request *SummonerRequest) *ValidationResult {
 	return result
 }
 
+// ValidatePartialSummonerRequest validates only the fields of a summoner request that are
+// set, so an incomplete request (e.g. a form being filled in) reports no "required" errors
+func ValidatePartialSummonerRequest(request *SummonerRequest) *ValidationResult {
+	result := &ValidationResult{}
+
+	if request.Region != "" {
+		validateRegion(request.Region, result)
+	}
+	if request.GameName != "" {
+		validateGameName(request.GameName, result)
+	}
+	if request.TagLine != "" {
+		validateTagLine(request.TagLine, result)
+	}
+
+	return result
+}
+
+// ValidateMatchTimelineRequest validates a match timeline request
+func ValidateMatchTimelineRequest(request *MatchTimelineRequest) *ValidationResult {
+	result := &ValidationResult{}
+
+	validateRegion(request.Region, result)
+	validateMatchID(request.MatchID, result)
+
+	return result
+}
+
+// ValidateMatchIDsRequest validates a match ID list request
+func ValidateMatchIDsRequest(request *MatchIDsRequest) *ValidationResult {
+	result := &ValidationResult{}
+
+	validateRegion(request.Region, result)
+	validatePUUID(request.PUUID, result)
+	validateCountField(request.Count, result)
+
+	return result
+}
+
 // ValidateMatchRequest validates a match history request
 func ValidateMatchRequest(request *MatchRequest) *ValidationResult {
 	result := &ValidationResult{}
 
 	validateRegion(request.Region, result)
 
-	// Either PUUID or GameName+TagLine must be provided
-	if request.PUUID != "" {
+	// Either PUUID or GameName+TagLine must be provided, never both: the two could name
+	// different players and clients should not rely on which one wins
+	if request.PUUID != "" && (request.GameName != "" || request.TagLine != "") {
+		result.AddError("puuid", "provide either puuid or gameName+tagLine, not both")
+	} else if request.PUUID != "" {
 		validatePUUID(request.PUUID, result)
 	} else {
 		validateGameName(request.GameName, result)
 		validateTagLine(request.TagLine, result)
 	}
 
-	validateCount(request.Count, result)
+	validateCountField(request.Count, result)
+	validateCursor(request.Cursor, result)
+	validateTimeRange(request.StartTime, request.EndTime, time.Now(), result)
 
 	return result
 }
 
-// ValidateAnalyzeRequest validates an analyze player request
+// ValidateAnalyzeRequest validates the identity fields of an analyze player request; options
+// are checked separately by ValidateAnalyzeOptions
 func ValidateAnalyzeRequest(request *AnalyzeRequest) *ValidationResult {
 	result := &ValidationResult{}
 
@@ -122,6 +301,108 @@ func ValidateAnalyzeRequest(request *AnalyzeRequest) *ValidationResult {
 	return result
 }
 
+// ValidateProfileRequest validates a player profile request
+func ValidateProfileRequest(request *ProfileRequest) *ValidationResult {
+	result := &ValidationResult{}
+
+	validateRegion(request.Region, result)
+	validateGameName(request.GameName, result)
+	validateTagLine(request.TagLine, result)
+	validateCountField(request.Count, result)
+
+	return result
+}
+
+// ValidateBatchMatchRequest validates a batch match request, enforcing a maximum batch size.
+// Item errors are reported with their index, e.g. "players[1].puuid".
+func ValidateBatchMatchRequest(request *BatchMatchRequest, maxBatchSize int) *ValidationResult {
+	result := &ValidationResult{}
+
+	if len(request.Players) == 0 {
+		result.AddError("players", "players must contain at least one entry")
+		return result
+	}
+
+	if len(request.Players) > maxBatchSize {
+		result.AddError("players", fmt.Sprintf("players cannot exceed %d entries", maxBatchSize))
+		return result
+	}
+
+	for index, item := range request.Players {
+		itemResult := &ValidationResult{}
+
+		validateRegion(item.Region, itemResult)
+		validatePUUID(item.PUUID, itemResult)
+		validateCountField(item.Count, itemResult)
+
+		for _, validationError := range itemResult.Errors {
+			result.AddError(fmt.Sprintf("players[%d].%s", index, validationError.Field), validationError.Message)
+		}
+	}
+
+	return result
+}
+
+// ValidateCompareRequest validates a head-to-head comparison request, which takes exactly two
+// players. Item errors are reported with their index, e.g. "players[1].tagLine".
+func ValidateCompareRequest(request *CompareRequest) *ValidationResult {
+	result := &ValidationResult{}
+
+	if len(request.Players) != ComparePlayerCount {
+		result.AddError("players", fmt.Sprintf("players must contain exactly %d entries", ComparePlayerCount))
+		return result
+	}
+
+	for index := range request.Players {
+		itemResult := ValidateSummonerRequest(&request.Players[index])
+
+		for _, validationError := range itemResult.Errors {
+			result.AddError(fmt.Sprintf("players[%d].%s", index, validationError.Field), validationError.Message)
+		}
+	}
+
+	validateCountField(request.Count, result)
+
+	return result
+}
+
+// ValidateCacheWarmRequest validates a cache warm request, enforcing a maximum number of players,
+// and canonicalizes orgId.
+// Item errors are reported with their index, e.g. "players[1].gameName".
+func ValidateCacheWarmRequest(request *CacheWarmRequest, maxPlayers int) *ValidationResult {
+	result := &ValidationResult{}
+
+	if request.OrgID != "" {
+		parsedOrgID, err := uuid.Parse(request.OrgID)
+		if err != nil {
+			result.AddError("orgId", "orgId must be a valid UUID")
+		} else {
+			// Match the canonical form OrgMiddleware stores so warmed keys are hit
+			request.OrgID = parsedOrgID.String()
+		}
+	}
+
+	if len(request.Players) == 0 {
+		result.AddError("players", "players must contain at least one entry")
+		return result
+	}
+
+	if len(request.Players) > maxPlayers {
+		result.AddError("players", fmt.Sprintf("players cannot exceed %d entries", maxPlayers))
+		return result
+	}
+
+	for index := range request.Players {
+		itemResult := ValidateSummonerRequest(&request.Players[index])
+
+		for _, validationError := range itemResult.Errors {
+			result.AddError(fmt.Sprintf("players[%d].%s", index, validationError.Field), validationError.Message)
+		}
+	}
+
+	return result
+}
+
 // validateRegion checks if region is valid
 func validateRegion(region string, result *ValidationResult) {
 	if region == "" {
@@ -129,6 +410,11 @@ func validateRegion(region string, result *ValidationResult) {
 		return
 	}
 
+	if len(region) > maxRegionLength {
+		result.AddError("region", fmt.Sprintf("region must be at most %d characters", maxRegionLength))
+		return
+	}
+
 	lowercaseRegion := strings.ToLower(region)
 	if !ValidRegions[lowercaseRegion] {
 		result.AddError("region", "invalid region. Valid regions: na, euw, eune, kr, jp, br, lan, las, oce, tr, ru, ph, sg, th, tw, vn")
@@ -154,7 +440,6 @@ func validateGameName(gameName string, result *ValidationResult) {
 	}
 
 	// Game names can only contain letters, numbers, spaces, and underscores
-	validGameNamePattern := regexp.MustCompile(`^[a-zA-Z0-9 _]+$`)
 	if !validGameNamePattern.MatchString(gameName) {
 		result.AddError("gameName", "gameName can only contain letters, numbers, spaces, and underscores")
 	}
@@ -179,7 +464,6 @@ func validateTagLine(tagLine string, result *ValidationResult) {
 	}
 
 	// Tag lines can only contain alphanumeric characters
-	validTagLinePattern := regexp.MustCompile(`^[a-zA-Z0-9]+$`)
 	if !validTagLinePattern.MatchString(tagLine) {
 		result.AddError("tagLine", "tagLine can only contain letters and numbers")
 	}
@@ -199,12 +483,34 @@ func validatePUUID(puuid string, result *ValidationResult) {
 	}
 
 	// PUUIDs contain alphanumeric characters, hyphens, and underscores
-	validPUUIDPattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 	if !validPUUIDPattern.MatchString(puuid) {
 		result.AddError("puuid", "puuid contains invalid characters")
 	}
 }
 
+// validateMatchID checks that a match ID has Riot's "<PLATFORM>_<gameId>" form, e.g. "NA1_4567890123"
+func validateMatchID(matchID string, result *ValidationResult) {
+	if matchID == "" {
+		result.AddError("matchId", "matchId is required")
+		return
+	}
+
+	if len(matchID) > maxMatchIDLength || !validMatchIDPattern.MatchString(matchID) {
+		result.AddError("matchId", "matchId must look like NA1_1234567890")
+	}
+}
+
+// validateCountField checks that count is an integer and within valid range
+func validateCountField(count IntegerField, result *ValidationResult) {
+	value, err := count.Int()
+	if err != nil {
+		result.AddError("count", "count must be an integer")
+		return
+	}
+
+	validateCount(value, result)
+}
+
 // validateCount checks if count is within valid range
 func validateCount(count int, result *ValidationResult) {
 	// Count of 0 is allowed (will use default of 20)
@@ -219,7 +525,74 @@ func validateCount(count int, result *ValidationResult) {
 	}
 }
 
+// validateCursor checks that a provided pagination cursor can be decoded
+func validateCursor(cursor *string, result *ValidationResult) {
+	// Absent or empty cursor requests the first page
+	if cursor == nil || *cursor == "" {
+		return
+	}
+
+	if len(*cursor) > maxCursorLength {
+		result.AddError("cursor", fmt.Sprintf("cursor must be at most %d characters", maxCursorLength))
+		return
+	}
+
+	if _, err := pagination.DecodeMatchCursor(*cursor); err != nil {
+		result.AddError("cursor", "cursor is malformed")
+	}
+}
+
+// maxFutureTimeSkew tolerates clock skew between clients and the gateway for time bounds
+const maxFutureTimeSkew = 5 * time.Minute
+
+// validateTimeRange checks optional Unix-second time bounds: each must be non-negative and not
+// in the future beyond maxFutureTimeSkew, and startTime must not be after endTime
+func validateTimeRange(startTime *int64, endTime *int64, now time.Time, result *ValidationResult) {
+	latestAllowed := now.Add(maxFutureTimeSkew).Unix()
+
+	for _, bound := range []struct {
+		field string
+		value *int64
+	}{
+		{"startTime", startTime},
+		{"endTime", endTime},
+	} {
+		if bound.value == nil {
+			continue
+		}
+		if *bound.value < 0 {
+			result.AddError(bound.field, bound.field+" cannot be negative")
+		} else if *bound.value > latestAllowed {
+			result.AddError(bound.field, bound.field+" cannot be in the future")
+		}
+	}
+
+	if startTime != nil && endTime != nil && *startTime > *endTime {
+		result.AddError("startTime", "startTime must not be after endTime")
+	}
+}
+
 // NormalizeRegion converts region to lowercase for consistent API calls
 func NormalizeRegion(region string) string {
 	return strings.ToLower(region)
 }
+
+// FoldRiotID returns the case-folded form of a game name or tag line. Riot IDs are
+// case-insensitive for lookup, so two spellings that differ only in case fold to the same
+// string. Each rune is mapped to the smallest member of its Unicode simple case-folding
+// orbit before lowercasing, so e.g. the Kelvin sign and "K", or final and medial sigma,
+// also compare equal.
+func FoldRiotID(value string) string {
+	return strings.ToLower(strings.Map(foldRune, value))
+}
+
+// foldRune returns the smallest rune that is case-equivalent to character
+func foldRune(character rune) rune {
+	smallest := character
+	for folded := unicode.SimpleFold(character); folded != character; folded = unicode.SimpleFold(folded) {
+		if folded < smallest {
+			smallest = folded
+		}
+	}
+	return smallest
+}
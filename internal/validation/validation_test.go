@@ -3,6 +3,10 @@ package validation
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
+	"github.com/OPGLOL/opgl-gateway-service/internal/pagination"
 )
 
 // TestValidationResult_IsValid tests the IsValid method
@@ -72,6 +76,18 @@ func TestValidateSummonerRequest_Valid(t *testing.T) {
 	}
 }
 
+// TestValidatePartialSummonerRequest tests that only the fields that are set are validated
+func TestValidatePartialSummonerRequest(t *testing.T) {
+	if result := ValidatePartialSummonerRequest(&SummonerRequest{Region: "na"}); !result.IsValid() {
+		t.Errorf("Expected empty gameName and tagLine to be skipped, got errors: %s", result.GetErrorMessages())
+	}
+
+	result := ValidatePartialSummonerRequest(&SummonerRequest{Region: "invalid", TagLine: "N"})
+	if len(result.Errors) != 2 || result.Errors[0].Field != "region" || result.Errors[1].Field != "tagLine" {
+		t.Errorf("Expected region and tagLine errors, got %+v", result.Errors)
+	}
+}
+
 // TestValidateSummonerRequest_ValidUppercaseRegion tests region normalization
 func TestValidateSummonerRequest_ValidUppercaseRegion(t *testing.T) {
 	request := &SummonerRequest{
@@ -236,7 +252,7 @@ func TestValidateMatchRequest_ValidWithRiotID(t *testing.T) {
 		Region:   "na",
 		GameName: "TestPlayer",
 		TagLine:  "NA1",
-		Count:    10,
+		Count:    IntegerField("10"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -254,7 +270,7 @@ func TestValidateMatchRequest_ValidWithPUUID(t *testing.T) {
 	request := &MatchRequest{
 		Region: "na",
 		PUUID:  validPUUID,
-		Count:  10,
+		Count:  IntegerField("10"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -264,12 +280,51 @@ func TestValidateMatchRequest_ValidWithPUUID(t *testing.T) {
 	}
 }
 
+// TestValidateMatchRequest_ConflictingIdentity tests that PUUID and Riot ID cannot both be provided
+func TestValidateMatchRequest_ConflictingIdentity(t *testing.T) {
+	validPUUID := "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789abcdef"
+
+	testCases := []struct {
+		name     string
+		gameName string
+		tagLine  string
+	}{
+		{"full riot id", "TestPlayer", "NA1"},
+		{"game name only", "TestPlayer", ""},
+		{"tag line only", "", "NA1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &MatchRequest{
+				Region:   "na",
+				PUUID:    validPUUID,
+				GameName: testCase.gameName,
+				TagLine:  testCase.tagLine,
+			}
+
+			result := ValidateMatchRequest(request)
+
+			if result.IsValid() {
+				t.Fatal("Expected invalid request when both identity forms are provided")
+			}
+			if result.Errors[0].Field != "puuid" {
+				t.Errorf("Expected error on field 'puuid', got '%s'", result.Errors[0].Field)
+			}
+			expectedMessage := "provide either puuid or gameName+tagLine, not both"
+			if result.Errors[0].Message != expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", expectedMessage, result.Errors[0].Message)
+			}
+		})
+	}
+}
+
 // TestValidateMatchRequest_InvalidPUUIDLength tests invalid PUUID length
 func TestValidateMatchRequest_InvalidPUUIDLength(t *testing.T) {
 	request := &MatchRequest{
 		Region: "na",
 		PUUID:  "short-puuid",
-		Count:  10,
+		Count:  IntegerField("10"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -285,7 +340,7 @@ func TestValidateMatchRequest_NegativeCount(t *testing.T) {
 		Region:   "na",
 		GameName: "TestPlayer",
 		TagLine:  "NA1",
-		Count:    -1,
+		Count:    IntegerField("-1"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -301,7 +356,7 @@ func TestValidateMatchRequest_CountTooHigh(t *testing.T) {
 		Region:   "na",
 		GameName: "TestPlayer",
 		TagLine:  "NA1",
-		Count:    101,
+		Count:    IntegerField("101"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -317,7 +372,7 @@ func TestValidateMatchRequest_ZeroCountAllowed(t *testing.T) {
 		Region:   "na",
 		GameName: "TestPlayer",
 		TagLine:  "NA1",
-		Count:    0,
+		Count:    IntegerField("0"),
 	}
 
 	result := ValidateMatchRequest(request)
@@ -383,6 +438,31 @@ func TestNormalizeRegion(t *testing.T) {
 	}
 }
 
+// TestFoldRiotID tests that Riot IDs differing only in case fold to the same value
+func TestFoldRiotID(t *testing.T) {
+	testCases := []struct {
+		first  string
+		second string
+	}{
+		{"Faker", "faker"},
+		{"KR1", "kr1"},
+		{"Ünsal", "üNSAL"},
+		{"\u212Aai", "Kai"},      // Kelvin sign
+		{"Οδυσσεύς", "ΟΔΥΣΣΕΎΣ"}, // final and medial sigma
+	}
+
+	for _, testCase := range testCases {
+		if FoldRiotID(testCase.first) != FoldRiotID(testCase.second) {
+			t.Errorf("Expected '%s' and '%s' to fold equally, got '%s' and '%s'",
+				testCase.first, testCase.second, FoldRiotID(testCase.first), FoldRiotID(testCase.second))
+		}
+	}
+
+	if FoldRiotID("Faker") != "faker" {
+		t.Errorf("Expected 'faker', got '%s'", FoldRiotID("Faker"))
+	}
+}
+
 // TestValidRegions tests that ValidRegions map contains expected regions
 func TestValidRegions(t *testing.T) {
 	expectedRegions := []string{"na", "euw", "eune", "kr", "jp", "br", "lan", "las", "oce", "tr", "ru", "ph", "sg", "th", "tw", "vn"}
@@ -418,3 +498,369 @@ func TestValidateSummonerRequest_MultipleErrors(t *testing.T) {
 		t.Errorf("Expected 3 errors, got %d: %s", len(result.Errors), result.GetErrorMessages())
 	}
 }
+
+// TestValidateBatchMatchRequest_Valid tests a valid batch match request
+func TestValidateBatchMatchRequest_Valid(t *testing.T) {
+	request := &BatchMatchRequest{
+		Players: []BatchMatchItem{
+			{Region: "na", PUUID: strings.Repeat("a", 78), Count: IntegerField("10")},
+			{Region: "EUW", PUUID: strings.Repeat("b", 78)},
+		},
+	}
+
+	result := ValidateBatchMatchRequest(request, 5)
+
+	if !result.IsValid() {
+		t.Errorf("Expected valid request, got errors: %s", result.GetErrorMessages())
+	}
+}
+
+// TestValidateBatchMatchRequest_Empty tests that an empty batch is rejected
+func TestValidateBatchMatchRequest_Empty(t *testing.T) {
+	result := ValidateBatchMatchRequest(&BatchMatchRequest{}, 5)
+
+	if result.IsValid() {
+		t.Error("Expected empty batch to be invalid")
+	}
+}
+
+// TestValidateBatchMatchRequest_ExceedsMaxSize tests the batch size cap
+func TestValidateBatchMatchRequest_ExceedsMaxSize(t *testing.T) {
+	players := make([]BatchMatchItem, 3)
+	for index := range players {
+		players[index] = BatchMatchItem{Region: "na", PUUID: strings.Repeat("a", 78)}
+	}
+
+	result := ValidateBatchMatchRequest(&BatchMatchRequest{Players: players}, 2)
+
+	if result.IsValid() {
+		t.Fatal("Expected batch exceeding max size to be invalid")
+	}
+
+	if !strings.Contains(result.GetErrorMessages(), "cannot exceed 2") {
+		t.Errorf("Expected size cap message, got '%s'", result.GetErrorMessages())
+	}
+}
+
+// TestValidateBatchMatchRequest_InvalidItem tests that item errors include the item index
+func TestValidateBatchMatchRequest_InvalidItem(t *testing.T) {
+	request := &BatchMatchRequest{
+		Players: []BatchMatchItem{
+			{Region: "na", PUUID: strings.Repeat("a", 78)},
+			{Region: "na", PUUID: "short", Count: IntegerField("-1")},
+		},
+	}
+
+	result := ValidateBatchMatchRequest(request, 5)
+
+	if result.IsValid() {
+		t.Fatal("Expected invalid item to fail validation")
+	}
+
+	errorMessages := result.GetErrorMessages()
+	if !strings.Contains(errorMessages, "players[1].puuid") {
+		t.Errorf("Expected indexed puuid error, got '%s'", errorMessages)
+	}
+
+	if !strings.Contains(errorMessages, "players[1].count") {
+		t.Errorf("Expected indexed count error, got '%s'", errorMessages)
+	}
+}
+
+// TestValidateMatchRequest_Cursor tests cursor validation on match requests
+func TestValidateMatchRequest_Cursor(t *testing.T) {
+	emptyCursor := ""
+	validCursor := pagination.EncodeMatchCursor(models.MatchCursor{Timestamp: 1700000000000, MatchID: "NA1_1"})
+	malformedCursor := "not-a-cursor"
+
+	testCases := []struct {
+		name    string
+		cursor  *string
+		isValid bool
+	}{
+		{"absent cursor", nil, true},
+		{"empty cursor", &emptyCursor, true},
+		{"valid cursor", &validCursor, true},
+		{"malformed cursor", &malformedCursor, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &MatchRequest{
+				Region:   "na",
+				GameName: "TestPlayer",
+				TagLine:  "NA1",
+				Cursor:   testCase.cursor,
+			}
+
+			result := ValidateMatchRequest(request)
+
+			if result.IsValid() != testCase.isValid {
+				t.Errorf("Expected valid=%v, got errors: %s", testCase.isValid, result.GetErrorMessages())
+			}
+		})
+	}
+}
+
+// TestValidateMatchRequest_NonIntegerCount tests that float and string counts report a clear error
+func TestValidateMatchRequest_NonIntegerCount(t *testing.T) {
+	testCases := []struct {
+		name  string
+		count IntegerField
+	}{
+		{"float count", IntegerField("1.5")},
+		{"string count", IntegerField(`"10"`)},
+		{"object count", IntegerField("{}")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &MatchRequest{
+				Region:   "na",
+				GameName: "TestPlayer",
+				TagLine:  "NA1",
+				Count:    testCase.count,
+			}
+
+			result := ValidateMatchRequest(request)
+
+			if result.IsValid() {
+				t.Fatal("Expected non-integer count to be invalid")
+			}
+
+			if !strings.Contains(result.GetErrorMessages(), "count must be an integer") {
+				t.Errorf("Expected 'count must be an integer', got '%s'", result.GetErrorMessages())
+			}
+		})
+	}
+}
+
+// TestIntegerField_Int tests integer parsing of raw JSON values
+func TestIntegerField_Int(t *testing.T) {
+	testCases := []struct {
+		name        string
+		field       IntegerField
+		expected    int
+		expectError bool
+	}{
+		{"absent", nil, 0, false},
+		{"null", IntegerField("null"), 0, false},
+		{"integer", IntegerField("25"), 25, false},
+		{"negative", IntegerField("-3"), -3, false},
+		{"float", IntegerField("2.0"), 0, true},
+		{"string", IntegerField(`"25"`), 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := testCase.field.Int()
+
+			if (err != nil) != testCase.expectError {
+				t.Fatalf("Expected error=%v, got %v", testCase.expectError, err)
+			}
+
+			if value != testCase.expected {
+				t.Errorf("Expected %d, got %d", testCase.expected, value)
+			}
+		})
+	}
+}
+
+// TestIntegerField_IsSet tests that absent and null fields are reported as not set
+func TestIntegerField_IsSet(t *testing.T) {
+	if (IntegerField(nil)).IsSet() || IntegerField("null").IsSet() {
+		t.Error("Expected absent and null fields to be unset")
+	}
+	if !IntegerField("0").IsSet() || !IntegerField(`"abc"`).IsSet() {
+		t.Error("Expected any sent value to be set")
+	}
+}
+
+// TestValidateMatchRequest_TimeRange tests validation of optional startTime/endTime bounds
+func TestValidateMatchRequest_TimeRange(t *testing.T) {
+	now := time.Now().Unix()
+	weekAgo := now - 7*24*60*60
+	farFuture := now + 24*60*60
+	slightlyAhead := now + 60
+	negative := int64(-1)
+
+	testCases := []struct {
+		name          string
+		startTime     *int64
+		endTime       *int64
+		isValid       bool
+		expectedError string
+	}{
+		{"no bounds", nil, nil, true, ""},
+		{"start only", &weekAgo, nil, true, ""},
+		{"end only", nil, &now, true, ""},
+		{"valid range", &weekAgo, &now, true, ""},
+		{"equal bounds", &weekAgo, &weekAgo, true, ""},
+		{"end within clock skew tolerance", &weekAgo, &slightlyAhead, true, ""},
+		{"inverted range", &now, &weekAgo, false, "startTime must not be after endTime"},
+		{"future end", &weekAgo, &farFuture, false, "endTime cannot be in the future"},
+		{"future start", &farFuture, nil, false, "startTime cannot be in the future"},
+		{"negative start", &negative, nil, false, "startTime cannot be negative"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &MatchRequest{
+				Region:    "na",
+				GameName:  "TestPlayer",
+				TagLine:   "NA1",
+				StartTime: testCase.startTime,
+				EndTime:   testCase.endTime,
+			}
+
+			result := ValidateMatchRequest(request)
+
+			if result.IsValid() != testCase.isValid {
+				t.Fatalf("Expected valid=%v, got errors: %s", testCase.isValid, result.GetErrorMessages())
+			}
+
+			if testCase.expectedError != "" && !strings.Contains(result.GetErrorMessages(), testCase.expectedError) {
+				t.Errorf("Expected error '%s', got '%s'", testCase.expectedError, result.GetErrorMessages())
+			}
+		})
+	}
+}
+
+// TestValidateCacheWarmRequest tests cache warm validation and orgId canonicalization
+func TestValidateCacheWarmRequest(t *testing.T) {
+	request := &CacheWarmRequest{
+		OrgID: "123E4567-E89B-12D3-A456-426614174000",
+		Players: []SummonerRequest{
+			{Region: "na", GameName: "TestPlayer", TagLine: "NA1"},
+			{Region: "xx", GameName: "TestPlayer", TagLine: "NA1"},
+		},
+	}
+
+	result := ValidateCacheWarmRequest(request, 10)
+
+	if !strings.Contains(result.GetErrorMessages(), "players[1].region") {
+		t.Errorf("Expected indexed region error, got '%s'", result.GetErrorMessages())
+	}
+	if request.OrgID != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Expected canonical orgId, got '%s'", request.OrgID)
+	}
+
+	result = ValidateCacheWarmRequest(&CacheWarmRequest{Players: request.Players[:1]}, 10)
+	if !result.IsValid() {
+		t.Errorf("Expected request without orgId to be valid, got '%s'", result.GetErrorMessages())
+	}
+}
+
+// TestValidate_OversizedFields tests that 10KB field values are rejected by the length bound before any pattern matching
+func TestValidate_OversizedFields(t *testing.T) {
+	oversized := strings.Repeat("a", 10<<10)
+
+	testCases := []struct {
+		name            string
+		validate        func() *ValidationResult
+		expectedField   string
+		expectedMessage string
+	}{
+		{
+			name: "region",
+			validate: func() *ValidationResult {
+				return ValidateSummonerRequest(&SummonerRequest{Region: oversized, GameName: "Faker", TagLine: "KR1"})
+			},
+			expectedField:   "region",
+			expectedMessage: "region must be at most 32 characters",
+		},
+		{
+			name: "gameName",
+			validate: func() *ValidationResult {
+				return ValidateSummonerRequest(&SummonerRequest{Region: "kr", GameName: oversized, TagLine: "KR1"})
+			},
+			expectedField:   "gameName",
+			expectedMessage: "gameName must be at most 16 characters",
+		},
+		{
+			name: "tagLine",
+			validate: func() *ValidationResult {
+				return ValidateAnalyzeRequest(&AnalyzeRequest{Region: "kr", GameName: "Faker", TagLine: oversized})
+			},
+			expectedField:   "tagLine",
+			expectedMessage: "tagLine must be at most 5 characters",
+		},
+		{
+			name: "puuid",
+			validate: func() *ValidationResult {
+				return ValidateMatchRequest(&MatchRequest{Region: "kr", PUUID: oversized})
+			},
+			expectedField:   "puuid",
+			expectedMessage: "puuid must be 78 characters",
+		},
+		{
+			name: "cursor",
+			validate: func() *ValidationResult {
+				return ValidateMatchRequest(&MatchRequest{Region: "kr", GameName: "Faker", TagLine: "KR1", Cursor: &oversized})
+			},
+			expectedField:   "cursor",
+			expectedMessage: "cursor must be at most 256 characters",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := testCase.validate()
+			if len(result.Errors) != 1 {
+				t.Fatalf("Expected exactly 1 error, got %v", result.Errors)
+			}
+			if result.Errors[0].Field != testCase.expectedField || result.Errors[0].Message != testCase.expectedMessage {
+				t.Errorf("Expected %s: %s, got %s: %s", testCase.expectedField, testCase.expectedMessage, result.Errors[0].Field, result.Errors[0].Message)
+			}
+
+			// The length bound makes rejection independent of the value size
+			started := time.Now()
+			for iteration := 0; iteration < 1000; iteration++ {
+				testCase.validate()
+			}
+			if elapsed := time.Since(started); elapsed > time.Second {
+				t.Errorf("Expected 1000 rejections well under 1s, took %v", elapsed)
+			}
+		})
+	}
+}
+
+// TestValidateMatchIDsRequest tests validation of match ID list requests
+func TestValidateMatchIDsRequest(t *testing.T) {
+	validPUUID := strings.Repeat("a", 78)
+
+	if result := ValidateMatchIDsRequest(&MatchIDsRequest{Region: "na", PUUID: validPUUID, Count: IntegerField("50")}); !result.IsValid() {
+		t.Errorf("Expected valid request, got errors: %s", result.GetErrorMessages())
+	}
+
+	result := ValidateMatchIDsRequest(&MatchIDsRequest{Region: "na", Count: IntegerField("-1")})
+	if len(result.Errors) != 2 || result.Errors[0].Field != "puuid" || result.Errors[1].Field != "count" {
+		t.Errorf("Expected puuid and count errors, got %+v", result.Errors)
+	}
+}
+
+// TestValidateMatchTimelineRequest tests match ID validation for timeline requests
+func TestValidateMatchTimelineRequest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		matchID       string
+		expectedValid bool
+	}{
+		{"valid", "NA1_4567890123", true},
+		{"valid short platform", "KR_123", true},
+		{"missing", "", false},
+		{"no separator", "NA14567890123", false},
+		{"lowercase platform", "na1_4567890123", false},
+		{"non-numeric game id", "NA1_abc", false},
+		{"too long", "NA1_" + strings.Repeat("1", 40), false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := ValidateMatchTimelineRequest(&MatchTimelineRequest{Region: "na", MatchID: testCase.matchID})
+			if result.IsValid() != testCase.expectedValid {
+				t.Errorf("Expected valid=%v for '%s', got errors: %s", testCase.expectedValid, testCase.matchID, result.GetErrorMessages())
+			}
+		})
+	}
+}
@@ -6,12 +6,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/OPGLOL/opgl-gateway-service/internal/api"
+	"github.com/OPGLOL/opgl-gateway-service/internal/cache"
+	apierrors "github.com/OPGLOL/opgl-gateway-service/internal/errors"
+	"github.com/OPGLOL/opgl-gateway-service/internal/metrics"
 	"github.com/OPGLOL/opgl-gateway-service/internal/middleware"
+	"github.com/OPGLOL/opgl-gateway-service/internal/models"
 	"github.com/OPGLOL/opgl-gateway-service/internal/proxy"
+	"github.com/OPGLOL/opgl-gateway-service/internal/server"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -24,7 +33,21 @@ func main() {
 	}).With().Timestamp().Caller().Logger()
 
 	// Set global log level (can be configured via environment variable)
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	logLevelName := os.Getenv("LOG_LEVEL")
+	if logLevelName == "" {
+		logLevelName = "info"
+	}
+	logLevel, err := zerolog.ParseLevel(logLevelName)
+	if err != nil || logLevel == zerolog.NoLevel {
+		log.Fatal().Str("value", os.Getenv("LOG_LEVEL")).Msg("Invalid LOG_LEVEL configuration")
+	}
+	zerolog.SetGlobalLevel(logLevel)
+
+	// In multi-region deployments, tag every log line with the serving gateway region
+	gatewayRegion := os.Getenv("GATEWAY_REGION")
+	if gatewayRegion != "" {
+		log.Logger = log.Logger.With().Str("gateway_region", gatewayRegion).Logger()
+	}
 
 	log.Info().Msg("Starting OPGL Gateway")
 
@@ -56,56 +79,465 @@ func main() {
 		Str("auth_service_url", authServiceURL).
 		Msg("Configuration loaded")
 
-	// Initialize service proxy
-	serviceProxy := proxy.NewServiceProxy(dataServiceURL, cortexServiceURL)
+	// Metrics exposed on GET /metrics (Prometheus) and GET /stats (JSON)
+	metricsRegistry := metrics.NewRegistry()
+	cacheMetrics := metrics.NewCacheMetrics(metricsRegistry)
+	httpMetrics := metrics.NewHTTPMetrics(metricsRegistry)
+	upstreamMetrics := metrics.NewUpstreamMetrics(metricsRegistry)
+
+	// Initialize service proxy; upstream retries are opt-in via UPSTREAM_MAX_ATTEMPTS
+	proxyOptions := []proxy.Option{
+		proxy.WithPathPrefixes(os.Getenv("OPGL_DATA_PATH_PREFIX"), os.Getenv("OPGL_CORTEX_PATH_PREFIX")),
+		proxy.WithUpstreamMetrics(upstreamMetrics),
+	}
+	// Optional opgl-data replicas, load-balanced with the primary by recent error rate and latency
+	if dataReplicaURLs := getEnvList("OPGL_DATA_REPLICA_URLS", ""); len(dataReplicaURLs) > 0 {
+		proxyOptions = append(proxyOptions, proxy.WithDataReplicas(dataReplicaURLs))
+		log.Info().Strs("data_replica_urls", dataReplicaURLs).Msg("Data service replicas enabled")
+	}
+	// Optional secondary cortex instance for analyses the primary fails to serve
+	if cortexFallbackURL := os.Getenv("OPGL_CORTEX_FALLBACK_URL"); cortexFallbackURL != "" {
+		proxyOptions = append(proxyOptions, proxy.WithFallbackCortex(cortexFallbackURL))
+		log.Info().Str("cortex_fallback_url", cortexFallbackURL).Msg("Cortex fallback enabled")
+	}
+	// Gzip large analyze payloads, only when opgl-cortex is known to accept Content-Encoding: gzip
+	if os.Getenv("CORTEX_ACCEPTS_GZIP") == "true" {
+		proxyOptions = append(proxyOptions, proxy.WithAnalyzeCompression(getEnvInt("CORTEX_GZIP_THRESHOLD_BYTES", 32<<10)))
+	}
+	// Egress proxy for upstream calls; by default HTTP_PROXY/HTTPS_PROXY/NO_PROXY are honored
+	outboundProxy, err := proxy.ParseOutboundProxy(os.Getenv("UPSTREAM_PROXY"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid UPSTREAM_PROXY configuration")
+	}
+	if outboundProxy != nil {
+		proxyOptions = append(proxyOptions, proxy.WithOutboundProxy(outboundProxy))
+	}
+	// Report players without matches consistently as [] (default) or 404
+	emptyMatchesPolicy, err := proxy.ParseEmptyMatchesPolicy(os.Getenv("EMPTY_MATCHES_POLICY"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid EMPTY_MATCHES_POLICY configuration")
+	}
+	proxyOptions = append(proxyOptions, proxy.WithEmptyMatchesPolicy(emptyMatchesPolicy))
+	// Performance diagnostics only: per-phase connection timings add overhead to every call
+	if os.Getenv("UPSTREAM_CONNECTION_TRACE") == "true" {
+		proxyOptions = append(proxyOptions, proxy.WithConnectionTrace())
+	}
+	if maxAttempts := getEnvInt("UPSTREAM_MAX_ATTEMPTS", 1); maxAttempts > 1 {
+		proxyOptions = append(proxyOptions, proxy.WithRetries(
+			maxAttempts,
+			getEnvDuration("UPSTREAM_RETRY_BASE_DELAY", 100*time.Millisecond),
+			getEnvDuration("UPSTREAM_MAX_RETRY_AFTER", 5*time.Second),
+		))
+		// Analysis may have side effects in opgl-cortex, so it is only retried on request
+		if os.Getenv("UPSTREAM_RETRY_ANALYZE") == "true" {
+			proxyOptions = append(proxyOptions, proxy.WithIdempotentCall(proxy.CallAnalyzePlayer, true))
+		}
+	}
+
+	// Chaos testing only: fault injection requires an explicit opt-in flag
+	if os.Getenv("ENABLE_FAULT_INJECTION") == "true" {
+		faultRules, err := proxy.ParseFaultSpec(os.Getenv("FAULT_INJECT"))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid FAULT_INJECT configuration")
+		}
+		proxyOptions = append(proxyOptions, proxy.WithFaultInjection(faultRules))
+		log.Warn().Str("fault_inject", os.Getenv("FAULT_INJECT")).Msg("Fault injection enabled for upstream calls")
+	} else if os.Getenv("FAULT_INJECT") != "" {
+		log.Warn().Msg("FAULT_INJECT is set but ignored because ENABLE_FAULT_INJECTION is not true")
+	}
+
+	serviceProxy := proxy.NewServiceProxy(dataServiceURL, cortexServiceURL, proxyOptions...)
+
+	// In production, server-side error messages are replaced with generic client-safe text
+	environment, err := apierrors.ParseEnvironment(os.Getenv("ENVIRONMENT"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid ENVIRONMENT configuration")
+	}
+	apierrors.SetEnvironment(environment)
+
+	// Error response shape (the gateway's own JSON by default, or RFC 7807 problem+json)
+	errorFormat, err := apierrors.ParseErrorFormat(os.Getenv("ERROR_FORMAT"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid ERROR_FORMAT configuration")
+	}
+	apierrors.SetErrorFormat(errorFormat)
+
+	// Response envelope mode (raw by default for backward compatibility)
+	responseEnvelope, err := api.ParseResponseEnvelope(os.Getenv("RESPONSE_ENVELOPE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid RESPONSE_ENVELOPE configuration")
+	}
+
+	// Optional allowlist of regions served by this deployment (empty serves all)
+	enabledRegions, err := api.ParseEnabledRegions(os.Getenv("ENABLED_REGIONS"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid ENABLED_REGIONS configuration")
+	}
+
+	// Strict regions reject platform codes like "na1"; lenient mode maps them to "na"
+	regionMode, err := api.ParseRegionMode(os.Getenv("REGION_MODE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid REGION_MODE configuration")
+	}
+
+	// Lenient field mode defaults omitted optional fields (count); strict mode requires them
+	fieldMode, err := api.ParseFieldMode(os.Getenv("FIELD_MODE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid FIELD_MODE configuration")
+	}
+
+	// Unknown ?fields= names are dropped by default; reject answers them with 400
+	unknownFieldsPolicy, err := api.ParseUnknownFieldsPolicy(os.Getenv("UNKNOWN_FIELDS_POLICY"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid UNKNOWN_FIELDS_POLICY configuration")
+	}
+
+	// Readiness is flipped to not-ready while draining before shutdown
+	readiness := server.NewReadiness()
+
+	// Upstreams checked by /ready: required ones failing report 503, optional ones only "degraded"
+	readinessDependenciesSpec := os.Getenv("READY_DEPENDENCIES")
+	if readinessDependenciesSpec == "" {
+		readinessDependenciesSpec = api.DefaultReadinessDependencies
+	}
+	readinessDependencies, err := api.ParseReadinessDependencies(readinessDependenciesSpec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid READY_DEPENDENCIES configuration")
+	}
+
+	// Reuse /ready dependency checks briefly so frequent probes do not each hit the upstreams
+	readinessCacheTTL := getEnvDuration("READY_CACHE_TTL", api.DefaultReadinessCacheTTL)
+
+	// Analyze mode defaults and permissions per API key tier reported by the auth service
+	analyzeTierModesSpec, found := os.LookupEnv("ANALYZE_TIER_MODES")
+	if !found {
+		analyzeTierModesSpec = api.DefaultAnalyzeTierModes
+	}
+	analyzeTierModes, err := api.ParseAnalyzeTierModes(analyzeTierModesSpec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid ANALYZE_TIER_MODES configuration")
+	}
+
+	// Cache backend for the summoner and analysis caches; redis shares entries across instances
+	cacheBackend, err := cache.ParseBackend(os.Getenv("CACHE_BACKEND"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid CACHE_BACKEND configuration")
+	}
+	var redisClient *redis.Client
+	if cacheBackend == cache.BackendRedis {
+		redisClient, err = cache.NewRedisClient(os.Getenv("REDIS_URL"))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid REDIS_URL configuration")
+		}
+		defer redisClient.Close()
+		log.Info().Msg("Redis cache backend enabled")
+	}
+
+	analyzeCoalesceWindow := getEnvDuration("ANALYZE_COALESCE_WINDOW", 10*time.Second)
+	handlerOptions := []api.HandlerOption{
+		api.WithResponseEnvelope(responseEnvelope),
+		api.WithReadiness(readiness),
+		api.WithReadinessDependencies(readinessDependencies),
+		api.WithReadinessCache(readinessCacheTTL),
+		api.WithEnabledRegions(enabledRegions),
+		api.WithRegionMode(regionMode),
+		api.WithFieldMode(fieldMode),
+		api.WithUnknownFieldsPolicy(unknownFieldsPolicy),
+		api.WithBatchLimits(getEnvInt("BATCH_MAX_SIZE", 0), getEnvDuration("BATCH_TIMEOUT", 0)),
+		api.WithBatchConcurrency(getEnvInt("BATCH_CONCURRENCY", 0)),
+		api.WithCacheWarmLimits(getEnvInt("CACHE_WARM_MAX_PLAYERS", 0), getEnvInt("CACHE_WARM_CONCURRENCY", 0)),
+		api.WithAnalyzeMatchDeadline(getEnvDuration("ANALYZE_MATCH_SOFT_DEADLINE", 0)),
+		api.WithAnalyzeCountLimit(getEnvInt("ANALYZE_MAX_COUNT", 0)),
+		api.WithAnalyzeMatchCount(getEnvInt("ANALYZE_MATCH_COUNT", 0)),
+		api.WithAnalyzeMinMatches(getEnvInt("ANALYZE_MIN_MATCHES", 0)),
+		api.WithAnalyzeCoalescing(analyzeCoalesceWindow),
+		api.WithSharedAnalysisTimeout(getEnvDuration("ANALYZE_SHARED_TIMEOUT", 0)),
+		api.WithAnalyzeTierModes(analyzeTierModes),
+		api.WithCacheMetrics(cacheMetrics),
+	}
+
+	if redisClient != nil && analyzeCoalesceWindow > 0 {
+		handlerOptions = append(handlerOptions, api.WithAnalysisCache(cache.NewRedisCache(redisClient, "opgl-gateway:analysis:", analyzeCoalesceWindow, 0, func() interface{} {
+			return &models.AnalysisResult{}
+		})))
+	}
+
+	// Summoner cache; stale entries are served when opgl-data is unavailable
+	summonerCacheTTL := getEnvDuration("SUMMONER_CACHE_TTL", 0)
+	var summonerCache cache.Cache
+	if summonerCacheTTL > 0 {
+		summonerCacheStaleWindow := getEnvDuration("SUMMONER_CACHE_STALE_WINDOW", time.Hour)
+		if redisClient != nil {
+			summonerCache = cache.NewRedisCache(redisClient, "opgl-gateway:summoner:", summonerCacheTTL, summonerCacheStaleWindow, func() interface{} {
+				return &models.Summoner{}
+			})
+		} else {
+			summonerCache = cache.NewMemoryCache(summonerCacheTTL, summonerCacheStaleWindow)
+		}
+		handlerOptions = append(handlerOptions, api.WithSummonerCache(summonerCache))
+		log.Info().
+			Str("backend", string(cacheBackend)).
+			Dur("ttl", summonerCacheTTL).
+			Dur("stale_window", summonerCacheStaleWindow).
+			Msg("Summoner cache enabled")
+	}
+
+	// Liveness self-check: /health reports 503 once the check has not succeeded recently.
+	// The check takes the handler's shared locks, so it times out if a request goroutine has
+	// deadlocked while holding one. It only runs once the handler below is assigned.
+	var handler *api.Handler
+	var livenessProbe *server.LivenessProbe
+	if livenessInterval := getEnvDuration("LIVENESS_CHECK_INTERVAL", 5*time.Second); livenessInterval > 0 {
+		livenessProbe = server.NewLivenessProbe(func(ctx context.Context) error {
+			return handler.LivenessCheck(ctx)
+		}, livenessInterval, getEnvDuration("LIVENESS_CHECK_TIMEOUT", time.Second), getEnvDuration("LIVENESS_STALE_AFTER", 30*time.Second))
+		handlerOptions = append(handlerOptions, api.WithLivenessProbe(livenessProbe))
+	}
 
 	// Initialize HTTP handler
-	handler := api.NewHandler(serviceProxy)
+	handler = api.NewHandler(serviceProxy, handlerOptions...)
 
-	// Initialize rate limit client for auth service
-	rateLimitClient := middleware.NewRateLimitServiceClient(authServiceURL)
+	// Initialize rate limit client for auth service; allowlisted keys (by SHA-256) bypass it
+	bypassKeyHashes, err := middleware.ParseBypassKeyHashes(os.Getenv("RATE_LIMIT_BYPASS_KEY_HASHES"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid RATE_LIMIT_BYPASS_KEY_HASHES configuration")
+	}
+	rateLimitFailurePolicy, err := middleware.ParseRateLimitFailurePolicy(os.Getenv("RATE_LIMIT_FAILURE_POLICY"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid RATE_LIMIT_FAILURE_POLICY configuration")
+	}
+	rateLimitClient := middleware.NewRateLimitServiceClient(authServiceURL,
+		middleware.WithBypassKeyHashes(bypassKeyHashes...),
+		middleware.WithRateLimitUpstreamMetrics(upstreamMetrics),
+		middleware.WithRateLimitFailurePolicy(rateLimitFailurePolicy),
+	)
 	log.Info().
 		Str("auth_service_url", authServiceURL).
+		Int("bypass_keys", len(bypassKeyHashes)).
+		Str("failure_policy", string(rateLimitFailurePolicy)).
 		Msg("Rate limiting enabled via auth service")
 
+	// Parse whitelisted passthrough routes to opgl-data
+	passthroughRoutes, err := api.ParsePassthroughRoutes(os.Getenv("PASSTHROUGH_ROUTES"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid PASSTHROUGH_ROUTES configuration")
+	}
+
+	// Optional concurrency cap on API routes; excess requests queue (bounded by MAX_QUEUED_REQUESTS)
+	queueMetrics := metrics.NewQueueMetrics(metricsRegistry)
+	var bulkhead *middleware.Bulkhead
+	if maxConcurrent := getEnvInt("MAX_CONCURRENT_REQUESTS", 0); maxConcurrent > 0 {
+		bulkhead = middleware.NewBulkhead(maxConcurrent, getEnvDuration("CONCURRENCY_QUEUE_TIMEOUT", 2*time.Second),
+			middleware.WithMaxQueued(getEnvInt("MAX_QUEUED_REQUESTS", 0)),
+			middleware.WithQueueMetrics(queueMetrics, metrics.BulkheadAPI),
+		)
+	}
+
+	// Optional separate cap on the expensive analyze orchestration; by default excess requests get 503 at once
+	var analyzeBulkhead *middleware.Bulkhead
+	if maxConcurrentAnalyze := getEnvInt("MAX_CONCURRENT_ANALYZE", 0); maxConcurrentAnalyze > 0 {
+		analyzeBulkhead = middleware.NewBulkhead(maxConcurrentAnalyze, getEnvDuration("ANALYZE_QUEUE_TIMEOUT", 0),
+			middleware.WithQueueMetrics(queueMetrics, metrics.BulkheadAnalyze),
+		)
+	}
+
+	// Accepted X-API-Schema request schema versions (default: only the current version)
+	schemaVersions, err := middleware.ParseSchemaVersions(os.Getenv("API_SCHEMA_VERSIONS"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid API_SCHEMA_VERSIONS configuration")
+	}
+
+	// Debug only: keep a bounded buffer of recent slow requests for GET /debug/slow
+	var slowRequestRecorder *middleware.SlowRequestRecorder
+	if os.Getenv("ENABLE_SLOW_REQUEST_LOG") == "true" {
+		slowRequestRecorder = middleware.NewSlowRequestRecorder(
+			getEnvDuration("SLOW_REQUEST_THRESHOLD", time.Second),
+			getEnvInt("SLOW_REQUEST_SAMPLES", 20),
+		)
+	}
+
 	// Set up router with all handlers
 	routerConfig := &api.RouterConfig{
-		Handler:         handler,
-		RateLimitClient: rateLimitClient,
+		Handler:           handler,
+		RateLimitClient:   rateLimitClient,
+		PassthroughRoutes: passthroughRoutes,
+		RequireOrgID:      os.Getenv("REQUIRE_ORG_ID") == "true",
+		Bulkhead:          bulkhead,
+		AnalyzeBulkhead:   analyzeBulkhead,
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+		MetricsHandler:    metricsRegistry.Handler(),
+		StatsHandler:      metricsRegistry.StatsHandler(cacheMetrics),
+		APIKeyHeaders:     getEnvList("API_KEY_HEADERS", middleware.DefaultAPIKeyHeader),
+		SchemaVersions:    schemaVersions,
+		HTTPMetrics:       httpMetrics,
+		DebugEcho:         os.Getenv("ENABLE_DEBUG_ECHO") == "true",
+	}
+	if slowRequestRecorder != nil {
+		routerConfig.SlowRequestsHandler = slowRequestRecorder.Handler()
 	}
 	router := api.SetupRouter(routerConfig)
 
+	// Restrict admin/debug routes to internal networks
+	trustedProxies, err := middleware.ParseCIDRs(getEnvList("TRUSTED_PROXIES", ""))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid TRUSTED_PROXIES configuration")
+	}
+	internalNetworks, err := middleware.ParseCIDRs(getEnvList("INTERNAL_NETWORKS", defaultInternalNetworks))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid INTERNAL_NETWORKS configuration")
+	}
+	internalPathPrefixes := getEnvList("INTERNAL_PATH_PREFIXES", "/admin,/debug,/metrics,/stats")
+	restrictedRouter := middleware.InternalNetworkMiddleware(internalPathPrefixes, internalNetworks, trustedProxies)(router)
+
+	// Require HMAC-signed requests from server-to-server callers on SIGNED_PATH_PREFIXES
+	signingSecret := os.Getenv("REQUEST_SIGNING_SECRET")
+	signedPathPrefixes := getEnvList("SIGNED_PATH_PREFIXES", "")
+	signedRouter := middleware.RequestSigningMiddleware([]byte(signingSecret), signedPathPrefixes,
+		getEnvDuration("SIGNATURE_MAX_AGE", middleware.DefaultSignatureMaxAge))(restrictedRouter)
+	if signingSecret != "" && len(signedPathPrefixes) > 0 {
+		log.Info().Strs("path_prefixes", signedPathPrefixes).Msg("Request signing enforced")
+	} else if signingSecret != "" {
+		log.Warn().Msg("REQUEST_SIGNING_SECRET is set but SIGNED_PATH_PREFIXES is empty; no route requires signatures")
+	}
+
 	// Wrap router with CORS middleware first to handle preflight requests
-	corsRouter := middleware.CORSMiddleware(router)
+	corsRouter := middleware.CORSMiddleware(signedRouter)
+
+	// Optional debug logging of request/response bodies (only emitted at LOG_LEVEL=debug)
+	var bodyLogger *middleware.BodyLogger
+	if os.Getenv("LOG_BODIES") == "true" {
+		redactFields := slices.Concat(middleware.DefaultRedactedFields, getEnvList("LOG_REDACT_FIELDS", ""))
+		bodyLogger = middleware.NewBodyLogger(getEnvInt("LOG_BODY_MAX_BYTES", middleware.DefaultBodyLogMaxBytes), redactFields)
+		if logLevel > zerolog.DebugLevel {
+			log.Warn().Msg("LOG_BODIES is set but LOG_LEVEL is above debug; bodies will not be logged")
+		}
+	}
+
+	// Announce deprecated routes (e.g. /api/v1) with Deprecation, Sunset and Link headers
+	deprecatedRoutes, err := middleware.ParseDeprecatedRoutes(os.Getenv("DEPRECATED_ROUTES"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid DEPRECATED_ROUTES configuration")
+	}
+	sunsetDate, err := middleware.ParseSunsetDate(os.Getenv("DEPRECATION_SUNSET_DATE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid DEPRECATION_SUNSET_DATE configuration")
+	}
+	deprecationRouter := middleware.DeprecationMiddleware(deprecatedRoutes, sunsetDate)(corsRouter)
+
+	// Reject requests with an abnormal number of headers (431)
+	headerLimitedRouter := middleware.HeaderLimitMiddleware(getEnvInt("MAX_HEADER_COUNT", middleware.DefaultMaxHeaderCount))(deprecationRouter)
+
+	// Report upstream request IDs in X-Upstream-Trace
+	tracedRouter := middleware.UpstreamTraceMiddleware(headerLimitedRouter)
+
+	// Report how many upstream calls were retried in X-Upstream-Retries
+	retriedRouter := middleware.UpstreamRetriesMiddleware(tracedRouter)
 
 	// Wrap with logging middleware
-	loggedRouter := middleware.LoggingMiddleware(corsRouter)
+	loggedRouter := middleware.NewLoggingMiddleware(bodyLogger)(retriedRouter)
+
+	// Sample slow requests (with their request IDs) when the slow-request log is enabled
+	sampledRouter := loggedRouter
+	if slowRequestRecorder != nil {
+		sampledRouter = slowRequestRecorder.Middleware(loggedRouter)
+	}
+
+	// Assign request IDs before logging so every log line carries one
+	requestIDRouter := middleware.RequestIDHeaderMiddleware(strings.TrimSpace(os.Getenv("REQUEST_ID_HEADER")))(sampledRouter)
+
+	// Count every request by status class for /metrics and /stats
+	meteredRouter := middleware.RequestMetricsMiddleware(httpMetrics)(requestIDRouter)
+
+	// Report the serving gateway region in X-Served-By on every response
+	servedByRouter := middleware.ServedByMiddleware(gatewayRegion)(meteredRouter)
+
+	// Set security headers on every response; "none" disables one, and HSTS is only sent over TLS
+	securedRouter := middleware.SecurityHeadersMiddleware(middleware.SecurityHeaders{
+		ContentTypeOptions:      middleware.SecurityHeaderValue(os.Getenv("SECURITY_CONTENT_TYPE_OPTIONS"), middleware.DefaultContentTypeOptions),
+		FrameOptions:            middleware.SecurityHeaderValue(os.Getenv("SECURITY_FRAME_OPTIONS"), middleware.DefaultFrameOptions),
+		ReferrerPolicy:          middleware.SecurityHeaderValue(os.Getenv("SECURITY_REFERRER_POLICY"), middleware.DefaultReferrerPolicy),
+		StrictTransportSecurity: middleware.SecurityHeaderValue(os.Getenv("SECURITY_HSTS"), middleware.DefaultStrictTransportSecurity),
+	})(servedByRouter)
+
+	// Bound how long writing each response may take so stalled clients release their goroutine;
+	// outermost so it reaches the server's own ResponseWriter
+	deadlineRouter := middleware.WriteDeadlineMiddleware(getEnvDuration("RESPONSE_WRITE_TIMEOUT", middleware.DefaultResponseWriteTimeout))(securedRouter)
 
 	// Create HTTP server
 	serverAddress := fmt.Sprintf(":%s", port)
-	server := &http.Server{
-		Addr:    serverAddress,
-		Handler: loggedRouter,
+	httpServer := &http.Server{
+		Addr:           serverAddress,
+		Handler:        deadlineRouter,
+		MaxHeaderBytes: getEnvInt("MAX_HEADER_BYTES", middleware.DefaultMaxHeaderBytes),
+	}
+
+	// TLS is enabled when both a certificate and key are configured
+	tlsCertFile := os.Getenv("TLS_CERT_FILE")
+	tlsKeyFile := os.Getenv("TLS_KEY_FILE")
+	tlsEnabled := tlsCertFile != "" && tlsKeyFile != ""
+	if tlsEnabled {
+		tlsMinVersion, err := server.ParseTLSVersion(os.Getenv("TLS_MIN_VERSION"))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid TLS_MIN_VERSION configuration")
+		}
+		httpServer.TLSConfig = server.NewTLSConfig(tlsMinVersion)
 	}
 
 	// Channel to listen for shutdown signals
 	shutdownChannel := make(chan os.Signal, 1)
 	signal.Notify(shutdownChannel, syscall.SIGINT, syscall.SIGTERM)
 
+	// Start the liveness self-check; it runs for the life of the process
+	if livenessProbe != nil {
+		go livenessProbe.Run(context.Background())
+	}
+
+	// Keep the /ready dependency cache fresh in the background for the life of the process
+	go handler.RunReadinessRefresher(context.Background())
+
+	// Optionally keep pooled upstream connections warm until shutdown begins (off by default)
+	warmerContext, stopWarmer := context.WithCancel(context.Background())
+	defer stopWarmer()
+	if keepaliveInterval := getEnvDuration("UPSTREAM_KEEPALIVE_INTERVAL", 0); keepaliveInterval > 0 {
+		go serviceProxy.RunConnectionWarmer(warmerContext, keepaliveInterval)
+		log.Info().Dur("interval", keepaliveInterval).Msg("Upstream connection warmer enabled")
+	}
+
 	// Start server in goroutine
 	go func() {
 		log.Info().
 			Str("address", serverAddress).
 			Str("port", port).
+			Bool("tls", tlsEnabled).
 			Msg("OPGL Gateway listening")
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsEnabled {
+			err = httpServer.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal().Err(err).Msg("Server failed to start")
 		}
 	}()
 
 	// Wait for shutdown signal
 	<-shutdownChannel
+	stopWarmer()
+
+	// Keep serving while the load balancer notices /ready is failing and deregisters us.
+	// A second signal skips the remaining delay.
+	prestopDelay := getEnvDuration("PRESTOP_DELAY", 0)
+	log.Info().Dur("prestop_delay", prestopDelay).Msg("Draining: /ready now reports 503")
+	drainContext, cancelDrain := context.WithCancel(context.Background())
+	go func() {
+		<-shutdownChannel
+		cancelDrain()
+	}()
+	readiness.Drain(drainContext, prestopDelay)
+	cancelDrain()
+
 	log.Info().Msg("Shutting down server...")
 
 	// Create shutdown context with timeout
@@ -113,9 +545,63 @@ func main() {
 	defer cancelShutdown()
 
 	// Gracefully shutdown HTTP server
-	if err := server.Shutdown(shutdownContext); err != nil {
+	if err := httpServer.Shutdown(shutdownContext); err != nil {
 		log.Error().Err(err).Msg("Server shutdown error")
 	}
 
 	log.Info().Msg("Server stopped")
 }
+
+// defaultInternalNetworks covers loopback and the RFC 1918 private ranges
+const defaultInternalNetworks = "127.0.0.0/8,::1/128,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16"
+
+// getEnvList reads a comma-separated list from the environment, falling back to
+// defaultValue when unset. Empty entries are dropped.
+func getEnvList(key string, defaultValue string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		value = defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// getEnvInt reads an integer from the environment, falling back to defaultValue when unset.
+// Invalid values are fatal so misconfiguration is caught at startup.
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatal().Err(err).Str("key", key).Msg("Invalid integer in environment")
+	}
+
+	return number
+}
+
+// getEnvDuration reads a duration (e.g. "30s", "5m") from the environment, falling back to
+// defaultValue when unset. Invalid values are fatal so misconfiguration is caught at startup.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatal().Err(err).Str("key", key).Msg("Invalid duration in environment")
+	}
+
+	return duration
+}